@@ -0,0 +1,471 @@
+// Package admin provides an optional, separately-mountable HTTP handler,
+// to inspect and revoke the authentication state kept by the application,
+// protected by its own authenticator, e.g
+//
+//	guard := auth.New()
+//	guard.EnableStrategy(basic.StrategyKey, basic.New(adminsOnly))
+//
+//	h := admin.New(guard,
+//		admin.SetAuthenticator(authenticator),
+//		admin.SetCache("tokens", tokensCache),
+//		admin.SetSessions(sessions),
+//	)
+//	http.Handle("/admin/", http.StripPrefix("/admin", h))
+//
+// The handler serves the following endpoints:
+//
+//	GET    /principals[?cache=name]      list the cached principals.
+//	DELETE /principals?cache=&handle=    revoke a cached principal.
+//	POST   /tokens/revoke                revoke the "token" form value.
+//	GET    /sessions?user=               list the user sessions.
+//	DELETE /sessions?user=&handle=       revoke a user session.
+//	DELETE /users?user=                  revoke all the user principals, sessions, and remember-me series.
+//	GET    /lockout?user=                view the user lockout state.
+//	POST   /reload[?name=]               trigger the keys reload.
+//
+// Responses never carry credentials, cache keys and session ids redacted into handles,
+// (i.e a truncated SHA-256 digest), that identify them in the revocation endpoints.
+package admin
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/session"
+	"github.com/shaj13/go-guardian/otp"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// Principal represents a redacted cached principal.
+type Principal struct {
+	Cache  string   `json:"cache"`
+	Handle string   `json:"handle"`
+	Name   string   `json:"name,omitempty"`
+	ID     string   `json:"id,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Session represents a redacted user session.
+type Session struct {
+	Handle    string    `json:"handle"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Lockout represents a user lockout state.
+type Lockout struct {
+	Locked   bool `json:"locked"`
+	Failures uint `json:"failures"`
+	// RetryAfter is the remaining lockout duration in seconds, 0 if permanently locked or not locked.
+	RetryAfter int `json:"retry_after"`
+}
+
+// LockoutFunc return the lockout state of the user of the given id,
+// e.g by loading the user otp.Verifier, See OTPLockout.
+type LockoutFunc func(r *http.Request, userID string) (Lockout, error)
+
+// OTPLockout return the lockout state of the given verifier.
+func OTPLockout(v *otp.Verifier) Lockout {
+	l := Lockout{Failures: v.Failures}
+
+	if v.Failures == 0 {
+		return l
+	}
+
+	if v.Failures == v.MaxAttempts {
+		l.Locked = true
+		return l
+	}
+
+	delay := v.DelayTime
+	if v.DealyTime.After(delay) {
+		delay = v.DealyTime
+	}
+
+	if remaining := time.Until(delay); remaining > 0 {
+		l.Locked = true
+		l.RetryAfter = int((remaining + time.Second - 1) / time.Second)
+	}
+
+	return l
+}
+
+// Reloader reloads keys, e.g signing keys from a key management service,
+// or a cached JSON web key set.
+type Reloader interface {
+	Reload(ctx context.Context) error
+}
+
+// ReloaderFunc is an adapter to allow the use of ordinary functions as Reloader.
+type ReloaderFunc func(ctx context.Context) error
+
+// Reload calls fn(ctx).
+func (fn ReloaderFunc) Reload(ctx context.Context) error {
+	return fn(ctx)
+}
+
+// Handler serves the admin endpoints.
+type Handler struct {
+	guard         auth.Authenticator
+	authenticator auth.Authenticator
+	caches        map[string]store.Cache
+	sessions      *session.Manager
+	remember      *session.RememberMe
+	lockout       LockoutFunc
+	reloaders     map[string]Reloader
+	realm         string
+	mux           *http.ServeMux
+}
+
+// New return new admin handler, that authenticate the admin requests using the given authenticator,
+// the guard strategies must authenticate the administrators only,
+// and disabled paths of the guard never bypass the authentication.
+// Endpoints of features not configured respond with 404.
+func New(guard auth.Authenticator, opts ...auth.Option) *Handler {
+	if guard == nil {
+		panic("Authenticator required and can't be nil")
+	}
+
+	h := &Handler{
+		guard:     guard,
+		caches:    make(map[string]store.Cache),
+		reloaders: make(map[string]Reloader),
+		realm:     "Admin",
+		mux:       http.NewServeMux(),
+	}
+
+	for _, opt := range opts {
+		opt.Apply(h)
+	}
+
+	h.mux.HandleFunc("/principals", h.principals)
+	h.mux.HandleFunc("/tokens/revoke", h.revokeToken)
+	h.mux.HandleFunc("/sessions", h.userSessions)
+	h.mux.HandleFunc("/users", h.revokeUser)
+	h.mux.HandleFunc("/lockout", h.userLockout)
+	h.mux.HandleFunc("/reload", h.reload)
+
+	return h
+}
+
+// ServeHTTP implements http.Handler, it authenticate the request using the guard,
+// and dispatch it to the admin endpoints.
+// Unauthenticated requests receive 401 with the guard strategies challenges.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	info, err := h.guard.Authenticate(r)
+	if err != nil {
+		auth.SetWWWAuthenticate(w, h.realm, auth.StrategiesOf(h.guard)...)
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.mux.ServeHTTP(w, auth.RequestWithUser(info, r))
+}
+
+func (h *Handler) principals(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listPrincipals(w, r)
+	case http.MethodDelete:
+		h.revokePrincipal(w, r)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+func (h *Handler) listPrincipals(w http.ResponseWriter, r *http.Request) {
+	names := h.cacheNames(r.URL.Query().Get("cache"))
+	if names == nil {
+		writeError(w, http.StatusNotFound, "unknown cache")
+		return
+	}
+
+	principals := []Principal{}
+
+	for _, name := range names {
+		c := h.caches[name]
+		for _, key := range c.Keys() {
+			v, ok, err := c.Load(key, r)
+			if err != nil || !ok {
+				continue
+			}
+
+			p := Principal{Cache: name, Handle: handle(key)}
+			if info, ok := v.(auth.Info); ok {
+				p.Name, p.ID, p.Groups = info.UserName(), info.ID(), info.Groups()
+			}
+
+			principals = append(principals, p)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, principals)
+}
+
+func (h *Handler) revokePrincipal(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	c, ok := h.caches[q.Get("cache")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown cache")
+		return
+	}
+
+	for _, key := range c.Keys() {
+		if handle(key) != q.Get("handle") {
+			continue
+		}
+
+		if err := c.Delete(key, r); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "unknown principal")
+}
+
+func (h *Handler) revokeToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	if h.authenticator == nil {
+		writeError(w, http.StatusNotFound, "token revocation not configured")
+		return
+	}
+
+	token := r.PostFormValue("token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "token required")
+		return
+	}
+
+	for _, s := range auth.StrategiesOf(h.authenticator) {
+		err := auth.Revoke(s, token, r)
+		if err != nil && err != auth.ErrInvalidStrategy {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) userSessions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+		return
+	}
+
+	if h.sessions == nil {
+		writeError(w, http.StatusNotFound, "sessions not configured")
+		return
+	}
+
+	q := r.URL.Query()
+	user := q.Get("user")
+	if user == "" {
+		writeError(w, http.StatusBadRequest, "user required")
+		return
+	}
+
+	list, err := h.sessions.List(r, user)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		sessions := make([]Session, 0, len(list))
+		for _, s := range list {
+			sessions = append(sessions, Session{
+				Handle:    handle(s.ID),
+				UserID:    s.Info.ID(),
+				CreatedAt: s.CreatedAt,
+				LastSeen:  s.LastSeen,
+			})
+		}
+
+		writeJSON(w, http.StatusOK, sessions)
+		return
+	}
+
+	for _, s := range list {
+		if handle(s.ID) != q.Get("handle") {
+			continue
+		}
+
+		if err := h.sessions.Destroy(r, s.ID); err != nil && err != session.ErrSessionNotFound {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "unknown session")
+}
+
+func (h *Handler) revokeUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+
+	user := r.URL.Query().Get("user")
+	if user == "" {
+		writeError(w, http.StatusBadRequest, "user required")
+		return
+	}
+
+	if err := h.revoke(r, user); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revoke delete the cached principals of the user of the given id,
+// and destroy its sessions and remember-me series.
+func (h *Handler) revoke(r *http.Request, user string) error {
+	for _, c := range h.caches {
+		for _, key := range c.Keys() {
+			v, ok, err := c.Load(key, r)
+			if err != nil || !ok {
+				continue
+			}
+
+			if info, ok := v.(auth.Info); !ok || info.ID() != user {
+				continue
+			}
+
+			if err := c.Delete(key, r); err != nil {
+				return err
+			}
+		}
+	}
+
+	if h.sessions != nil {
+		if err := h.sessions.DestroyAll(r, user); err != nil {
+			return err
+		}
+	}
+
+	if h.remember != nil {
+		return h.remember.ForgetAll(r, user)
+	}
+
+	return nil
+}
+
+func (h *Handler) userLockout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	if h.lockout == nil {
+		writeError(w, http.StatusNotFound, "lockout not configured")
+		return
+	}
+
+	user := r.URL.Query().Get("user")
+	if user == "" {
+		writeError(w, http.StatusBadRequest, "user required")
+		return
+	}
+
+	l, err := h.lockout(r, user)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, l)
+}
+
+func (h *Handler) reload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	names := make([]string, 0, len(h.reloaders))
+	if name := r.URL.Query().Get("name"); name != "" {
+		if _, ok := h.reloaders[name]; !ok {
+			writeError(w, http.StatusNotFound, "unknown reloader")
+			return
+		}
+		names = append(names, name)
+	} else {
+		for name := range h.reloaders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		if err := h.reloaders[name].Reload(r.Context()); err != nil {
+			writeError(w, http.StatusInternalServerError, name+": "+err.Error())
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cacheNames return the sorted names of all caches, or the given cache name,
+// nil returned if the given cache unknown.
+func (h *Handler) cacheNames(name string) []string {
+	if name != "" {
+		if _, ok := h.caches[name]; !ok {
+			return nil
+		}
+		return []string{name}
+	}
+
+	names := make([]string, 0, len(h.caches))
+	for name := range h.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// handle return the redacted handle of the given cache key or session id.
+func handle(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return hex.EncodeToString(sum[:8])
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	for _, m := range methods {
+		w.Header().Add("Allow", m)
+	}
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, code, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,252 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/session"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/otp"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func newHandler(t *testing.T, opts ...auth.Option) (*Handler, store.Cache, *session.Manager) {
+	guard := auth.New()
+	guard.EnableStrategy("admin", token.NewStatic(map[string]auth.Info{
+		"admin": auth.NewUserInfo("admin", "0", nil, nil),
+	}))
+
+	cache := store.New(0)
+	fn := func(ctx context.Context, r *http.Request, tk string) (auth.Info, error) {
+		return nil, errors.New("invalid")
+	}
+
+	a := auth.New()
+	a.EnableStrategy(token.CachedStrategyKey, token.New(fn, cache))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	_ = cache.Store("token-1", auth.NewUserInfo("alice", "1", []string{"dev"}, nil), r)
+	_ = cache.Store("token-2", auth.NewUserInfo("bob", "2", nil, nil), r)
+
+	m := session.New(store.New(0))
+	_, err := m.Create(r, auth.NewUserInfo("alice", "1", nil, nil))
+	assert.NoError(t, err)
+
+	opts = append([]auth.Option{
+		SetAuthenticator(a),
+		SetCache("tokens", cache),
+		SetSessions(m),
+	}, opts...)
+
+	return New(guard, opts...), cache, m
+}
+
+func serve(h http.Handler, method, target string, body url.Values) *httptest.ResponseRecorder {
+	var r *http.Request
+	if body != nil {
+		r = httptest.NewRequest(method, target, strings.NewReader(body.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequest(method, target, nil)
+	}
+
+	r.Header.Set("Authorization", "Bearer admin")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestHandlerUnauthorized(t *testing.T) {
+	h, _, _ := newHandler(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/principals", nil)
+	r.Header.Set("Authorization", "Bearer token-1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `Bearer realm="Admin"`)
+}
+
+func TestHandlerPrincipals(t *testing.T) {
+	h, cache, _ := newHandler(t)
+
+	w := serve(h, http.MethodGet, "/principals", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.NotContains(t, w.Body.String(), "token-1")
+
+	var principals []Principal
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&principals))
+	assert.Len(t, principals, 2)
+
+	var alice Principal
+	for _, p := range principals {
+		if p.ID == "1" {
+			alice = p
+		}
+	}
+
+	assert.Equal(t, Principal{Cache: "tokens", Handle: handle("token-1"), Name: "alice", ID: "1",
+		Groups: []string{"dev"}}, alice)
+
+	w = serve(h, http.MethodGet, "/principals?cache=unknown", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(h, http.MethodDelete, "/principals?cache=tokens&handle=unknown", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(h, http.MethodDelete, "/principals?cache=tokens&handle="+alice.Handle, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	_, ok, _ := cache.Load("token-1", nil)
+	assert.False(t, ok)
+
+	w = serve(h, http.MethodPut, "/principals", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestHandlerRevokeToken(t *testing.T) {
+	h, cache, _ := newHandler(t)
+
+	w := serve(h, http.MethodPost, "/tokens/revoke", url.Values{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(h, http.MethodPost, "/tokens/revoke", url.Values{"token": {"token-2"}})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	_, ok, _ := cache.Load("token-2", nil)
+	assert.False(t, ok)
+
+	w = serve(New(auth.New()), http.MethodPost, "/tokens/revoke", url.Values{"token": {"token-2"}})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestHandlerSessions(t *testing.T) {
+	h, _, m := newHandler(t)
+
+	w := serve(h, http.MethodGet, "/sessions", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(h, http.MethodGet, "/sessions?user=1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var sessions []Session
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, "1", sessions[0].UserID)
+
+	w = serve(h, http.MethodDelete, "/sessions?user=1&handle="+sessions[0].Handle, nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	list, _ := m.List(httptest.NewRequest(http.MethodGet, "/", nil), "1")
+	assert.Len(t, list, 0)
+
+	w = serve(h, http.MethodDelete, "/sessions?user=1&handle="+sessions[0].Handle, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandlerRevokeUser(t *testing.T) {
+	h, cache, m := newHandler(t)
+
+	w := serve(h, http.MethodDelete, "/users?user=1", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	_, ok, _ := cache.Load("token-1", nil)
+	assert.False(t, ok)
+
+	_, ok, _ = cache.Load("token-2", nil)
+	assert.True(t, ok)
+
+	list, _ := m.List(httptest.NewRequest(http.MethodGet, "/", nil), "1")
+	assert.Len(t, list, 0)
+}
+
+func TestHandlerLockout(t *testing.T) {
+	v := &otp.Verifier{Failures: 1, MaxAttempts: 3, DelayTime: time.Now().Add(time.Minute)}
+	fn := func(r *http.Request, user string) (Lockout, error) {
+		return OTPLockout(v), nil
+	}
+
+	h, _, _ := newHandler(t, SetLockout(fn))
+
+	w := serve(h, http.MethodGet, "/lockout?user=1", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"locked":true,"failures":1,"retry_after":60}`, w.Body.String())
+
+	h, _, _ = newHandler(t)
+	w = serve(h, http.MethodGet, "/lockout?user=1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOTPLockout(t *testing.T) {
+	table := []struct {
+		name     string
+		verifier *otp.Verifier
+		expected Lockout
+	}{
+		{
+			name:     "it return not locked when no failures",
+			verifier: &otp.Verifier{MaxAttempts: 3},
+			expected: Lockout{},
+		},
+		{
+			name:     "it return locked when max attempts reached",
+			verifier: &otp.Verifier{Failures: 3, MaxAttempts: 3},
+			expected: Lockout{Locked: true, Failures: 3},
+		},
+		{
+			name:     "it return not locked when delay elapsed",
+			verifier: &otp.Verifier{Failures: 1, MaxAttempts: 3, DelayTime: time.Now().Add(-time.Minute)},
+			expected: Lockout{Failures: 1},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, OTPLockout(tt.verifier))
+		})
+	}
+}
+
+func TestHandlerReload(t *testing.T) {
+	var reloaded []string
+	reloader := func(name string, err error) Reloader {
+		return ReloaderFunc(func(ctx context.Context) error {
+			reloaded = append(reloaded, name)
+			return err
+		})
+	}
+
+	h, _, _ := newHandler(t,
+		SetReloader("jwks", reloader("jwks", nil)),
+		SetReloader("kms", reloader("kms", nil)),
+	)
+
+	w := serve(h, http.MethodPost, "/reload", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"jwks", "kms"}, reloaded)
+
+	w = serve(h, http.MethodPost, "/reload?name=kms", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, []string{"jwks", "kms", "kms"}, reloaded)
+
+	w = serve(h, http.MethodPost, "/reload?name=unknown", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	h, _, _ = newHandler(t, SetReloader("jwks", reloader("jwks", errors.New("unavailable"))))
+	w = serve(h, http.MethodPost, "/reload", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "jwks: unavailable")
+}
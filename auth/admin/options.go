@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/session"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// SetAuthenticator sets the application authenticator,
+// its strategies revoke the tokens posted to the token revocation endpoint.
+func SetAuthenticator(a auth.Authenticator) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*Handler); ok {
+			h.authenticator = a
+		}
+	})
+}
+
+// SetCache register a strategy cache of the given name, to list and revoke its cached principals.
+// Only the cached values implementing auth.Info matched when revoking a user.
+func SetCache(name string, c store.Cache) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*Handler); ok {
+			h.caches[name] = c
+		}
+	})
+}
+
+// SetSessions sets the sessions manager, to list and revoke the users sessions.
+func SetSessions(m *session.Manager) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*Handler); ok {
+			h.sessions = m
+		}
+	})
+}
+
+// SetRememberMe sets the remember-me, its series revoked along with the user sessions.
+func SetRememberMe(rm *session.RememberMe) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*Handler); ok {
+			h.remember = rm
+		}
+	})
+}
+
+// SetLockout sets the function returning the users lockout state.
+func SetLockout(fn LockoutFunc) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*Handler); ok {
+			h.lockout = fn
+		}
+	})
+}
+
+// SetReloader register a keys reloader of the given name.
+func SetReloader(name string, r Reloader) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*Handler); ok {
+			h.reloaders[name] = r
+		}
+	})
+}
+
+// SetRealm sets the realm of the challenges sent to unauthenticated requests,
+// Default Admin.
+func SetRealm(realm string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*Handler); ok {
+			h.realm = realm
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// Package apigateway provides an adapter to run go-guardian strategies,
+// as AWS API Gateway Lambda custom authorizer.
+// The adapter accepts API Gateway TOKEN/REQUEST authorizer events,
+// reconstructs an HTTP request, runs the authenticator,
+// and returns the IAM policy or the HTTP API simple response.
+//
+// The events and responses are JSON compatible with the AWS Lambda events,
+// So the Authorizer methods can be passed directly to lambda.Start.
+package apigateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// ErrUnauthorized is returned by Authorizer when the request not authenticated,
+// API Gateway respond 401 Unauthorized when the authorizer return an error with "Unauthorized" message.
+var ErrUnauthorized = errors.New("Unauthorized")
+
+const (
+	// ContextUserName is the authorizer context key carries the authenticated user name.
+	ContextUserName = "username"
+	// ContextID is the authorizer context key carries the authenticated user id.
+	ContextID = "id"
+	// ContextGroups is the authorizer context key carries the authenticated user groups,
+	// as comma separated values.
+	ContextGroups = "groups"
+)
+
+// Authorizer run authenticator against API Gateway authorizer events.
+type Authorizer struct {
+	authenticator auth.Authenticator
+	resource      func(arn string) string
+}
+
+// HandleToken authenticate TOKEN authorizer event,
+// the event authorization token passed as is in the Authorization header.
+func (a *Authorizer) HandleToken(ctx context.Context, e TokenEvent) (PolicyResponse, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	if err != nil {
+		return PolicyResponse{}, err
+	}
+
+	r.Header.Set("Authorization", e.AuthorizationToken)
+
+	return a.policy(r, e.MethodArn)
+}
+
+// HandleRequest authenticate REQUEST authorizer event (REST API).
+func (a *Authorizer) HandleRequest(ctx context.Context, e RequestEvent) (PolicyResponse, error) {
+	r, err := http.NewRequestWithContext(ctx, e.HTTPMethod, "/", nil)
+	if err != nil {
+		return PolicyResponse{}, err
+	}
+
+	q := url.Values{}
+	for k, v := range e.QueryStringParameters {
+		q.Set(k, v)
+	}
+	for k, v := range e.MultiValueQueryStringParameters {
+		q[k] = v
+	}
+
+	setURL(r, e.Path, q.Encode())
+
+	for k, v := range e.Headers {
+		r.Header.Set(k, v)
+	}
+	for k, v := range e.MultiValueHeaders {
+		r.Header[http.CanonicalHeaderKey(k)] = v
+	}
+
+	r.Host = r.Header.Get("Host")
+	r.RemoteAddr = e.RequestContext.Identity.SourceIP
+
+	return a.policy(r, e.MethodArn)
+}
+
+// HandleHTTPRequest authenticate REQUEST authorizer event (HTTP API) payload format version 2.0,
+// and return the simple response.
+// Requests to the authenticator disabled paths not authorized,
+// since API Gateway caches the simple response per identity source across all routes,
+// configure those routes without an authorizer instead.
+func (a *Authorizer) HandleHTTPRequest(ctx context.Context, e V2RequestEvent) (SimpleResponse, error) {
+	r, err := http.NewRequestWithContext(ctx, e.RequestContext.HTTP.Method, "/", nil)
+	if err != nil {
+		return SimpleResponse{}, err
+	}
+
+	setURL(r, e.RawPath, e.RawQueryString)
+
+	for k, v := range e.Headers {
+		r.Header.Set(k, v)
+	}
+
+	if len(e.Cookies) > 0 {
+		r.Header.Set("Cookie", strings.Join(e.Cookies, "; "))
+	}
+
+	r.Host = e.RequestContext.DomainName
+	r.RemoteAddr = e.RequestContext.HTTP.SourceIP
+
+	info, err := a.authenticator.Authenticate(r)
+	if err != nil {
+		return SimpleResponse{IsAuthorized: false}, nil
+	}
+
+	return SimpleResponse{IsAuthorized: true, Context: authContext(info)}, nil
+}
+
+func (a *Authorizer) policy(r *http.Request, arn string) (PolicyResponse, error) {
+	resource := a.resource(arn)
+
+	info, err := a.authenticator.Authenticate(r)
+	if err == auth.ErrDisabledPath {
+		// the policy cached per identity source,
+		// so a disabled path allowed only on the invoked method, never the wildcard resource,
+		// otherwise any token sent to a public path would grant access to all methods.
+		info, err = auth.NewUserInfo("", "", nil, nil), nil
+		resource = arn
+	}
+
+	if err != nil {
+		return PolicyResponse{}, ErrUnauthorized
+	}
+
+	return PolicyResponse{
+		PrincipalID: info.ID(),
+		PolicyDocument: PolicyDocument{
+			Version: "2012-10-17",
+			Statement: []PolicyStatement{
+				{
+					Action:   []string{"execute-api:Invoke"},
+					Effect:   "Allow",
+					Resource: []string{resource},
+				},
+			},
+		},
+		Context: authContext(info),
+	}, nil
+}
+
+func authContext(info auth.Info) map[string]interface{} {
+	return map[string]interface{}{
+		ContextUserName: info.UserName(),
+		ContextID:       info.ID(),
+		ContextGroups:   strings.Join(info.Groups(), ","),
+	}
+}
+
+func setURL(r *http.Request, path, query string) {
+	if len(path) == 0 {
+		path = "/"
+	}
+
+	r.URL.Path = path
+	r.URL.RawQuery = query
+	r.RequestURI = r.URL.RequestURI()
+}
+
+// New return new Authorizer.
+func New(a auth.Authenticator, opts ...auth.Option) *Authorizer {
+	az := &Authorizer{
+		authenticator: a,
+		resource:      func(arn string) string { return arn },
+	}
+
+	for _, opt := range opts {
+		opt.Apply(az)
+	}
+
+	return az
+}
+
+// SetWildcardResource sets the allowed policy resource to all stage methods of the API,
+// instead of the invoked method ARN, so API Gateway can cache the policy across methods.
+// Requests to the authenticator disabled paths always allowed on the invoked method ARN only.
+func SetWildcardResource() auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*Authorizer); ok {
+			a.resource = wildcard
+		}
+	})
+}
+
+// wildcard convert arn:aws:execute-api:region:account:api/stage/method/path
+// to arn:aws:execute-api:region:account:api/stage/*.
+func wildcard(arn string) string {
+	parts := strings.SplitN(arn, "/", 3)
+	if len(parts) < 2 {
+		return arn
+	}
+	return parts[0] + "/" + parts[1] + "/*"
+}
@@ -0,0 +1,208 @@
+package apigateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+const arn = "arn:aws:execute-api:us-east-1:123456789012:api/prod/GET/books"
+
+func authenticator() auth.Authenticator {
+	a := auth.New("/health")
+	a.EnableStrategy("test", strategyFunc(func(ctx context.Context, r *http.Request) (auth.Info, error) {
+		if r.Header.Get("Authorization") != "Bearer token" && r.URL.Query().Get("token") != "token" {
+			return nil, errors.New("invalid token")
+		}
+		return auth.NewUserInfo("test", "1", []string{"admin", "dev"}, nil), nil
+	}))
+	return a
+}
+
+func TestHandleToken(t *testing.T) {
+	table := []struct {
+		name     string
+		token    string
+		opts     []auth.Option
+		err      error
+		resource string
+	}{
+		{
+			name:  "it return ErrUnauthorized when authentication fail",
+			token: "Bearer invalid",
+			err:   ErrUnauthorized,
+		},
+		{
+			name:     "it return allow policy for method arn",
+			token:    "Bearer token",
+			resource: arn,
+		},
+		{
+			name:     "it return allow policy for all stage methods",
+			token:    "Bearer token",
+			opts:     []auth.Option{SetWildcardResource()},
+			resource: "arn:aws:execute-api:us-east-1:123456789012:api/prod/*",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			az := New(authenticator(), tt.opts...)
+			res, err := az.HandleToken(context.Background(), TokenEvent{
+				Type:               "TOKEN",
+				AuthorizationToken: tt.token,
+				MethodArn:          arn,
+			})
+
+			assert.Equal(t, tt.err, err)
+
+			if tt.err != nil {
+				return
+			}
+
+			assert.Equal(t, "1", res.PrincipalID)
+			assert.Equal(t, "Allow", res.PolicyDocument.Statement[0].Effect)
+			assert.Equal(t, []string{tt.resource}, res.PolicyDocument.Statement[0].Resource)
+			assert.Equal(t, "admin,dev", res.Context[ContextGroups])
+		})
+	}
+}
+
+func TestHandleRequest(t *testing.T) {
+	table := []struct {
+		name  string
+		event RequestEvent
+		err   error
+	}{
+		{
+			name: "it return ErrUnauthorized when authentication fail",
+			event: RequestEvent{
+				HTTPMethod: http.MethodGet,
+				Path:       "/books",
+				MethodArn:  arn,
+			},
+			err: ErrUnauthorized,
+		},
+		{
+			name: "it authenticate request using headers",
+			event: RequestEvent{
+				HTTPMethod: http.MethodGet,
+				Path:       "/books",
+				MethodArn:  arn,
+				Headers:    map[string]string{"authorization": "Bearer token"},
+			},
+		},
+		{
+			name: "it authenticate request using query",
+			event: RequestEvent{
+				HTTPMethod:            http.MethodGet,
+				Path:                  "/books",
+				MethodArn:             arn,
+				QueryStringParameters: map[string]string{"token": "token"},
+			},
+		},
+		{
+			name: "it allow request when path disabled",
+			event: RequestEvent{
+				HTTPMethod: http.MethodGet,
+				Path:       "/health",
+				MethodArn:  arn,
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := New(authenticator()).HandleRequest(context.Background(), tt.event)
+
+			assert.Equal(t, tt.err, err)
+
+			if tt.err == nil {
+				assert.Equal(t, "Allow", res.PolicyDocument.Statement[0].Effect)
+				assert.Equal(t, []string{arn}, res.PolicyDocument.Statement[0].Resource)
+			}
+		})
+	}
+}
+
+func TestHandleRequestDisabledPathWildcard(t *testing.T) {
+	e := RequestEvent{
+		HTTPMethod: http.MethodGet,
+		Path:       "/health",
+		MethodArn:  "arn:aws:execute-api:us-east-1:123456789012:api/prod/GET/health",
+		Headers:    map[string]string{"authorization": "garbage"},
+	}
+
+	res, err := New(authenticator(), SetWildcardResource()).HandleRequest(context.Background(), e)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Allow", res.PolicyDocument.Statement[0].Effect)
+	assert.Equal(t, []string{e.MethodArn}, res.PolicyDocument.Statement[0].Resource)
+
+	e.Path = "/books"
+	e.MethodArn = arn
+	res, err = New(authenticator(), SetWildcardResource()).HandleRequest(context.Background(), e)
+	assert.Equal(t, ErrUnauthorized, err)
+}
+
+func TestHandleHTTPRequest(t *testing.T) {
+	table := []struct {
+		name       string
+		path       string
+		headers    map[string]string
+		authorized bool
+	}{
+		{
+			name:       "it return unauthorized response when authentication fail",
+			authorized: false,
+		},
+		{
+			name:       "it return unauthorized response when path disabled",
+			path:       "/health",
+			headers:    map[string]string{"authorization": "garbage"},
+			authorized: false,
+		},
+		{
+			name:       "it return authorized response with user context",
+			headers:    map[string]string{"authorization": "Bearer token"},
+			authorized: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/books"
+			}
+
+			e := V2RequestEvent{
+				Version:  "2.0",
+				RawPath:  path,
+				RouteArn: arn,
+				Headers:  tt.headers,
+			}
+			e.RequestContext.HTTP.Method = http.MethodGet
+
+			res, err := New(authenticator()).HandleHTTPRequest(context.Background(), e)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.authorized, res.IsAuthorized)
+
+			if tt.authorized {
+				assert.Equal(t, "test", res.Context[ContextUserName])
+			}
+		})
+	}
+}
+
+type strategyFunc func(ctx context.Context, r *http.Request) (auth.Info, error)
+
+func (fn strategyFunc) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	return fn(ctx, r)
+}
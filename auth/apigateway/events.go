@@ -0,0 +1,105 @@
+package apigateway
+
+// TokenEvent represents API Gateway TOKEN authorizer event.
+type TokenEvent struct {
+	Type               string `json:"type"`
+	AuthorizationToken string `json:"authorizationToken"`
+	MethodArn          string `json:"methodArn"`
+}
+
+// RequestEvent represents API Gateway (REST API) REQUEST authorizer event.
+type RequestEvent struct {
+	Type                            string              `json:"type"`
+	MethodArn                       string              `json:"methodArn"`
+	Resource                        string              `json:"resource"`
+	Path                            string              `json:"path"`
+	HTTPMethod                      string              `json:"httpMethod"`
+	Headers                         map[string]string   `json:"headers"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+	PathParameters                  map[string]string   `json:"pathParameters"`
+	StageVariables                  map[string]string   `json:"stageVariables"`
+	RequestContext                  RequestContext      `json:"requestContext"`
+}
+
+// RequestContext represents API Gateway (REST API) request context.
+type RequestContext struct {
+	Path       string          `json:"path"`
+	AccountID  string          `json:"accountId"`
+	ResourceID string          `json:"resourceId"`
+	Stage      string          `json:"stage"`
+	RequestID  string          `json:"requestId"`
+	Identity   RequestIdentity `json:"identity"`
+	HTTPMethod string          `json:"httpMethod"`
+	APIID      string          `json:"apiId"`
+}
+
+// RequestIdentity represents API Gateway (REST API) request identity.
+type RequestIdentity struct {
+	SourceIP  string `json:"sourceIp"`
+	UserAgent string `json:"userAgent"`
+}
+
+// V2RequestEvent represents API Gateway (HTTP API) REQUEST authorizer event, payload format version 2.0.
+type V2RequestEvent struct {
+	Version               string            `json:"version"`
+	Type                  string            `json:"type"`
+	RouteArn              string            `json:"routeArn"`
+	IdentitySource        []string          `json:"identitySource"`
+	RouteKey              string            `json:"routeKey"`
+	RawPath               string            `json:"rawPath"`
+	RawQueryString        string            `json:"rawQueryString"`
+	Cookies               []string          `json:"cookies"`
+	Headers               map[string]string `json:"headers"`
+	QueryStringParameters map[string]string `json:"queryStringParameters"`
+	RequestContext        V2RequestContext  `json:"requestContext"`
+	PathParameters        map[string]string `json:"pathParameters"`
+	StageVariables        map[string]string `json:"stageVariables"`
+}
+
+// V2RequestContext represents API Gateway (HTTP API) request context.
+type V2RequestContext struct {
+	AccountID  string               `json:"accountId"`
+	APIID      string               `json:"apiId"`
+	DomainName string               `json:"domainName"`
+	RequestID  string               `json:"requestId"`
+	RouteKey   string               `json:"routeKey"`
+	Stage      string               `json:"stage"`
+	HTTP       V2RequestContextHTTP `json:"http"`
+}
+
+// V2RequestContextHTTP represents API Gateway (HTTP API) request context http description.
+type V2RequestContextHTTP struct {
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Protocol  string `json:"protocol"`
+	SourceIP  string `json:"sourceIp"`
+	UserAgent string `json:"userAgent"`
+}
+
+// PolicyResponse represents API Gateway authorizer IAM policy response.
+type PolicyResponse struct {
+	PrincipalID    string                 `json:"principalId"`
+	PolicyDocument PolicyDocument         `json:"policyDocument"`
+	Context        map[string]interface{} `json:"context,omitempty"`
+}
+
+// PolicyDocument represents an IAM policy document.
+type PolicyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+// PolicyStatement represents an IAM policy statement.
+type PolicyStatement struct {
+	Action   []string `json:"Action"`
+	Effect   string   `json:"Effect"`
+	Resource []string `json:"Resource"`
+}
+
+// SimpleResponse represents API Gateway (HTTP API) authorizer simple response.
+type SimpleResponse struct {
+	IsAuthorized bool                   `json:"isAuthorized"`
+	Context      map[string]interface{} `json:"context,omitempty"`
+}
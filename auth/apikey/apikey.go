@@ -0,0 +1,413 @@
+// Package apikey provides API keys lifecycle management,
+// to generate prefixed API keys (e.g gk_live_...), name, scope, expire, rotate, and revoke them,
+// while only the keys hashes ever stored.
+//
+// Manager Authenticate plugs directly into the token strategy as an API-key lookup:
+//
+//	s := token.New(m.Authenticate, cache,
+//		token.SetType(token.APIKey),
+//		token.SetParser(token.XHeaderParser("X-API-Key")),
+//		token.SetRevocationCheck(m.Revoked),
+//	)
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+const (
+	// ExtensionKeyID is the auth.Info extension key carries the authenticated API key id.
+	ExtensionKeyID = "apikey_id"
+	// ExtensionKeyName is the auth.Info extension key carries the authenticated API key name.
+	ExtensionKeyName = "apikey_name"
+	// ExtensionScopes is the auth.Info extension key carries the authenticated API key scopes.
+	ExtensionScopes = "scopes"
+)
+
+// idLength is the hex encoded key id length.
+const idLength = 16
+
+var (
+	// ErrInvalidKey is returned by Manager,
+	// when the API key malformed, does not exist, revoked, or does not match.
+	ErrInvalidKey = gerrors.WithReason(
+		errors.New("apikey: Invalid API key"),
+		gerrors.ReasonInvalidCredentials,
+	)
+
+	// ErrKeyExpired is returned by Manager, when the API key expired.
+	ErrKeyExpired = gerrors.WithReason(errors.New("apikey: API key expired"), gerrors.ReasonExpired)
+
+	// ErrKeyNotFound is returned by Manager, when the API key id does not exist.
+	ErrKeyNotFound = errors.New("apikey: API key does not exist")
+)
+
+func init() {
+	gob.Register(&record{})
+	gob.Register([]string{})
+}
+
+// Key represents an API key metadata, the key itself returned only once at creation or rotation.
+type Key struct {
+	// ID is the key unique id, embedded in the key.
+	ID string
+	// Name is the key human readable name, e.g "CI deploy".
+	Name string
+	// OwnerID is the key owner user id.
+	OwnerID string
+	// Scopes is the key granted scopes.
+	Scopes []string
+	// CreatedAt is the key creation time.
+	CreatedAt time.Time
+	// ExpiresAt is the key expiration time, zero means the key never expires.
+	ExpiresAt time.Time
+}
+
+// Expired reports whether the key expired.
+func (k *Key) Expired() bool {
+	return !k.ExpiresAt.IsZero() && time.Now().After(k.ExpiresAt)
+}
+
+type record struct {
+	Key
+	Hash       []byte
+	UserName   string
+	Groups     []string
+	Roles      []string
+	Extensions map[string][]string
+}
+
+// Manager generate and authenticate API keys.
+// Keys records kept in the cache keyed by their ids,
+// thus the cache entries lifetime should not be less than the keys lifetime,
+// and a persistent cache should be used for keys that never expire.
+type Manager struct {
+	mu     *sync.Mutex
+	cache  store.Cache
+	prefix string
+}
+
+// New return new Manager, that generate keys prefixed with "gk_live_" by default.
+func New(c store.Cache, opts ...auth.Option) *Manager {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	m := &Manager{
+		mu:     new(sync.Mutex),
+		cache:  c,
+		prefix: "gk_live_",
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	return m
+}
+
+// Create generate a new API key for the given owner,
+// with the given name, scopes, and lifetime, zero ttl means the key never expires.
+// The returned key string must be shown to the owner once, as only its hash stored.
+func (m *Manager) Create(
+	r *http.Request,
+	owner auth.Info,
+	name string,
+	scopes []string,
+	ttl time.Duration,
+) (string, *Key, error) {
+	rec := &record{
+		Key: Key{
+			Name:      name,
+			OwnerID:   owner.ID(),
+			Scopes:    scopes,
+			CreatedAt: time.Now(),
+		},
+		UserName:   owner.UserName(),
+		Groups:     owner.Groups(),
+		Roles:      auth.UserRoles(owner),
+		Extensions: owner.Extensions(),
+	}
+
+	if ttl != 0 {
+		rec.ExpiresAt = rec.CreatedAt.Add(ttl)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.create(r, rec)
+}
+
+// Get return the metadata of the key of the given id.
+func (m *Manager) Get(r *http.Request, id string) (*Key, error) {
+	rec, err := m.load(r, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rec.Key, nil
+}
+
+// List return the metadata of the given owner keys, including expired keys.
+func (m *Manager) List(r *http.Request, ownerID string) ([]*Key, error) {
+	ids, err := m.ids(r, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(ids))
+
+	for _, id := range ids {
+		rec, err := m.load(r, id)
+		if err == ErrKeyNotFound {
+			// evicted by the cache.
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, &rec.Key)
+	}
+
+	return keys, nil
+}
+
+// Rotate generate a new API key replacing the key of the given id,
+// with the same name, owner, scopes, and lifetime.
+// The replaced key remains valid for the given grace period,
+// so clients can be switched to the new key without downtime,
+// zero grace revokes the replaced key immediately.
+func (m *Manager) Rotate(r *http.Request, id string, grace time.Duration) (string, *Key, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old, err := m.load(r, id)
+	if err != nil {
+		return "", nil, err
+	}
+
+	rec := *old
+	now := time.Now()
+
+	rec.CreatedAt = now
+	if !old.ExpiresAt.IsZero() {
+		rec.ExpiresAt = now.Add(old.ExpiresAt.Sub(old.CreatedAt))
+	}
+
+	key, k, err := m.create(r, &rec)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if grace <= 0 {
+		return key, k, m.revoke(r, old)
+	}
+
+	if end := now.Add(grace); old.ExpiresAt.IsZero() || end.Before(old.ExpiresAt) {
+		// copy, so the cached value read by Authenticate never mutated.
+		o := *old
+		o.ExpiresAt = end
+		if err := m.cache.Store(recordKey(o.ID), &o, r); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return key, k, nil
+}
+
+// Revoke revoke the key of the given id.
+func (m *Manager) Revoke(r *http.Request, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rec, err := m.load(r, id)
+	if err != nil {
+		return err
+	}
+
+	return m.revoke(r, rec)
+}
+
+// Authenticate authenticate the given API key,
+// and return the key owner info carrying the key id, name, and scopes extensions.
+// Authenticate signature matches token.AuthenticateFunc.
+func (m *Manager) Authenticate(ctx context.Context, r *http.Request, key string) (auth.Info, error) {
+	id, secret, ok := m.parse(key)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	rec, err := m.load(r, id)
+	if err == ErrKeyNotFound {
+		return nil, ErrInvalidKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare(rec.Hash, hash(secret)) != 1 {
+		return nil, ErrInvalidKey
+	}
+
+	if rec.Expired() {
+		return nil, ErrKeyExpired
+	}
+
+	ext := make(map[string][]string, len(rec.Extensions)+3)
+	for k, v := range rec.Extensions {
+		ext[k] = v
+	}
+
+	ext[ExtensionKeyID] = []string{rec.ID}
+	ext[ExtensionKeyName] = []string{rec.Name}
+	ext[ExtensionScopes] = rec.Scopes
+
+	info := auth.NewUserInfo(rec.UserName, rec.OwnerID, rec.Groups, ext)
+	auth.SetUserRoles(info, rec.Roles)
+
+	if !rec.ExpiresAt.IsZero() {
+		auth.SetUserExpiry(info, rec.ExpiresAt)
+	}
+
+	return info, nil
+}
+
+// Revoked reports whether the given API key revoked or expired,
+// Revoked signature matches token.RevocationCheckFunc,
+// so keys authenticated by the cached token strategy stop working once revoked.
+func (m *Manager) Revoked(ctx context.Context, r *http.Request, key string, _ auth.Info) (bool, error) {
+	_, err := m.Authenticate(ctx, r, key)
+	if err == ErrInvalidKey || err == ErrKeyExpired {
+		return true, nil
+	}
+
+	return false, err
+}
+
+func (m *Manager) create(r *http.Request, rec *record) (string, *Key, error) {
+	b := make([]byte, idLength/2+32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(b[idLength/2:])
+	rec.ID = hex.EncodeToString(b[:idLength/2])
+	rec.Hash = hash(secret)
+
+	ids, err := m.ids(r, rec.OwnerID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := m.cache.Store(recordKey(rec.ID), rec, r); err != nil {
+		return "", nil, err
+	}
+
+	if err := m.cache.Store(ownerKey(rec.OwnerID), append(ids, rec.ID), r); err != nil {
+		return "", nil, err
+	}
+
+	k := rec.Key
+
+	return m.prefix + rec.ID + "_" + secret, &k, nil
+}
+
+func (m *Manager) revoke(r *http.Request, rec *record) error {
+	if err := m.cache.Delete(recordKey(rec.ID), r); err != nil {
+		return err
+	}
+
+	ids, err := m.ids(r, rec.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if id == rec.ID {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+
+	return m.cache.Store(ownerKey(rec.OwnerID), ids, r)
+}
+
+func (m *Manager) load(r *http.Request, id string) (*record, error) {
+	v, ok, err := m.cache.Load(recordKey(id), r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+
+	rec, ok := v.(*record)
+	if !ok {
+		return nil, gerrors.NewInvalidType((*record)(nil), v)
+	}
+
+	return rec, nil
+}
+
+func (m *Manager) ids(r *http.Request, ownerID string) ([]string, error) {
+	v, ok, err := m.cache.Load(ownerKey(ownerID), r)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	ids, ok := v.([]string)
+	if !ok {
+		return nil, gerrors.NewInvalidType([]string(nil), v)
+	}
+
+	// copy, so the cached value never mutated.
+	return append([]string(nil), ids...), nil
+}
+
+// parse split the key into its id and secret.
+func (m *Manager) parse(key string) (string, string, bool) {
+	if !strings.HasPrefix(key, m.prefix) {
+		return "", "", false
+	}
+
+	key = key[len(m.prefix):]
+
+	if len(key) <= idLength+1 || key[idLength] != '_' {
+		return "", "", false
+	}
+
+	return key[:idLength], key[idLength+1:], true
+}
+
+func recordKey(id string) string {
+	return "apikey:" + id
+}
+
+func ownerKey(ownerID string) string {
+	return "apikey:owner:" + ownerID
+}
+
+func hash(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
@@ -0,0 +1,222 @@
+package apikey
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestManager(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	owner := auth.NewUserInfo("alice", "1", []string{"dev"}, nil)
+
+	table := []struct {
+		name string
+		ttl  time.Duration
+		key  func(m *Manager, key string, k *Key) string
+		err  error
+	}{
+		{
+			name: "it authenticate created key",
+			key: func(m *Manager, key string, k *Key) string {
+				return key
+			},
+		},
+		{
+			name: "it return error when key expired",
+			ttl:  -time.Second,
+			key: func(m *Manager, key string, k *Key) string {
+				return key
+			},
+			err: ErrKeyExpired,
+		},
+		{
+			name: "it return error when key revoked",
+			key: func(m *Manager, key string, k *Key) string {
+				_ = m.Revoke(r, k.ID)
+				return key
+			},
+			err: ErrInvalidKey,
+		},
+		{
+			name: "it return error when secret does not match",
+			key: func(m *Manager, key string, k *Key) string {
+				return key[:len(key)-1] + "x"
+			},
+			err: ErrInvalidKey,
+		},
+		{
+			name: "it return error when prefix does not match",
+			key: func(m *Manager, key string, k *Key) string {
+				return strings.Replace(key, "gk_live_", "gk_test_", 1)
+			},
+			err: ErrInvalidKey,
+		},
+		{
+			name: "it return error when key malformed",
+			key: func(m *Manager, key string, k *Key) string {
+				return "gk_live_abc"
+			},
+			err: ErrInvalidKey,
+		},
+		{
+			name: "it return error when rotated without grace",
+			key: func(m *Manager, key string, k *Key) string {
+				_, _, _ = m.Rotate(r, k.ID, 0)
+				return key
+			},
+			err: ErrInvalidKey,
+		},
+		{
+			name: "it authenticate replaced key within grace",
+			key: func(m *Manager, key string, k *Key) string {
+				_, _, _ = m.Rotate(r, k.ID, time.Minute)
+				return key
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(store.New(0))
+			key, k, err := m.Create(r, owner, "ci", []string{"read"}, tt.ttl)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(key, "gk_live_"+k.ID+"_"))
+
+			info, err := m.Authenticate(r.Context(), r, tt.key(m, key, k))
+
+			assert.Equal(t, tt.err, err)
+			if tt.err != nil {
+				return
+			}
+
+			assert.Equal(t, "alice", info.UserName())
+			assert.Equal(t, "1", info.ID())
+			assert.Equal(t, []string{"read"}, info.Extensions()[ExtensionScopes])
+			assert.Equal(t, []string{k.ID}, info.Extensions()[ExtensionKeyID])
+			assert.Equal(t, []string{"ci"}, info.Extensions()[ExtensionKeyName])
+		})
+	}
+}
+
+func TestManagerRotateList(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	m := New(store.New(0), SetPrefix("gk_test_"))
+	owner := auth.NewUserInfo("alice", "1", nil, nil)
+
+	old, k1, _ := m.Create(r, owner, "ci", []string{"read"}, time.Hour)
+	_, k2, _ := m.Create(r, owner, "deploy", nil, 0)
+
+	key, k3, err := m.Rotate(r, k1.ID, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "gk_test_"))
+	assert.Equal(t, "ci", k3.Name)
+	assert.Equal(t, []string{"read"}, k3.Scopes)
+	assert.NotEqual(t, k1.ID, k3.ID)
+
+	replaced, err := m.Get(r, k1.ID)
+	assert.NoError(t, err)
+	assert.True(t, replaced.ExpiresAt.Before(time.Now().Add(time.Minute+time.Second)))
+
+	keys, err := m.List(r, "1")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 3)
+
+	assert.NoError(t, m.Revoke(r, k2.ID))
+
+	keys, _ = m.List(r, "1")
+	assert.Len(t, keys, 2)
+
+	_, err = m.Get(r, k2.ID)
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	revoked, err := m.Revoked(r.Context(), r, old, nil)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	_ = m.Revoke(r, k1.ID)
+
+	revoked, err = m.Revoked(r.Context(), r, old, nil)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestManagerRotateConcurrentAuthenticate(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	m := New(store.New(0))
+	owner := auth.NewUserInfo("alice", "1", nil, nil)
+
+	keys := make([]string, 50)
+	ids := make([]string, 50)
+	for i := range keys {
+		key, k, err := m.Create(r, owner, "ci", nil, time.Hour)
+		assert.NoError(t, err)
+		keys[i], ids[i] = key, k.ID
+	}
+
+	started, done := make(chan struct{}), make(chan struct{})
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			for _, key := range keys {
+				_, err := m.Authenticate(r.Context(), r, key)
+				assert.NoError(t, err)
+			}
+
+			if i == 0 {
+				close(started)
+			}
+		}
+	}()
+
+	<-started
+	for _, id := range ids {
+		_, _, err := m.Rotate(r, id, time.Minute)
+		assert.NoError(t, err)
+	}
+
+	close(done)
+	wg.Wait()
+}
+
+func TestManagerTokenStrategy(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	m := New(store.New(0))
+	key, k, _ := m.Create(r, auth.NewUserInfo("alice", "1", nil, nil), "ci", nil, 0)
+
+	s := token.New(
+		m.Authenticate,
+		store.New(0),
+		token.SetType(token.APIKey),
+		token.SetParser(token.XHeaderParser("X-API-Key")),
+		token.SetRevocationCheck(m.Revoked),
+	)
+
+	r.Header.Set("X-API-Key", key)
+
+	info, err := s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+
+	_ = m.Revoke(r, k.ID)
+
+	_, err = s.Authenticate(r.Context(), r)
+	assert.Error(t, err)
+}
@@ -0,0 +1,16 @@
+package apikey
+
+import (
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetPrefix sets the generated keys prefix, e.g "gk_test_" for test mode keys,
+// a distinct prefix makes leaked keys detectable by secret scanners.
+// Default "gk_live_".
+func SetPrefix(prefix string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.prefix = prefix
+		}
+	})
+}
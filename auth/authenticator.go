@@ -1,9 +1,12 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	gerrors "github.com/shaj13/go-guardian/errors"
 )
@@ -35,25 +38,59 @@ type Authenticator interface {
 	// Otherwise, start the authentication process.
 	// See ErrDisabledPath documentation for more info.
 	//
+	// Users outside their credential validity window rejected, See ValidityInfo.
+	//
+	// if request context carries a memoization slot, the authentication result memoized,
+	// and subsequent calls for the same request return it, See RequestWithMemo.
+	//
 	// NOTICE: Authenticate does not guarantee the order strategies run in.
 	Authenticate(r *http.Request) (Info, error)
+	// AuthenticateToken dispatch the token to the registered strategies that implement TokenStrategy,
+	// and return user information from the first strategy that successfully authenticates the token.
+	// Otherwise, an aggregated error returned.
+	// Typically used to validate a credential already extracted outside of HTTP,
+	// e.g by message-queue consumers, CLI tools, and gRPC services.
+	//
+	// NOTICE: AuthenticateToken does not guarantee the order strategies run in.
+	AuthenticateToken(ctx context.Context, token string) (Info, error)
 	// EnableStrategy register a new strategy to the authenticator.
 	EnableStrategy(key StrategyKey, strategy Strategy)
 	// DisableStrategy unregister a strategy from the authenticator.
 	DisableStrategy(key StrategyKey)
+	// SetStrategyTimeout sets the max duration of a strategy authentication attempt,
+	// the authenticator derive a child context with the timeout for each attempt,
+	// and move to next strategy once the timeout reached, even if the strategy does not honor the context.
+	// 0 timeout means no timeout.
+	SetStrategyTimeout(key StrategyKey, d time.Duration)
 	// Strategy return a registered strategy, Otherwise, nil.
 	Strategy(key StrategyKey) Strategy
+	// Strategies return a copy of the registered strategies map.
+	Strategies() map[StrategyKey]Strategy
+	// StrategyKeys return the registered strategies keys sorted in increasing order.
+	StrategyKeys() []StrategyKey
 	// DisabledPaths return a map[string]struct{} represents a paths disabled from authentication.
 	// Typically the paths are given during authenticator initialization.
 	DisabledPaths() map[string]struct{}
+	// Close release the background resources owned by the registered strategies and their caches,
+	// (e.g cache garbage collectors and background token re-validations), See Close.
+	// Typically called on the application graceful shutdown, the authenticator not usable afterwards.
+	// All the strategies closed even if one fails, and their errors aggregated.
+	Close() error
 }
 
 type authenticator struct {
 	strategies map[StrategyKey]Strategy
+	timeouts   map[StrategyKey]time.Duration
 	paths      map[string]struct{}
 }
 
 func (a *authenticator) Authenticate(r *http.Request) (Info, error) {
+	return memoize(r.Context(), a, func() (Info, error) {
+		return a.authenticate(r)
+	})
+}
+
+func (a *authenticator) authenticate(r *http.Request) (Info, error) {
 	// check if request to a disabled path
 	if a.disabledPath(r.RequestURI) {
 		return nil, ErrDisabledPath
@@ -61,15 +98,61 @@ func (a *authenticator) Authenticate(r *http.Request) (Info, error) {
 
 	errs := gerrors.MultiError{ErrNoMatch}
 
-	for _, strategy := range a.strategies {
-		info, err := strategy.Authenticate(r.Context(), r)
+	for key, strategy := range a.strategies {
+		info, err := authenticateWithTimeout(r.Context(), strategy, r, a.timeouts[key])
+		if err == nil {
+			err = ValidateUser(info, time.Now(), ValidityLeeway)
+		}
+
+		if err == nil {
+			if m := memoFromCtx(r.Context()); m != nil {
+				m.setMethod(key)
+			}
+			return info, nil
+		}
+		errs = append(errs, err)
+	}
+
+	// strategies errors may echo upstream responses, redact them before leaving the package.
+	return nil, gerrors.Redacted(errs)
+}
+
+func (a *authenticator) AuthenticateToken(ctx context.Context, token string) (Info, error) {
+	errs := gerrors.MultiError{ErrNoMatch}
+
+	for key, strategy := range a.strategies {
+		info, err := a.authenticateToken(ctx, key, strategy, token)
+		if err == ErrInvalidStrategy {
+			continue
+		}
+
+		if err == nil {
+			err = ValidateUser(info, time.Now(), ValidityLeeway)
+		}
+
 		if err == nil {
 			return info, nil
 		}
+
 		errs = append(errs, err)
 	}
 
-	return nil, errs
+	return nil, gerrors.Redacted(errs)
+}
+
+func (a *authenticator) authenticateToken(
+	ctx context.Context,
+	key StrategyKey,
+	s Strategy,
+	token string,
+) (Info, error) {
+	if d := a.timeouts[key]; d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	return AuthenticateToken(ctx, s, token)
 }
 
 func (a *authenticator) disabledPath(path string) bool {
@@ -83,6 +166,47 @@ func (a *authenticator) EnableStrategy(key StrategyKey, s Strategy) { a.strategi
 func (a *authenticator) DisableStrategy(key StrategyKey)            { delete(a.strategies, key) }
 func (a *authenticator) DisabledPaths() map[string]struct{}         { return a.paths }
 
+func (a *authenticator) Strategies() map[StrategyKey]Strategy {
+	m := make(map[StrategyKey]Strategy, len(a.strategies))
+	for k, v := range a.strategies {
+		m[k] = v
+	}
+	return m
+}
+
+func (a *authenticator) StrategyKeys() []StrategyKey {
+	keys := make([]StrategyKey, 0, len(a.strategies))
+	for k := range a.strategies {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func (a *authenticator) Close() error {
+	errs := gerrors.MultiError{}
+
+	for _, key := range a.StrategyKeys() {
+		if err := Close(a.strategies[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+func (a *authenticator) SetStrategyTimeout(key StrategyKey, d time.Duration) {
+	if d <= 0 {
+		delete(a.timeouts, key)
+		return
+	}
+	a.timeouts[key] = d
+}
+
 // New return new Authenticator and disables authentication process at a given paths.
 // The returned authenticator not safe for concurrent access.
 func New(paths ...string) Authenticator {
@@ -95,6 +219,20 @@ func New(paths ...string) Authenticator {
 
 	return &authenticator{
 		strategies: make(map[StrategyKey]Strategy),
+		timeouts:   make(map[StrategyKey]time.Duration),
 		paths:      p,
 	}
 }
+
+// StrategiesOf return the authenticator strategies ordered by their keys, See StrategyKeys.
+// Typically used to set the WWW-Authenticate header, See SetWWWAuthenticate.
+func StrategiesOf(a Authenticator) []Strategy {
+	keys := a.StrategyKeys()
+	s := make([]Strategy, 0, len(keys))
+
+	for _, k := range keys {
+		s = append(s, a.Strategy(k))
+	}
+
+	return s
+}
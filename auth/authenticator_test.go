@@ -2,10 +2,12 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -92,3 +94,151 @@ func (s strategy) Authenticate(ctx context.Context, r *http.Request) (Info, erro
 	}
 	return NewDefaultUser("", s.id, nil, nil), nil
 }
+
+func TestAuthenticatorValidityWindow(t *testing.T) {
+	a := New()
+	a.EnableStrategy("expired", expiredStrategy{})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	_, err := a.Authenticate(r)
+	assert.True(t, errors.Is(err, ErrCredentialExpired))
+
+	a.EnableStrategy("valid", strategy{id: "2"})
+
+	info, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", info.ID())
+}
+
+type expiredStrategy struct{}
+
+func (expiredStrategy) Authenticate(ctx context.Context, r *http.Request) (Info, error) {
+	return NewUser("test", "1", WithExpiry(time.Now().Add(-time.Hour))), nil
+}
+
+func TestAuthenticatorStrategyTimeout(t *testing.T) {
+	authenticator := New()
+	authenticator.EnableStrategy("slow", &slowStrategy{d: time.Second})
+	authenticator.SetStrategyTimeout("slow", time.Millisecond)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	start := time.Now()
+	_, err := authenticator.Authenticate(r)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	assert.True(t, time.Since(start) < time.Second)
+
+	authenticator.SetStrategyTimeout("slow", 0)
+	info, err := authenticator.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+}
+
+type tokenStrategy struct {
+	strategy
+	token string
+	d     time.Duration
+}
+
+func (s tokenStrategy) AuthenticateToken(ctx context.Context, token string) (Info, error) {
+	select {
+	case <-time.After(s.d):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if token != s.token {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return NewDefaultUser("", s.id, nil, nil), nil
+}
+
+func TestAuthenticatorAuthenticateToken(t *testing.T) {
+	table := []struct {
+		name        string
+		strategies  map[StrategyKey]Strategy
+		token       string
+		expectedErr bool
+		id          string
+	}{
+		{
+			name:        "it return error when no token strategy registered",
+			strategies:  map[StrategyKey]Strategy{"1": strategy{id: "1"}},
+			token:       "token",
+			expectedErr: true,
+		},
+		{
+			name:        "it return error when token invalid",
+			strategies:  map[StrategyKey]Strategy{"1": tokenStrategy{token: "token"}},
+			token:       "invalid",
+			expectedErr: true,
+		},
+		{
+			name: "it return user from token strategy",
+			strategies: map[StrategyKey]Strategy{
+				"1": strategy{id: "1"},
+				"2": Decorate(tokenStrategy{strategy: strategy{id: "2"}, token: "token"}, Timeout(time.Second)),
+			},
+			token: "token",
+			id:    "2",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := New()
+			for k, s := range tt.strategies {
+				authenticator.EnableStrategy(k, s)
+			}
+
+			info, err := authenticator.AuthenticateToken(context.Background(), tt.token)
+
+			if tt.expectedErr {
+				assert.True(t, errors.Is(err, ErrNoMatch))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.id, info.ID())
+		})
+	}
+}
+
+func TestAuthenticatorAuthenticateTokenTimeout(t *testing.T) {
+	authenticator := New()
+	authenticator.EnableStrategy("slow", tokenStrategy{token: "token", d: time.Second})
+	authenticator.SetStrategyTimeout("slow", time.Millisecond)
+
+	start := time.Now()
+	_, err := authenticator.AuthenticateToken(context.Background(), "token")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestAuthenticatorStrategies(t *testing.T) {
+	authenticator := New()
+	authenticator.EnableStrategy("b", strategy{id: "b"})
+	authenticator.EnableStrategy("a", strategy{id: "a"})
+
+	assert.Equal(t, []StrategyKey{"a", "b"}, authenticator.StrategyKeys())
+
+	strategies := authenticator.Strategies()
+	assert.Len(t, strategies, 2)
+
+	delete(strategies, "a")
+	assert.NotNil(t, authenticator.Strategy("a"))
+}
+
+func TestStrategiesOf(t *testing.T) {
+	a := New()
+	a.EnableStrategy("b", strategy{id: "2"})
+	a.EnableStrategy("a", strategy{id: "1"})
+
+	assert.Equal(t, []Strategy{strategy{id: "1"}, strategy{id: "2"}}, StrategiesOf(a))
+	assert.Empty(t, StrategiesOf(New()))
+}
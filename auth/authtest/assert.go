@@ -0,0 +1,40 @@
+package authtest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// AssertChallenge asserts that the strategy challenge for the given realm equals the expected one.
+// A strategy not implementing Challenge has an empty challenge.
+func AssertChallenge(t testing.TB, s auth.Strategy, realm, expected string) bool {
+	t.Helper()
+
+	got, _ := auth.Challenge(s, realm)
+	if got != expected {
+		t.Errorf("authtest: Unexpected challenge\n\texpected: %q\n\tactual  : %q", expected, got)
+		return false
+	}
+
+	return true
+}
+
+// AssertWWWAuthenticate asserts that the header WWW-Authenticate challenges contain the expected ones,
+// in any order. The header typically obtained from httptest.ResponseRecorder or http.Response.
+func AssertWWWAuthenticate(t testing.TB, h http.Header, expected ...string) bool {
+	t.Helper()
+
+	got := strings.Join(h["Www-Authenticate"], ", ")
+
+	for _, c := range expected {
+		if !strings.Contains(got, c) {
+			t.Errorf("authtest: WWW-Authenticate missing challenge\n\texpected: %q\n\tactual  : %q", c, got)
+			return false
+		}
+	}
+
+	return true
+}
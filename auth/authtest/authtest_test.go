@@ -0,0 +1,97 @@
+package authtest
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+)
+
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeT) Helper()                                   {}
+func (f *fakeT) Errorf(format string, args ...interface{}) { f.failed = true }
+
+func TestStrategy(t *testing.T) {
+	s := SucceedAs("alice", "1", "admin").WithChallenge("Basic")
+	r := NewRequest("GET", "/", Basic("alice", "secret"))
+
+	info, err := s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", info.UserName())
+	assert.Equal(t, []string{"admin"}, info.Groups())
+	assert.Equal(t, 1, s.Calls())
+	assert.Equal(t, r, s.Requests()[0])
+	assert.True(t, AssertChallenge(t, s, "test", `Basic realm="test"`))
+
+	_, err = Fail(nil).Authenticate(r.Context(), r)
+	assert.Equal(t, ErrAuthenticationFailed, err)
+	assert.True(t, AssertChallenge(t, Fail(nil), "test", ""))
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache()
+	fn := func(ctx context.Context, r *http.Request, tk string) (auth.Info, error) {
+		return auth.NewUserInfo("alice", "1", nil, nil), nil
+	}
+	s := token.New(fn, c)
+
+	r := NewRequest("GET", "/", Bearer("token"))
+	_, _ = s.Authenticate(r.Context(), r)
+	_, _ = s.Authenticate(r.Context(), r)
+
+	assert.Equal(t, 2, c.Count(OpLoad))
+	assert.Equal(t, 1, c.Count(OpStore))
+	assert.Equal(t, "token", c.Ops()[1].Key)
+	assert.Equal(t, []string{"token"}, c.Keys())
+
+	_ = c.Delete("token", nil)
+	assert.Equal(t, 1, c.Count(OpDelete))
+
+	c.Reset()
+	assert.Empty(t, c.Ops())
+}
+
+func TestNewRequest(t *testing.T) {
+	cert := new(x509.Certificate)
+	r := NewRequest(
+		"POST",
+		"/",
+		Token("ApiKey", "key"),
+		Header("X-Tenant", "t1"),
+		Cookie(&http.Cookie{Name: "session", Value: "s"}),
+		ClientCertificate(cert),
+	)
+
+	assert.Equal(t, "ApiKey key", r.Header.Get("Authorization"))
+	assert.Equal(t, "t1", r.Header.Get("X-Tenant"))
+	assert.Equal(t, cert, r.TLS.PeerCertificates[0])
+
+	c, err := r.Cookie("session")
+	assert.NoError(t, err)
+	assert.Equal(t, "s", c.Value)
+}
+
+func TestAssertions(t *testing.T) {
+	w := httptest.NewRecorder()
+	auth.SetWWWAuthenticate(w, "test", Fail(nil).WithChallenge("Basic"), Fail(nil).WithChallenge("Bearer"))
+
+	assert.True(t, AssertWWWAuthenticate(t, w.Header(), `Bearer realm="test"`, `Basic realm="test"`))
+
+	ft := new(fakeT)
+	assert.False(t, AssertWWWAuthenticate(ft, w.Header(), `Digest realm="test"`))
+	assert.True(t, ft.failed)
+
+	ft = new(fakeT)
+	assert.False(t, AssertChallenge(ft, Fail(nil), "test", `Basic realm="test"`))
+	assert.True(t, ft.failed)
+}
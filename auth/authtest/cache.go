@@ -0,0 +1,94 @@
+package authtest
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/shaj13/go-guardian/store"
+)
+
+// Operation names recorded by Cache.
+const (
+	OpLoad   = "load"
+	OpStore  = "store"
+	OpDelete = "delete"
+)
+
+// Op represents a recorded cache operation.
+type Op struct {
+	Name  string
+	Key   string
+	Value interface{}
+}
+
+// Cache implements store.Cache and records the operations,
+// while storing the records in an in-memory cache.
+// Cache safe for concurrent usage.
+type Cache struct {
+	cache store.Cache
+	mu    sync.Mutex
+	ops   []Op
+}
+
+// NewCache return new recording Cache backed by an unbounded LRU cache.
+func NewCache() *Cache {
+	return &Cache{cache: store.New(0)}
+}
+
+// Load records the operation and load the value from the backing cache.
+func (c *Cache) Load(key string, r *http.Request) (interface{}, bool, error) {
+	c.record(Op{Name: OpLoad, Key: key})
+	return c.cache.Load(key, r)
+}
+
+// Store records the operation and store the value in the backing cache.
+func (c *Cache) Store(key string, value interface{}, r *http.Request) error {
+	c.record(Op{Name: OpStore, Key: key, Value: value})
+	return c.cache.Store(key, value, r)
+}
+
+// Delete records the operation and delete the value from the backing cache.
+func (c *Cache) Delete(key string, r *http.Request) error {
+	c.record(Op{Name: OpDelete, Key: key})
+	return c.cache.Delete(key, r)
+}
+
+// Keys return the backing cache records keys.
+func (c *Cache) Keys() []string {
+	return c.cache.Keys()
+}
+
+// Ops return the recorded operations.
+func (c *Cache) Ops() []Op {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Op(nil), c.ops...)
+}
+
+// Count return the number of recorded operations with the given name.
+func (c *Cache) Count(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, op := range c.ops {
+		if op.Name == name {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Reset clears the recorded operations, the records kept.
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ops = nil
+}
+
+func (c *Cache) record(op Op) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ops = append(c.ops, op)
+}
@@ -0,0 +1,63 @@
+package authtest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+)
+
+// Credential attaches a credential to a request.
+type Credential func(r *http.Request)
+
+// NewRequest return new incoming server request, suitable for passing to strategies,
+// with the given credentials attached.
+func NewRequest(method, target string, creds ...Credential) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	for _, c := range creds {
+		c(r)
+	}
+	return r
+}
+
+// Basic return Credential attaching the user name and password using the basic scheme.
+func Basic(userName, password string) Credential {
+	return func(r *http.Request) {
+		r.SetBasicAuth(userName, password)
+	}
+}
+
+// Bearer return Credential attaching the token using the bearer scheme.
+func Bearer(token string) Credential {
+	return Token("Bearer", token)
+}
+
+// Token return Credential attaching the token using the given authorization scheme.
+func Token(scheme, token string) Credential {
+	return Header("Authorization", scheme+" "+token)
+}
+
+// Header return Credential setting the given request header, e.g an API key header.
+func Header(key, value string) Credential {
+	return func(r *http.Request) {
+		r.Header.Set(key, value)
+	}
+}
+
+// Cookie return Credential attaching the given cookie, e.g a session cookie.
+func Cookie(c *http.Cookie) Credential {
+	return func(r *http.Request) {
+		r.AddCookie(c)
+	}
+}
+
+// ClientCertificate return Credential attaching the given certificates chain,
+// as the request TLS connection peer certificates, the client certificate first.
+func ClientCertificate(chain ...*x509.Certificate) Credential {
+	return func(r *http.Request) {
+		if r.TLS == nil {
+			r.TLS = new(tls.ConnectionState)
+		}
+		r.TLS.PeerCertificates = chain
+	}
+}
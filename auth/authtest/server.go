@@ -0,0 +1,110 @@
+package authtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// Server is an httptest.Server serving a handler wrapped by the go-guardian middleware,
+// for end-to-end tests of protected APIs. See NewServer.
+type Server struct {
+	*httptest.Server
+	// Authenticator represents the authenticator used by the server middleware.
+	Authenticator auth.Authenticator
+}
+
+// NewServer starts and return new Server serving h wrapped by the middleware,
+// authenticating requests using the given strategies in order.
+// if h nil, UserHandler used. The caller should call Close when finished, to shut it down.
+func NewServer(h http.Handler, strategies ...auth.Strategy) *Server {
+	a := auth.New()
+	for i, s := range strategies {
+		a.EnableStrategy(auth.StrategyKey(strconv.Itoa(i)), s)
+	}
+
+	return NewAuthenticatorServer(a, h)
+}
+
+// NewAuthenticatorServer starts and return new Server serving h wrapped by the middleware,
+// authenticating requests using the given authenticator, the options passed to the middleware.
+// if h nil, UserHandler used. The caller should call Close when finished, to shut it down.
+func NewAuthenticatorServer(a auth.Authenticator, h http.Handler, opts ...auth.Option) *Server {
+	if h == nil {
+		h = UserHandler()
+	}
+
+	return &Server{
+		Server:        httptest.NewServer(auth.Middleware(a, opts...)(h)),
+		Authenticator: a,
+	}
+}
+
+// UserHandler return handler that writes the authenticated user information as JSON,
+// Typically used to assert the identity a request authenticated as.
+func UserHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := auth.User(r)
+		if info == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(auth.NewUserInfo(
+			info.UserName(),
+			info.ID(),
+			info.Groups(),
+			info.Extensions(),
+		))
+	})
+}
+
+// ClientWith return HTTP client that attaches the given credentials to every request.
+func (s *Server) ClientWith(creds ...Credential) *http.Client {
+	c := *s.Client()
+	c.Transport = transport{next: c.Transport, creds: creds}
+	return &c
+}
+
+// BasicClient return HTTP client that attaches the user name and password using the basic scheme.
+func (s *Server) BasicClient(userName, password string) *http.Client {
+	return s.ClientWith(Basic(userName, password))
+}
+
+// BearerClient return HTTP client that attaches the token using the bearer scheme.
+func (s *Server) BearerClient(token string) *http.Client {
+	return s.ClientWith(Bearer(token))
+}
+
+// CookieClient return HTTP client that attaches the given cookies, e.g a session cookie.
+func (s *Server) CookieClient(cookies ...*http.Cookie) *http.Client {
+	creds := make([]Credential, 0, len(cookies))
+	for _, c := range cookies {
+		creds = append(creds, Cookie(c))
+	}
+	return s.ClientWith(creds...)
+}
+
+type transport struct {
+	next  http.RoundTripper
+	creds []Credential
+}
+
+func (t transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// round trippers must not modify the original request.
+	r = r.Clone(r.Context())
+	for _, c := range t.creds {
+		c(r)
+	}
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return next.RoundTrip(r)
+}
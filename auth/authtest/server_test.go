@@ -0,0 +1,75 @@
+package authtest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestServer(t *testing.T) {
+	srv := NewServer(
+		nil,
+		basic.New(func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+			if userName == "alice" && password == "secret" {
+				return auth.NewUserInfo("alice", "1", nil, nil), nil
+			}
+			return nil, basic.ErrInvalidCredentials
+		}, store.New(0)),
+		token.NewStatic(map[string]auth.Info{"token": auth.NewUserInfo("bob", "2", nil, nil)}),
+	)
+	defer srv.Close()
+
+	table := []struct {
+		name   string
+		client *http.Client
+		code   int
+		user   string
+	}{
+		{
+			name:   "it authenticate bearer client",
+			client: srv.BearerClient("token"),
+			code:   http.StatusOK,
+			user:   "bob",
+		},
+		{
+			name:   "it authenticate basic client",
+			client: srv.BasicClient("alice", "secret"),
+			code:   http.StatusOK,
+			user:   "alice",
+		},
+		{
+			name:   "it reject unauthenticated client",
+			client: srv.Client(),
+			code:   http.StatusUnauthorized,
+		},
+		{
+			name:   "it reject invalid credentials",
+			client: srv.CookieClient(&http.Cookie{Name: "session", Value: "invalid"}),
+			code:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.client.Get(srv.URL)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+
+			if len(tt.user) > 0 {
+				info := new(auth.DefaultUser)
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(info))
+				assert.Equal(t, tt.user, info.UserName())
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Package authtest provides utilities for testing applications using go-guardian,
+// fake strategies, a recording cache, request builders attaching credentials,
+// and assertions for challenges.
+package authtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// ErrAuthenticationFailed is returned by strategies created using Fail,
+// when no error provided.
+var ErrAuthenticationFailed = errors.New("authtest: Authentication failed")
+
+// Strategy implements auth.Strategy and return a predefined user information or error,
+// while recording the authenticated requests.
+// Strategy safe for concurrent usage.
+type Strategy struct {
+	info      auth.Info
+	err       error
+	challenge string
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+// Succeed return Strategy that authenticate every request as the given user information.
+func Succeed(info auth.Info) *Strategy {
+	return &Strategy{info: info}
+}
+
+// SucceedAs return Strategy that authenticate every request as a user with the given name,
+// id and groups.
+func SucceedAs(name, id string, groups ...string) *Strategy {
+	return Succeed(auth.NewUserInfo(name, id, groups, nil))
+}
+
+// Fail return Strategy that fail every request with the given error,
+// or ErrAuthenticationFailed if err nil.
+func Fail(err error) *Strategy {
+	if err == nil {
+		err = ErrAuthenticationFailed
+	}
+	return &Strategy{err: err}
+}
+
+// WithChallenge sets the strategy challenge scheme (e.g Basic),
+// so the strategy challenge in the form `<scheme> realm="<realm>"`.
+func (s *Strategy) WithChallenge(scheme string) *Strategy {
+	s.challenge = scheme
+	return s
+}
+
+// Authenticate records the request and return the predefined user information or error.
+func (s *Strategy) Authenticate(_ context.Context, r *http.Request) (auth.Info, error) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.mu.Unlock()
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.info, nil
+}
+
+// Challenge returns the strategy challenge, or empty string if the challenge scheme not set.
+func (s *Strategy) Challenge(realm string) string {
+	if len(s.challenge) == 0 {
+		return ""
+	}
+	return s.challenge + ` realm="` + realm + `"`
+}
+
+// Calls return the number of authenticated requests.
+func (s *Strategy) Calls() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// Requests return the authenticated requests.
+func (s *Strategy) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
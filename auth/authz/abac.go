@@ -0,0 +1,455 @@
+// Package authz provides authorization for the requests authenticated by go-guardian,
+// using declarative attribute-based access control (ABAC) policies,
+// as a lighter-weight alternative to OPA or Casbin integrations,
+// and route tables declaring the required roles and scopes per route.
+package authz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// Effect is the rule effect when it matches the request.
+type Effect string
+
+// Rules effects.
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Operator is the condition operator, applied to the attribute values and the condition values.
+type Operator string
+
+// Conditions operators.
+const (
+	// In matches when any of the attribute values equal any of the condition values.
+	In Operator = "in"
+	// NotIn matches when none of the attribute values equal any of the condition values.
+	NotIn Operator = "not_in"
+	// Prefix matches when any of the attribute values starts with any of the condition values.
+	Prefix Operator = "prefix"
+	// Exists matches when the attribute has a value.
+	Exists Operator = "exists"
+	// NotExists matches when the attribute does not have a value.
+	NotExists Operator = "not_exists"
+	// CIDR matches when the attribute IP address within any of the condition CIDR blocks.
+	CIDR Operator = "cidr"
+	// Between matches when the attribute clock time (15:04) within the condition two values range,
+	// the range wraps midnight when the start after the end, e.g ["22:00", "06:00"].
+	Between Operator = "between"
+)
+
+// Attributes names, resolved from the request attributes.
+// Condition values written as ${<attribute>} resolved to the attribute values,
+// e.g {"attribute": "request.query.tenant", "operator": "in", "values": ["${user.ext.tenant}"]}.
+const (
+	AttrUserID        = "user.id"
+	AttrUserName      = "user.name"
+	AttrUserGroups    = "user.groups"
+	AttrUserRoles     = "user.roles"
+	AttrUserExtension = "user.ext." // followed by the extension key.
+	AttrMethod        = "request.method"
+	AttrPath          = "request.path"
+	AttrQuery         = "request.query." // followed by the query param key.
+	AttrIP            = "env.ip"
+	AttrTime          = "env.time"
+	AttrWeekday       = "env.weekday"
+)
+
+// Attributes represents the attributes of an authorization request.
+type Attributes struct {
+	// User is the authenticated user, or nil.
+	User   auth.Info
+	Method string
+	Path   string
+	Query  url.Values
+	// Time is the request time, its location used by the env.time and env.weekday attributes.
+	Time time.Time
+	IP   net.IP
+}
+
+// RequestAttributes return the attributes of the given HTTP request,
+// the user read from the request context and the IP address from the request remote address,
+// See the proxy package to set the remote address of requests behind proxies.
+func RequestAttributes(r *http.Request) *Attributes {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return &Attributes{
+		User:   auth.User(r),
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Time:   time.Now(),
+		IP:     net.ParseIP(host),
+	}
+}
+
+// Values return the values of the given attribute name.
+func (a *Attributes) Values(name string) []string {
+	switch {
+	case name == AttrMethod:
+		return []string{a.Method}
+	case name == AttrPath:
+		return []string{a.Path}
+	case strings.HasPrefix(name, AttrQuery):
+		return a.Query[name[len(AttrQuery):]]
+	case name == AttrIP:
+		if a.IP == nil {
+			return nil
+		}
+		return []string{a.IP.String()}
+	case name == AttrTime:
+		return []string{a.Time.Format("15:04")}
+	case name == AttrWeekday:
+		return []string{strings.ToLower(a.Time.Weekday().String())}
+	case a.User == nil:
+		return nil
+	case name == AttrUserID:
+		return []string{a.User.ID()}
+	case name == AttrUserName:
+		return []string{a.User.UserName()}
+	case name == AttrUserGroups:
+		return a.User.Groups()
+	case name == AttrUserRoles:
+		return auth.UserRoles(a.User)
+	case strings.HasPrefix(name, AttrUserExtension):
+		return a.User.Extensions()[name[len(AttrUserExtension):]]
+	}
+
+	return nil
+}
+
+// Condition represents a rule condition on a request attribute.
+type Condition struct {
+	Attribute string   `json:"attribute"`
+	Operator  Operator `json:"operator"`
+	Values    []string `json:"values,omitempty"`
+}
+
+// Rule represents a policy rule, that matches a request when the request method and path matches,
+// and all the rule conditions match.
+type Rule struct {
+	// Name identifies the rule in the policy decisions.
+	Name   string `json:"name"`
+	Effect Effect `json:"effect"`
+	// Methods is the rule HTTP methods, empty matches any method.
+	Methods []string `json:"methods,omitempty"`
+	// Paths is the rule path patterns, empty matches any path.
+	// Patterns use path.Match syntax, and a pattern ending with "/**" matches the path and its sub paths.
+	Paths      []string    `json:"paths,omitempty"`
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Decision represents a policy evaluation result.
+type Decision struct {
+	Allowed bool
+	// Rule is the name of the rule decided the result, empty when no rule matched.
+	Rule string
+}
+
+// Policy evaluates requests attributes against a set of rules.
+// A request denied if any deny rule matches, Otherwise, allowed if any allow rule matches,
+// and denied by default when no rule matches.
+type Policy struct {
+	rules []rule
+}
+
+// NewPolicy return new Policy of the given rules,
+// or an error if a rule invalid, e.g unknown operator or malformed path pattern.
+func NewPolicy(rules ...Rule) (*Policy, error) {
+	p := &Policy{rules: make([]rule, 0, len(rules))}
+
+	for i, r := range rules {
+		cr, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("authz: Invalid rule #%d %q: %w", i, r.Name, err)
+		}
+		p.rules = append(p.rules, cr)
+	}
+
+	return p, nil
+}
+
+// ParsePolicy return new Policy of the given JSON document, i.e {"rules": [<Rule>...]}.
+func ParsePolicy(b []byte) (*Policy, error) {
+	doc := struct {
+		Rules []Rule `json:"rules"`
+	}{}
+
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	return NewPolicy(doc.Rules...)
+}
+
+// Evaluate evaluates the given attributes against the policy rules.
+func (p *Policy) Evaluate(a *Attributes) Decision {
+	d := Decision{}
+
+	for _, r := range p.rules {
+		if !r.match(a) {
+			continue
+		}
+
+		if r.Effect == Deny {
+			return Decision{Rule: r.Name}
+		}
+
+		if !d.Allowed {
+			d = Decision{Allowed: true, Rule: r.Name}
+		}
+	}
+
+	return d
+}
+
+// Allowed reports whether the policy allows the given HTTP request.
+func (p *Policy) Allowed(r *http.Request) bool {
+	return p.Evaluate(RequestAttributes(r)).Allowed
+}
+
+// SetErrorHandler sets the authorization middlewares error handler,
+// invoked with auth.ErrForbidden when the request denied,
+// or ErrUnauthenticated when a non public route requested without authentication.
+// Default auth.ProblemJSONErrorHandler.
+func SetErrorHandler(h auth.ErrorHandler) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*middleware); ok {
+			m.errHandler = h
+		}
+	})
+}
+
+// Middleware return HTTP middleware that authorize requests using the given policy,
+// it must be placed after the authentication middleware, so the user attributes available.
+func Middleware(p *Policy, opts ...auth.Option) func(http.Handler) http.Handler {
+	m := &middleware{
+		authorize: func(r *http.Request) error {
+			if !p.Allowed(r) {
+				return auth.ErrForbidden
+			}
+			return nil
+		},
+		errHandler: auth.ProblemJSONErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	return m.handler
+}
+
+type middleware struct {
+	authorize  func(r *http.Request) error
+	errHandler auth.ErrorHandler
+}
+
+func (m *middleware) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := m.authorize(r); err != nil {
+			m.errHandler(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+type condition struct {
+	Condition
+	nets       []*net.IPNet
+	start, end int
+}
+
+type rule struct {
+	Rule
+	conditions []condition
+}
+
+func compile(r Rule) (rule, error) {
+	if r.Effect != Allow && r.Effect != Deny {
+		return rule{}, fmt.Errorf("unknown effect %q", r.Effect)
+	}
+
+	for _, p := range r.Paths {
+		if _, err := path.Match(strings.TrimSuffix(p, "/**"), ""); err != nil {
+			return rule{}, fmt.Errorf("path %q: %w", p, err)
+		}
+	}
+
+	cr := rule{Rule: r, conditions: make([]condition, 0, len(r.Conditions))}
+
+	for _, c := range r.Conditions {
+		cc := condition{Condition: c}
+
+		switch c.Operator {
+		case In, NotIn, Prefix, Exists, NotExists:
+		case CIDR:
+			for _, v := range c.Values {
+				_, n, err := net.ParseCIDR(v)
+				if err != nil {
+					return rule{}, err
+				}
+				cc.nets = append(cc.nets, n)
+			}
+		case Between:
+			if len(c.Values) != 2 {
+				return rule{}, fmt.Errorf("%s operator requires two values", c.Operator)
+			}
+
+			var err1, err2 error
+			cc.start, err1 = minutes(c.Values[0])
+			cc.end, err2 = minutes(c.Values[1])
+
+			if err1 != nil || err2 != nil {
+				return rule{}, fmt.Errorf("%s operator values must be in 15:04 format", c.Operator)
+			}
+		default:
+			return rule{}, fmt.Errorf("unknown operator %q", c.Operator)
+		}
+
+		cr.conditions = append(cr.conditions, cc)
+	}
+
+	return cr, nil
+}
+
+func (r rule) match(a *Attributes) bool {
+	if len(r.Methods) > 0 && !containsFold(r.Methods, a.Method) {
+		return false
+	}
+
+	if len(r.Paths) > 0 && !matchPath(r.Paths, a.Path) {
+		return false
+	}
+
+	for _, c := range r.conditions {
+		if !c.match(a) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c condition) match(a *Attributes) bool {
+	attrs := a.Values(c.Attribute)
+
+	switch c.Operator {
+	case Exists:
+		return len(attrs) > 0
+	case NotExists:
+		return len(attrs) == 0
+	case CIDR:
+		for _, v := range attrs {
+			ip := net.ParseIP(v)
+			for _, n := range c.nets {
+				if ip != nil && n.Contains(ip) {
+					return true
+				}
+			}
+		}
+		return false
+	case Between:
+		for _, v := range attrs {
+			m, err := minutes(v)
+			if err != nil {
+				continue
+			}
+
+			if c.start <= c.end && m >= c.start && m < c.end ||
+				c.start > c.end && (m >= c.start || m < c.end) {
+				return true
+			}
+		}
+		return false
+	}
+
+	values := c.resolve(a)
+	found := false
+
+	for _, attr := range attrs {
+		for _, v := range values {
+			if c.Operator == Prefix && strings.HasPrefix(attr, v) || c.Operator != Prefix && attr == v {
+				found = true
+			}
+		}
+	}
+
+	if c.Operator == NotIn {
+		return !found
+	}
+
+	return found
+}
+
+// resolve return the condition values, with ${<attribute>} values replaced by the attribute values.
+func (c condition) resolve(a *Attributes) []string {
+	values := make([]string, 0, len(c.Values))
+
+	for _, v := range c.Values {
+		if strings.HasPrefix(v, "${") && strings.HasSuffix(v, "}") {
+			values = append(values, a.Values(v[2:len(v)-1])...)
+			continue
+		}
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func matchPath(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if prefix := strings.TrimSuffix(pattern, "/**"); prefix != pattern {
+			if ok, _ := path.Match(prefix, p); ok {
+				return true
+			}
+
+			// match the sub paths, by matching the pattern against the path leading segments.
+			n := strings.Count(prefix, "/")
+			if parts := strings.SplitN(p, "/", n+2); len(parts) == n+2 {
+				if ok, _ := path.Match(prefix, strings.Join(parts[:n+1], "/")); ok {
+					return true
+				}
+			}
+			continue
+		}
+
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsFold(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func minutes(v string) (int, error) {
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
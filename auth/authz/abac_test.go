@@ -0,0 +1,173 @@
+package authz
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+const testPolicy = `{
+	"rules": [
+		{
+			"name": "admins",
+			"effect": "allow",
+			"conditions": [{"attribute": "user.groups", "operator": "in", "values": ["admin"]}]
+		},
+		{
+			"name": "tenant-read",
+			"effect": "allow",
+			"methods": ["GET"],
+			"paths": ["/tenants/*/**"],
+			"conditions": [
+				{"attribute": "request.query.tenant", "operator": "in", "values": ["${user.ext.tenant}"]}
+			]
+		},
+		{
+			"name": "office-hours",
+			"effect": "deny",
+			"paths": ["/billing/**"],
+			"conditions": [{"attribute": "env.time", "operator": "between", "values": ["18:00", "08:00"]}]
+		},
+		{
+			"name": "internal",
+			"effect": "allow",
+			"paths": ["/billing/**"],
+			"conditions": [{"attribute": "env.ip", "operator": "cidr", "values": ["10.0.0.0/8"]}]
+		},
+		{
+			"name": "blocked",
+			"effect": "deny",
+			"conditions": [{"attribute": "user.ext.blocked", "operator": "exists"}]
+		}
+	]
+}`
+
+func TestPolicyEvaluate(t *testing.T) {
+	p, err := ParsePolicy([]byte(testPolicy))
+	assert.NoError(t, err)
+
+	noon := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2020, 1, 1, 23, 0, 0, 0, time.UTC)
+	tenant := auth.NewUserInfo("alice", "1", nil, map[string][]string{"tenant": {"acme"}})
+
+	table := []struct {
+		name  string
+		attrs *Attributes
+		rule  string
+		allow bool
+	}{
+		{
+			name:  "it allow admin",
+			attrs: &Attributes{User: auth.NewUserInfo("bob", "2", []string{"admin"}, nil), Path: "/x"},
+			rule:  "admins",
+			allow: true,
+		},
+		{
+			name:  "it deny when no rule matches",
+			attrs: &Attributes{User: tenant, Path: "/x"},
+		},
+		{
+			name: "it allow tenant own resources",
+			attrs: &Attributes{
+				User:   tenant,
+				Method: "GET",
+				Path:   "/tenants/acme/users/1",
+				Query:  url.Values{"tenant": {"acme"}},
+			},
+			rule:  "tenant-read",
+			allow: true,
+		},
+		{
+			name: "it deny tenant other tenants resources",
+			attrs: &Attributes{
+				User:   tenant,
+				Method: "GET",
+				Path:   "/tenants/other/users",
+				Query:  url.Values{"tenant": {"other"}},
+			},
+		},
+		{
+			name: "it deny tenant write",
+			attrs: &Attributes{
+				User:   tenant,
+				Method: "POST",
+				Path:   "/tenants/acme/users",
+				Query:  url.Values{"tenant": {"acme"}},
+			},
+		},
+		{
+			name:  "it allow internal network",
+			attrs: &Attributes{Path: "/billing/invoices", IP: net.ParseIP("10.1.2.3"), Time: noon},
+			rule:  "internal",
+			allow: true,
+		},
+		{
+			name:  "it deny outside internal network",
+			attrs: &Attributes{Path: "/billing/invoices", IP: net.ParseIP("8.8.8.8"), Time: noon},
+		},
+		{
+			name:  "it deny outside office hours",
+			attrs: &Attributes{Path: "/billing", IP: net.ParseIP("10.1.2.3"), Time: night},
+			rule:  "office-hours",
+		},
+		{
+			name: "it deny overrides allow",
+			attrs: &Attributes{
+				User: auth.NewUserInfo("bob", "2", []string{"admin"}, map[string][]string{"blocked": {"true"}}),
+				Path: "/x",
+			},
+			rule: "blocked",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			d := p.Evaluate(tt.attrs)
+			assert.Equal(t, tt.allow, d.Allowed)
+			assert.Equal(t, tt.rule, d.Rule)
+		})
+	}
+}
+
+func TestNewPolicyInvalid(t *testing.T) {
+	cidr := Condition{Attribute: AttrIP, Operator: CIDR, Values: []string{"x"}}
+	table := []Rule{
+		{Name: "effect", Effect: "maybe"},
+		{Name: "operator", Effect: Allow, Conditions: []Condition{{Attribute: "user.id", Operator: "like"}}},
+		{Name: "cidr", Effect: Allow, Conditions: []Condition{cidr}},
+		{Name: "between", Effect: Allow, Conditions: []Condition{{Attribute: "env.time", Operator: Between}}},
+		{Name: "path", Effect: Allow, Paths: []string{"/[a"}},
+	}
+
+	for _, r := range table {
+		_, err := NewPolicy(r)
+		assert.Error(t, err, r.Name)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	p, _ := NewPolicy(Rule{
+		Name:       "users",
+		Effect:     Allow,
+		Conditions: []Condition{{Attribute: AttrUserID, Operator: Exists}},
+	})
+
+	h := Middleware(p)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	r = auth.RequestWithUser(auth.NewUserInfo("alice", "1", nil, nil), r)
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
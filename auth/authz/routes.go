@@ -0,0 +1,265 @@
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// ErrUnauthenticated is returned by the routes middleware,
+// when a request to a non public route not authenticated.
+var ErrUnauthenticated = errors.New("authz: Request not authenticated")
+
+// Permission represents a route required permissions,
+// the zero value requires only an authenticated user.
+type Permission struct {
+	// Public allows unauthenticated requests.
+	Public bool
+	// Roles requires the user to have any of the roles, or any of the groups of the same names.
+	Roles []string
+	// Scopes requires the user to be granted all of the scopes.
+	Scopes []string
+}
+
+// RouteTable maps routes to their required permissions,
+// a route is a method and a path pattern separated by a space, e.g "GET /users/*",
+// where the method "*" or an omitted method matches any method.
+// Patterns use path.Match syntax, and a pattern ending with "/**" matches the path and its sub paths.
+// When more than one route matches a request, the most specific route used,
+// i.e a pattern without wildcards, then the longest pattern, then a route with an explicit method.
+type RouteTable map[string]Permission
+
+// Routes return RouteTable of the given struct (or pointer to struct) route table fields tags,
+// each exported field carries a route tag, and its permission declared by the roles and scopes tags,
+// with comma separated values, or the authz tag of "public" or "authenticated" value, e.g:
+//
+//	type API struct {
+//		Health    http.HandlerFunc `route:"GET /health" authz:"public"`
+//		Profile   http.HandlerFunc `route:"GET /me" authz:"authenticated"`
+//		ListUsers http.HandlerFunc `route:"GET /users" roles:"admin" scopes:"users:read"`
+//	}
+//
+// Routes return an error if a field does not declare a route or a permission,
+// so routes without a policy detected at startup.
+func Routes(v interface{}) (RouteTable, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("authz: Route table must be a struct, got %T", v)
+	}
+
+	rt := rv.Type()
+	table := make(RouteTable, rt.NumField())
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		route := f.Tag.Get("route")
+		if route == "" {
+			return nil, fmt.Errorf("authz: Route table field %s missing route tag", f.Name)
+		}
+
+		p := Permission{
+			Roles:  split(f.Tag.Get("roles")),
+			Scopes: split(f.Tag.Get("scopes")),
+		}
+
+		switch f.Tag.Get("authz") {
+		case "public":
+			p.Public = true
+		case "authenticated":
+		case "":
+			if len(p.Roles) == 0 && len(p.Scopes) == 0 {
+				return nil, fmt.Errorf("authz: Route %q (%s) missing permission", route, f.Name)
+			}
+		default:
+			return nil, fmt.Errorf("authz: Route %q (%s) has unknown authz tag", route, f.Name)
+		}
+
+		table[route] = p
+	}
+
+	return table, nil
+}
+
+// Check return an error listing the given routes (e.g registered by the router at startup),
+// that does not have a permission in the table, so routes without a policy detected early.
+// Routes are in the table route format, and their path parameters (e.g /users/{id})
+// matched as literal segments.
+func (t RouteTable) Check(routes ...string) error {
+	compiled := t.compile()
+	missing := []string{}
+
+	for _, r := range routes {
+		method, pattern := parseRoute(r)
+		if _, ok := match(compiled, method, pattern); !ok {
+			missing = append(missing, r)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("authz: Routes missing permission: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// SetScopesExtension sets the auth.Info extension key holds the user granted scopes,
+// each extension value may hold multiple space separated scopes (e.g the OAuth2 scope claim).
+// Default "scopes".
+func SetScopesExtension(key string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if r, ok := v.(*routes); ok {
+			r.scopesKey = key
+		}
+	})
+}
+
+// RoutesMiddleware return HTTP middleware that authorize requests using the permission,
+// of the request route in the given table, instead of wrapping every handler individually.
+// Requests to routes not in the table denied.
+// It must be placed after the authentication middleware, and the authentication middleware
+// must not reject unauthenticated requests, if the table has public routes.
+func RoutesMiddleware(t RouteTable, opts ...auth.Option) func(http.Handler) http.Handler {
+	r := &routes{
+		compiled:  t.compile(),
+		scopesKey: "scopes",
+	}
+
+	m := &middleware{
+		errHandler: auth.ProblemJSONErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(r)
+		opt.Apply(m)
+	}
+
+	m.authorize = r.authorize
+
+	return m.handler
+}
+
+type route struct {
+	method, pattern string
+	Permission
+}
+
+type routes struct {
+	compiled  []route
+	scopesKey string
+}
+
+func (rs *routes) authorize(r *http.Request) error {
+	p, ok := match(rs.compiled, r.Method, r.URL.Path)
+	if !ok {
+		return auth.ErrForbidden
+	}
+
+	if p.Public {
+		return nil
+	}
+
+	info := auth.User(r)
+	if info == nil {
+		return ErrUnauthenticated
+	}
+
+	if len(p.Roles) > 0 && !hasAny(p.Roles, auth.UserRoles(info), info.Groups()) {
+		return auth.ErrForbidden
+	}
+
+	granted := []string{}
+	for _, v := range info.Extensions()[rs.scopesKey] {
+		granted = append(granted, strings.Fields(v)...)
+	}
+
+	for _, s := range p.Scopes {
+		if !hasAny([]string{s}, granted) {
+			return auth.ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+func (t RouteTable) compile() []route {
+	compiled := make([]route, 0, len(t))
+
+	for k, p := range t {
+		method, pattern := parseRoute(k)
+		compiled = append(compiled, route{method: method, pattern: pattern, Permission: p})
+	}
+
+	// most specific routes first.
+	sort.Slice(compiled, func(i, j int) bool {
+		a, b := compiled[i], compiled[j]
+		wa, wb := strings.ContainsAny(a.pattern, "*?["), strings.ContainsAny(b.pattern, "*?[")
+
+		switch {
+		case wa != wb:
+			return !wa
+		case len(a.pattern) != len(b.pattern):
+			return len(a.pattern) > len(b.pattern)
+		case (a.method == "*") != (b.method == "*"):
+			return b.method == "*"
+		}
+
+		return a.method < b.method
+	})
+
+	return compiled
+}
+
+func match(compiled []route, method, p string) (Permission, bool) {
+	for _, r := range compiled {
+		if (r.method == "*" || strings.EqualFold(r.method, method)) && matchPath([]string{r.pattern}, p) {
+			return r.Permission, true
+		}
+	}
+
+	return Permission{}, false
+}
+
+func parseRoute(r string) (string, string) {
+	r = strings.TrimSpace(r)
+
+	if i := strings.IndexByte(r, ' '); i > 0 {
+		return r[:i], strings.TrimSpace(r[i+1:])
+	}
+
+	return "*", r
+}
+
+func hasAny(want []string, sets ...[]string) bool {
+	for _, set := range sets {
+		for _, v := range set {
+			for _, w := range want {
+				if v == w {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func split(v string) []string {
+	out := []string{}
+
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
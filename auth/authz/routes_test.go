@@ -0,0 +1,105 @@
+package authz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+type testAPI struct {
+	Health    http.HandlerFunc `route:"GET /health" authz:"public"`
+	Profile   http.HandlerFunc `route:"GET /me" authz:"authenticated"`
+	ListUsers http.HandlerFunc `route:"GET /users" roles:"admin" scopes:"users:read"`
+	GetUser   http.HandlerFunc `route:"GET /users/*" roles:"admin, support"`
+	Admin     http.HandlerFunc `route:"/admin/**" roles:"admin"`
+	private   http.HandlerFunc // nolint:unused,structcheck
+}
+
+func TestRoutes(t *testing.T) {
+	table, err := Routes(&testAPI{})
+	assert.NoError(t, err)
+	assert.Equal(t, RouteTable{
+		"GET /health":  {Public: true, Roles: []string{}, Scopes: []string{}},
+		"GET /me":      {Roles: []string{}, Scopes: []string{}},
+		"GET /users":   {Roles: []string{"admin"}, Scopes: []string{"users:read"}},
+		"GET /users/*": {Roles: []string{"admin", "support"}, Scopes: []string{}},
+		"/admin/**":    {Roles: []string{"admin"}, Scopes: []string{}},
+	}, table)
+
+	_, err = Routes(struct {
+		Missing http.HandlerFunc `route:"GET /missing"`
+	}{})
+	assert.Error(t, err)
+
+	_, err = Routes(struct {
+		NoRoute http.HandlerFunc `roles:"admin"`
+	}{})
+	assert.Error(t, err)
+
+	_, err = Routes("")
+	assert.Error(t, err)
+}
+
+func TestRouteTableCheck(t *testing.T) {
+	table, _ := Routes(testAPI{})
+
+	assert.NoError(t, table.Check("GET /health", "GET /users/{id}", "DELETE /admin/users/{id}"))
+
+	err := table.Check("GET /health", "POST /users", "GET /metrics")
+	assert.EqualError(t, err, "authz: Routes missing permission: POST /users, GET /metrics")
+}
+
+func TestRoutesMiddleware(t *testing.T) {
+	table, _ := Routes(testAPI{})
+	h := RoutesMiddleware(table, SetErrorHandler(auth.PlainTextErrorHandler))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	admin := auth.NewUserInfo("admin", "1", nil, map[string][]string{"scopes": {"users:read users:write"}})
+	auth.SetUserRoles(admin, []string{"admin"})
+	support := auth.NewUserInfo("support", "2", []string{"support"}, nil)
+
+	table2 := []struct {
+		name   string
+		method string
+		path   string
+		user   auth.Info
+		code   int
+	}{
+		{name: "it allow public route", method: "GET", path: "/health", code: http.StatusOK},
+		{name: "it reject unauthenticated", method: "GET", path: "/me", code: http.StatusUnauthorized},
+		{name: "it allow authenticated", method: "GET", path: "/me", user: support, code: http.StatusOK},
+		{name: "it allow role and scope", method: "GET", path: "/users", user: admin, code: http.StatusOK},
+		{name: "it reject missing role", method: "GET", path: "/users", user: support, code: http.StatusForbidden},
+		{name: "it allow group as role", method: "GET", path: "/users/1", user: support, code: http.StatusOK},
+		{name: "it allow any method", method: "DELETE", path: "/admin/x", user: admin, code: http.StatusOK},
+		{name: "it reject unknown route", method: "POST", path: "/users", user: admin, code: http.StatusForbidden},
+	}
+
+	for _, tt := range table2 {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tt.method, tt.path, nil)
+			if tt.user != nil {
+				r = auth.RequestWithUser(tt.user, r)
+			}
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+
+	// missing scope.
+	noScope := auth.NewUserInfo("admin", "1", nil, nil)
+	auth.SetUserRoles(noScope, []string{"admin"})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/users", nil)
+	h.ServeHTTP(w, auth.RequestWithUser(noScope, r))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// ErrCircuitOpen is returned by CircuitBreaker decorated strategy,
+// when the circuit is open and the request failed fast without reaching the strategy.
+var ErrCircuitOpen = gerrors.WithReason(
+	errors.New("strategy: Circuit breaker open"),
+	gerrors.ReasonUpstreamUnavailable,
+)
+
+// FailureFunc define function signature to report whether a strategy authentication error,
+// represents an upstream failure (e.g network error or timeout),
+// as opposed to a request carrying invalid credentials.
+type FailureFunc func(err error) bool
+
+// UpstreamFailure implements FailureFunc and report network errors and timeouts as failures.
+func UpstreamFailure(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// SetFailureFunc sets the circuit breaker function that classify authentication errors,
+// Default UpstreamFailure.
+func SetFailureFunc(fn FailureFunc) Option {
+	return OptionFunc(func(v interface{}) {
+		if b, ok := v.(*breaker); ok {
+			b.failure = fn
+		}
+	})
+}
+
+// SetFallback sets the strategy that authenticate requests while the circuit is open,
+// instead of failing fast with ErrCircuitOpen.
+// Typically a cached strategy that never calls upstream,
+// e.g token.New(token.NoOpAuthenticate, cache), to keep serving the already validated tokens.
+func SetFallback(s Strategy) Option {
+	return OptionFunc(func(v interface{}) {
+		if b, ok := v.(*breaker); ok {
+			b.fallback = s
+		}
+	})
+}
+
+// CircuitBreaker return Decorator that stop calling the strategy,
+// after threshold consecutive upstream failures, and fail fast with ErrCircuitOpen for the cool-down period,
+// to protect request latency during upstream outages.
+// Once the cool-down elapsed a single trial request reaches the strategy,
+// and its result closes or re-opens the circuit.
+// Errors not classified as failures (e.g invalid credentials) resets the failures count, See SetFailureFunc.
+// The returned decorator holds a single circuit, so decorate each strategy using its own CircuitBreaker.
+func CircuitBreaker(threshold int, cooldown time.Duration, opts ...Option) Decorator {
+	b := &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		failure:   UpstreamFailure,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(b)
+	}
+
+	return DecoratorFunc(func(ctx context.Context, r *http.Request, next Strategy) (Info, error) {
+		if !b.allow() {
+			if token, ok := tokenFromCtx(ctx); ok && b.fallback != nil {
+				return AuthenticateToken(ctx, b.fallback, token)
+			}
+			if b.fallback != nil {
+				return b.fallback.Authenticate(ctx, r)
+			}
+			return nil, ErrCircuitOpen
+		}
+
+		info, err := next.Authenticate(ctx, r)
+		b.done(err)
+		return info, err
+	})
+}
+
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	trial     bool
+	failure   FailureFunc
+	fallback  Strategy
+}
+
+// allow reports whether the request may reach the strategy.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+
+	if b.trial || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+
+	b.trial = true
+	return true
+}
+
+// done record the strategy authentication result.
+func (b *breaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+
+	if err != nil && b.failure(err) {
+		b.failures++
+		if b.failures >= b.threshold {
+			b.openedAt = time.Now()
+		}
+		return
+	}
+
+	b.failures = 0
+}
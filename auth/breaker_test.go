@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type flakyStrategy struct {
+	calls int
+	err   error
+}
+
+func (f *flakyStrategy) Authenticate(ctx context.Context, r *http.Request) (Info, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return NewUser("test", "1"), nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	upstreamErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	s := &flakyStrategy{err: upstreamErr}
+	cb := Decorate(s, CircuitBreaker(2, time.Millisecond*20))
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	// consecutive failures open the circuit.
+	for i := 0; i < 2; i++ {
+		_, err := cb.Authenticate(r.Context(), r)
+		assert.Equal(t, upstreamErr, err)
+	}
+
+	_, err := cb.Authenticate(r.Context(), r)
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, 2, s.calls)
+
+	// failed trial re-open the circuit.
+	time.Sleep(time.Millisecond * 25)
+	_, err = cb.Authenticate(r.Context(), r)
+	assert.Equal(t, upstreamErr, err)
+	_, err = cb.Authenticate(r.Context(), r)
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, 3, s.calls)
+
+	// successful trial close the circuit.
+	s.err = nil
+	time.Sleep(time.Millisecond * 25)
+	for i := 0; i < 2; i++ {
+		info, err := cb.Authenticate(r.Context(), r)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", info.ID())
+	}
+	assert.Equal(t, 5, s.calls)
+}
+
+func TestCircuitBreakerIgnoreInvalidCredentials(t *testing.T) {
+	s := &flakyStrategy{err: errors.New("invalid token")}
+	cb := Decorate(s, CircuitBreaker(1, time.Hour))
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := cb.Authenticate(r.Context(), r)
+		assert.Equal(t, s.err, err)
+	}
+
+	assert.Equal(t, 3, s.calls)
+}
+
+func TestCircuitBreakerOptions(t *testing.T) {
+	errUpstream := errors.New("upstream 503")
+	s := &flakyStrategy{err: errUpstream}
+	fallback := strategy{id: "cached"}
+	cb := Decorate(s, CircuitBreaker(
+		1,
+		time.Hour,
+		SetFailureFunc(func(err error) bool { return err == errUpstream }),
+		SetFallback(fallback),
+	))
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	_, err := cb.Authenticate(r.Context(), r)
+	assert.Equal(t, errUpstream, err)
+
+	info, err := cb.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "cached", info.ID())
+	assert.Equal(t, 1, s.calls)
+}
+
+func TestUpstreamFailure(t *testing.T) {
+	assert.True(t, UpstreamFailure(context.DeadlineExceeded))
+	assert.True(t, UpstreamFailure(&net.OpError{Op: "dial", Err: errors.New("refused")}))
+	assert.False(t, UpstreamFailure(errors.New("invalid token")))
+}
+
+func TestCircuitBreakerAuthenticateToken(t *testing.T) {
+	upstreamErr := &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+	fallback := tokenStrategy{strategy: strategy{id: "fallback"}, token: "token"}
+	cb := CircuitBreaker(1, time.Minute, SetFallback(fallback))
+	s := Decorate(&flakyTokenStrategy{flakyStrategy{err: upstreamErr}}, cb)
+	ctx := context.Background()
+
+	_, err := AuthenticateToken(ctx, s, "token")
+	assert.Equal(t, upstreamErr, err)
+
+	// the circuit open, and the token authenticated by the fallback.
+	info, err := AuthenticateToken(ctx, s, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "fallback", info.ID())
+}
+
+type flakyTokenStrategy struct {
+	flakyStrategy
+}
+
+func (f *flakyTokenStrategy) AuthenticateToken(ctx context.Context, _ string) (Info, error) {
+	return f.Authenticate(ctx, nil)
+}
@@ -0,0 +1,272 @@
+// Package claims provides a configurable mapper that translates identity provider token claims,
+// into auth.Info, since every identity provider names the user name, id, groups, and roles differently.
+package claims
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// ErrMissingID is returned by Mapper,
+// when none of the ID expressions resolve to a value.
+var ErrMissingID = errors.New("claims: Claims does not carry a user id")
+
+// Mapper translates token claims into auth.Info using expressions.
+//
+// An expression is either a dot-separated path (e.g realm_access.roles),
+// where "*" matches every member of an object or array (e.g resource_access.*.roles),
+// and a numeric segment indexes an array, or a text/template (e.g {{.given_name}} {{.family_name}}),
+// when it contains "{{", executed with the claims as data.
+// Path segments matched greedily against keys containing dots (e.g https://example.com/roles),
+// and a template referencing a missing claim does not resolve.
+//
+// UserName and ID resolved from the first expression that yields a non-empty value,
+// while Groups and Roles collect the values of all expressions, deduplicated in order.
+// String values used as is, numbers and booleans formatted, and arrays flattened.
+//
+// Mapper safe for concurrent usage, and must not be modified after first use.
+type Mapper struct {
+	// UserName expressions, Default preferred_username, email, and sub.
+	UserName []string
+	// ID expressions, Default sub.
+	ID []string
+	// Groups expressions.
+	Groups []string
+	// Roles expressions.
+	Roles []string
+	// Extensions maps the auth.Info extension key to its expression.
+	Extensions map[string]string
+
+	once sync.Once
+	err  error
+	expr map[string]expression
+}
+
+// Map translates the given claims into auth.Info.
+func (m *Mapper) Map(claims map[string]interface{}) (auth.Info, error) {
+	if err := m.compile(); err != nil {
+		return nil, err
+	}
+
+	id := m.first(m.ID, []string{"sub"}, claims)
+	if id == "" {
+		return nil, ErrMissingID
+	}
+
+	name := m.first(m.UserName, []string{"preferred_username", "email", "sub"}, claims)
+	groups := m.all(m.Groups, claims)
+
+	var ext map[string][]string
+
+	for k, e := range m.Extensions {
+		if v := m.expr[e].eval(claims); len(v) > 0 {
+			if ext == nil {
+				ext = make(map[string][]string, len(m.Extensions))
+			}
+			ext[k] = v
+		}
+	}
+
+	info := auth.NewUserInfo(name, id, groups, ext)
+
+	if roles := m.all(m.Roles, claims); len(roles) > 0 {
+		auth.SetUserRoles(info, roles)
+	}
+
+	return info, nil
+}
+
+// MapJSON translates the given JSON encoded claims (e.g a JWT payload) into auth.Info.
+func (m *Mapper) MapJSON(b []byte) (auth.Info, error) {
+	claims := make(map[string]interface{})
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	if err := d.Decode(&claims); err != nil {
+		return nil, err
+	}
+
+	return m.Map(claims)
+}
+
+func (m *Mapper) compile() error {
+	m.once.Do(func() {
+		m.expr = make(map[string]expression)
+
+		add := func(exprs ...string) {
+			for _, e := range exprs {
+				if _, ok := m.expr[e]; ok || m.err != nil {
+					continue
+				}
+
+				m.expr[e], m.err = parse(e)
+			}
+		}
+
+		add("sub", "preferred_username", "email")
+		add(m.UserName...)
+		add(m.ID...)
+		add(m.Groups...)
+		add(m.Roles...)
+
+		for _, e := range m.Extensions {
+			add(e)
+		}
+	})
+
+	return m.err
+}
+
+func (m *Mapper) first(exprs, defaults []string, claims map[string]interface{}) string {
+	if len(exprs) == 0 {
+		exprs = defaults
+	}
+
+	for _, e := range exprs {
+		for _, v := range m.expr[e].eval(claims) {
+			if v != "" {
+				return v
+			}
+		}
+	}
+
+	return ""
+}
+
+func (m *Mapper) all(exprs []string, claims map[string]interface{}) []string {
+	var values []string
+
+	seen := make(map[string]struct{})
+
+	for _, e := range exprs {
+		for _, v := range m.expr[e].eval(claims) {
+			if _, ok := seen[v]; ok || v == "" {
+				continue
+			}
+
+			seen[v] = struct{}{}
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+type expression interface {
+	eval(claims map[string]interface{}) []string
+}
+
+func parse(expr string) (expression, error) {
+	if !strings.Contains(expr, "{{") {
+		return path(strings.Split(expr, ".")), nil
+	}
+
+	t, err := template.New("claims").Option("missingkey=error").Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl{t}, nil
+}
+
+type tmpl struct {
+	t *template.Template
+}
+
+func (t tmpl) eval(claims map[string]interface{}) []string {
+	sb := new(strings.Builder)
+
+	if err := t.t.Execute(sb, claims); err != nil {
+		return nil
+	}
+
+	if s := strings.TrimSpace(sb.String()); s != "" {
+		return []string{s}
+	}
+
+	return nil
+}
+
+type path []string
+
+func (p path) eval(claims map[string]interface{}) []string {
+	return stringify(nil, resolve(claims, p))
+}
+
+func resolve(v interface{}, segments []string) []interface{} {
+	if len(segments) == 0 {
+		return []interface{}{v}
+	}
+
+	seg := segments[0]
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if seg == "*" {
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+
+			sort.Strings(keys)
+
+			var values []interface{}
+			for _, k := range keys {
+				values = append(values, resolve(v[k], segments[1:])...)
+			}
+
+			return values
+		}
+
+		// match the longest key first, since claims names may contain dots.
+		for i := len(segments); i > 0; i-- {
+			if next, ok := v[strings.Join(segments[:i], ".")]; ok {
+				return resolve(next, segments[i:])
+			}
+		}
+	case []interface{}:
+		if seg == "*" {
+			var values []interface{}
+			for _, e := range v {
+				values = append(values, resolve(e, segments[1:])...)
+			}
+			return values
+		}
+
+		if i, err := strconv.Atoi(seg); err == nil && i >= 0 && i < len(v) {
+			return resolve(v[i], segments[1:])
+		}
+	}
+
+	return nil
+}
+
+func stringify(dst []string, values []interface{}) []string {
+	for _, v := range values {
+		switch v := v.(type) {
+		case string:
+			dst = append(dst, v)
+		case json.Number:
+			dst = append(dst, v.String())
+		case float64:
+			dst = append(dst, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			dst = append(dst, strconv.FormatBool(v))
+		case []string:
+			dst = append(dst, v...)
+		case []interface{}:
+			dst = stringify(dst, v)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,129 @@
+package claims
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestMapper(t *testing.T) {
+	claims := map[string]interface{}{
+		"sub":         "1",
+		"email":       "test@example.com",
+		"given_name":  "John",
+		"family_name": "Doe",
+		"oid":         json.Number("42"),
+		"realm_access": map[string]interface{}{
+			"roles": []interface{}{"user", "admin"},
+		},
+		"resource_access": map[string]interface{}{
+			"b": map[string]interface{}{"roles": []interface{}{"editor"}},
+			"a": map[string]interface{}{"roles": []interface{}{"viewer", "user"}},
+		},
+		"https://example.com/groups": []interface{}{"devs", "ops"},
+		"cognito:groups":             []interface{}{"pool"},
+		"verified":                   true,
+	}
+
+	table := []struct {
+		name   string
+		mapper *Mapper
+		user   string
+		id     string
+		groups []string
+		roles  []string
+		ext    map[string][]string
+		err    error
+	}{
+		{
+			name:   "it map standard claims by default",
+			mapper: &Mapper{},
+			user:   "test@example.com",
+			id:     "1",
+		},
+		{
+			name: "it map the first resolved expression",
+			mapper: &Mapper{
+				UserName: []string{"upn", "{{.nickname}}", "{{.given_name}} {{.family_name}}"},
+				ID:       []string{"oid", "sub"},
+			},
+			user: "John Doe",
+			id:   "42",
+		},
+		{
+			name: "it collect groups and roles of all expressions",
+			mapper: &Mapper{
+				Groups: []string{"https://example.com/groups", "cognito:groups", "missing"},
+				Roles:  []string{"realm_access.roles", "resource_access.*.roles"},
+			},
+			user:   "test@example.com",
+			id:     "1",
+			groups: []string{"devs", "ops", "pool"},
+			roles:  []string{"user", "admin", "viewer", "editor"},
+		},
+		{
+			name: "it map extensions",
+			mapper: &Mapper{
+				Extensions: map[string]string{
+					"verified": "verified",
+					"group":    "https://example.com/groups.1",
+					"missing":  "missing",
+				},
+			},
+			user: "test@example.com",
+			id:   "1",
+			ext: map[string][]string{
+				"verified": {"true"},
+				"group":    {"ops"},
+			},
+		},
+		{
+			name:   "it return error when id missing",
+			mapper: &Mapper{ID: []string{"missing"}},
+			err:    ErrMissingID,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := tt.mapper.Map(claims)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.user, info.UserName())
+			assert.Equal(t, tt.id, info.ID())
+			assert.Equal(t, tt.groups, info.Groups())
+			assert.Equal(t, tt.roles, auth.UserRoles(info))
+
+			if tt.ext != nil {
+				assert.Equal(t, tt.ext, map[string][]string(info.Extensions()))
+			}
+		})
+	}
+}
+
+func TestMapperMapJSON(t *testing.T) {
+	m := &Mapper{ID: []string{"oid"}}
+
+	info, err := m.MapJSON([]byte(`{"oid":12345678901234567890,"sub":"1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678901234567890", info.ID())
+	assert.Equal(t, "1", info.UserName())
+
+	_, err = m.MapJSON([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestMapperInvalidTemplate(t *testing.T) {
+	m := &Mapper{UserName: []string{"{{.name"}}
+
+	_, err := m.Map(map[string]interface{}{"sub": "1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,278 @@
+// Package client provides helpers for Go clients of go-guardian protected services,
+// to react to authentication challenges and attach credentials to outgoing requests.
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidChallenge is returned by ParseChallenges,
+// when the WWW-Authenticate header value is malformed.
+var ErrInvalidChallenge = errors.New("client: Invalid WWW-Authenticate challenge")
+
+// Challenge represents an authentication challenge,
+// sent within WWW-Authenticate header as described in RFC 7235 section-4.1.
+type Challenge struct {
+	// Scheme is the authentication scheme e.g Bearer.
+	Scheme string
+	// Token68 is the challenge token68 value, when the challenge does not carry params.
+	Token68 string
+	// Params holds the challenge params keyed by lowercase param name.
+	Params map[string]string
+}
+
+// Param return the named challenge param, names matched case-insensitively.
+func (c Challenge) Param(name string) string {
+	return c.Params[strings.ToLower(name)]
+}
+
+// Realm return the challenge realm param.
+func (c Challenge) Realm() string {
+	return c.Param("realm")
+}
+
+// String return the challenge string representation,
+// params sorted by name and their values quoted.
+func (c Challenge) String() string {
+	if c.Token68 != "" {
+		return c.Scheme + " " + c.Token68
+	}
+
+	names := make([]string, 0, len(c.Params))
+	for name := range c.Params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	params := make([]string, 0, len(names))
+	for _, name := range names {
+		v := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(c.Params[name])
+		params = append(params, name+`="`+v+`"`)
+	}
+
+	if len(params) == 0 {
+		return c.Scheme
+	}
+
+	return c.Scheme + " " + strings.Join(params, ", ")
+}
+
+// Challenges represents the challenges of a WWW-Authenticate header.
+type Challenges []Challenge
+
+// Get return the first challenge of the given scheme, scheme matched case-insensitively.
+func (cs Challenges) Get(scheme string) (Challenge, bool) {
+	for _, c := range cs {
+		if strings.EqualFold(c.Scheme, scheme) {
+			return c, true
+		}
+	}
+	return Challenge{}, false
+}
+
+// Has reports whether the challenges contain a challenge of the given scheme.
+func (cs Challenges) Has(scheme string) bool {
+	_, ok := cs.Get(scheme)
+	return ok
+}
+
+// ResponseChallenges parse the challenges of all the response WWW-Authenticate headers.
+func ResponseChallenges(resp *http.Response) (Challenges, error) {
+	return HeaderChallenges(resp.Header)
+}
+
+// HeaderChallenges parse the challenges of all the WWW-Authenticate header values.
+func HeaderChallenges(h http.Header) (Challenges, error) {
+	var cs Challenges
+
+	for _, v := range h["Www-Authenticate"] {
+		c, err := ParseChallenges(v)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c...)
+	}
+
+	return cs, nil
+}
+
+// ParseChallenges parse a WWW-Authenticate header value,
+// that may contain multiple comma separated challenges,
+// e.g `Bearer realm="example", error="invalid_token", Basic realm="example"`.
+func ParseChallenges(header string) (Challenges, error) {
+	p := &parser{s: header}
+	cs := Challenges{}
+
+	for {
+		p.skip(", \t")
+		if p.eof() {
+			return cs, nil
+		}
+
+		c, err := p.challenge()
+		if err != nil {
+			return nil, err
+		}
+
+		cs = append(cs, c)
+	}
+}
+
+type parser struct {
+	s string
+	i int
+}
+
+func (p *parser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.s[p.i]
+}
+
+func (p *parser) skip(chars string) {
+	for !p.eof() && strings.IndexByte(chars, p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+func (p *parser) read(valid func(byte) bool) string {
+	start := p.i
+	for !p.eof() && valid(p.s[p.i]) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+func (p *parser) challenge() (Challenge, error) {
+	c := Challenge{Scheme: p.read(isTokenChar)}
+	if c.Scheme == "" || (!p.eof() && p.peek() != ' ' && p.peek() != '\t' && p.peek() != ',') {
+		return c, ErrInvalidChallenge
+	}
+
+	p.skip(" \t")
+
+	for first := true; !p.eof() && p.peek() != ','; first = false {
+		name := p.read(isToken68Char)
+		p.skip(" \t")
+
+		if p.peek() != '=' {
+			if first && name != "" && (p.eof() || p.peek() == ',') {
+				c.Token68 = name
+				return c, nil
+			}
+			return c, ErrInvalidChallenge
+		}
+
+		// token68 may end with "=" padding, while a param "=" always followed by a value.
+		eq := p.i
+		p.skip("=")
+		padding := p.s[eq:p.i]
+		p.skip(" \t")
+
+		if first && (p.eof() || p.peek() == ',') {
+			c.Token68 = name + padding
+			return c, nil
+		}
+
+		if len(padding) != 1 || name == "" || strings.IndexFunc(name, isNotTokenRune) >= 0 {
+			return c, ErrInvalidChallenge
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return c, err
+		}
+
+		if c.Params == nil {
+			c.Params = make(map[string]string)
+		}
+
+		c.Params[strings.ToLower(name)] = value
+
+		p.skip(" \t")
+
+		if p.eof() {
+			break
+		}
+
+		if p.peek() != ',' {
+			return c, ErrInvalidChallenge
+		}
+
+		// the next comma separated element, is either the next param or the next challenge.
+		start := p.i
+		p.skip(", \t")
+		p.read(isTokenChar)
+		p.skip(" \t")
+		isParam := p.peek() == '='
+		p.i = start
+
+		if !isParam {
+			break
+		}
+
+		p.skip(", \t")
+	}
+
+	return c, nil
+}
+
+func (p *parser) value() (string, error) {
+	if p.peek() != '"' {
+		v := p.read(isTokenChar)
+		if v == "" {
+			return "", ErrInvalidChallenge
+		}
+		return v, nil
+	}
+
+	p.i++
+
+	sb := strings.Builder{}
+
+	for !p.eof() {
+		ch := p.s[p.i]
+		p.i++
+
+		switch ch {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", ErrInvalidChallenge
+			}
+			ch = p.s[p.i]
+			p.i++
+		}
+
+		sb.WriteByte(ch)
+	}
+
+	return "", ErrInvalidChallenge
+}
+
+// isTokenChar reports whether ch is a valid token char as described in RFC 7230 section-3.2.6.
+func isTokenChar(ch byte) bool {
+	if ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9' {
+		return true
+	}
+	return strings.IndexByte("!#$%&'*+-.^_`|~", ch) >= 0
+}
+
+func isNotTokenRune(r rune) bool {
+	return r > 127 || !isTokenChar(byte(r))
+}
+
+// isToken68Char reports whether ch is a valid token or token68 char.
+func isToken68Char(ch byte) bool {
+	return isTokenChar(ch) || ch == '/'
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChallenges(t *testing.T) {
+	table := []struct {
+		name     string
+		header   string
+		expected Challenges
+		err      bool
+	}{
+		{
+			name:     "it parse scheme only challenge",
+			header:   "Negotiate",
+			expected: Challenges{{Scheme: "Negotiate"}},
+		},
+		{
+			name:   "it parse challenge params",
+			header: `Bearer realm="example", error="invalid_token", Scope=read`,
+			expected: Challenges{
+				{
+					Scheme: "Bearer",
+					Params: map[string]string{"realm": "example", "error": "invalid_token", "scope": "read"},
+				},
+			},
+		},
+		{
+			name:   "it parse multiple challenges",
+			header: `Basic realm="a, b", Digest realm="d", qop="auth,auth-int", nonce="n" ,Negotiate`,
+			expected: Challenges{
+				{Scheme: "Basic", Params: map[string]string{"realm": "a, b"}},
+				{Scheme: "Digest", Params: map[string]string{"realm": "d", "qop": "auth,auth-int", "nonce": "n"}},
+				{Scheme: "Negotiate"},
+			},
+		},
+		{
+			name:   "it parse token68 challenges",
+			header: `Negotiate YII/+a==, NTLM abc, Basic realm = "x"`,
+			expected: Challenges{
+				{Scheme: "Negotiate", Token68: "YII/+a=="},
+				{Scheme: "NTLM", Token68: "abc"},
+				{Scheme: "Basic", Params: map[string]string{"realm": "x"}},
+			},
+		},
+		{
+			name:   "it unescape quoted string",
+			header: `Bearer error_description="the \"token\" expired \\ revoked"`,
+			expected: Challenges{
+				{Scheme: "Bearer", Params: map[string]string{"error_description": `the "token" expired \ revoked`}},
+			},
+		},
+		{
+			name:     "it parse empty header",
+			header:   " ",
+			expected: Challenges{},
+		},
+		{
+			name:   "it return error when quoted string unterminated",
+			header: `Bearer realm="example`,
+			err:    true,
+		},
+		{
+			name:   "it return error when param value missing",
+			header: `Bearer realm=, error="x"`,
+			err:    true,
+		},
+		{
+			name:   "it return error when params not comma separated",
+			header: `Bearer realm="a" error="b"`,
+			err:    true,
+		},
+		{
+			name:   "it return error when scheme invalid",
+			header: `"Bearer"`,
+			err:    true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChallenges(tt.header)
+			if tt.err {
+				assert.Equal(t, ErrInvalidChallenge, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestHeaderChallenges(t *testing.T) {
+	h := http.Header{}
+	h.Add("WWW-Authenticate", `Basic realm="example"`)
+	h.Add("WWW-Authenticate", `Bearer realm="example", scope="read write"`)
+
+	cs, err := HeaderChallenges(h)
+	assert.NoError(t, err)
+	assert.Len(t, cs, 2)
+	assert.True(t, cs.Has("bearer"))
+	assert.False(t, cs.Has("digest"))
+
+	c, ok := cs.Get("BEARER")
+	assert.True(t, ok)
+	assert.Equal(t, "example", c.Realm())
+	assert.Equal(t, "read write", c.Param("Scope"))
+	assert.Equal(t, `Bearer realm="example", scope="read write"`, c.String())
+
+	h.Add("WWW-Authenticate", `Bearer realm="`)
+	_, err = ResponseChallenges(&http.Response{Header: h})
+	assert.Error(t, err)
+}
+
+func TestChallengeString(t *testing.T) {
+	assert.Equal(t, "Negotiate", Challenge{Scheme: "Negotiate"}.String())
+	assert.Equal(t, "Negotiate abc=", Challenge{Scheme: "Negotiate", Token68: "abc="}.String())
+	assert.Equal(
+		t,
+		`Bearer error_description="a \"b\""`,
+		Challenge{Scheme: "Bearer", Params: map[string]string{"error_description": `a "b"`}}.String(),
+	)
+}
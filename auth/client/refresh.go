@@ -0,0 +1,268 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// expiryDelta is how earlier a token considered expired than its actual expiration time,
+// to avoid sending tokens that expire in transit.
+const expiryDelta = 10 * time.Second
+
+// ErrTokenEndpoint is returned by the token sources,
+// when the token endpoint respond with an error.
+var ErrTokenEndpoint = errors.New("client: Token endpoint responded with an error")
+
+// Token represents an access token and its optional refresh token.
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+	// Expiry is the access token expiration time, zero means the token does not expire.
+	Expiry time.Time
+}
+
+// Valid reports whether the token is non-empty and not expired.
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Add(expiryDelta).Before(t.Expiry))
+}
+
+// TokenSource supplies access tokens,
+// each Token call return a new token e.g by using a refresh token, client credentials, or reloading a file.
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc is an adapter to allow the use of ordinary functions as TokenSource.
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+// Token return fn(ctx).
+func (fn TokenSourceFunc) Token(ctx context.Context) (*Token, error) {
+	return fn(ctx)
+}
+
+// FileTokenSource return TokenSource that reads the token from the given file on each call,
+// Typically used with tokens rotated on disk, e.g Kubernetes projected service account tokens.
+func FileTokenSource(path string) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return &Token{AccessToken: strings.TrimSpace(string(b))}, nil
+	})
+}
+
+// ClientCredentialsTokenSource return TokenSource that request tokens from the given token endpoint,
+// using the OAuth2 client credentials grant as described in RFC 6749 section-4.4.
+// The client authenticates using basic authentication,
+// and c used to send requests or http.DefaultClient if nil.
+func ClientCredentialsTokenSource(
+	c *http.Client,
+	tokenURL, clientID, secret string,
+	scopes ...string,
+) TokenSource {
+	return TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		if len(scopes) > 0 {
+			form.Set("scope", strings.Join(scopes, " "))
+		}
+		return requestToken(ctx, c, tokenURL, clientID, secret, form)
+	})
+}
+
+// RefreshTokenSource return TokenSource that request tokens from the given token endpoint,
+// using the OAuth2 refresh token grant as described in RFC 6749 section-6.
+// The refresh token replaced once the token endpoint rotates it.
+// The client authenticates using basic authentication,
+// and c used to send requests or http.DefaultClient if nil.
+func RefreshTokenSource(c *http.Client, tokenURL, clientID, secret, refreshToken string) TokenSource {
+	mu := sync.Mutex{}
+
+	return TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		form := url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {refreshToken},
+		}
+
+		t, err := requestToken(ctx, c, tokenURL, clientID, secret, form)
+		if err != nil {
+			return nil, err
+		}
+
+		if t.RefreshToken != "" {
+			refreshToken = t.RefreshToken
+		}
+
+		return t, nil
+	})
+}
+
+func requestToken(
+	ctx context.Context,
+	c *http.Client,
+	tokenURL, clientID, secret string,
+	form url.Values,
+) (*Token, error) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Accept", "application/json")
+	r.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(secret))
+
+	resp, err := c.Do(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrTokenEndpoint
+	}
+
+	tr := struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}{}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, err
+	}
+
+	if tr.AccessToken == "" {
+		return nil, ErrTokenEndpoint
+	}
+
+	t := &Token{AccessToken: tr.AccessToken, RefreshToken: tr.RefreshToken}
+	if tr.ExpiresIn > 0 {
+		t.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+
+	return t, nil
+}
+
+// RefreshTransport return http.RoundTripper that attach a bearer token obtained from the source,
+// to the outgoing requests, before sending them using next, or http.DefaultTransport if nil.
+// The token cached until expired, or the server responds with 401 and a Bearer invalid_token challenge,
+// then a new token obtained from the source, and the request retried once.
+// Requests whose body can't be replayed (i.e GetBody is nil) not retried.
+func RefreshTransport(next http.RoundTripper, src TokenSource) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &refreshTransport{next: next, src: src}
+}
+
+type refreshTransport struct {
+	next  http.RoundTripper
+	src   TokenSource
+	mu    sync.Mutex
+	token *Token
+}
+
+func (t *refreshTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	tk, err := t.current(r.Context(), nil)
+	if err != nil {
+		closeBody(r)
+		return nil, err
+	}
+
+	resp, err := t.send(r, r.Body, tk)
+	if err != nil || !invalidToken(resp) {
+		return resp, err
+	}
+
+	var body io.ReadCloser
+
+	if r.Body != nil && r.Body != http.NoBody {
+		if r.GetBody == nil {
+			return resp, nil
+		}
+
+		if body, err = r.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+
+	tk, err = t.current(r.Context(), tk)
+	if err != nil {
+		if body != nil {
+			_ = body.Close()
+		}
+		return resp, nil
+	}
+
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return t.send(r, body, tk)
+}
+
+// current return the cached token if valid and not the rejected token,
+// Otherwise, obtain a new token from the source.
+func (t *refreshTransport) current(ctx context.Context, rejected *Token) (*Token, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token.Valid() && t.token != rejected {
+		return t.token, nil
+	}
+
+	tk, err := t.src.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	t.token = tk
+
+	return tk, nil
+}
+
+func (t *refreshTransport) send(r *http.Request, body io.ReadCloser, tk *Token) (*http.Response, error) {
+	// round trippers must not modify the original request.
+	r = r.Clone(r.Context())
+	r.Body = body
+	r.Header.Set("Authorization", "Bearer "+tk.AccessToken)
+	return t.next.RoundTrip(r)
+}
+
+func invalidToken(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return false
+	}
+
+	cs, err := ResponseChallenges(resp)
+	if err != nil {
+		return false
+	}
+
+	c, ok := cs.Get("Bearer")
+
+	return ok && c.Param("error") == "invalid_token"
+}
+
+func closeBody(r *http.Request) {
+	if r.Body != nil {
+		_ = r.Body.Close()
+	}
+}
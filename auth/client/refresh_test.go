@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshTransport(t *testing.T) {
+	valid := "1"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+valid {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="test", error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer srv.Close()
+
+	calls := 0
+	src := TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		calls++
+		return &Token{AccessToken: strconv.Itoa(calls)}, nil
+	})
+
+	c := &http.Client{Transport: RefreshTransport(srv.Client().Transport, src)}
+
+	post := func(body string) (*http.Response, error) {
+		return c.Post(srv.URL, "text/plain", strings.NewReader(body))
+	}
+
+	// Round #1 it obtain token from source
+	resp, err := post("round1")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+	_ = resp.Body.Close()
+
+	// Round #2 it reuse the cached token
+	resp, err = post("round2")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	_ = resp.Body.Close()
+
+	// Round #3 it refresh the token and replay the body when token rejected
+	valid = "2"
+	resp, err = post("round3")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 2, calls)
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, "round3", string(body))
+	_ = resp.Body.Close()
+
+	// Round #4 it retry only once
+	valid = "invalid"
+	resp, err = post("round4")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 3, calls)
+	_ = resp.Body.Close()
+}
+
+func TestRefreshTransportSourceError(t *testing.T) {
+	errSrc := errors.New("source error")
+	src := TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		return nil, errSrc
+	})
+
+	c := &http.Client{Transport: RefreshTransport(nil, src)}
+	_, err := c.Get("http://127.0.0.1:0")
+	assert.True(t, errors.Is(err, errSrc))
+}
+
+func TestTokenValid(t *testing.T) {
+	assert.False(t, (*Token)(nil).Valid())
+	assert.False(t, (&Token{}).Valid())
+	assert.True(t, (&Token{AccessToken: "t"}).Valid())
+	assert.True(t, (&Token{AccessToken: "t", Expiry: time.Now().Add(time.Hour)}).Valid())
+	assert.False(t, (&Token{AccessToken: "t", Expiry: time.Now().Add(time.Second)}).Valid())
+}
+
+func TestTokenEndpointSources(t *testing.T) {
+	refresh := "r1"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		_ = r.ParseForm()
+
+		if id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.PostForm.Get("grant_type") {
+		case "client_credentials":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": r.PostForm.Get("scope"),
+				"expires_in":   3600,
+			})
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != refresh {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			refresh += "1"
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "access",
+				"refresh_token": refresh,
+			})
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+
+	cc := ClientCredentialsTokenSource(srv.Client(), srv.URL, "client", "secret", "read", "write")
+
+	tk, err := cc.Token(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "read write", tk.AccessToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), tk.Expiry, time.Minute)
+
+	_, err = ClientCredentialsTokenSource(srv.Client(), srv.URL, "client", "invalid").Token(ctx)
+	assert.Equal(t, ErrTokenEndpoint, err)
+
+	src := RefreshTokenSource(srv.Client(), srv.URL, "client", "secret", "r1")
+
+	for i := 0; i < 2; i++ {
+		tk, err = src.Token(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "access", tk.AccessToken)
+		assert.Equal(t, refresh, tk.RefreshToken)
+	}
+}
+
+func TestFileTokenSource(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "token")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	src := FileTokenSource(path)
+
+	_, err := src.Token(context.Background())
+	assert.Error(t, err)
+
+	_ = ioutil.WriteFile(path, []byte("token\n"), 0600)
+
+	tk, err := src.Token(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token", tk.AccessToken)
+}
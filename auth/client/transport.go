@@ -0,0 +1,156 @@
+package client
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HMACScheme is the Authorization scheme of HMAC signed requests.
+const HMACScheme = "HMAC-SHA256"
+
+// Credential attach credentials to an outgoing request,
+// the request is a clone of the original request, so it's safe to be modified.
+type Credential func(r *http.Request) error
+
+// Transport return http.RoundTripper that attach the given credentials to the outgoing requests,
+// before sending them using next, or http.DefaultTransport if nil.
+func Transport(next http.RoundTripper, creds ...Credential) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &transport{next: next, creds: creds}
+}
+
+// NewClient return http.Client that attach the given credentials to its outgoing requests.
+func NewClient(creds ...Credential) *http.Client {
+	return &http.Client{Transport: Transport(nil, creds...)}
+}
+
+// Basic return Credential that attach the basic authentication credentials,
+// as expected by the basic strategy.
+func Basic(username, password string) Credential {
+	return func(r *http.Request) error {
+		r.SetBasicAuth(username, password)
+		return nil
+	}
+}
+
+// Bearer return Credential that attach the bearer token within Authorization header,
+// as expected by the token and jwt strategies Default parser.
+func Bearer(token string) Credential {
+	return Authorization("Bearer", token)
+}
+
+// Authorization return Credential that attach the Authorization header of the given scheme and value.
+func Authorization(scheme, value string) Credential {
+	return func(r *http.Request) error {
+		r.Header.Set("Authorization", scheme+" "+value)
+		return nil
+	}
+}
+
+// APIKeyHeader return Credential that attach the API key within the given header,
+// as expected by the token strategy XHeaderParser.
+func APIKeyHeader(header, key string) Credential {
+	return func(r *http.Request) error {
+		r.Header.Set(header, key)
+		return nil
+	}
+}
+
+// APIKeyQuery return Credential that attach the API key within the given query param,
+// as expected by the token strategy QueryParser.
+func APIKeyQuery(name, key string) Credential {
+	return func(r *http.Request) error {
+		q := r.URL.Query()
+		q.Set(name, key)
+		r.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+// HMAC return Credential that sign the request using HMAC-SHA256 and the given secret,
+// and attach the signature within Authorization header, in the following format:
+//
+//	HMAC-SHA256 keyId="<key id>", timestamp="<unix seconds>", signature="<base64 signature>"
+//
+// Where the signature computed by HMACSignature, so the server verify it using the same function.
+// The request body read to be signed, and restored.
+func HMAC(keyID string, secret []byte) Credential {
+	return func(r *http.Request) error {
+		var body []byte
+
+		if r.Body != nil && r.Body != http.NoBody {
+			b, err := ioutil.ReadAll(r.Body)
+			_ = r.Body.Close()
+			if err != nil {
+				return err
+			}
+
+			body = b
+			r.Body = ioutil.NopCloser(bytes.NewReader(b))
+			r.GetBody = func() (io.ReadCloser, error) {
+				return ioutil.NopCloser(bytes.NewReader(b)), nil
+			}
+		}
+
+		ts := time.Now().Unix()
+		sig := HMACSignature(secret, r, ts, body)
+
+		r.Header.Set(
+			"Authorization",
+			HMACScheme+` keyId="`+keyID+`", timestamp="`+strconv.FormatInt(ts, 10)+`", signature="`+sig+`"`,
+		)
+
+		return nil
+	}
+}
+
+// HMACSignature return the base64 encoded HMAC-SHA256 signature of the request,
+// computed over the following newline separated string to sign:
+// request method, request URI, host, unix timestamp, and hex encoded SHA256 of the body.
+func HMACSignature(secret []byte, r *http.Request, timestamp int64, body []byte) string {
+	host := r.Host
+	if host == "" && r.URL != nil {
+		host = r.URL.Host
+	}
+
+	sum := sha256.Sum256(body)
+
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(r.Method + "\n" +
+		r.URL.RequestURI() + "\n" +
+		host + "\n" +
+		strconv.FormatInt(timestamp, 10) + "\n" +
+		hex.EncodeToString(sum[:])))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type transport struct {
+	next  http.RoundTripper
+	creds []Credential
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// round trippers must not modify the original request.
+	r = r.Clone(r.Context())
+
+	for _, c := range t.creds {
+		if err := c(r); err != nil {
+			closeBody(r)
+			return nil, err
+		}
+	}
+
+	return t.next.RoundTrip(r)
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+)
+
+func TestTransport(t *testing.T) {
+	table := []struct {
+		name  string
+		cred  Credential
+		check func(t *testing.T, r *http.Request)
+	}{
+		{
+			name: "it attach basic credentials",
+			cred: Basic("test", "test"),
+			check: func(t *testing.T, r *http.Request) {
+				fn := basic.AuthenticateFunc(func(_ context.Context, _ *http.Request, u, p string) (auth.Info, error) {
+					return auth.NewDefaultUser(u, "1", nil, nil), nil
+				})
+				info, err := fn.Authenticate(r.Context(), r)
+				assert.NoError(t, err)
+				assert.Equal(t, "test", info.UserName())
+			},
+		},
+		{
+			name: "it attach bearer token",
+			cred: Bearer("token"),
+			check: func(t *testing.T, r *http.Request) {
+				assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			},
+		},
+		{
+			name: "it attach api key header",
+			cred: APIKeyHeader("X-API-Key", "key"),
+			check: func(t *testing.T, r *http.Request) {
+				tk, err := token.XHeaderParser("X-API-Key").Token(r)
+				assert.NoError(t, err)
+				assert.Equal(t, "key", tk)
+			},
+		},
+		{
+			name: "it attach api key query param",
+			cred: APIKeyQuery("api_key", "key"),
+			check: func(t *testing.T, r *http.Request) {
+				tk, err := token.QueryParser("api_key").Token(r)
+				assert.NoError(t, err)
+				assert.Equal(t, "key", tk)
+				assert.Equal(t, "x", r.URL.Query().Get("q"))
+			},
+		},
+		{
+			name: "it sign request using hmac",
+			cred: HMAC("id", []byte("secret")),
+			check: func(t *testing.T, r *http.Request) {
+				cs, err := ParseChallenges(r.Header.Get("Authorization"))
+				assert.NoError(t, err)
+
+				c, _ := cs.Get(HMACScheme)
+				ts, _ := strconv.ParseInt(c.Param("timestamp"), 10, 64)
+				body, _ := ioutil.ReadAll(r.Body)
+
+				assert.Equal(t, "id", c.Param("keyId"))
+				assert.Equal(t, "body", string(body))
+				assert.Equal(t, HMACSignature([]byte("secret"), r, ts, body), c.Param("signature"))
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.check(t, r)
+			}))
+			defer srv.Close()
+
+			c := &http.Client{Transport: Transport(srv.Client().Transport, tt.cred)}
+
+			r, _ := http.NewRequest("POST", srv.URL+"/path?q=x", strings.NewReader("body"))
+			resp, err := c.Do(r)
+			assert.NoError(t, err)
+			_ = resp.Body.Close()
+
+			assert.Empty(t, r.Header.Get("Authorization"), "original request modified")
+		})
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	errCred := errors.New("credential error")
+	c := NewClient(func(r *http.Request) error { return errCred })
+
+	_, err := c.Get("http://127.0.0.1:0")
+	assert.True(t, errors.Is(err, errCred))
+}
@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// ErrInvalidComposition is returned by Composer Build,
+// when the composed strategies chain is invalid.
+var ErrInvalidComposition = errors.New("compose: Invalid strategies composition")
+
+// SecondFactor verifies the request second factor (e.g one-time password),
+// for the user authenticated by the primary strategies, and return the resulting user info.
+type SecondFactor interface {
+	Verify(ctx context.Context, r *http.Request, info Info) (Info, error)
+}
+
+// SecondFactorFunc is an adapter to allow the use of ordinary functions as SecondFactor.
+type SecondFactorFunc func(ctx context.Context, r *http.Request, info Info) (Info, error)
+
+// Verify calls fn(ctx, r, info).
+func (fn SecondFactorFunc) Verify(ctx context.Context, r *http.Request, info Info) (Info, error) {
+	return fn(ctx, r, info)
+}
+
+// Composer builds a single strategy out of a readable strategies chain, e.g
+//
+//	auth.Compose().Try(mtls).Then(jwt).RequireSecondFactor(otp).FallbackAnonymous(anonymous.New()).Build()
+//
+// The chain validated when built, so misuse surfaces at construction time instead of at request time.
+type Composer struct {
+	steps    []Strategy
+	factors  []SecondFactor
+	fallback Strategy
+	errs     []string
+}
+
+// Compose return new Composer.
+func Compose() *Composer {
+	return new(Composer)
+}
+
+// Try sets the first strategies of the chain, tried in order,
+// the first strategy that successfully authenticates the request wins.
+// Try must be the first call of the chain.
+func (c *Composer) Try(strategies ...Strategy) *Composer {
+	if len(c.steps) > 0 || len(c.factors) > 0 || c.fallback != nil {
+		c.invalid("Try must be the first call")
+	}
+	return c.add("Try", strategies)
+}
+
+// Then adds strategies tried in order, when the previous strategies fail to authenticate the request.
+func (c *Composer) Then(strategies ...Strategy) *Composer {
+	if len(c.steps) == 0 {
+		c.invalid("Then called before Try")
+	}
+	return c.add("Then", strategies)
+}
+
+// RequireSecondFactor requires the users authenticated by the chain strategies,
+// to pass the given second factor, multiple second factors verified in order.
+// Users authenticated by the fallback strategy not required to pass the second factors.
+func (c *Composer) RequireSecondFactor(f SecondFactor) *Composer {
+	switch {
+	case f == nil:
+		c.invalid("RequireSecondFactor called with nil second factor")
+	case len(c.steps) == 0:
+		c.invalid("RequireSecondFactor called before Try")
+	case c.fallback != nil:
+		c.invalid("RequireSecondFactor called after Fallback")
+	}
+
+	c.factors = append(c.factors, f)
+
+	return c
+}
+
+// Fallback sets the strategy authenticating the requests that all the chain strategies
+// fail to authenticate, Fallback must be the last call of the chain.
+func (c *Composer) Fallback(s Strategy) *Composer {
+	switch {
+	case s == nil:
+		c.invalid("Fallback called with nil strategy")
+	case c.fallback != nil:
+		c.invalid("Fallback called more than once")
+	case len(c.steps) == 0:
+		c.invalid("Fallback called before Try")
+	}
+
+	c.fallback = s
+
+	return c
+}
+
+// FallbackAnonymous authenticate the requests that all the chain strategies fail to authenticate,
+// as the guest user of the given strategy, typically anonymous.New(), See Fallback.
+//
+// WARNING: Fallback treats any strategy error as unauthenticated request,
+// including invalid credentials, so protected resources must check the guest user explicitly.
+func (c *Composer) FallbackAnonymous(guest Strategy) *Composer {
+	if guest == nil {
+		c.invalid("FallbackAnonymous called with nil guest strategy")
+		return c
+	}
+	return c.Fallback(guest)
+}
+
+// Build validates the chain and return the composed strategy or ErrInvalidComposition.
+func (c *Composer) Build() (Strategy, error) {
+	errs := c.errs
+	if len(c.steps) == 0 && len(errs) == 0 {
+		errs = append(errs, "no strategies composed, Try must be called")
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%w, %s", ErrInvalidComposition, strings.Join(errs, ", "))
+	}
+
+	return &composed{
+		steps:    append([]Strategy(nil), c.steps...),
+		factors:  append([]SecondFactor(nil), c.factors...),
+		fallback: c.fallback,
+	}, nil
+}
+
+// MustBuild is like Build but panics if the chain is invalid.
+func (c *Composer) MustBuild() Strategy {
+	s, err := c.Build()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (c *Composer) add(call string, strategies []Strategy) *Composer {
+	if len(strategies) == 0 {
+		c.invalid(call + " called without strategies")
+	}
+
+	if c.fallback != nil {
+		c.invalid(call + " called after Fallback")
+	}
+
+	for _, s := range strategies {
+		if s == nil {
+			c.invalid(call + " called with nil strategy")
+			continue
+		}
+		c.steps = append(c.steps, s)
+	}
+
+	return c
+}
+
+func (c *Composer) invalid(msg string) {
+	c.errs = append(c.errs, msg)
+}
+
+type composed struct {
+	steps    []Strategy
+	factors  []SecondFactor
+	fallback Strategy
+}
+
+func (c *composed) Authenticate(ctx context.Context, r *http.Request) (Info, error) {
+	errs := gerrors.MultiError{}
+
+	for _, s := range c.steps {
+		info, err := s.Authenticate(ctx, r)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		return c.verify(ctx, r, info)
+	}
+
+	if c.fallback != nil {
+		return c.fallback.Authenticate(ctx, r)
+	}
+
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+
+	return nil, errs
+}
+
+// Close release the background resources owned by the composed strategies, See Close.
+func (c *composed) Close() error {
+	errs := gerrors.MultiError{}
+
+	for _, s := range c.steps {
+		if err := Close(s); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := Close(c.fallback); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// verify the user second factors, the second factor errors never fallback,
+// since the user already proved its identity using the first factor.
+func (c *composed) verify(ctx context.Context, r *http.Request, info Info) (Info, error) {
+	for _, f := range c.factors {
+		var err error
+		if info, err = f.Verify(ctx, r, info); err != nil {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+// Challenge returns the consolidated challenges of the chain strategies.
+func (c *composed) Challenge(realm string) string {
+	challenges := []string{}
+	for _, s := range c.steps {
+		if v, ok := Challenge(s, realm); ok {
+			challenges = append(challenges, v)
+		}
+	}
+	return strings.Join(challenges, ", ")
+}
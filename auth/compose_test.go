@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type guest struct{}
+
+func (guest) Authenticate(context.Context, *http.Request) (Info, error) {
+	return NewUserInfo("anonymous", "", nil, nil), nil
+}
+
+func TestCompose(t *testing.T) {
+	errFactor := errors.New("invalid second factor")
+	pass := SecondFactorFunc(func(ctx context.Context, r *http.Request, info Info) (Info, error) {
+		return NewDefaultUser("2fa", info.ID(), nil, nil), nil
+	})
+	fail := SecondFactorFunc(func(ctx context.Context, r *http.Request, info Info) (Info, error) {
+		return nil, errFactor
+	})
+
+	table := []struct {
+		name        string
+		composer    *Composer
+		id          string
+		userName    string
+		expectedErr error
+	}{
+		{
+			name:     "it authenticate using the first succeeded strategy",
+			composer: Compose().Try(strategy{returnErr: true}).Then(strategy{id: "2"}, strategy{id: "3"}),
+			id:       "2",
+		},
+		{
+			name:     "it verify second factor",
+			composer: Compose().Try(strategy{id: "1"}).RequireSecondFactor(pass),
+			id:       "1",
+			userName: "2fa",
+		},
+		{
+			name:        "it never fallback when second factor fails",
+			composer:    Compose().Try(strategy{id: "1"}).RequireSecondFactor(fail).FallbackAnonymous(guest{}),
+			expectedErr: errFactor,
+		},
+		{
+			name:     "it fallback to anonymous user",
+			composer: Compose().Try(strategy{returnErr: true}).RequireSecondFactor(fail).FallbackAnonymous(guest{}),
+			userName: "anonymous",
+		},
+		{
+			name:        "it return error when all strategies fail",
+			composer:    Compose().Try(strategy{returnErr: true}),
+			expectedErr: errors.New("authenticator test strategy L25"),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.composer.Build()
+			assert.NoError(t, err)
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			info, err := s.Authenticate(r.Context(), r)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.id, info.ID())
+			assert.Equal(t, tt.userName, info.UserName())
+		})
+	}
+}
+
+func TestComposeInvalid(t *testing.T) {
+	table := []struct {
+		name     string
+		composer *Composer
+	}{
+		{name: "it return error when no strategies", composer: Compose()},
+		{name: "it return error when then before try", composer: Compose().Then(strategy{})},
+		{name: "it return error when try called twice", composer: Compose().Try(strategy{}).Try(strategy{})},
+		{name: "it return error when nil strategy", composer: Compose().Try(nil)},
+		{name: "it return error when try without strategies", composer: Compose().Try()},
+		{
+			name:     "it return error when second factor before try",
+			composer: Compose().RequireSecondFactor(SecondFactorFunc(nil)).Try(strategy{}),
+		},
+		{
+			name:     "it return error when fallback not last",
+			composer: Compose().Try(strategy{}).FallbackAnonymous(guest{}).Then(strategy{}),
+		},
+		{
+			name:     "it return error when fallback anonymous without guest strategy",
+			composer: Compose().Try(strategy{}).FallbackAnonymous(nil),
+		},
+		{
+			name:     "it return error when fallback called twice",
+			composer: Compose().Try(strategy{}).FallbackAnonymous(guest{}).FallbackAnonymous(guest{}),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.composer.Build()
+			assert.True(t, errors.Is(err, ErrInvalidComposition))
+			assert.Panics(t, func() { tt.composer.MustBuild() })
+		})
+	}
+}
+
+func TestComposeChallenge(t *testing.T) {
+	s := Compose().
+		Try(&mockStrategy{challenge: `Basic realm="test"`}).
+		Then(strategy{}, &mockStrategy{challenge: `Bearer realm="test"`}).
+		MustBuild()
+
+	c, ok := Challenge(s, "test")
+	assert.True(t, ok)
+	assert.Equal(t, `Basic realm="test", Bearer realm="test"`, c)
+}
@@ -0,0 +1,161 @@
+// Package cookie provides an encrypt-and-sign cookie codec,
+// shared by the features that keep state in cookies (e.g sessions and remember-me),
+// so no feature ships its own cookie crypto.
+//
+// Cookies values encrypted and authenticated using AES-GCM, bound to the cookie name,
+// and carry their creation time to enforce a max age.
+package cookie
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal/keyring"
+)
+
+var (
+	// ErrInvalidCookie is returned by Codec,
+	// when the cookie value malformed, tampered, or encoded by an unknown key.
+	ErrInvalidCookie = gerrors.WithReason(errors.New("cookie: Invalid cookie value"), gerrors.ReasonMalformed)
+
+	// ErrCookieExpired is returned by Codec, when the cookie exceeded the codec max age.
+	ErrCookieExpired = gerrors.WithReason(errors.New("cookie: Cookie expired"), gerrors.ReasonExpired)
+
+	// ErrInvalidKey is returned by NewCodec, when a key is not 16, 24, or 32 bytes.
+	ErrInvalidKey = errors.New("cookie: Key must be 16, 24, or 32 bytes")
+
+	// ErrMissingKeys is returned by NewCodec, when no keys given.
+	ErrMissingKeys = errors.New("cookie: At least one key required")
+)
+
+// timestampSize is the size of the encoded creation time prefixing the plaintext.
+const timestampSize = 8
+
+// Codec encode and decode cookies values.
+type Codec struct {
+	ring   *keyring.Ring
+	maxAge time.Duration
+}
+
+// NewCodec return Codec that encode values using the first key,
+// and decode values encoded by any of the keys,
+// so keys rotated by prepending a new key and dropping the oldest key once its cookies expired.
+// Keys must be random 16, 24, or 32 bytes, selecting AES-128, AES-192, or AES-256.
+func NewCodec(keys [][]byte, opts ...auth.Option) (*Codec, error) {
+	if len(keys) == 0 {
+		return nil, ErrMissingKeys
+	}
+
+	ring, err := keyring.New(keys)
+	if err == keyring.ErrInvalidKey {
+		return nil, ErrInvalidKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Codec{
+		ring:   ring,
+		maxAge: time.Hour * 24,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(c)
+	}
+
+	return c, nil
+}
+
+// Encode encrypt and sign the given value of the cookie of the given name.
+func (c *Codec) Encode(name string, value []byte) (string, error) {
+	plaintext := make([]byte, timestampSize, timestampSize+len(value))
+	binary.BigEndian.PutUint64(plaintext, uint64(time.Now().Unix()))
+	plaintext = append(plaintext, value...)
+
+	out, err := c.ring.Seal(plaintext, []byte(name))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+// Decode verify and decrypt the given encoded value of the cookie of the given name.
+func (c *Codec) Decode(name, value string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCookie
+	}
+
+	plaintext, err := c.ring.Open(b, []byte(name))
+	if err != nil || len(plaintext) < timestampSize {
+		return nil, ErrInvalidCookie
+	}
+
+	created := time.Unix(int64(binary.BigEndian.Uint64(plaintext)), 0)
+
+	if c.maxAge > 0 && time.Since(created) > c.maxAge {
+		return nil, ErrCookieExpired
+	}
+
+	return plaintext[timestampSize:], nil
+}
+
+// Cookie return a cookie of the given name carrying the encoded value,
+// with the codec max age, and secure defaults, i.e Path "/", Secure, HttpOnly, and SameSite Lax.
+// Callers may adjust the cookie attributes before writing it.
+func (c *Codec) Cookie(name string, value []byte) (*http.Cookie, error) {
+	v, err := c.Encode(name, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Cookie{
+		Name:     name,
+		Value:    v,
+		Path:     "/",
+		MaxAge:   int(c.maxAge / time.Second),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}, nil
+}
+
+// Read return the decoded value of the request cookie of the given name.
+// http.ErrNoCookie returned if the request does not carry the cookie.
+func (c *Codec) Read(r *http.Request, name string) ([]byte, error) {
+	ck, err := r.Cookie(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Decode(name, ck.Value)
+}
+
+// Parser return TokenParser of the cookie of the given name.
+func (c *Codec) Parser(name string) TokenParser {
+	return TokenParser{codec: c, name: name}
+}
+
+// TokenParser extract token from an encoded cookie, and satisfies token.Parser,
+// so token based strategies read their tokens from encoded cookies, e.g session ids.
+type TokenParser struct {
+	codec *Codec
+	name  string
+}
+
+// Token return the decoded token of the request cookie.
+func (p TokenParser) Token(r *http.Request) (string, error) {
+	v, err := p.codec.Read(r, p.name)
+	if err != nil {
+		return "", err
+	}
+
+	return string(v), nil
+}
@@ -0,0 +1,104 @@
+package cookie
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodec(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 16)
+
+	old, err := NewCodec([][]byte{oldKey})
+	assert.NoError(t, err)
+
+	rotated, err := NewCodec([][]byte{newKey, oldKey})
+	assert.NoError(t, err)
+
+	v, err := old.Encode("session", []byte("value"))
+	assert.NoError(t, err)
+
+	tampered := tamper(v)
+
+	table := []struct {
+		name   string
+		codec  *Codec
+		cookie string
+		value  string
+		err    error
+	}{
+		{name: "it decode value", codec: old, cookie: "session", value: v},
+		{name: "it decode value of rotated key", codec: rotated, cookie: "session", value: v},
+		{name: "it return error when name differ", codec: old, cookie: "other", value: v, err: ErrInvalidCookie},
+		{name: "it return error if forged", codec: old, cookie: "session", value: tampered, err: ErrInvalidCookie},
+		{name: "it return error when malformed", codec: old, cookie: "session", value: "#", err: ErrInvalidCookie},
+		{name: "it return error when short", codec: old, cookie: "session", value: "AA", err: ErrInvalidCookie},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.codec.Decode(tt.cookie, tt.value)
+			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Equal(t, []byte("value"), got)
+			}
+		})
+	}
+
+	// key dropped from rotation.
+	current, _ := NewCodec([][]byte{newKey})
+	_, err = current.Decode("session", v)
+	assert.Equal(t, ErrInvalidCookie, err)
+}
+
+func TestCodecMaxAge(t *testing.T) {
+	c, _ := NewCodec([][]byte{bytes.Repeat([]byte{1}, 32)}, SetMaxAge(time.Nanosecond))
+	v, _ := c.Encode("session", []byte("value"))
+	time.Sleep(time.Millisecond)
+
+	_, err := c.Decode("session", v)
+	assert.Equal(t, ErrCookieExpired, err)
+
+	c.maxAge = 0
+	_, err = c.Decode("session", v)
+	assert.NoError(t, err)
+}
+
+func TestNewCodec(t *testing.T) {
+	_, err := NewCodec(nil)
+	assert.Equal(t, ErrMissingKeys, err)
+
+	_, err = NewCodec([][]byte{[]byte("short")})
+	assert.Equal(t, ErrInvalidKey, err)
+}
+
+func TestCodecCookie(t *testing.T) {
+	c, _ := NewCodec([][]byte{bytes.Repeat([]byte{1}, 32)})
+
+	ck, err := c.Cookie("session", []byte("id"))
+	assert.NoError(t, err)
+	assert.True(t, ck.Secure)
+	assert.True(t, ck.HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, ck.SameSite)
+	assert.Equal(t, 86400, ck.MaxAge)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	_, err = c.Parser("session").Token(r)
+	assert.Equal(t, http.ErrNoCookie, err)
+
+	r.AddCookie(ck)
+	tk, err := c.Parser("session").Token(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "id", tk)
+}
+
+func tamper(v string) string {
+	b, _ := base64.RawURLEncoding.DecodeString(v)
+	b[len(b)-1] ^= 1
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,18 @@
+package cookie
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetMaxAge sets the max age of the cookies, enforced on decode regardless of the browser cookie expiry,
+// and set as the Max-Age attribute of the cookies returned by Codec.Cookie.
+// Default 24 hours, zero means the cookies never expire.
+func SetMaxAge(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*Codec); ok {
+			c.maxAge = d
+		}
+	})
+}
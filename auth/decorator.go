@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// ErrRateLimited is returned by RateLimit decorated strategy,
+// when the request exceed the rate limit.
+var ErrRateLimited = gerrors.WithReason(
+	errors.New("strategy: Rate limit exceeded"),
+	gerrors.ReasonRateLimited,
+)
+
+// Decorator wraps a Strategy to add cross-cutting behavior,
+// such as logging, metrics, rate limiting, and timeout,
+// without re-implementing it inside each strategy.
+type Decorator func(Strategy) Strategy
+
+// Limiter reports whether an event may happen now.
+// golang.org/x/time/rate Limiter satisfies the interface.
+type Limiter interface {
+	Allow() bool
+}
+
+// HookFunc define function signature invoked after strategy authenticate the request,
+// with the authentication result and the elapsed time.
+// Typically used for logging and metrics.
+type HookFunc func(ctx context.Context, r *http.Request, info Info, err error, elapsed time.Duration)
+
+// Decorate wraps the given strategy with the given decorators,
+// the first decorator is the outermost, so it runs first.
+// The returned strategy still exposes the wrapped strategy Append, Revoke, Renew, and Challenge
+// through the package functions, See Unwrap.
+func Decorate(s Strategy, decorators ...Decorator) Strategy {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		s = decorators[i](s)
+	}
+	return s
+}
+
+// Unwrap return the strategy wrapped by a decorator, Otherwise, nil.
+func Unwrap(s Strategy) Strategy {
+	u, ok := s.(interface {
+		Unwrap() Strategy
+	})
+
+	if !ok {
+		return nil
+	}
+
+	return u.Unwrap()
+}
+
+// DecoratorFunc return Decorator from the given function,
+// the function receive the wrapped strategy and the authentication arguments.
+func DecoratorFunc(fn func(ctx context.Context, r *http.Request, next Strategy) (Info, error)) Decorator {
+	return func(next Strategy) Strategy {
+		return &decorated{
+			next: next,
+			fn:   fn,
+		}
+	}
+}
+
+// Hook return Decorator that invoke the given function after each authentication attempt.
+// The error passed to the function redacted, so secrets never reach logs, See errors.Redacted,
+// and carries the failure reason, to break failures down by cause, See errors.ReasonOf.
+func Hook(fn HookFunc) Decorator {
+	return DecoratorFunc(func(ctx context.Context, r *http.Request, next Strategy) (Info, error) {
+		start := time.Now()
+		info, err := next.Authenticate(ctx, r)
+		fn(ctx, r, info, gerrors.Redacted(err), time.Since(start))
+		return info, err
+	})
+}
+
+// RateLimit return Decorator that reject the request with ErrRateLimited,
+// when limiter does not allow it.
+// if limiter implements RateLimitReporter, the request rejected with RateLimitError instead,
+// to expose the quota state to clients, See SetRateLimitHeaders.
+func RateLimit(l Limiter) Decorator {
+	return DecoratorFunc(func(ctx context.Context, r *http.Request, next Strategy) (Info, error) {
+		if !l.Allow() {
+			if rr, ok := l.(RateLimitReporter); ok {
+				return nil, &RateLimitError{RateLimitInfo: rr.RateLimit()}
+			}
+			return nil, ErrRateLimited
+		}
+		return next.Authenticate(ctx, r)
+	})
+}
+
+// Timeout return Decorator that derive a child context with the given timeout,
+// and return context.DeadlineExceeded once it reached even if the strategy does not honor the context.
+func Timeout(d time.Duration) Decorator {
+	return DecoratorFunc(func(ctx context.Context, r *http.Request, next Strategy) (Info, error) {
+		return authenticateWithTimeout(ctx, next, r, d)
+	})
+}
+
+func authenticateWithTimeout(ctx context.Context, s Strategy, r *http.Request, d time.Duration) (Info, error) {
+	if d <= 0 {
+		return s.Authenticate(ctx, r)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+
+	type result struct {
+		info Info
+		err  error
+	}
+
+	c := make(chan result, 1)
+
+	go func() {
+		info, err := s.Authenticate(ctx, r.WithContext(ctx))
+		c <- result{info, err}
+	}()
+
+	select {
+	case res := <-c:
+		return res.info, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type decorated struct {
+	next Strategy
+	fn   func(ctx context.Context, r *http.Request, next Strategy) (Info, error)
+}
+
+func (d *decorated) Authenticate(ctx context.Context, r *http.Request) (Info, error) {
+	return d.fn(ctx, r, d.next)
+}
+
+// AuthenticateToken applies the decorator around the wrapped strategy token authentication,
+// the decorator receive a bare request carrying only ctx, and the token within ctx, See tokenFromCtx.
+func (d *decorated) AuthenticateToken(ctx context.Context, token string) (Info, error) {
+	if !supportsToken(d.next) {
+		return nil, ErrInvalidStrategy
+	}
+
+	ctx = context.WithValue(ctx, tokenKey{}, token)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.fn(ctx, r, tokenAuthenticator{next: d.next, token: token})
+}
+
+type tokenKey struct{}
+
+// tokenFromCtx return the token authenticated by a decorated strategy AuthenticateToken.
+func tokenFromCtx(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenKey{}).(string)
+	return token, ok
+}
+
+// supportsToken reports whether the given strategy or any strategy it wraps implements TokenStrategy.
+func supportsToken(s Strategy) bool {
+	for ; s != nil; s = Unwrap(s) {
+		if _, ok := s.(TokenStrategy); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenAuthenticator adapts a token authentication into a Strategy passed to decorators as next.
+type tokenAuthenticator struct {
+	next  Strategy
+	token string
+}
+
+func (t tokenAuthenticator) Authenticate(ctx context.Context, _ *http.Request) (Info, error) {
+	return AuthenticateToken(ctx, t.next, t.token)
+}
+
+func (d *decorated) Unwrap() Strategy {
+	return d.next
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecorate(t *testing.T) {
+	order := []string{}
+	decorator := func(name string) Decorator {
+		return DecoratorFunc(func(ctx context.Context, r *http.Request, next Strategy) (Info, error) {
+			order = append(order, name)
+			return next.Authenticate(ctx, r)
+		})
+	}
+
+	s := Decorate(strategy{id: "1"}, decorator("first"), decorator("second"))
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	info, err := s.Authenticate(r.Context(), r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+	assert.Equal(t, []string{"first", "second"}, order)
+}
+
+func TestUnwrap(t *testing.T) {
+	inner := &mockStrategy{challenge: `Basic realm="test"`}
+	s := Decorate(inner, Hook(func(context.Context, *http.Request, Info, error, time.Duration) {}))
+
+	assert.Equal(t, inner, Unwrap(s))
+	assert.Nil(t, Unwrap(inner))
+
+	assert.NoError(t, Append(s, "token", nil, nil))
+	assert.True(t, inner.called)
+
+	w := httptest.NewRecorder()
+	SetWWWAuthenticate(w, "test", s)
+	assert.Equal(t, `Basic realm="test"`, w.Header().Get("WWW-Authenticate"))
+}
+
+func TestHook(t *testing.T) {
+	var (
+		gotInfo Info
+		gotErr  error
+	)
+
+	s := Decorate(strategy{returnErr: true}, Hook(
+		func(_ context.Context, _ *http.Request, info Info, err error, _ time.Duration) {
+			gotInfo, gotErr = info, err
+		},
+	))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	_, err := s.Authenticate(r.Context(), r)
+
+	assert.Nil(t, gotInfo)
+	assert.Equal(t, err, gotErr)
+}
+
+func TestHookRedact(t *testing.T) {
+	var gotErr error
+
+	secret := errors.New("upstream rejected Bearer abc.def.ghi")
+	s := Decorate(
+		strategy{},
+		Hook(func(_ context.Context, _ *http.Request, _ Info, err error, _ time.Duration) {
+			gotErr = err
+		}),
+		DecoratorFunc(func(_ context.Context, _ *http.Request, _ Strategy) (Info, error) {
+			return nil, secret
+		}),
+	)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	_, err := s.Authenticate(r.Context(), r)
+
+	assert.Equal(t, secret, err)
+	assert.Equal(t, "upstream rejected Bearer [REDACTED]", gotErr.Error())
+	assert.True(t, errors.Is(gotErr, secret))
+}
+
+func TestRateLimit(t *testing.T) {
+	l := &mockLimiter{allow: 1}
+	s := Decorate(strategy{id: "1"}, RateLimit(l))
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	_, err := s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	_, err = s.Authenticate(r.Context(), r)
+	assert.Equal(t, ErrRateLimited, err)
+}
+
+func TestDecoratedAuthenticateToken(t *testing.T) {
+	l := &mockLimiter{allow: 1}
+	s := Decorate(tokenStrategy{strategy: strategy{id: "1"}, token: "token"}, RateLimit(l))
+	ctx := context.Background()
+
+	info, err := AuthenticateToken(ctx, s, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+
+	_, err = AuthenticateToken(ctx, s, "token")
+	assert.Equal(t, ErrRateLimited, err)
+
+	// the decorator not applied when the wrapped strategy does not support tokens.
+	s = Decorate(strategy{id: "1"}, RateLimit(&mockLimiter{}))
+	_, err = AuthenticateToken(ctx, s, "token")
+	assert.Equal(t, ErrInvalidStrategy, err)
+
+	a := New()
+	s = Decorate(tokenStrategy{strategy: strategy{id: "1"}, token: "token"}, RateLimit(&mockLimiter{}))
+	a.EnableStrategy("test", s)
+	_, err = a.AuthenticateToken(ctx, "token")
+	assert.True(t, errors.Is(err, ErrRateLimited))
+}
+
+func TestTimeout(t *testing.T) {
+	slow := &slowStrategy{d: time.Second}
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	s := Decorate(slow, Timeout(time.Millisecond))
+	_, err := s.Authenticate(r.Context(), r)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	s = Decorate(strategy{id: "1"}, Timeout(time.Second))
+	info, err := s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+}
+
+type mockLimiter struct {
+	allow int
+}
+
+func (m *mockLimiter) Allow() bool {
+	m.allow--
+	return m.allow >= 0
+}
+
+type slowStrategy struct {
+	d time.Duration
+}
+
+func (s *slowStrategy) Authenticate(ctx context.Context, _ *http.Request) (Info, error) {
+	select {
+	case <-time.After(s.d):
+		return NewDefaultUser("slow", "1", nil, nil), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,374 @@
+// Package device implements OAuth 2.0 device authorization grant (RFC 8628) handlers,
+// to let input-constrained clients such as CLIs and TVs obtain a token,
+// issued by a go-guardian backed service, after the user approve the request from another device.
+package device
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// GrantType is the device code grant type.
+const GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// Errors codes as defined in RFC 8628 section 3.5 and RFC 6749 section 5.2.
+const (
+	ErrAuthorizationPending = "authorization_pending"
+	ErrSlowDown             = "slow_down"
+	ErrAccessDenied         = "access_denied"
+	ErrExpiredToken         = "expired_token"
+	ErrInvalidRequest       = "invalid_request"
+	ErrInvalidClient        = "invalid_client"
+	ErrUnsupportedGrantType = "unsupported_grant_type"
+)
+
+// ErrInvalidUserCode is returned by Approve and Deny,
+// when the user code does not exist or expired.
+var ErrInvalidUserCode = errors.New("device: Invalid or expired user code")
+
+// userCodeCharset is the user code charset, vowels excluded to avoid forming words,
+// as recommended by RFC 8628 section 6.1.
+const userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+
+func init() {
+	gob.Register(&grant{})
+}
+
+// Token represents the issued token response.
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// IssueFunc define function signature to issue a token for the approving user,
+// invoked once when the client polls an approved device code.
+type IssueFunc func(ctx context.Context, info auth.Info, clientID, scope string) (*Token, error)
+
+// Authorization represents device authorization response.
+type Authorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+type status int
+
+const (
+	pending status = iota
+	approved
+	denied
+)
+
+type grant struct {
+	DeviceCode string
+	UserCode   string
+	ClientID   string
+	Scope      string
+	Status     status
+	Info       auth.Info
+	ExpiresAt  time.Time
+	LastPoll   time.Time
+	Interval   time.Duration
+}
+
+// Server implements the device authorization grant endpoints.
+// Pending grants kept in the cache keyed by the device code and the user code,
+// the cache entries lifetime should not be less than the device code lifetime.
+type Server struct {
+	mu              *sync.Mutex
+	cache           store.Cache
+	issue           IssueFunc
+	verificationURI string
+	expiresIn       time.Duration
+	interval        time.Duration
+	client          func(clientID string) error
+	page            pageRenderer
+}
+
+// New return new device authorization Server.
+// verificationURI is the user facing URL of the verification page handler.
+func New(verificationURI string, c store.Cache, issue IssueFunc, opts ...auth.Option) *Server {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	if issue == nil {
+		panic("Issue Function required and can't be nil")
+	}
+
+	s := &Server{
+		mu:              new(sync.Mutex),
+		cache:           c,
+		issue:           issue,
+		verificationURI: verificationURI,
+		expiresIn:       time.Minute * 10,
+		interval:        time.Second * 5,
+		client:          func(string) error { return nil },
+		page:            defaultPage,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(s)
+	}
+
+	return s
+}
+
+// Authorize start a new device authorization for the given client and scope.
+func (s *Server) Authorize(r *http.Request, clientID, scope string) (*Authorization, error) {
+	g := &grant{
+		DeviceCode: deviceCode(),
+		UserCode:   userCode(),
+		ClientID:   clientID,
+		Scope:      scope,
+		ExpiresAt:  time.Now().Add(s.expiresIn),
+		Interval:   s.interval,
+	}
+
+	if err := s.cache.Store(g.DeviceCode, g, r); err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Store(userKey(g.UserCode), g.DeviceCode, r); err != nil {
+		return nil, err
+	}
+
+	return &Authorization{
+		DeviceCode:              g.DeviceCode,
+		UserCode:                g.UserCode,
+		VerificationURI:         s.verificationURI,
+		VerificationURIComplete: s.verificationURI + "?user_code=" + g.UserCode,
+		ExpiresIn:               int64(s.expiresIn / time.Second),
+		Interval:                int64(s.interval / time.Second),
+	}, nil
+}
+
+// Approve approve the device authorization of the given user code on behalf of the given user.
+func (s *Server) Approve(r *http.Request, code string, info auth.Info) error {
+	return s.decide(r, code, approved, info)
+}
+
+// Deny deny the device authorization of the given user code.
+func (s *Server) Deny(r *http.Request, code string) error {
+	return s.decide(r, code, denied, nil)
+}
+
+func (s *Server) decide(r *http.Request, code string, st status, info auth.Info) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := userKey(NormalizeUserCode(code))
+	v, ok, err := s.cache.Load(key, r)
+	if err != nil || !ok {
+		return ErrInvalidUserCode
+	}
+
+	dc, _ := v.(string)
+
+	g, err := s.load(r, dc)
+	if err != nil || g.Status != pending || time.Now().After(g.ExpiresAt) {
+		return ErrInvalidUserCode
+	}
+
+	// user code is single use.
+	_ = s.cache.Delete(key, r)
+
+	g.Status = st
+	g.Info = info
+
+	return s.cache.Store(dc, g, r)
+}
+
+// poll return the issued token of the device code or the polling error code.
+func (s *Server) poll(r *http.Request, clientID, code string) (*Token, string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, err := s.load(r, code)
+	if err != nil || g.ClientID != clientID {
+		return nil, ErrInvalidRequest
+	}
+
+	now := time.Now()
+
+	if now.After(g.ExpiresAt) {
+		_ = s.cache.Delete(code, r)
+		return nil, ErrExpiredToken
+	}
+
+	switch g.Status {
+	case denied:
+		_ = s.cache.Delete(code, r)
+		return nil, ErrAccessDenied
+	case approved:
+		// device code is single use.
+		_ = s.cache.Delete(code, r)
+
+		t, err := s.issue(r.Context(), g.Info, g.ClientID, g.Scope)
+		if err != nil {
+			return nil, ErrAccessDenied
+		}
+
+		return t, ""
+	}
+
+	if now.Sub(g.LastPoll) < g.Interval {
+		// RFC 8628 section 3.5 the interval must be increased by 5 seconds for all subsequent requests.
+		g.Interval += time.Second * 5
+		g.LastPoll = now
+		_ = s.cache.Store(code, g, r)
+		return nil, ErrSlowDown
+	}
+
+	g.LastPoll = now
+	_ = s.cache.Store(code, g, r)
+
+	return nil, ErrAuthorizationPending
+}
+
+func (s *Server) load(r *http.Request, code string) (*grant, error) {
+	v, ok, err := s.cache.Load(code, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrInvalidUserCode
+	}
+
+	g, ok := v.(*grant)
+	if !ok {
+		return nil, gerrors.NewInvalidType((*grant)(nil), v)
+	}
+
+	return g, nil
+}
+
+// AuthorizationHandler return the device authorization endpoint HTTP handler.
+func (s *Server) AuthorizationHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, ErrInvalidRequest)
+			return
+		}
+
+		clientID := r.PostFormValue("client_id")
+		if clientID == "" || s.client(clientID) != nil {
+			writeError(w, http.StatusUnauthorized, ErrInvalidClient)
+			return
+		}
+
+		a, err := s.Authorize(r, clientID, r.PostFormValue("scope"))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, ErrInvalidRequest)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, a)
+	})
+}
+
+// TokenHandler return the device access token endpoint HTTP handler, polled by the client.
+func (s *Server) TokenHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, ErrInvalidRequest)
+			return
+		}
+
+		if r.PostFormValue("grant_type") != GrantType {
+			writeError(w, http.StatusBadRequest, ErrUnsupportedGrantType)
+			return
+		}
+
+		t, code := s.poll(r, r.PostFormValue("client_id"), r.PostFormValue("device_code"))
+		if code != "" {
+			writeError(w, http.StatusBadRequest, code)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, http.StatusOK, t)
+	})
+}
+
+// NormalizeUserCode convert user input to the canonical user code form,
+// by upper casing it and dropping any separators.
+func NormalizeUserCode(code string) string {
+	code = strings.ToUpper(code)
+	b := new(strings.Builder)
+
+	for _, c := range code {
+		if strings.ContainsRune(userCodeCharset, c) {
+			b.WriteRune(c)
+		}
+	}
+
+	v := b.String()
+	if len(v) != 8 {
+		return v
+	}
+
+	return v[:4] + "-" + v[4:]
+}
+
+func userKey(code string) string {
+	return "user_code:" + code
+}
+
+func deviceCode() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func userCode() string {
+	b := make([]byte, 0, 9)
+	max := big.NewInt(int64(len(userCodeCharset)))
+
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			b = append(b, '-')
+		}
+
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic(err)
+		}
+
+		b = append(b, userCodeCharset[n.Int64()])
+	}
+
+	return string(b)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, e string) {
+	writeJSON(w, code, map[string]string{"error": e})
+}
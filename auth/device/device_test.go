@@ -0,0 +1,149 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func issue(ctx context.Context, info auth.Info, clientID, scope string) (*Token, error) {
+	return &Token{AccessToken: info.ID(), TokenType: "Bearer"}, nil
+}
+
+func post(h http.Handler, form url.Values, info auth.Info) *httptest.ResponseRecorder {
+	r, _ := http.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if info != nil {
+		r = auth.RequestWithUser(info, r)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func pollForm(code string) url.Values {
+	return url.Values{"grant_type": {GrantType}, "client_id": {"cli"}, "device_code": {code}}
+}
+
+func errorCode(w *httptest.ResponseRecorder) string {
+	e := map[string]string{}
+	_ = json.Unmarshal(w.Body.Bytes(), &e)
+	return e["error"]
+}
+
+func TestFlow(t *testing.T) {
+	s := New("https://example.com/device", store.New(0), issue, SetInterval(0))
+	user := auth.NewUserInfo("test", "1", nil, nil)
+
+	w := post(s.AuthorizationHandler(), url.Values{"client_id": {"cli"}}, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	a := Authorization{}
+	_ = json.Unmarshal(w.Body.Bytes(), &a)
+	assert.Regexp(t, "^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$", a.UserCode)
+	assert.Equal(t, "https://example.com/device?user_code="+a.UserCode, a.VerificationURIComplete)
+
+	w = post(s.TokenHandler(), pollForm(a.DeviceCode), nil)
+	assert.Equal(t, ErrAuthorizationPending, errorCode(w))
+
+	// verification page requires authenticated user.
+	w = post(s.VerificationHandler(), url.Values{"user_code": {a.UserCode}, "action": {"approve"}}, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	code := strings.ToLower(strings.Replace(a.UserCode, "-", "", 1))
+	w = post(s.VerificationHandler(), url.Values{"user_code": {code}, "action": {"approve"}}, user)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Device approved")
+
+	// user code is single use.
+	w = post(s.VerificationHandler(), url.Values{"user_code": {a.UserCode}, "action": {"deny"}}, user)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = post(s.TokenHandler(), pollForm(a.DeviceCode), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	tk := Token{}
+	_ = json.Unmarshal(w.Body.Bytes(), &tk)
+	assert.Equal(t, "1", tk.AccessToken)
+
+	// device code is single use.
+	w = post(s.TokenHandler(), pollForm(a.DeviceCode), nil)
+	assert.Equal(t, ErrInvalidRequest, errorCode(w))
+}
+
+func TestPoll(t *testing.T) {
+	table := []struct {
+		name    string
+		opts    []auth.Option
+		prepare func(s *Server, a *Authorization)
+		code    string
+	}{
+		{
+			name: "it return slow_down when client poll faster than interval",
+			opts: []auth.Option{SetInterval(time.Hour)},
+			prepare: func(s *Server, a *Authorization) {
+				_, _ = s.poll(&http.Request{}, "cli", a.DeviceCode)
+			},
+			code: ErrSlowDown,
+		},
+		{
+			name: "it return access_denied when user deny the request",
+			prepare: func(s *Server, a *Authorization) {
+				_ = s.Deny(&http.Request{}, a.UserCode)
+			},
+			code: ErrAccessDenied,
+		},
+		{
+			name: "it return expired_token when device code expired",
+			opts: []auth.Option{SetExpiresIn(-time.Second)},
+			code: ErrExpiredToken,
+		},
+		{
+			name: "it return invalid_request when device code unknown",
+			prepare: func(s *Server, a *Authorization) {
+				a.DeviceCode = "unknown"
+			},
+			code: ErrInvalidRequest,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("https://example.com/device", store.New(0), issue, tt.opts...)
+			a, _ := s.Authorize(&http.Request{}, "cli", "")
+
+			if tt.prepare != nil {
+				tt.prepare(s, a)
+			}
+
+			w := post(s.TokenHandler(), pollForm(a.DeviceCode), nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.code, errorCode(w))
+		})
+	}
+}
+
+func TestAuthorizationHandlerInvalidClient(t *testing.T) {
+	s := New("https://example.com/device", store.New(0), issue, SetClientValidator(func(string) error {
+		return ErrInvalidUserCode
+	}))
+
+	w := post(s.AuthorizationHandler(), url.Values{"client_id": {"cli"}}, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, ErrInvalidClient, errorCode(w))
+}
+
+func TestNormalizeUserCode(t *testing.T) {
+	assert.Equal(t, "BCDF-GHJK", NormalizeUserCode("bcdf ghjk"))
+	assert.Equal(t, "BCDF-GHJK", NormalizeUserCode("BCDF-GHJK"))
+}
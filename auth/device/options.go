@@ -0,0 +1,37 @@
+package device
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetExpiresIn sets the device code and user code lifetime,
+// Default 10 minutes.
+func SetExpiresIn(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.expiresIn = d
+		}
+	})
+}
+
+// SetInterval sets the minimum polling interval the client must wait between token requests,
+// Default 5 seconds.
+func SetInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.interval = d
+		}
+	})
+}
+
+// SetClientValidator sets function to validate the client id of device authorization requests,
+// By default all client ids are accepted.
+func SetClientValidator(fn func(clientID string) error) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.client = fn
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package device
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// PageData represents the data passed to the verification page template.
+type PageData struct {
+	// UserCode is the user code entered by the user, or prefilled from verification_uri_complete.
+	UserCode string
+	// Error is a user facing error message.
+	Error string
+	// Done reports whether the user approved or denied the request.
+	Done bool
+	// Approved reports whether the user approved the request.
+	Approved bool
+}
+
+type pageRenderer func(w http.ResponseWriter, r *http.Request, data PageData)
+
+var defaultTemplate = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Device Login</title></head>
+<body>
+{{- if .Done }}
+<p>{{ if .Approved }}Device approved, you can return to your device.{{ else }}Device denied.{{ end }}</p>
+{{- else }}
+{{- if .Error }}<p>{{ .Error }}</p>{{ end }}
+<form method="POST">
+<label>Enter the code displayed on your device <input name="user_code" value="{{ .UserCode }}"></label>
+<button name="action" value="approve">Approve</button>
+<button name="action" value="deny">Deny</button>
+</form>
+{{- end }}
+</body>
+</html>
+`))
+
+var defaultPage = templatePage(defaultTemplate)
+
+func templatePage(t *template.Template) pageRenderer {
+	return func(w http.ResponseWriter, r *http.Request, data PageData) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = t.Execute(w, data)
+	}
+}
+
+// SetPageTemplate sets the verification page template, executed with PageData.
+func SetPageTemplate(t *template.Template) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.page = templatePage(t)
+		}
+	})
+}
+
+// VerificationHandler return the user facing verification page HTTP handler,
+// where the user enter the user code and approve or deny the device request.
+// The handler must be wrapped with auth.Middleware (or equivalent),
+// since the request approved on behalf of the authenticated user found in the request context,
+// and with a CSRF protection middleware, as the approval is a state changing form submission.
+func (s *Server) VerificationHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := auth.User(r)
+		if info == nil {
+			http.Error(w, auth.StatusMessage(r, http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			s.page(w, r, PageData{UserCode: r.URL.Query().Get("user_code")})
+			return
+		}
+
+		code := r.PostFormValue("user_code")
+		approve := r.PostFormValue("action") == "approve"
+
+		var err error
+		if approve {
+			err = s.Approve(r, code, info)
+		} else {
+			err = s.Deny(r, code)
+		}
+
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			s.page(w, r, PageData{UserCode: code, Error: "Invalid or expired code."})
+			return
+		}
+
+		s.page(w, r, PageData{Done: true, Approved: approve})
+	})
+}
@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// ErrNoRoute is returned by Dispatcher,
+// when the request does not match any registered route.
+var ErrNoRoute = errors.New("dispatcher: No strategy routed for the request")
+
+// Dispatcher implements Strategy and routes requests directly to the strategies,
+// registered for the request Authorization scheme or for a header present in the request,
+// instead of trying every strategy sequentially, to reduce wasted work when many strategies enabled.
+// Authorization scheme routes take precedence over header routes, which checked in registration order,
+// requests matching no route dispatched to the default strategies.
+//
+// Strategies of the same route tried in registration order,
+// and the request never dispatched to another route strategies once a route matched.
+//
+// Dispatcher not safe for concurrent registration, register routes before serving requests.
+type Dispatcher struct {
+	schemes  map[string][]Strategy
+	headers  []headerRoute
+	fallback []Strategy
+}
+
+type headerRoute struct {
+	header     string
+	strategies []Strategy
+}
+
+// NewDispatcher return new Dispatcher.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		schemes: make(map[string][]Strategy),
+	}
+}
+
+// HandleScheme routes requests having the given Authorization scheme (e.g Bearer) to the strategies,
+// the scheme matched case-insensitively.
+func (d *Dispatcher) HandleScheme(scheme string, strategies ...Strategy) {
+	scheme = strings.ToLower(scheme)
+	d.schemes[scheme] = append(d.schemes[scheme], strategies...)
+}
+
+// HandleHeader routes requests having the given header (e.g X-API-Key) to the strategies.
+func (d *Dispatcher) HandleHeader(header string, strategies ...Strategy) {
+	header = http.CanonicalHeaderKey(header)
+
+	for i, h := range d.headers {
+		if h.header == header {
+			d.headers[i].strategies = append(h.strategies, strategies...)
+			return
+		}
+	}
+
+	d.headers = append(d.headers, headerRoute{header: header, strategies: strategies})
+}
+
+// HandleDefault routes requests matching no route to the strategies.
+func (d *Dispatcher) HandleDefault(strategies ...Strategy) {
+	d.fallback = append(d.fallback, strategies...)
+}
+
+// Authenticate dispatch the request to the matched route strategies,
+// and return user information from the first strategy that successfully authenticates the request.
+func (d *Dispatcher) Authenticate(ctx context.Context, r *http.Request) (Info, error) {
+	strategies := d.route(r)
+	if len(strategies) == 0 {
+		return nil, ErrNoRoute
+	}
+
+	errs := gerrors.MultiError{}
+
+	for _, s := range strategies {
+		info, err := s.Authenticate(ctx, r)
+		if err == nil {
+			return info, nil
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+
+	return nil, errs
+}
+
+// Challenge returns the consolidated challenges of the routed strategies.
+// Typically used to adds a HTTP WWW-Authenticate header.
+func (d *Dispatcher) Challenge(realm string) string {
+	challenges := []string{}
+
+	add := func(strategies []Strategy) {
+		for _, s := range strategies {
+			if c, ok := Challenge(s, realm); ok {
+				challenges = append(challenges, c)
+			}
+		}
+	}
+
+	schemes := make([]string, 0, len(d.schemes))
+	for scheme := range d.schemes {
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+
+	for _, scheme := range schemes {
+		add(d.schemes[scheme])
+	}
+
+	for _, h := range d.headers {
+		add(h.strategies)
+	}
+
+	add(d.fallback)
+
+	return strings.Join(challenges, ", ")
+}
+
+func (d *Dispatcher) route(r *http.Request) []Strategy {
+	if v := strings.TrimSpace(r.Header.Get("Authorization")); v != "" {
+		scheme := v
+		if i := strings.IndexByte(v, ' '); i > 0 {
+			scheme = v[:i]
+		}
+
+		if strategies, ok := d.schemes[strings.ToLower(scheme)]; ok {
+			return strategies
+		}
+	}
+
+	for _, h := range d.headers {
+		if _, ok := r.Header[h.header]; ok {
+			return h.strategies
+		}
+	}
+
+	return d.fallback
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type challengeStrategy struct {
+	strategy
+	challenge string
+}
+
+func (c challengeStrategy) Challenge(realm string) string {
+	return c.challenge + ` realm="` + realm + `"`
+}
+
+func TestDispatcher(t *testing.T) {
+	d := NewDispatcher()
+	d.HandleScheme("Bearer", strategy{returnErr: true}, strategy{id: "bearer"})
+	d.HandleScheme("basic", strategy{returnErr: true})
+	d.HandleHeader("x-api-key", strategy{id: "apikey"})
+	d.HandleHeader("X-Api-Key", strategy{id: "apikey2"})
+
+	table := []struct {
+		name        string
+		header      http.Header
+		id          string
+		expectedErr error
+	}{
+		{
+			name:   "it route by scheme case-insensitively",
+			header: http.Header{"Authorization": {"bearer token"}},
+			id:     "bearer",
+		},
+		{
+			name:   "it route by header",
+			header: http.Header{"X-Api-Key": {"key"}},
+			id:     "apikey",
+		},
+		{
+			name:   "it fall back to header route when scheme unknown",
+			header: http.Header{"Authorization": {"Digest x"}, "X-Api-Key": {"key"}},
+			id:     "apikey",
+		},
+		{
+			name:        "it return error when no route matched",
+			header:      http.Header{"Authorization": {"Digest x"}},
+			expectedErr: ErrNoRoute,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header = tt.header
+
+			info, err := d.Authenticate(context.Background(), r)
+
+			if tt.expectedErr != nil {
+				assert.Equal(t, tt.expectedErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.id, info.ID())
+		})
+	}
+}
+
+func TestDispatcherRouteErrors(t *testing.T) {
+	d := NewDispatcher()
+	d.HandleScheme("Basic", strategy{returnErr: true})
+	d.HandleDefault(strategy{id: "default"})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("test", "test")
+
+	// matched route errors never dispatched to other routes.
+	_, err := d.Authenticate(context.Background(), r)
+	assert.EqualError(t, err, "authenticator test strategy L25")
+
+	r.Header.Del("Authorization")
+	info, err := d.Authenticate(context.Background(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", info.ID())
+}
+
+func TestDispatcherChallenge(t *testing.T) {
+	d := NewDispatcher()
+	d.HandleScheme("Bearer", challengeStrategy{challenge: "Bearer"})
+	d.HandleScheme("Basic", challengeStrategy{challenge: "Basic"})
+	d.HandleHeader("X-API-Key", strategy{})
+
+	assert.Equal(t, `Basic realm="test", Bearer realm="test"`, d.Challenge("test"))
+}
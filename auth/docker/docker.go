@@ -0,0 +1,121 @@
+// Package docker implements Docker Registry v2 token authentication specification,
+// to let go-guardian front a private registry (e.g distribution),
+// where the registry challenge clients to obtain a bearer token from the token server,
+// and the token server issues a short lived JWT, after authenticating the client using go-guardian,
+// granting only the access allowed by the access callback.
+//
+// The registry must be configured to trust the token server issuer, service, and signing key,
+// See KeyID to compute the key id expected by the registry.
+package docker
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// ErrInvalidScope is returned by ParseScope,
+// when the scope not in the form of type:name:actions.
+var ErrInvalidScope = errors.New("docker: Invalid scope")
+
+// ErrUnsupportedKey is returned by New and KeyID,
+// when the signing key type not supported.
+var ErrUnsupportedKey = errors.New("docker: Unsupported signing key")
+
+// Access represents a resource access, requested by the client scope,
+// and granted by the issued token access claim.
+type Access struct {
+	// Type is the resource type e.g repository, or registry.
+	Type string `json:"type"`
+	// Name is the resource name e.g samalba/my-app.
+	Name string `json:"name"`
+	// Actions is the resource actions e.g pull, push, or *.
+	Actions []string `json:"actions"`
+}
+
+// String return the access in the scope form, type:name:actions.
+func (a Access) String() string {
+	return a.Type + ":" + a.Name + ":" + strings.Join(a.Actions, ",")
+}
+
+// ParseScope parse docker token scope in the form of type:name:actions,
+// e.g repository:samalba/my-app:pull,push.
+// The resource name may contain a colon, e.g a registry host port.
+func ParseScope(scope string) (Access, error) {
+	i := strings.Index(scope, ":")
+	j := strings.LastIndex(scope, ":")
+
+	if i <= 0 || j == i || j == len(scope)-1 {
+		return Access{}, ErrInvalidScope
+	}
+
+	return Access{
+		Type:    scope[:i],
+		Name:    scope[i+1 : j],
+		Actions: strings.Split(scope[j+1:], ","),
+	}, nil
+}
+
+// Challenge return the WWW-Authenticate header value,
+// a registry respond with to instruct clients to obtain a token from the given realm.
+func Challenge(realm, service string, scopes ...Access) string {
+	c := fmt.Sprintf(`Bearer realm=%q,service=%q`, realm, service)
+
+	if len(scopes) > 0 {
+		s := make([]string, 0, len(scopes))
+		for _, a := range scopes {
+			s = append(s, a.String())
+		}
+		c += fmt.Sprintf(`,scope=%q`, strings.Join(s, " "))
+	}
+
+	return c
+}
+
+// KeyID return the key id of the given public key, in the libtrust fingerprint format,
+// that registry uses to look up the token signing key from its trusted root certificates bundle.
+func KeyID(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(der)
+	s := strings.TrimRight(base32.StdEncoding.EncodeToString(sum[:30]), "=")
+	groups := make([]string, 0, len(s)/4)
+
+	for i := 0; i < len(s); i += 4 {
+		groups = append(groups, s[i:i+4])
+	}
+
+	return strings.Join(groups, ":"), nil
+}
+
+func algorithm(pub crypto.PublicKey) (string, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return jwt.RS256, nil
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return jwt.ES256, nil
+		case 384:
+			return jwt.ES384, nil
+		case 521:
+			return jwt.ES512, nil
+		}
+	case ed25519.PublicKey:
+		return jwt.EdDSA, nil
+	}
+
+	return "", ErrUnsupportedKey
+}
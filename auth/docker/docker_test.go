@@ -0,0 +1,207 @@
+package docker
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+func TestParseScope(t *testing.T) {
+	table := []struct {
+		scope  string
+		access Access
+		err    error
+	}{
+		{
+			scope:  "repository:samalba/my-app:pull,push",
+			access: Access{Type: "repository", Name: "samalba/my-app", Actions: []string{"pull", "push"}},
+		},
+		{
+			scope:  "repository:localhost:5000/my-app:pull",
+			access: Access{Type: "repository", Name: "localhost:5000/my-app", Actions: []string{"pull"}},
+		},
+		{
+			scope:  "registry:catalog:*",
+			access: Access{Type: "registry", Name: "catalog", Actions: []string{"*"}},
+		},
+		{scope: "repository", err: ErrInvalidScope},
+		{scope: "repository:my-app", err: ErrInvalidScope},
+		{scope: "repository:my-app:", err: ErrInvalidScope},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.scope, func(t *testing.T) {
+			a, err := ParseScope(tt.scope)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.access, a)
+			if err == nil {
+				assert.Equal(t, tt.scope, a.String())
+			}
+		})
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	a, _ := ParseScope("repository:samalba/my-app:pull,push")
+	c := Challenge("https://auth.example.com/token", "registry.example.com", a)
+	assert.Equal(
+		t,
+		`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:samalba/my-app:pull,push"`, // nolint:lll
+		c,
+	)
+}
+
+func TestKeyID(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	kid, err := KeyID(key.Public())
+	assert.NoError(t, err)
+	assert.Len(t, kid, 59)
+	assert.Regexp(t, `^([A-Z2-7]{4}:){11}[A-Z2-7]{4}$`, kid)
+}
+
+func TestServer(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	a := auth.New("/anonymous")
+	a.EnableStrategy("basic", basic.AuthenticateFunc(
+		func(ctx context.Context, r *http.Request, user, pass string) (auth.Info, error) {
+			if user == "alice" && pass == "secret" {
+				return auth.NewUserInfo("alice", "1", nil, nil), nil
+			}
+			return nil, basic.ErrInvalidCredentials
+		},
+	))
+
+	fn := func(ctx context.Context, info auth.Info, requested []Access) ([]Access, error) {
+		granted := []Access{}
+		for _, r := range requested {
+			if r.Name == "forbidden" {
+				return nil, errors.New("forbidden")
+			}
+			if info == nil {
+				r.Actions = []string{"pull"}
+			}
+			granted = append(granted, r)
+		}
+		return granted, nil
+	}
+
+	s, err := New(a, "issuer", key, fn, SetServices("registry"))
+	assert.NoError(t, err)
+
+	table := []struct {
+		name    string
+		path    string
+		query   string
+		auth    bool
+		code    int
+		subject string
+		access  []Access
+	}{
+		{
+			name:    "it issue token with requested access",
+			query:   "service=registry&scope=repository:app:pull,push",
+			auth:    true,
+			code:    http.StatusOK,
+			subject: "alice",
+			access:  []Access{{Type: "repository", Name: "app", Actions: []string{"pull", "push"}}},
+		},
+		{
+			name:    "it issue token without access for login",
+			query:   "service=registry&account=alice",
+			auth:    true,
+			code:    http.StatusOK,
+			subject: "alice",
+			access:  []Access{},
+		},
+		{
+			name:   "it issue anonymous token when path disabled",
+			path:   "/anonymous",
+			query:  "service=registry&scope=repository:app:pull,push",
+			code:   http.StatusOK,
+			access: []Access{{Type: "repository", Name: "app", Actions: []string{"pull"}}},
+		},
+		{
+			name:  "it return 401 when client not authenticated",
+			query: "service=registry&scope=repository:app:pull",
+			code:  http.StatusUnauthorized,
+		},
+		{
+			name:  "it return 400 when service unknown",
+			query: "service=other",
+			auth:  true,
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "it return 400 when scope invalid",
+			query: "service=registry&scope=repository",
+			auth:  true,
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "it return 403 when access func fails",
+			query: "service=registry&scope=repository:forbidden:pull",
+			auth:  true,
+			code:  http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = "/token"
+			}
+
+			r := httptest.NewRequest(http.MethodGet, path+"?"+tt.query, nil)
+			r.RequestURI = path
+			if tt.auth {
+				r.SetBasicAuth("alice", "secret")
+			}
+
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+
+			if tt.code == http.StatusUnauthorized {
+				assert.Contains(t, w.Header().Get("WWW-Authenticate"), `Basic realm="Registry"`)
+			}
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			resp := Token{}
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			assert.Equal(t, resp.Token, resp.AccessToken)
+			assert.Equal(t, 300, resp.ExpiresIn)
+
+			tk, err := jwt.Parse(resp.Token)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, jwt.ES256, tk.Header.Algorithm)
+			assert.NoError(t, tk.Verify(key.Public()))
+
+			c := claims{}
+			_ = tk.Decode(&c)
+			assert.Equal(t, "issuer", c.Issuer)
+			assert.Equal(t, tt.subject, c.Subject)
+			assert.True(t, c.Audience.Contains("registry"))
+			assert.Equal(t, tt.access, c.Access)
+		})
+	}
+}
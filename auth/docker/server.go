@@ -0,0 +1,245 @@
+package docker
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// AccessFunc define function signature to filter the access requested by the client,
+// and return the granted subset, info is nil when the client anonymous.
+// Returning an empty access still issues a token, as the specification requires,
+// the registry then deny the operation.
+type AccessFunc func(ctx context.Context, info auth.Info, requested []Access) ([]Access, error)
+
+// Token represents the token server response.
+type Token struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	IssuedAt    string `json:"issued_at"`
+}
+
+type claims struct {
+	jwt.Claims
+	Access []Access `json:"access"`
+}
+
+// Server is the registry token server.
+type Server struct {
+	authenticator auth.Authenticator
+	access        AccessFunc
+	issuer        string
+	key           crypto.Signer
+	alg           string
+	kid           string
+	services      map[string]struct{}
+	expiresIn     time.Duration
+	realm         string
+}
+
+// New return new token server, that authenticate clients using the given authenticator,
+// and issue tokens signed by key, using RS256, ES256/384/512, or EdDSA based on the key type,
+// granting the access returned by fn.
+// The authenticator disabled paths treated as anonymous clients.
+func New(
+	a auth.Authenticator,
+	issuer string,
+	key crypto.Signer,
+	fn AccessFunc,
+	opts ...auth.Option,
+) (*Server, error) {
+	if a == nil {
+		panic("Authenticator required and can't be nil")
+	}
+
+	if fn == nil {
+		panic("Access Function required and can't be nil")
+	}
+
+	alg, err := algorithm(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := KeyID(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		authenticator: a,
+		access:        fn,
+		issuer:        issuer,
+		key:           key,
+		alg:           alg,
+		kid:           kid,
+		expiresIn:     time.Minute * 5,
+		realm:         "Registry",
+	}
+
+	for _, opt := range opts {
+		opt.Apply(s)
+	}
+
+	return s, nil
+}
+
+// Token issues a token for the given service,
+// granting the subset of the requested access allowed by the access function.
+func (s *Server) Token(ctx context.Context, info auth.Info, service string, req []Access) (*Token, error) {
+	granted, err := s.access(ctx, info, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if granted == nil {
+		granted = []Access{}
+	}
+
+	now := time.Now()
+	c := claims{
+		Claims: jwt.Claims{
+			Issuer:    s.issuer,
+			Audience:  jwt.Audience{service},
+			Expiry:    jwt.NewNumericDate(now.Add(s.expiresIn)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        random(),
+		},
+		Access: granted,
+	}
+
+	if info != nil {
+		c.Subject = info.UserName()
+	}
+
+	str, err := jwt.Sign(s.alg, s.kid, s.key, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		Token:       str,
+		AccessToken: str,
+		ExpiresIn:   int(s.expiresIn.Seconds()),
+		IssuedAt:    now.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// ServeHTTP implements http.Handler and serve the token endpoint,
+// it authenticate the client, parse the service and scope query parameters,
+// and respond with the issued token.
+// Unauthenticated clients receive 401 with the authenticator strategies challenges.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "method not allowed")
+		return
+	}
+
+	q := r.URL.Query()
+	service := q.Get("service")
+
+	if _, ok := s.services[service]; len(s.services) > 0 && !ok {
+		writeError(w, http.StatusBadRequest, "DENIED", "unknown service")
+		return
+	}
+
+	requested := make([]Access, 0, len(q["scope"]))
+	for _, scope := range q["scope"] {
+		a, err := ParseScope(scope)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "DENIED", err.Error())
+			return
+		}
+		requested = append(requested, a)
+	}
+
+	info, err := s.authenticator.Authenticate(r)
+	if err == auth.ErrDisabledPath {
+		info, err = nil, nil
+	}
+
+	if err != nil {
+		auth.SetWWWAuthenticate(w, s.realm, auth.StrategiesOf(s.authenticator)...)
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "authentication required")
+		return
+	}
+
+	t, err := s.Token(r.Context(), info, service, requested)
+	if err != nil {
+		writeError(w, http.StatusForbidden, "DENIED", "requested access to the resource is denied")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(t)
+}
+
+// SetExpiresIn sets the issued tokens lifetime,
+// Default 5 minutes, the specification recommends at least 60 seconds.
+func SetExpiresIn(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.expiresIn = d
+		}
+	})
+}
+
+// SetServices sets the services (registries) the server issues tokens for,
+// Default any service.
+func SetServices(services ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.services = make(map[string]struct{}, len(services))
+			for _, v := range services {
+				s.services[v] = struct{}{}
+			}
+		}
+	})
+}
+
+// SetKeyID sets the issued tokens kid header,
+// Default the signing key libtrust fingerprint, See KeyID.
+func SetKeyID(kid string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.kid = kid
+		}
+	})
+}
+
+// SetRealm sets the realm of the challenges sent to unauthenticated clients,
+// Default Registry.
+func SetRealm(realm string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*Server); ok {
+			s.realm = realm
+		}
+	})
+}
+
+func writeError(w http.ResponseWriter, code int, errCode, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"code": errCode, "message": msg}},
+	})
+}
+
+func random() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
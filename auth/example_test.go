@@ -27,3 +27,14 @@ func ExampleRevoke() {
 	// Output:
 	// <nil>
 }
+
+func ExampleRenew() {
+	strategy := &mockStrategy{}
+	r, _ := http.NewRequest("GET", "/", nil)
+	// assume token extracted from header
+	token := "90d64460d14870c08c81352a05dedd3465940a7"
+	err := Renew(strategy, token, r)
+	fmt.Println(err)
+	// Output:
+	// <nil>
+}
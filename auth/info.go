@@ -4,8 +4,25 @@ import (
 	"bytes"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
+	"strings"
+	"time"
 )
 
+// ErrCredentialNotYetValid is returned by Authenticator,
+// when the credential the user authenticated with not valid yet, See ValidityInfo.
+var ErrCredentialNotYetValid = errors.New("authenticator: Credential not valid yet")
+
+// InternalExtensionPrefix is the prefix of the extensions keys used internally by strategies,
+// e.g the basic strategy cached password hash, such extensions must never leave the process.
+const InternalExtensionPrefix = "x-go-guardian-"
+
+// IsInternalExtension reports whether the given extension key used internally by strategies,
+// See InternalExtensionPrefix.
+func IsInternalExtension(key string) bool {
+	return strings.HasPrefix(strings.ToLower(key), InternalExtensionPrefix)
+}
+
 var ic InfoConstructor
 
 func init() {
@@ -29,6 +46,115 @@ type Info interface {
 	SetExtensions(exts map[string][]string)
 }
 
+// RolesInfo is an optional interface implemented by Info carrying the user roles,
+// so the authorization layer reads roles without depending on concrete types.
+// Groups already part of the Info contract.
+type RolesInfo interface {
+	// Roles returns the names of the roles granted to the user.
+	Roles() []string
+	// SetRoles set the names of the roles granted to the user.
+	SetRoles(roles []string)
+}
+
+// ExpiryInfo is an optional interface implemented by Info carrying,
+// the expiry of the credential the user authenticated with (e.g token exp claim).
+type ExpiryInfo interface {
+	// Expiry returns the time the user credential expires at, or zero time if unknown.
+	Expiry() time.Time
+	// SetExpiry set the time the user credential expires at.
+	SetExpiry(t time.Time)
+}
+
+// ValidityInfo is an optional interface implemented by Info carrying,
+// the start of the validity window of the credential the user authenticated with (e.g token nbf claim),
+// while the window end is the credential expiry, See ExpiryInfo.
+// The Authenticator rejects users outside their validity window, even when strategies cache them,
+// so temporary credentials stop working once expired.
+type ValidityInfo interface {
+	// NotBefore returns the time the user credential valid from, or zero time if unknown.
+	NotBefore() time.Time
+	// SetNotBefore set the time the user credential valid from.
+	SetNotBefore(t time.Time)
+}
+
+// UserRoles return the user roles, or nil if info does not implement RolesInfo.
+func UserRoles(info Info) []string {
+	if r, ok := info.(RolesInfo); ok {
+		return r.Roles()
+	}
+	return nil
+}
+
+// SetUserRoles set the user roles, if info implements RolesInfo, and reports whether it's set.
+func SetUserRoles(info Info, roles []string) bool {
+	r, ok := info.(RolesInfo)
+	if ok {
+		r.SetRoles(roles)
+	}
+	return ok
+}
+
+// UserExpiry return the user credential expiry, the ok result reports whether the expiry known.
+func UserExpiry(info Info) (t time.Time, ok bool) {
+	if e, ok := info.(ExpiryInfo); ok {
+		t = e.Expiry()
+	}
+	return t, !t.IsZero()
+}
+
+// SetUserExpiry set the user credential expiry, if info implements ExpiryInfo, and reports whether it's set.
+func SetUserExpiry(info Info, t time.Time) bool {
+	e, ok := info.(ExpiryInfo)
+	if ok {
+		e.SetExpiry(t)
+	}
+	return ok
+}
+
+// UserNotBefore return the user credential validity start, the ok result reports whether it's known.
+func UserNotBefore(info Info) (t time.Time, ok bool) {
+	if v, ok := info.(ValidityInfo); ok {
+		t = v.NotBefore()
+	}
+	return t, !t.IsZero()
+}
+
+// SetUserNotBefore set the user credential validity start, if info implements ValidityInfo,
+// and reports whether it's set.
+func SetUserNotBefore(info Info, t time.Time) bool {
+	v, ok := info.(ValidityInfo)
+	if ok {
+		v.SetNotBefore(t)
+	}
+	return ok
+}
+
+// SetUserValidity set the user credential validity window, i.e the not before and the expiry (not after),
+// zero times leave the window open ended.
+func SetUserValidity(info Info, notBefore, notAfter time.Time) {
+	SetUserNotBefore(info, notBefore)
+	SetUserExpiry(info, notAfter)
+}
+
+// ValidityLeeway is the allowed clock skew used by the Authenticator,
+// when validating users credential validity window.
+var ValidityLeeway = time.Second * 30
+
+// ValidateUser return ErrCredentialNotYetValid or ErrCredentialExpired,
+// if the given time is outside the user credential validity window,
+// extended by the given leeway on both ends, Otherwise, nil.
+func ValidateUser(info Info, now time.Time, leeway time.Duration) error {
+	if nbf, ok := UserNotBefore(info); ok && now.Add(leeway).Before(nbf) {
+		return ErrCredentialNotYetValid
+	}
+
+	if exp, ok := UserExpiry(info); ok && !now.Add(-leeway).Before(exp) {
+		return ErrCredentialExpired
+	}
+
+	return nil
+}
+
 // InfoConstructor define function signature to create new Info object.
 type InfoConstructor func(name, id string, groups []string, extensions map[string][]string) Info
 
@@ -38,6 +164,9 @@ type DefaultUser struct {
 	id         string
 	groups     []string
 	extensions map[string][]string
+	roles      []string
+	expiry     time.Time
+	notBefore  time.Time
 }
 
 // UserName returns the name that uniquely identifies this user among all
@@ -61,6 +190,36 @@ func (d *DefaultUser) SetGroups(groups []string) {
 	d.groups = groups
 }
 
+// Roles returns the names of the roles granted to the user.
+func (d *DefaultUser) Roles() []string {
+	return d.roles
+}
+
+// SetRoles set the names of the roles granted to the user.
+func (d *DefaultUser) SetRoles(roles []string) {
+	d.roles = roles
+}
+
+// Expiry returns the time the user credential expires at, or zero time if unknown.
+func (d *DefaultUser) Expiry() time.Time {
+	return d.expiry
+}
+
+// SetExpiry set the time the user credential expires at.
+func (d *DefaultUser) SetExpiry(t time.Time) {
+	d.expiry = t
+}
+
+// NotBefore returns the time the user credential valid from, or zero time if unknown.
+func (d *DefaultUser) NotBefore() time.Time {
+	return d.notBefore
+}
+
+// SetNotBefore set the time the user credential valid from.
+func (d *DefaultUser) SetNotBefore(t time.Time) {
+	d.notBefore = t
+}
+
 // Extensions return additional information.
 func (d *DefaultUser) Extensions() map[string][]string {
 	return d.extensions
@@ -114,7 +273,7 @@ func (d *DefaultUser) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	i.from(d)
+	i.to(d)
 	return nil
 }
 
@@ -128,6 +287,106 @@ func NewDefaultUser(name, id string, groups []string, extensions map[string][]st
 	}
 }
 
+// NewUser return new default user configured using the given options,
+// See WithGroups, WithExtensions, and WithEmail.
+func NewUser(name, id string, opts ...Option) *DefaultUser {
+	return new(DefaultUser).Init(name, id, opts...)
+}
+
+// Init (re)initialize the default user with the given name, id, and options, and return it.
+// Groups and extensions storage reused, so users obtained from a sync.Pool,
+// can be initialized without allocating new groups slice and extensions map.
+func (d *DefaultUser) Init(name, id string, opts ...Option) *DefaultUser {
+	d.Reset()
+	d.name = name
+	d.id = id
+
+	for _, opt := range opts {
+		opt.Apply(d)
+	}
+
+	return d
+}
+
+// Reset clears the default user fields while retaining the groups and extensions storage,
+// Typically called before returning the user to a sync.Pool.
+// The user groups and extensions must not be referenced after Reset.
+func (d *DefaultUser) Reset() {
+	d.name = ""
+	d.id = ""
+	d.groups = d.groups[:0]
+	d.roles = d.roles[:0]
+	d.expiry = time.Time{}
+	d.notBefore = time.Time{}
+
+	for k := range d.extensions {
+		delete(d.extensions, k)
+	}
+}
+
+// WithGroups append the given groups to the default user groups.
+func WithGroups(groups ...string) Option {
+	return OptionFunc(func(v interface{}) {
+		if d, ok := v.(*DefaultUser); ok {
+			d.groups = append(d.groups, groups...)
+		}
+	})
+}
+
+// WithRoles append the given roles to the default user roles.
+func WithRoles(roles ...string) Option {
+	return OptionFunc(func(v interface{}) {
+		if d, ok := v.(*DefaultUser); ok {
+			d.roles = append(d.roles, roles...)
+		}
+	})
+}
+
+// WithExpiry sets the default user credential expiry.
+func WithExpiry(t time.Time) Option {
+	return OptionFunc(func(v interface{}) {
+		if d, ok := v.(*DefaultUser); ok {
+			d.expiry = t
+		}
+	})
+}
+
+// WithNotBefore sets the default user credential validity start.
+func WithNotBefore(t time.Time) Option {
+	return OptionFunc(func(v interface{}) {
+		if d, ok := v.(*DefaultUser); ok {
+			d.notBefore = t
+		}
+	})
+}
+
+// WithExtensions add the given extensions to the default user extensions.
+func WithExtensions(exts map[string][]string) Option {
+	return OptionFunc(func(v interface{}) {
+		if d, ok := v.(*DefaultUser); ok {
+			for k, v := range exts {
+				d.extension(k, v...)
+			}
+		}
+	})
+}
+
+// WithEmail sets the default user email extension.
+func WithEmail(email string) Option {
+	return OptionFunc(func(v interface{}) {
+		if d, ok := v.(*DefaultUser); ok {
+			d.extension("email", email)
+		}
+	})
+}
+
+func (d *DefaultUser) extension(key string, values ...string) {
+	if d.extensions == nil {
+		d.extensions = make(map[string][]string)
+	}
+	d.extensions[key] = values
+}
+
 // NewUserInfo implements InfoConstructor and return Info object.
 // Typically called from strategies to create a new user object when its authenticated.
 func NewUserInfo(name, id string, groups []string, extensions map[string][]string) Info {
@@ -153,6 +412,9 @@ type internalUser struct {
 	ID         string              `json:"id,omitempty"`
 	Groups     []string            `json:"groups,omitempty"`
 	Extensions map[string][]string `json:"extensions,omitempty"`
+	Roles      []string            `json:"roles,omitempty"`
+	Expiry     *time.Time          `json:"expiry,omitempty"`
+	NotBefore  *time.Time          `json:"not_before,omitempty"`
 }
 
 func (i *internalUser) from(d *DefaultUser) {
@@ -160,6 +422,17 @@ func (i *internalUser) from(d *DefaultUser) {
 	i.ID = d.id
 	i.Groups = d.groups
 	i.Extensions = d.extensions
+	i.Roles = d.roles
+
+	if !d.expiry.IsZero() {
+		exp := d.expiry
+		i.Expiry = &exp
+	}
+
+	if !d.notBefore.IsZero() {
+		nbf := d.notBefore
+		i.NotBefore = &nbf
+	}
 }
 
 func (i *internalUser) to(d *DefaultUser) {
@@ -167,4 +440,16 @@ func (i *internalUser) to(d *DefaultUser) {
 	d.id = i.ID
 	d.groups = i.Groups
 	d.extensions = i.Extensions
+	d.roles = i.Roles
+	d.expiry = time.Time{}
+
+	if i.Expiry != nil {
+		d.expiry = *i.Expiry
+	}
+
+	d.notBefore = time.Time{}
+
+	if i.NotBefore != nil {
+		d.notBefore = *i.NotBefore
+	}
 }
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUser(t *testing.T) {
+	u := NewUser(
+		"test",
+		"1",
+		WithGroups("admin"),
+		WithGroups("dev"),
+		WithExtensions(map[string][]string{"k": {"v"}}),
+		WithEmail("test@example.com"),
+	)
+
+	assert.Equal(t, "test", u.UserName())
+	assert.Equal(t, "1", u.ID())
+	assert.Equal(t, []string{"admin", "dev"}, u.Groups())
+	assert.Equal(t, map[string][]string{"k": {"v"}, "email": {"test@example.com"}}, u.Extensions())
+}
+
+func TestDefaultUserReset(t *testing.T) {
+	u := NewUser("test", "1", WithGroups("admin"), WithEmail("test@example.com"))
+	u.Reset()
+
+	assert.Empty(t, u.UserName())
+	assert.Empty(t, u.ID())
+	assert.Empty(t, u.Groups())
+	assert.Empty(t, u.Extensions())
+
+	u.Init("other", "2", WithGroups("dev"))
+
+	assert.Equal(t, "other", u.UserName())
+	assert.Equal(t, "2", u.ID())
+	assert.Equal(t, []string{"dev"}, u.Groups())
+	assert.Empty(t, u.Extensions())
+}
+
+// benchUser used to prevent the compiler from optimizing away benchmarks allocations.
+var benchUser Info
+
+func BenchmarkNewDefaultUser(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ext := map[string][]string{"email": {"test@example.com"}}
+		benchUser = NewDefaultUser("test", "1", []string{"admin"}, ext)
+	}
+}
+
+func BenchmarkDefaultUserPool(b *testing.B) {
+	pool := sync.Pool{New: func() interface{} { return new(DefaultUser) }}
+	groups := WithGroups("admin")
+	email := WithEmail("test@example.com")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		u := pool.Get().(*DefaultUser).Init("test", "1", groups, email)
+		benchUser = u
+		pool.Put(u)
+	}
+}
+
+func TestDefaultUserJSON(t *testing.T) {
+	u := NewDefaultUser("test", "1", []string{"admin"}, map[string][]string{"k": {"v"}})
+
+	b, err := json.Marshal(u)
+	assert.NoError(t, err)
+
+	got := new(DefaultUser)
+	err = json.Unmarshal(b, got)
+
+	assert.NoError(t, err)
+	assert.Equal(t, u, got)
+}
+
+func TestDefaultUserRolesExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Round(0)
+	u := NewUser("test", "1", WithRoles("admin"), WithExpiry(exp))
+
+	assert.Equal(t, []string{"admin"}, UserRoles(u))
+
+	got, ok := UserExpiry(u)
+	assert.True(t, ok)
+	assert.Equal(t, exp, got)
+
+	b, err := u.MarshalBinary()
+	assert.NoError(t, err)
+
+	decoded := new(DefaultUser)
+	assert.NoError(t, decoded.UnmarshalBinary(b))
+	assert.Equal(t, []string{"admin"}, decoded.Roles())
+	assert.True(t, exp.Equal(decoded.Expiry()))
+
+	assert.True(t, SetUserRoles(u, []string{"viewer"}))
+	assert.True(t, SetUserExpiry(u, time.Time{}))
+	assert.Equal(t, []string{"viewer"}, u.Roles())
+
+	_, ok = UserExpiry(u)
+	assert.False(t, ok)
+
+	u.Reset()
+	assert.Empty(t, u.Roles())
+}
+
+func TestUserRolesExpiryUnsupported(t *testing.T) {
+	var info Info = struct{ Info }{NewDefaultUser("test", "1", nil, nil)}
+
+	assert.Nil(t, UserRoles(info))
+	assert.False(t, SetUserRoles(info, []string{"admin"}))
+	assert.False(t, SetUserExpiry(info, time.Now()))
+
+	_, ok := UserExpiry(info)
+	assert.False(t, ok)
+}
+
+func TestValidateUser(t *testing.T) {
+	now := time.Now()
+
+	table := []struct {
+		name string
+		opts []Option
+		err  error
+	}{
+		{
+			name: "it return nil when window unknown",
+		},
+		{
+			name: "it return nil when within window",
+			opts: []Option{WithNotBefore(now.Add(-time.Hour)), WithExpiry(now.Add(time.Hour))},
+		},
+		{
+			name: "it return nil when within leeway",
+			opts: []Option{WithNotBefore(now.Add(time.Second)), WithExpiry(now.Add(-time.Second))},
+		},
+		{
+			name: "it return error when not valid yet",
+			opts: []Option{WithNotBefore(now.Add(time.Hour))},
+			err:  ErrCredentialNotYetValid,
+		},
+		{
+			name: "it return error when expired",
+			opts: []Option{WithExpiry(now.Add(-time.Hour))},
+			err:  ErrCredentialExpired,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewUser("test", "1", tt.opts...)
+			assert.Equal(t, tt.err, ValidateUser(u, now, time.Minute))
+		})
+	}
+}
+
+func TestDefaultUserNotBefore(t *testing.T) {
+	nbf := time.Now().Round(0)
+	u := NewUser("test", "1")
+
+	_, ok := UserNotBefore(u)
+	assert.False(t, ok)
+
+	SetUserValidity(u, nbf, nbf.Add(time.Hour))
+
+	got, ok := UserNotBefore(u)
+	assert.True(t, ok)
+	assert.Equal(t, nbf, got)
+
+	b, err := u.MarshalJSON()
+	assert.NoError(t, err)
+
+	decoded := new(DefaultUser)
+	assert.NoError(t, decoded.UnmarshalJSON(b))
+	assert.True(t, nbf.Equal(decoded.NotBefore()))
+	assert.True(t, nbf.Add(time.Hour).Equal(decoded.Expiry()))
+
+	u.Reset()
+	assert.True(t, u.NotBefore().IsZero())
+}
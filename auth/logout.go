@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// RevokeFunc define function signature invoked by LogoutHandler,
+// to revoke application managed credentials associated with the user session,
+// such as refresh tokens, or the session itself.
+type RevokeFunc func(ctx context.Context, r *http.Request, info Info) error
+
+// TokensFunc define function signature to extract the tokens to be revoked from the logout request.
+type TokensFunc func(r *http.Request) []string
+
+type logoutHandler struct {
+	authenticator Authenticator
+	cookies       []string
+	tokens        TokensFunc
+	revokers      []RevokeFunc
+	endSession    func(r *http.Request) string
+	redirect      string
+}
+
+func (l *logoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, StatusMessage(r, http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	info := User(r)
+
+	tokens := l.tokens(r)
+
+	for _, name := range l.cookies {
+		if c, err := r.Cookie(name); err == nil && c.Value != "" {
+			tokens = append(tokens, c.Value)
+		}
+	}
+
+	for _, token := range tokens {
+		for _, s := range l.authenticator.Strategies() {
+			// strategies that does not store tokens ignored.
+			_ = Revoke(s, token, r)
+		}
+	}
+
+	// the cookies cleared regardless of the revocation result,
+	// so a failed revoker never leaves the user agent logged in.
+	failed := false
+	for _, fn := range l.revokers {
+		if err := fn(r.Context(), r, info); err != nil {
+			failed = true
+		}
+	}
+
+	for _, name := range l.cookies {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+		})
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Clear-Site-Data", `"cookies", "storage"`)
+
+	if failed {
+		http.Error(w, StatusMessage(r, http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if l.endSession != nil {
+		if u := l.endSession(r); u != "" {
+			http.Redirect(w, r, u, http.StatusSeeOther)
+			return
+		}
+	}
+
+	if l.redirect != "" {
+		http.Redirect(w, r, l.redirect, http.StatusSeeOther)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LogoutHandler return HTTP handler that logs the user out,
+// by revoking the request tokens from all the authenticator strategies stores,
+// invoking the registered revoke functions, clearing the session cookies,
+// and optionally redirecting the user agent to the upstream IdP end-session endpoint.
+// If a revoke function fails, the handler still clears the session cookies and site data,
+// then respond 500 Internal Server Error, so the client may retry the server side revocation.
+// The handler accepts only POST requests to prevent cross site logout using links,
+// and should be wrapped with Middleware so revoke functions receive the user info.
+func LogoutHandler(a Authenticator, opts ...Option) http.Handler {
+	l := &logoutHandler{
+		authenticator: a,
+		tokens:        BearerTokens,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(l)
+	}
+
+	return l
+}
+
+// BearerTokens implements TokensFunc and return the request Authorization header bearer token.
+func BearerTokens(r *http.Request) []string {
+	h := r.Header.Get("Authorization")
+	if len(h) > 7 && strings.EqualFold(h[:7], "Bearer ") {
+		return []string{strings.TrimSpace(h[7:])}
+	}
+	return nil
+}
+
+// SetLogoutCookies sets the session cookies names cleared by LogoutHandler,
+// the cookies values revoked as tokens as well.
+func SetLogoutCookies(names ...string) Option {
+	return OptionFunc(func(v interface{}) {
+		if l, ok := v.(*logoutHandler); ok {
+			l.cookies = names
+		}
+	})
+}
+
+// SetLogoutTokens sets the function used by LogoutHandler to extract tokens to be revoked,
+// Default BearerTokens.
+func SetLogoutTokens(fn TokensFunc) Option {
+	return OptionFunc(func(v interface{}) {
+		if l, ok := v.(*logoutHandler); ok {
+			l.tokens = fn
+		}
+	})
+}
+
+// SetLogoutRevoker adds a revoke function invoked by LogoutHandler,
+// e.g to revoke the user refresh tokens or delete the server side session.
+func SetLogoutRevoker(fn RevokeFunc) Option {
+	return OptionFunc(func(v interface{}) {
+		if l, ok := v.(*logoutHandler); ok {
+			l.revokers = append(l.revokers, fn)
+		}
+	})
+}
+
+// SetEndSession sets function return the upstream IdP end-session URL,
+// LogoutHandler redirect the user agent to it once logged out locally.
+func SetEndSession(fn func(r *http.Request) string) Option {
+	return OptionFunc(func(v interface{}) {
+		if l, ok := v.(*logoutHandler); ok {
+			l.endSession = fn
+		}
+	})
+}
+
+// SetLogoutRedirect sets the URL LogoutHandler redirect the user agent to,
+// when there is no end-session URL, Otherwise, it respond 204 No Content.
+func SetLogoutRedirect(url string) Option {
+	return OptionFunc(func(v interface{}) {
+		if l, ok := v.(*logoutHandler); ok {
+			l.redirect = url
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogoutHandler(t *testing.T) {
+	table := []struct {
+		name     string
+		method   string
+		opts     []Option
+		code     int
+		location string
+		revoked  []string
+		cleared  bool
+	}{
+		{
+			name:   "it reject non POST requests",
+			method: http.MethodGet,
+			code:   http.StatusMethodNotAllowed,
+		},
+		{
+			name:    "it revoke bearer token and respond no content",
+			method:  http.MethodPost,
+			code:    http.StatusNoContent,
+			revoked: []string{"token"},
+		},
+		{
+			name:     "it revoke cookies tokens and redirect",
+			method:   http.MethodPost,
+			opts:     []Option{SetLogoutCookies("session"), SetLogoutRedirect("/login")},
+			code:     http.StatusSeeOther,
+			location: "/login",
+			revoked:  []string{"token", "session-id"},
+			cleared:  true,
+		},
+		{
+			name:   "it redirect to end session url",
+			method: http.MethodPost,
+			opts: []Option{
+				SetLogoutRedirect("/login"),
+				SetEndSession(func(r *http.Request) string { return "https://idp/logout" }),
+			},
+			code:     http.StatusSeeOther,
+			location: "https://idp/logout",
+			revoked:  []string{"token"},
+		},
+		{
+			name:   "it clear cookies and respond 500 when revoke function fail",
+			method: http.MethodPost,
+			opts: []Option{
+				SetLogoutCookies("session"),
+				SetLogoutRevoker(func(ctx context.Context, r *http.Request, info Info) error {
+					return fmt.Errorf("revoke error")
+				}),
+			},
+			code:    http.StatusInternalServerError,
+			revoked: []string{"token", "session-id"},
+			cleared: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(revokeStrategy)
+			a := New()
+			a.EnableStrategy("token", s)
+			a.EnableStrategy("invalid", new(mockInvalidStrategy))
+
+			r, _ := http.NewRequest(tt.method, "/logout", nil)
+			r.Header.Set("Authorization", "Bearer token")
+			r.AddCookie(&http.Cookie{Name: "session", Value: "session-id"})
+			w := httptest.NewRecorder()
+
+			LogoutHandler(a, tt.opts...).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.location, w.Header().Get("Location"))
+			assert.Equal(t, tt.revoked, s.revoked)
+
+			if tt.cleared {
+				assert.Contains(t, w.Header().Get("Set-Cookie"), "session=;")
+				assert.Equal(t, `"cookies", "storage"`, w.Header().Get("Clear-Site-Data"))
+			}
+		})
+	}
+}
+
+type revokeStrategy struct {
+	revoked []string
+}
+
+func (s *revokeStrategy) Authenticate(ctx context.Context, r *http.Request) (Info, error) {
+	return nil, nil
+}
+
+func (s *revokeStrategy) Revoke(token string, r *http.Request) error {
+	s.revoked = append(s.revoked, token)
+	return nil
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+type memoKey struct{}
+
+type memo struct {
+	mu      *sync.Mutex
+	results map[interface{}]*memoResult
+	method  StrategyKey
+}
+
+type memoResult struct {
+	once *sync.Once
+	info Info
+	err  error
+}
+
+func (m *memo) result(key interface{}) *memoResult {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res, ok := m.results[key]
+	if !ok {
+		res = &memoResult{once: new(sync.Once)}
+		m.results[key] = res
+	}
+
+	return res
+}
+
+func (m *memo) setMethod(key StrategyKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.method = key
+}
+
+// AuthMethodFromCtx return the key of the strategy that authenticated the request,
+// The ok result reports whether it's recorded, which requires a context carrying a memoization slot,
+// See CtxWithMemo.
+func AuthMethodFromCtx(ctx context.Context) (StrategyKey, bool) {
+	m := memoFromCtx(ctx)
+	if m == nil {
+		return "", false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.method, m.method != ""
+}
+
+// AuthMethod return the key of the strategy that authenticated the request,
+// See AuthMethodFromCtx.
+func AuthMethod(r *http.Request) (StrategyKey, bool) {
+	return AuthMethodFromCtx(r.Context())
+}
+
+// CtxWithMemo return a copy of parent context that carries a memoization slot,
+// Authenticator store the authentication result in the slot,
+// so a request authenticated at most once per Authenticator,
+// even if multiple middlewares/handlers call Authenticate for the same request.
+func CtxWithMemo(ctx context.Context) context.Context {
+	if memoFromCtx(ctx) != nil {
+		return ctx
+	}
+
+	m := &memo{
+		mu:      new(sync.Mutex),
+		results: make(map[interface{}]*memoResult),
+	}
+
+	return context.WithValue(ctx, memoKey{}, m)
+}
+
+// RequestWithMemo return a shallow copy of request that carries a memoization slot.
+// See CtxWithMemo.
+func RequestWithMemo(r *http.Request) *http.Request {
+	return r.WithContext(CtxWithMemo(r.Context()))
+}
+
+// withoutMemo return a copy of parent context that masks the parent memoization slot,
+// so the request re-authenticated instead of returning the memoized result.
+func withoutMemo(ctx context.Context) context.Context {
+	if memoFromCtx(ctx) == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, memoKey{}, (*memo)(nil))
+}
+
+func memoFromCtx(ctx context.Context) *memo {
+	m, _ := ctx.Value(memoKey{}).(*memo)
+	return m
+}
+
+func memoize(ctx context.Context, key interface{}, fn func() (Info, error)) (Info, error) {
+	m := memoFromCtx(ctx)
+	if m == nil {
+		return fn()
+	}
+
+	res := m.result(key)
+	res.once.Do(func() {
+		res.info, res.err = fn()
+	})
+
+	return res.info, res.err
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemo(t *testing.T) {
+	counter := &countStrategy{}
+	a := New()
+	a.EnableStrategy("counter", counter)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	// Round #1 -- request without memo authenticated every call.
+	_, _ = a.Authenticate(r)
+	_, _ = a.Authenticate(r)
+	assert.Equal(t, 2, counter.calls)
+
+	// Round #2 -- request with memo authenticated once.
+	counter.calls = 0
+	r = RequestWithMemo(r)
+	info1, err1 := a.Authenticate(r)
+	info2, err2 := a.Authenticate(r)
+	assert.Equal(t, 1, counter.calls)
+	assert.Equal(t, info1, info2)
+	assert.Equal(t, err1, err2)
+
+	// Round #3 -- memo survive nested RequestWithMemo.
+	r = RequestWithMemo(r)
+	_, _ = a.Authenticate(r)
+	assert.Equal(t, 1, counter.calls)
+
+	// Round #4 -- memo scoped per authenticator.
+	b := New()
+	b.EnableStrategy("counter", counter)
+	_, _ = b.Authenticate(r)
+	assert.Equal(t, 2, counter.calls)
+}
+
+type countStrategy struct {
+	calls int
+}
+
+func (c *countStrategy) Authenticate(_ context.Context, _ *http.Request) (Info, error) {
+	c.calls++
+	return NewDefaultUser("test", "1", nil, nil), nil
+}
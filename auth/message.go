@@ -0,0 +1,184 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MessageMaxAttempts is the message key of the OTP max attempts reached error.
+	MessageMaxAttempts = "otp.max_attempts"
+	// MessageVerificationDisabled is the message key of the OTP verification disabled error,
+	// the message formatted with the remaining lockout duration.
+	MessageVerificationDisabled = "otp.verification_disabled"
+	// MessageSessionExpired is the message key of the session absolute lifetime reached error.
+	MessageSessionExpired = "session.expired"
+	// MessageSessionIdle is the message key of the session idle timeout reached error.
+	MessageSessionIdle = "session.idle"
+)
+
+// DefaultLanguage is the language used when the request accepted languages,
+// are not supported by the catalog.
+const DefaultLanguage = "en"
+
+// Catalog holds the user-visible messages keyed by language and message key,
+// Typically used to customize or localize the messages written to client-facing responses.
+type Catalog interface {
+	// Message return the message format of the given key in the given language,
+	// or false if not found.
+	Message(lang, key string) (string, bool)
+}
+
+// Messages implements Catalog as a map of language tag to the message formats keyed by message key.
+type Messages map[string]map[string]string
+
+// Message return the message format of the given key in the given language,
+// or false if not found.
+func (m Messages) Message(lang, key string) (string, bool) {
+	msg, ok := m[lang][key]
+	return msg, ok
+}
+
+// DefaultMessages is the built-in English messages catalog.
+var DefaultMessages = Messages{
+	DefaultLanguage: {
+		MessageMaxAttempts:          "Maximum attempts reached, account locked out",
+		MessageVerificationDisabled: "Verification disabled, try again in %s",
+		MessageSessionExpired:       "Session expired, log in again",
+		MessageSessionIdle:          "Session timed out due to inactivity, log in again",
+	},
+}
+
+// MessageCatalog is the catalog used to lookup user-visible messages,
+// Applications override it to customize or localize messages,
+// keys missing in the catalog looked up in DefaultMessages.
+// MessageCatalog must be set before serving requests.
+var MessageCatalog Catalog = DefaultMessages
+
+// LocalizableError is implemented by errors carrying a user-visible message,
+// to be written to client-facing responses using the message catalog.
+type LocalizableError interface {
+	error
+	// MessageKey return the error message key.
+	MessageKey() string
+	// MessageArgs return the error message format arguments.
+	MessageArgs() []interface{}
+}
+
+// StatusMessageKey return the message key of the given HTTP status code, e.g "status.401".
+// Status messages missing in the catalog default to http.StatusText.
+func StatusMessageKey(code int) string {
+	return "status." + strconv.Itoa(code)
+}
+
+// Message return the user-visible message of the given key and format arguments,
+// in the request most preferred language found in MessageCatalog,
+// as listed by the request Accept-Language header, falling back to DefaultLanguage.
+// The key itself returned if the message not found.
+func Message(r *http.Request, key string, args ...interface{}) string {
+	format, ok := lookupMessage(languages(r), key)
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+// StatusMessage return the user-visible message of the given HTTP status code, See Message.
+func StatusMessage(r *http.Request, code int) string {
+	return Message(r, StatusMessageKey(code))
+}
+
+// ErrorMessage return the user-visible message of the given error,
+// if err or the errors it wraps implements LocalizableError,
+// Otherwise, the message of the status code the error represents. See StatusCode.
+func ErrorMessage(r *http.Request, err error) string {
+	var le LocalizableError
+	if errors.As(err, &le) {
+		return Message(r, le.MessageKey(), le.MessageArgs()...)
+	}
+
+	return StatusMessage(r, StatusCode(err))
+}
+
+func lookupMessage(langs []string, key string) (string, bool) {
+	catalogs := []Catalog{MessageCatalog, DefaultMessages}
+
+	for _, c := range catalogs {
+		if c == nil {
+			continue
+		}
+
+		for _, lang := range langs {
+			if msg, ok := c.Message(lang, key); ok {
+				return msg, true
+			}
+		}
+	}
+
+	if strings.HasPrefix(key, "status.") {
+		code, _ := strconv.Atoi(strings.TrimPrefix(key, "status."))
+		if text := http.StatusText(code); text != "" {
+			return text, true
+		}
+	}
+
+	return "", false
+}
+
+// languages return the request accepted languages ordered by preference,
+// each language tag followed by its base language, and the default language last.
+func languages(r *http.Request) []string {
+	type lang struct {
+		tag string
+		q   float64
+	}
+
+	var accepted []lang
+
+	if r != nil {
+		for _, v := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+			parts := strings.Split(v, ";")
+			tag := strings.TrimSpace(parts[0])
+
+			if tag == "" || tag == "*" {
+				continue
+			}
+
+			q := 1.0
+			for _, p := range parts[1:] {
+				p = strings.TrimSpace(p)
+				if strings.HasPrefix(p, "q=") {
+					q, _ = strconv.ParseFloat(p[2:], 64)
+				}
+			}
+
+			if q > 0 {
+				accepted = append(accepted, lang{tag: tag, q: q})
+			}
+		}
+	}
+
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].q > accepted[j].q
+	})
+
+	tags := make([]string, 0, len(accepted)*2+1)
+
+	for _, l := range accepted {
+		tags = append(tags, l.tag)
+		if i := strings.IndexByte(l.tag, '-'); i > 0 {
+			tags = append(tags, l.tag[:i])
+		}
+	}
+
+	return append(tags, DefaultLanguage)
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lockedOutError time.Duration
+
+func (e lockedOutError) Error() string              { return "locked out" }
+func (e lockedOutError) MessageKey() string         { return MessageVerificationDisabled }
+func (e lockedOutError) MessageArgs() []interface{} { return []interface{}{time.Duration(e).String()} }
+
+func TestMessage(t *testing.T) {
+	defer func(c Catalog) { MessageCatalog = c }(MessageCatalog)
+
+	MessageCatalog = Messages{
+		"fr": {
+			StatusMessageKey(http.StatusUnauthorized): "Non autorisé",
+			MessageVerificationDisabled:               "Vérification désactivée, réessayez dans %s",
+		},
+		"en-GB": {
+			StatusMessageKey(http.StatusUnauthorized): "Not authorised",
+		},
+	}
+
+	table := []struct {
+		name     string
+		language string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{
+			name:     "it return the catalog message of the base language",
+			language: "fr-CA",
+			key:      StatusMessageKey(http.StatusUnauthorized),
+			expected: "Non autorisé",
+		},
+		{
+			name:     "it return the message of the most preferred language",
+			language: "fr;q=0.5, en-GB",
+			key:      StatusMessageKey(http.StatusUnauthorized),
+			expected: "Not authorised",
+		},
+		{
+			name:     "it format the message arguments",
+			language: "fr",
+			key:      MessageVerificationDisabled,
+			args:     []interface{}{"30s"},
+			expected: "Vérification désactivée, réessayez dans 30s",
+		},
+		{
+			name:     "it fallback to the default messages",
+			language: "de",
+			key:      MessageMaxAttempts,
+			expected: "Maximum attempts reached, account locked out",
+		},
+		{
+			name:     "it fallback to the status text",
+			language: "de",
+			key:      StatusMessageKey(http.StatusTooManyRequests),
+			expected: "Too Many Requests",
+		},
+		{
+			name:     "it return the key when message not found",
+			key:      "unknown",
+			expected: "unknown",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Accept-Language", tt.language)
+			assert.Equal(t, tt.expected, Message(r, tt.key, tt.args...))
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	err := fmt.Errorf("wrapped: %w", lockedOutError(time.Minute))
+	assert.Equal(t, "Verification disabled, try again in 1m0s", ErrorMessage(r, err))
+	assert.Equal(t, "Unauthorized", ErrorMessage(r, ErrNoMatch))
+	assert.Equal(t, "Service Unavailable", ErrorMessage(r, ErrCircuitOpen))
+
+	w := httptest.NewRecorder()
+	PlainTextErrorHandler(w, r, err)
+	assert.Equal(t, "Verification disabled, try again in 1m0s\n", w.Body.String())
+}
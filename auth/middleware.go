@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// ErrForbidden is returned by the authorization middlewares,
+// when the authenticated user not allowed to access the requested resource.
+var ErrForbidden = errors.New("authorization: Access denied")
+
+// ErrorHandler define function signature to write an HTTP error response,
+// when the authenticator failed to authenticate the request.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// Problem represents an HTTP API problem details as described in RFC 7807.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Reason is the machine-readable failure reason, See errors.ReasonOf.
+	Reason string `json:"reason,omitempty"`
+}
+
+// StatusCode return the HTTP status code that represents the given authentication error.
+// ErrRateLimited and RetryAfterError (e.g lockout errors) mapped to 429 Too Many Requests,
+// ErrCircuitOpen mapped to 503 Service Unavailable,
+// ErrForbidden mapped to 403 Forbidden, Otherwise, 401 Unauthorized.
+func StatusCode(err error) int {
+	if errors.Is(err, ErrForbidden) {
+		return http.StatusForbidden
+	}
+
+	if _, ok := RetryAfter(err); ok || errors.Is(err, ErrRateLimited) {
+		return http.StatusTooManyRequests
+	}
+
+	if errors.Is(err, ErrCircuitOpen) {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusUnauthorized
+}
+
+// ProblemJSONErrorHandler implements ErrorHandler and writes an RFC 7807 problem+json response,
+// the title is the status code message and the detail is the error message if it implements LocalizableError,
+// both looked up in the message catalog. See Message.
+// The rate limit headers set if the error carries them, See SetRateLimitHeaders,
+// and the reason member set if the error carries a failure reason, See errors.ReasonOf.
+// Other error details never written to the response, to not leak authentication internals to end users.
+func ProblemJSONErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	code := StatusCode(err)
+	p := Problem{
+		Type:   "about:blank",
+		Title:  StatusMessage(r, code),
+		Status: code,
+	}
+
+	var le LocalizableError
+	if errors.As(err, &le) {
+		p.Detail = ErrorMessage(r, le)
+	}
+
+	if reason := gerrors.ReasonOf(err); reason != gerrors.ReasonUnknown {
+		p.Reason = string(reason)
+	}
+
+	SetRateLimitHeaders(w, err)
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// PlainTextErrorHandler implements ErrorHandler and writes a plain text response using http.Error,
+// the response body is the error message looked up in the message catalog. See ErrorMessage.
+func PlainTextErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	code := StatusCode(err)
+	SetRateLimitHeaders(w, err)
+	http.Error(w, ErrorMessage(r, err), code)
+}
+
+// SetErrorHandler sets the middleware error handler.
+// Default ProblemJSONErrorHandler.
+func SetErrorHandler(h ErrorHandler) Option {
+	return OptionFunc(func(v interface{}) {
+		if m, ok := v.(*middleware); ok {
+			m.errHandler = h
+		}
+	})
+}
+
+// SetRealm sets the middleware realm,
+// used in the WWW-Authenticate challenges of 401 Unauthorized responses.
+func SetRealm(realm string) Option {
+	return OptionFunc(func(v interface{}) {
+		if m, ok := v.(*middleware); ok {
+			m.realm = realm
+		}
+	})
+}
+
+type middleware struct {
+	authenticator Authenticator
+	errHandler    ErrorHandler
+	realm         string
+}
+
+func (m *middleware) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = RequestWithMemo(r)
+		info, err := m.authenticator.Authenticate(r)
+
+		if err == ErrDisabledPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err != nil {
+			if StatusCode(err) == http.StatusUnauthorized {
+				SetWWWAuthenticate(w, m.realm, StrategiesOf(m.authenticator)...)
+			}
+			m.errHandler(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, RequestWithUser(info, r))
+	})
+}
+
+// Middleware return HTTP middleware that authenticate requests using the given authenticator,
+// and store the user info in the request context before calling next handler, See User.
+// When authentication fails the error handler invoked and next handler never called,
+// unless the request path disabled from authentication.
+// 401 Unauthorized responses carry the WWW-Authenticate challenges of the authenticator strategies,
+// See SetWWWAuthenticate and SetRealm.
+func Middleware(a Authenticator, opts ...Option) func(http.Handler) http.Handler {
+	m := &middleware{
+		authenticator: a,
+		errHandler:    ProblemJSONErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	return m.handler
+}
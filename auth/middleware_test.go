@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+func TestMiddleware(t *testing.T) {
+	table := []struct {
+		name     string
+		strategy Strategy
+		path     string
+		opts     []Option
+		code     int
+		body     string
+		ctype    string
+		www      string
+	}{
+		{
+			name:     "it call next handler with user when request authenticated",
+			strategy: strategy{id: "1"},
+			code:     http.StatusOK,
+			body:     "1",
+		},
+		{
+			name:     "it call next handler when path disabled",
+			strategy: strategy{returnErr: true},
+			path:     "/health",
+			code:     http.StatusOK,
+			body:     "anonymous",
+		},
+		{
+			name:     "it write problem json by default",
+			strategy: strategy{returnErr: true},
+			code:     http.StatusUnauthorized,
+			body:     `{"type":"about:blank","title":"Unauthorized","status":401}` + "\n",
+			ctype:    "application/problem+json",
+		},
+		{
+			name:     "it write response using custom error handler",
+			strategy: strategy{returnErr: true},
+			opts:     []Option{SetErrorHandler(PlainTextErrorHandler)},
+			code:     http.StatusUnauthorized,
+			body:     "Unauthorized\n",
+			ctype:    "text/plain; charset=utf-8",
+		},
+		{
+			name:     "it write WWW-Authenticate challenges on unauthorized",
+			strategy: challengeStrategy{strategy: strategy{returnErr: true}, challenge: "Bearer"},
+			opts:     []Option{SetRealm("api")},
+			code:     http.StatusUnauthorized,
+			body:     `{"type":"about:blank","title":"Unauthorized","status":401}` + "\n",
+			ctype:    "application/problem+json",
+			www:      `Bearer realm="api"`,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New("/health")
+			a.EnableStrategy("test", tt.strategy)
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if info := User(r); info != nil {
+					_, _ = w.Write([]byte(info.ID()))
+					return
+				}
+				_, _ = w.Write([]byte("anonymous"))
+			})
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.RequestURI = tt.path
+			w := httptest.NewRecorder()
+
+			Middleware(a, tt.opts...)(next).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+			if tt.ctype != "" {
+				assert.Equal(t, tt.ctype, w.Header().Get("Content-Type"))
+			}
+			assert.Equal(t, tt.www, w.Header().Get("WWW-Authenticate"))
+		})
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusUnauthorized, StatusCode(ErrNoMatch))
+	assert.Equal(t, http.StatusTooManyRequests, StatusCode(ErrRateLimited))
+	assert.Equal(t, http.StatusForbidden, StatusCode(ErrForbidden))
+	assert.Equal(t, http.StatusTooManyRequests, StatusCode(gerrors.MultiError{ErrNoMatch, ErrRateLimited}))
+	assert.Equal(t, http.StatusServiceUnavailable, StatusCode(ErrCircuitOpen))
+}
+
+func TestProblemJSONErrorHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	ProblemJSONErrorHandler(w, r, ErrRateLimited)
+
+	p := Problem{}
+	_ = json.Unmarshal(w.Body.Bytes(), &p)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, http.StatusTooManyRequests, p.Status)
+	assert.Equal(t, "Too Many Requests", p.Title)
+	assert.Equal(t, "rate_limited", p.Reason)
+}
+
+func TestProblemJSONErrorHandlerDetail(t *testing.T) {
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	ProblemJSONErrorHandler(w, r, lockedOutError(time.Minute))
+
+	p := Problem{}
+	_ = json.Unmarshal(w.Body.Bytes(), &p)
+
+	assert.Equal(t, http.StatusUnauthorized, p.Status)
+	assert.Equal(t, "Unauthorized", p.Title)
+	assert.Equal(t, "Verification disabled, try again in 1m0s", p.Detail)
+}
@@ -0,0 +1,93 @@
+package onetime
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// VerifiedHandler define function signature invoked by the verification handler,
+// when the subject code successfully verified, typically used to mark the email address,
+// or the phone number as verified, and redirect the user to the application.
+type VerifiedHandler func(w http.ResponseWriter, r *http.Request, subject string)
+
+// SetErrorHandler sets the verification handler error handler,
+// Default auth.PlainTextErrorHandler.
+func SetErrorHandler(h auth.ErrorHandler) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if vh, ok := v.(*verificationHandler); ok {
+			vh.errHandler = h
+		}
+	})
+}
+
+// Handler return the verification endpoint HTTP handler of the given purpose,
+// that reads the subject and code from the query of GET requests (i.e links),
+// or from the form or JSON body of POST requests, verify them, and invoke the verified handler.
+//
+// Links consumed once visited, and some email scanners prefetch links,
+// so the link URL should rather point to a page that POSTs the code on user action.
+func (m *Manager) Handler(purpose string, verified VerifiedHandler, opts ...auth.Option) http.Handler {
+	if verified == nil {
+		panic("Verified Handler required and can't be nil")
+	}
+
+	vh := &verificationHandler{
+		m:          m,
+		purpose:    purpose,
+		verified:   verified,
+		errHandler: auth.PlainTextErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(vh)
+	}
+
+	return vh
+}
+
+type verificationHandler struct {
+	m          *Manager
+	purpose    string
+	verified   VerifiedHandler
+	errHandler auth.ErrorHandler
+}
+
+func (vh *verificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var subject, code string
+
+	switch r.Method {
+	case http.MethodGet:
+		subject, code = r.URL.Query().Get("subject"), r.URL.Query().Get("code")
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+		if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/json" {
+			body := struct {
+				Subject string `json:"subject"`
+				Code    string `json:"code"`
+			}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			subject, code = body.Subject, body.Code
+		} else {
+			subject, code = r.PostFormValue("subject"), r.PostFormValue("code")
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, auth.StatusMessage(r, http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if subject == "" || code == "" {
+		vh.errHandler(w, r, ErrInvalidCode)
+		return
+	}
+
+	if err := vh.m.Verify(r, vh.purpose, subject, code); err != nil {
+		vh.errHandler(w, r, err)
+		return
+	}
+
+	vh.verified(w, r, subject)
+}
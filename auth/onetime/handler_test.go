@@ -0,0 +1,89 @@
+package onetime
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestHandler(t *testing.T) {
+	table := []struct {
+		name    string
+		request func(tk *Token) *http.Request
+		code    int
+		body    string
+	}{
+		{
+			name: "it verify link",
+			request: func(tk *Token) *http.Request {
+				r, _ := http.NewRequest("GET", tk.Link, nil)
+				return r
+			},
+			code: http.StatusOK,
+			body: "alice@example.com",
+		},
+		{
+			name: "it verify form",
+			request: func(tk *Token) *http.Request {
+				form := url.Values{"subject": {tk.Subject}, "code": {tk.Code}}
+				r, _ := http.NewRequest("POST", "/verify", strings.NewReader(form.Encode()))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return r
+			},
+			code: http.StatusOK,
+			body: "alice@example.com",
+		},
+		{
+			name: "it verify json",
+			request: func(tk *Token) *http.Request {
+				body := `{"subject":"` + tk.Subject + `","code":"` + tk.Code + `"}`
+				r, _ := http.NewRequest("POST", "/verify", strings.NewReader(body))
+				r.Header.Set("Content-Type", "application/json")
+				return r
+			},
+			code: http.StatusOK,
+			body: "alice@example.com",
+		},
+		{
+			name: "it return error when code invalid",
+			request: func(tk *Token) *http.Request {
+				r, _ := http.NewRequest("GET", "/verify?subject=alice@example.com&code=000", nil)
+				return r
+			},
+			code: http.StatusUnauthorized,
+			body: "Unauthorized\n",
+		},
+		{
+			name: "it return 405 when method not allowed",
+			request: func(tk *Token) *http.Request {
+				r, _ := http.NewRequest("PUT", "/verify", nil)
+				return r
+			},
+			code: http.StatusMethodNotAllowed,
+			body: "Method Not Allowed\n",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(store.New(0), SetLinkURL("/verify"))
+			h := m.Handler(EmailVerification, func(w http.ResponseWriter, r *http.Request, subject string) {
+				_, _ = w.Write([]byte(subject))
+			})
+
+			tk, _ := m.Issue(nil, EmailVerification, "alice@example.com")
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, tt.request(tk))
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
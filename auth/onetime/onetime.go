@@ -0,0 +1,247 @@
+// Package onetime provides short-lived single-use codes and links,
+// bound to a purpose and a subject, e.g to confirm an email address or a phone number,
+// sign in using a magic link, or reset a password.
+package onetime
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// Purposes of the single-use secrets, a secret issued for a purpose never verifies for another.
+const (
+	EmailVerification = "email_verification"
+	PhoneVerification = "phone_verification"
+	MagicLink         = "magic_link"
+	PasswordReset     = "password_reset"
+)
+
+var (
+	// ErrInvalidCode is returned by Manager,
+	// when the code does not exist, expired, or does not match.
+	ErrInvalidCode = gerrors.WithReason(
+		errors.New("onetime: Invalid or expired code"),
+		gerrors.ReasonInvalidCredentials,
+	)
+
+	// ErrTooManyAttempts is returned by Manager,
+	// when the code verification failures count reached the max attempts,
+	// the code invalidated and a new code must be issued.
+	ErrTooManyAttempts = gerrors.WithReason(
+		errors.New("onetime: Too many attempts, Request a new code"),
+		gerrors.ReasonLockedOut,
+	)
+
+	// ErrMissingDeliverFunc is returned by Manager Send,
+	// when the manager does not have a deliver function.
+	ErrMissingDeliverFunc = errors.New("onetime: Deliver function not set")
+)
+
+func init() {
+	gob.Register(&record{})
+}
+
+// Token represents an issued single-use secret, to be delivered to the subject.
+type Token struct {
+	// Purpose is the secret purpose, e.g EmailVerification.
+	Purpose string
+	// Subject is the secret owner, e.g an email address or a phone number.
+	Subject string
+	// Code is the secret the subject presents back.
+	Code string
+	// Link is the link URL carrying the subject and code, if the manager link URL set.
+	Link string
+	// ExpiresAt is the code expiration time.
+	ExpiresAt time.Time
+}
+
+// DeliverFunc define function signature to deliver the issued token to its subject,
+// e.g by sending an email or an SMS.
+type DeliverFunc func(ctx context.Context, t *Token) error
+
+type record struct {
+	Hash      []byte
+	Attempts  int
+	ExpiresAt time.Time
+}
+
+// Manager issue and verify single-use secrets.
+// Secrets kept hashed in the cache keyed by the purpose and the subject,
+// so only the most recently issued secret of a subject purpose valid,
+// the cache entries lifetime should not be less than the secrets lifetime.
+type Manager struct {
+	mu          *sync.Mutex
+	cache       store.Cache
+	ttl         time.Duration
+	length      int
+	maxAttempts int
+	linkURL     string
+	deliver     DeliverFunc
+}
+
+// New return new Manager, that issue 6 digits codes valid for 15 minutes,
+// and invalidate a code after 5 failed attempts by default.
+func New(c store.Cache, opts ...auth.Option) *Manager {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	m := &Manager{
+		mu:          new(sync.Mutex),
+		cache:       c,
+		ttl:         time.Minute * 15,
+		length:      6,
+		maxAttempts: 5,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	return m
+}
+
+// Issue issue a new single-use code for the given purpose and subject,
+// invalidating the previously issued code.
+func (m *Manager) Issue(r *http.Request, purpose, subject string) (*Token, error) {
+	code, err := m.code()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Token{
+		Purpose:   purpose,
+		Subject:   subject,
+		Code:      code,
+		ExpiresAt: time.Now().Add(m.ttl),
+	}
+
+	if m.linkURL != "" {
+		sep := "?"
+		if strings.Contains(m.linkURL, "?") {
+			sep = "&"
+		}
+
+		q := url.Values{"subject": {subject}, "code": {code}}
+		t.Link = m.linkURL + sep + q.Encode()
+	}
+
+	rec := &record{
+		Hash:      hash(code),
+		ExpiresAt: t.ExpiresAt,
+	}
+
+	if err := m.cache.Store(key(purpose, subject), rec, r); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Send issue a new single-use code for the given purpose and subject,
+// and deliver it using the manager deliver function. See SetDeliverFunc.
+func (m *Manager) Send(r *http.Request, purpose, subject string) error {
+	if m.deliver == nil {
+		return ErrMissingDeliverFunc
+	}
+
+	t, err := m.Issue(r, purpose, subject)
+	if err != nil {
+		return err
+	}
+
+	return m.deliver(r.Context(), t)
+}
+
+// Verify verify the given code of the purpose and subject, and consume it on success.
+func (m *Manager) Verify(r *http.Request, purpose, subject, code string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := key(purpose, subject)
+
+	v, ok, err := m.cache.Load(k, r)
+	if err != nil || !ok {
+		return ErrInvalidCode
+	}
+
+	rec, ok := v.(*record)
+	if !ok {
+		return gerrors.NewInvalidType((*record)(nil), v)
+	}
+
+	if time.Now().After(rec.ExpiresAt) {
+		_ = m.cache.Delete(k, r)
+		return ErrInvalidCode
+	}
+
+	if subtle.ConstantTimeCompare(rec.Hash, hash(code)) == 1 {
+		// code is single use.
+		return m.cache.Delete(k, r)
+	}
+
+	rec.Attempts++
+
+	if m.maxAttempts > 0 && rec.Attempts >= m.maxAttempts {
+		_ = m.cache.Delete(k, r)
+		return ErrTooManyAttempts
+	}
+
+	if err := m.cache.Store(k, rec, r); err != nil {
+		return err
+	}
+
+	return ErrInvalidCode
+}
+
+// Revoke invalidate the issued code of the given purpose and subject.
+func (m *Manager) Revoke(r *http.Request, purpose, subject string) error {
+	return m.cache.Delete(key(purpose, subject), r)
+}
+
+func (m *Manager) code() (string, error) {
+	if m.length <= 0 {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		return base64.RawURLEncoding.EncodeToString(b), nil
+	}
+
+	max := big.NewInt(10)
+	code := make([]byte, m.length)
+
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+
+	return string(code), nil
+}
+
+func key(purpose, subject string) string {
+	return "onetime:" + purpose + ":" + strings.ToLower(subject)
+}
+
+func hash(code string) []byte {
+	sum := sha256.Sum256([]byte(code))
+	return sum[:]
+}
@@ -0,0 +1,136 @@
+package onetime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestManager(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	table := []struct {
+		name   string
+		opts   []auth.Option
+		verify func(m *Manager, t *Token) error
+		err    error
+	}{
+		{
+			name: "it verify issued code",
+			verify: func(m *Manager, tk *Token) error {
+				return m.Verify(r, EmailVerification, "Alice@example.com", tk.Code)
+			},
+		},
+		{
+			name: "it verify code once",
+			verify: func(m *Manager, tk *Token) error {
+				_ = m.Verify(r, EmailVerification, tk.Subject, tk.Code)
+				return m.Verify(r, EmailVerification, tk.Subject, tk.Code)
+			},
+			err: ErrInvalidCode,
+		},
+		{
+			name: "it return error when purpose mismatch",
+			verify: func(m *Manager, tk *Token) error {
+				return m.Verify(r, PasswordReset, tk.Subject, tk.Code)
+			},
+			err: ErrInvalidCode,
+		},
+		{
+			name: "it return error when code expired",
+			opts: []auth.Option{SetTTL(-time.Second)},
+			verify: func(m *Manager, tk *Token) error {
+				return m.Verify(r, EmailVerification, tk.Subject, tk.Code)
+			},
+			err: ErrInvalidCode,
+		},
+		{
+			name: "it return error when code revoked",
+			verify: func(m *Manager, tk *Token) error {
+				_ = m.Revoke(r, EmailVerification, tk.Subject)
+				return m.Verify(r, EmailVerification, tk.Subject, tk.Code)
+			},
+			err: ErrInvalidCode,
+		},
+		{
+			name: "it invalidate code after max attempts",
+			opts: []auth.Option{SetMaxAttempts(2)},
+			verify: func(m *Manager, tk *Token) error {
+				assert.Equal(t, ErrInvalidCode, m.Verify(r, EmailVerification, tk.Subject, "invalid"))
+				assert.Equal(t, ErrTooManyAttempts, m.Verify(r, EmailVerification, tk.Subject, "invalid"))
+				return m.Verify(r, EmailVerification, tk.Subject, tk.Code)
+			},
+			err: ErrInvalidCode,
+		},
+		{
+			name: "it invalidate previously issued code",
+			verify: func(m *Manager, tk *Token) error {
+				_, _ = m.Issue(r, EmailVerification, tk.Subject)
+				return m.Verify(r, EmailVerification, tk.Subject, tk.Code)
+			},
+			err: ErrInvalidCode,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(store.New(0), tt.opts...)
+
+			tk, err := m.Issue(r, EmailVerification, "alice@example.com")
+			assert.NoError(t, err)
+			assert.Regexp(t, regexp.MustCompile(`^\d{6}$`), tk.Code)
+
+			err = tt.verify(m, tk)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestManagerLink(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	m := New(store.New(0), SetCodeLength(0), SetLinkURL("https://example.com/verify?lang=en"))
+
+	tk, err := m.Issue(r, MagicLink, "alice@example.com")
+	assert.NoError(t, err)
+	assert.Len(t, tk.Code, 43)
+
+	u, _ := url.Parse(tk.Link)
+	assert.Equal(t, "en", u.Query().Get("lang"))
+	assert.Equal(t, "alice@example.com", u.Query().Get("subject"))
+	assert.Equal(t, tk.Code, u.Query().Get("code"))
+}
+
+func TestManagerSend(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	err := New(store.New(0)).Send(r, PhoneVerification, "+15550100")
+	assert.Equal(t, ErrMissingDeliverFunc, err)
+
+	var delivered *Token
+
+	m := New(store.New(0), SetDeliverFunc(func(ctx context.Context, t *Token) error {
+		delivered = t
+		return nil
+	}))
+
+	err = m.Send(r, PhoneVerification, "+15550100")
+	assert.NoError(t, err)
+	assert.Equal(t, "+15550100", delivered.Subject)
+	assert.NoError(t, m.Verify(r, PhoneVerification, "+15550100", delivered.Code))
+
+	m = New(store.New(0), SetDeliverFunc(func(ctx context.Context, t *Token) error {
+		return errors.New("smtp error")
+	}))
+
+	err = m.Send(r, PhoneVerification, "+15550100")
+	assert.EqualError(t, err, "smtp error")
+}
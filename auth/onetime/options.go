@@ -0,0 +1,60 @@
+package onetime
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetTTL sets the issued codes lifetime,
+// Default 15 minutes.
+func SetTTL(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.ttl = d
+		}
+	})
+}
+
+// SetCodeLength sets the issued codes digits count,
+// a length less than 1 issue 256 bits random URL safe codes, suitable for links.
+// Default 6.
+func SetCodeLength(n int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.length = n
+		}
+	})
+}
+
+// SetMaxAttempts sets the max verification failures of a code before it's invalidated,
+// zero means unlimited attempts, which is only safe with codes issued for links.
+// Default 5.
+func SetMaxAttempts(n int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.maxAttempts = n
+		}
+	})
+}
+
+// SetLinkURL sets the URL of the verification handler,
+// used to build the issued tokens links carrying the subject and code query params.
+// No default value.
+func SetLinkURL(u string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.linkURL = u
+		}
+	})
+}
+
+// SetDeliverFunc sets the function used by Send to deliver the issued tokens,
+// No default value.
+func SetDeliverFunc(fn DeliverFunc) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.deliver = fn
+		}
+	})
+}
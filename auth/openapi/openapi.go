@@ -0,0 +1,152 @@
+// Package openapi generates OpenAPI 3 security schemes from an authenticator enabled strategies,
+// So API docs stay in sync with the actual authentication configuration.
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+const (
+	// HTTP represents OpenAPI http security scheme type.
+	HTTP = "http"
+	// APIKey represents OpenAPI apiKey security scheme type.
+	APIKey = "apiKey"
+	// OAuth2 represents OpenAPI oauth2 security scheme type.
+	OAuth2 = "oauth2"
+	// OpenIDConnect represents OpenAPI openIdConnect security scheme type.
+	OpenIDConnect = "openIdConnect"
+	// MutualTLS represents OpenAPI mutualTLS security scheme type.
+	MutualTLS = "mutualTLS"
+)
+
+// SecurityScheme represents OpenAPI 3 security scheme object.
+// See https://spec.openapis.org/oas/v3.0.3#security-scheme-object
+type SecurityScheme struct {
+	Type             string      `json:"type"`
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	In               string      `json:"in,omitempty"`
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+}
+
+// OAuthFlows represents OpenAPI 3 oauth flows object.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow represents OpenAPI 3 oauth flow object.
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+// Describer is implemented by strategies that can describe,
+// its authentication scheme as OpenAPI security scheme.
+type Describer interface {
+	SecurityScheme() SecurityScheme
+}
+
+// Override sets the security scheme of the given strategy key,
+// Typically used for strategies that can't be described from its challenge,
+// e.g api key in a custom header or query string.
+func Override(key auth.StrategyKey, s SecurityScheme) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if g, ok := v.(*generator); ok {
+			g.overrides[key] = s
+		}
+	})
+}
+
+// SetRealm sets the realm passed to strategies challenges.
+func SetRealm(realm string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if g, ok := v.(*generator); ok {
+			g.realm = realm
+		}
+	})
+}
+
+type generator struct {
+	realm     string
+	overrides map[auth.StrategyKey]SecurityScheme
+}
+
+// SecuritySchemes return the OpenAPI security schemes of the given authenticator strategies,
+// keyed by strategy key.
+// The strategy security scheme resolved by the following order, overrides,
+// the strategy or the strategy it wraps implements Describer,
+// and finally derived from strategy challenge scheme (Basic, Bearer, Digest, X.509).
+// Strategies that can't be described ignored.
+func SecuritySchemes(a auth.Authenticator, opts ...auth.Option) map[string]SecurityScheme {
+	g := &generator{
+		overrides: make(map[auth.StrategyKey]SecurityScheme),
+	}
+
+	for _, opt := range opts {
+		opt.Apply(g)
+	}
+
+	schemes := make(map[string]SecurityScheme)
+
+	for key, s := range a.Strategies() {
+		if ss, ok := g.overrides[key]; ok {
+			schemes[string(key)] = ss
+			continue
+		}
+
+		if ss, ok := describe(s, g.realm); ok {
+			schemes[string(key)] = ss
+		}
+	}
+
+	return schemes
+}
+
+// JSON return the OpenAPI components object contains the security schemes as json.
+func JSON(a auth.Authenticator, opts ...auth.Option) ([]byte, error) {
+	v := struct {
+		SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+	}{
+		SecuritySchemes: SecuritySchemes(a, opts...),
+	}
+
+	return json.Marshal(v)
+}
+
+func describe(s auth.Strategy, realm string) (SecurityScheme, bool) {
+	for c := s; c != nil; c = auth.Unwrap(c) {
+		if d, ok := c.(Describer); ok {
+			return d.SecurityScheme(), true
+		}
+	}
+
+	challenge, ok := auth.Challenge(s, realm)
+	if !ok {
+		return SecurityScheme{}, false
+	}
+
+	scheme := challenge
+	if i := strings.IndexByte(challenge, ' '); i != -1 {
+		scheme = challenge[:i]
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic", "bearer", "digest":
+		return SecurityScheme{Type: HTTP, Scheme: strings.ToLower(scheme)}, true
+	case "x.509":
+		return SecurityScheme{Type: MutualTLS}, true
+	}
+
+	return SecurityScheme{}, false
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestSecuritySchemes(t *testing.T) {
+	a := auth.New()
+	a.EnableStrategy("basic", challenger(`Basic realm="test"`))
+	a.EnableStrategy("bearer", challenger(`Bearer realm="test"`))
+	a.EnableStrategy("x509", challenger(`X.509 realm="test"`))
+	a.EnableStrategy("apikey", challenger(`ApiKey realm="test"`))
+	a.EnableStrategy("custom", challenger(`Custom realm="test"`))
+	a.EnableStrategy("describer", describer{})
+
+	got := SecuritySchemes(a, Override("apikey", SecurityScheme{Type: APIKey, In: "header", Name: "X-API-Key"}))
+
+	expected := map[string]SecurityScheme{
+		"basic":     {Type: HTTP, Scheme: "basic"},
+		"bearer":    {Type: HTTP, Scheme: "bearer"},
+		"x509":      {Type: MutualTLS},
+		"apikey":    {Type: APIKey, In: "header", Name: "X-API-Key"},
+		"describer": {Type: OpenIDConnect, OpenIDConnectURL: "https://example.com"},
+	}
+
+	assert.Equal(t, expected, got)
+}
+
+func TestJSON(t *testing.T) {
+	a := auth.New()
+	a.EnableStrategy("basic", challenger(`Basic realm="test"`))
+
+	b, err := JSON(a)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"securitySchemes":{"basic":{"type":"http","scheme":"basic"}}}`, string(b))
+}
+
+type challenger string
+
+func (c challenger) Authenticate(_ context.Context, _ *http.Request) (auth.Info, error) {
+	return nil, nil
+}
+
+func (c challenger) Challenge(string) string {
+	return string(c)
+}
+
+type describer struct{}
+
+func (describer) Authenticate(_ context.Context, _ *http.Request) (auth.Info, error) {
+	return nil, nil
+}
+
+func (describer) SecurityScheme() SecurityScheme {
+	return SecurityScheme{Type: OpenIDConnect, OpenIDConnectURL: "https://example.com"}
+}
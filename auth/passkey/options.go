@@ -0,0 +1,51 @@
+package passkey
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetOrigins sets the origins the assertions accepted from,
+// Default the https origin of the relying party id.
+func SetOrigins(origins ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if k, ok := v.(*Kit); ok {
+			k.origins = make(map[string]struct{}, len(origins))
+			for _, o := range origins {
+				k.origins[o] = struct{}{}
+			}
+		}
+	})
+}
+
+// SetTimeout sets the login ceremony timeout, challenges expire once it elapsed,
+// Default 5 minutes.
+func SetTimeout(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if k, ok := v.(*Kit); ok {
+			k.timeout = d
+		}
+	})
+}
+
+// SetUserVerification sets whether the user verification (e.g biometric or PIN) required,
+// Default preferred but not required.
+func SetUserVerification(required bool) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if k, ok := v.(*Kit); ok {
+			k.verification = required
+		}
+	})
+}
+
+// SetErrorHandler sets the finish endpoint error handler,
+// invoked when the assertion malformed or invalid.
+// Default auth.ProblemJSONErrorHandler.
+func SetErrorHandler(h auth.ErrorHandler) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if k, ok := v.(*Kit); ok {
+			k.errHandler = h
+		}
+	})
+}
@@ -0,0 +1,283 @@
+// Package passkey provides the HTTP endpoints of a WebAuthn discoverable credential (usernameless) login,
+// where the browser lets the user pick a passkey without typing a user name,
+// so a passkey login page needs only frontend work.
+//
+// The begin endpoint responds with the navigator.credentials.get() options,
+// and the finish endpoint verifies the returned assertion against the credentials registered,
+// by the application (passkeys registration not covered), and invoke the success handler,
+// e.g to establish a session, See SessionSuccessHandler.
+//
+//	k := passkey.New("example.com", credentials, cache,
+//		passkey.SessionSuccessHandler(sessions, codec, "session"),
+//	)
+//	http.HandleFunc("/passkey/begin", k.Begin)
+//	http.HandleFunc("/passkey/finish", k.Finish)
+//
+// Binary values (i.e challenge, credential id, and the assertion response fields),
+// encoded as base64 URL encoding without padding.
+package passkey
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/cookie"
+	"github.com/shaj13/go-guardian/auth/session"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// maxAssertionBody is the max finish request body size.
+const maxAssertionBody = 1 << 16
+
+var (
+	// ErrInvalidAssertion is returned by Kit,
+	// when the assertion malformed, or does not match the relying party, origin, or credential.
+	ErrInvalidAssertion = gerrors.WithReason(
+		errors.New("passkey: Invalid assertion"),
+		gerrors.ReasonInvalidCredentials,
+	)
+
+	// ErrInvalidChallenge is returned by Kit,
+	// when the assertion challenge unknown, already used, or expired.
+	ErrInvalidChallenge = gerrors.WithReason(
+		errors.New("passkey: Invalid or expired challenge"),
+		gerrors.ReasonExpired,
+	)
+
+	// ErrUnknownCredential is returned by CredentialStore,
+	// when the credential does not exist.
+	ErrUnknownCredential = gerrors.WithReason(
+		errors.New("passkey: Unknown credential"),
+		gerrors.ReasonInvalidCredentials,
+	)
+
+	// ErrClonedCredential is returned by Kit,
+	// when the authenticator signature counter did not increase, which means the authenticator cloned.
+	ErrClonedCredential = gerrors.WithReason(
+		errors.New("passkey: Signature counter did not increase, Credential may be cloned"),
+		gerrors.ReasonRevoked,
+	)
+)
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// Credential represents a passkey registered by the application.
+type Credential struct {
+	// ID is the credential id.
+	ID []byte
+	// UserHandle is the WebAuthn user handle the credential registered for.
+	UserHandle []byte
+	// PublicKey is the credential public key,
+	// *ecdsa.PublicKey (ES256), *rsa.PublicKey (RS256), or ed25519.PublicKey (EdDSA).
+	PublicKey crypto.PublicKey
+	// SignCount is the last authenticator signature counter seen.
+	SignCount uint32
+	// Info is the credential user info.
+	Info auth.Info
+}
+
+// CredentialStore load the registered credentials, and persist their signature counters.
+type CredentialStore interface {
+	// Credential return the credential of the given id, or ErrUnknownCredential.
+	Credential(ctx context.Context, id []byte) (*Credential, error)
+	// UpdateSignCount persist the credential signature counter.
+	UpdateSignCount(ctx context.Context, id []byte, count uint32) error
+}
+
+// SuccessHandler define function signature invoked by the finish endpoint,
+// when the assertion successfully verified.
+type SuccessHandler func(w http.ResponseWriter, r *http.Request, info auth.Info)
+
+// SessionSuccessHandler return SuccessHandler that create a session of the logged in user,
+// and write its id in a cookie of the given name encoded by the given codec,
+// the response is 204 No Content.
+func SessionSuccessHandler(m *session.Manager, codec *cookie.Codec, name string) SuccessHandler {
+	return func(w http.ResponseWriter, r *http.Request, info auth.Info) {
+		sess, err := m.Create(r, info)
+		if err != nil {
+			http.Error(w, auth.StatusMessage(r, http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		c, err := codec.Cookie(name, []byte(sess.ID))
+		if err != nil {
+			http.Error(w, auth.StatusMessage(r, http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		http.SetCookie(w, c)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Options represents the navigator.credentials.get() public key options.
+type Options struct {
+	Challenge        string        `json:"challenge"`
+	RPID             string        `json:"rpId"`
+	Timeout          int64         `json:"timeout"`
+	UserVerification string        `json:"userVerification"`
+	AllowCredentials []interface{} `json:"allowCredentials"`
+}
+
+// Kit serves the passkey login endpoints.
+type Kit struct {
+	mu           *sync.Mutex
+	rpID         string
+	origins      map[string]struct{}
+	creds        CredentialStore
+	cache        store.Cache
+	success      SuccessHandler
+	errHandler   auth.ErrorHandler
+	timeout      time.Duration
+	verification bool
+}
+
+// New return new Kit of the given relying party id (i.e the site domain),
+// accepting assertions of the https origin of the relying party id, See SetOrigins.
+// Challenges kept in the given cache until used or the timeout elapsed, See SetTimeout.
+func New(
+	rpID string,
+	creds CredentialStore,
+	c store.Cache,
+	success SuccessHandler,
+	opts ...auth.Option,
+) *Kit {
+	if creds == nil {
+		panic("CredentialStore object required and can't be nil")
+	}
+
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	if success == nil {
+		panic("Success Handler required and can't be nil")
+	}
+
+	k := &Kit{
+		mu:         new(sync.Mutex),
+		rpID:       rpID,
+		origins:    map[string]struct{}{"https://" + rpID: {}},
+		creds:      creds,
+		cache:      c,
+		success:    success,
+		errHandler: auth.ProblemJSONErrorHandler,
+		timeout:    time.Minute * 5,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(k)
+	}
+
+	return k
+}
+
+// Options return new login options carrying a fresh challenge,
+// without allowed credentials, so the browser offers the discoverable credentials.
+func (k *Kit) Options(r *http.Request) (*Options, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	challenge := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := k.cache.Store(challengeKey(challenge), time.Now().Add(k.timeout), r); err != nil {
+		return nil, err
+	}
+
+	uv := "preferred"
+	if k.verification {
+		uv = "required"
+	}
+
+	return &Options{
+		Challenge:        challenge,
+		RPID:             k.rpID,
+		Timeout:          int64(k.timeout / time.Millisecond),
+		UserVerification: uv,
+		AllowCredentials: []interface{}{},
+	}, nil
+}
+
+// Begin is the begin endpoint HTTP handler,
+// it responds with the login options as JSON, i.e {"publicKey": Options}.
+func (k *Kit) Begin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, auth.StatusMessage(r, http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	opts, err := k.Options(r)
+	if err != nil {
+		http.Error(w, auth.StatusMessage(r, http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(map[string]*Options{"publicKey": opts})
+}
+
+// Finish is the finish endpoint HTTP handler,
+// it reads the assertion (i.e the PublicKeyCredential) from the JSON body, verify it,
+// and invoke the success handler with the user information stored in the request context.
+func (k *Kit) Finish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, auth.StatusMessage(r, http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAssertionBody)
+
+	a := new(Assertion)
+	if err := json.NewDecoder(r.Body).Decode(a); err != nil {
+		k.errHandler(w, r, ErrInvalidAssertion)
+		return
+	}
+
+	info, err := k.Verify(r, a)
+	if err != nil {
+		k.errHandler(w, r, err)
+		return
+	}
+
+	k.success(w, auth.RequestWithUser(info, r), info)
+}
+
+// consume delete the given challenge, and report whether it was issued and not expired,
+// so each challenge used once.
+func (k *Kit) consume(r *http.Request, challenge string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	v, ok, err := k.cache.Load(challengeKey(challenge), r)
+	if err != nil || !ok {
+		return false
+	}
+
+	if err := k.cache.Delete(challengeKey(challenge), r); err != nil {
+		return false
+	}
+
+	exp, ok := v.(time.Time)
+	return ok && time.Now().Before(exp)
+}
+
+func challengeKey(challenge string) string {
+	return "passkey_challenge:" + challenge
+}
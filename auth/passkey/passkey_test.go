@@ -0,0 +1,300 @@
+package passkey
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/cookie"
+	"github.com/shaj13/go-guardian/auth/session"
+	"github.com/shaj13/go-guardian/store"
+)
+
+type memCredentials struct {
+	creds map[string]*Credential
+}
+
+func (m *memCredentials) Credential(ctx context.Context, id []byte) (*Credential, error) {
+	c, ok := m.creds[string(id)]
+	if !ok {
+		return nil, ErrUnknownCredential
+	}
+	cp := *c
+	return &cp, nil
+}
+
+func (m *memCredentials) UpdateSignCount(ctx context.Context, id []byte, count uint32) error {
+	m.creds[string(id)].SignCount = count
+	return nil
+}
+
+type authenticator struct {
+	id     []byte
+	handle []byte
+	sign   func(data []byte) []byte
+	count  uint32
+}
+
+func (a *authenticator) assert(challenge, origin, rpID string, flags byte) *Assertion {
+	enc := base64.RawURLEncoding
+	cd, _ := json.Marshal(clientData{Type: "webauthn.get", Challenge: challenge, Origin: origin})
+
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(authData[33:], a.count)
+
+	cdHash := sha256.Sum256(cd)
+	sig := a.sign(append(append([]byte(nil), authData...), cdHash[:]...))
+
+	return &Assertion{
+		ID:    enc.EncodeToString(a.id),
+		RawID: enc.EncodeToString(a.id),
+		Type:  "public-key",
+		Response: AssertionResponse{
+			ClientDataJSON:    enc.EncodeToString(cd),
+			AuthenticatorData: enc.EncodeToString(authData),
+			Signature:         enc.EncodeToString(sig),
+			UserHandle:        enc.EncodeToString(a.handle),
+		},
+	}
+}
+
+func newKit(t *testing.T, opts ...auth.Option) (*Kit, *memCredentials, *authenticator) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	a := &authenticator{
+		id:     []byte("cred-1"),
+		handle: []byte("user-1"),
+		sign: func(data []byte) []byte {
+			sum := sha256.Sum256(data)
+			r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+			assert.NoError(t, err)
+			sig, _ := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+			return sig
+		},
+	}
+
+	creds := &memCredentials{creds: map[string]*Credential{
+		"cred-1": {
+			ID:         a.id,
+			UserHandle: a.handle,
+			PublicKey:  &key.PublicKey,
+			Info:       auth.NewUserInfo("alice", "1", nil, nil),
+		},
+	}}
+
+	success := func(w http.ResponseWriter, r *http.Request, info auth.Info) {
+		w.Header().Set("X-User", info.UserName())
+	}
+
+	return New("example.com", creds, store.New(0), success, opts...), creds, a
+}
+
+func challenge(t *testing.T, k *Kit) string {
+	opts, err := k.Options(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.NoError(t, err)
+	return opts.Challenge
+}
+
+func TestKitVerify(t *testing.T) {
+	const origin = "https://example.com"
+
+	table := []struct {
+		name     string
+		mutate   func(k *Kit, a *authenticator) *Assertion
+		expected error
+	}{
+		{
+			name: "it verify valid assertion",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				return a.assert(challenge(t, k), origin, "example.com", flagUserPresent)
+			},
+		},
+		{
+			name: "it return error when challenge unknown",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				return a.assert("unknown", origin, "example.com", flagUserPresent)
+			},
+			expected: ErrInvalidChallenge,
+		},
+		{
+			name: "it return error when origin invalid",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				return a.assert(challenge(t, k), "https://evil.com", "example.com", flagUserPresent)
+			},
+			expected: ErrInvalidAssertion,
+		},
+		{
+			name: "it return error when rp id invalid",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				return a.assert(challenge(t, k), origin, "evil.com", flagUserPresent)
+			},
+			expected: ErrInvalidAssertion,
+		},
+		{
+			name: "it return error when user not present",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				return a.assert(challenge(t, k), origin, "example.com", 0)
+			},
+			expected: ErrInvalidAssertion,
+		},
+		{
+			name: "it return error when user handle does not match",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				a.handle = []byte("user-2")
+				return a.assert(challenge(t, k), origin, "example.com", flagUserPresent)
+			},
+			expected: ErrInvalidAssertion,
+		},
+		{
+			name: "it return error when signature invalid",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				as := a.assert(challenge(t, k), origin, "example.com", flagUserPresent)
+				as.Response.Signature = a.assert(challenge(t, k), origin, "example.com", 5).Response.Signature
+				return as
+			},
+			expected: ErrInvalidAssertion,
+		},
+		{
+			name: "it return error when credential unknown",
+			mutate: func(k *Kit, a *authenticator) *Assertion {
+				a.id = []byte("cred-2")
+				return a.assert(challenge(t, k), origin, "example.com", flagUserPresent)
+			},
+			expected: ErrUnknownCredential,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			k, _, a := newKit(t)
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+			info, err := k.Verify(r, tt.mutate(k, a))
+			assert.Equal(t, tt.expected, err)
+
+			if tt.expected == nil {
+				assert.Equal(t, "1", info.ID())
+			}
+		})
+	}
+}
+
+func TestKitVerifyReplay(t *testing.T) {
+	k, _, a := newKit(t)
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	as := a.assert(challenge(t, k), "https://example.com", "example.com", flagUserPresent)
+
+	_, err := k.Verify(r, as)
+	assert.NoError(t, err)
+
+	_, err = k.Verify(r, as)
+	assert.Equal(t, ErrInvalidChallenge, err)
+}
+
+func TestKitVerifySignCount(t *testing.T) {
+	k, creds, a := newKit(t)
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	a.count = 5
+	_, err := k.Verify(r, a.assert(challenge(t, k), "https://example.com", "example.com", flagUserPresent))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), creds.creds["cred-1"].SignCount)
+
+	_, err = k.Verify(r, a.assert(challenge(t, k), "https://example.com", "example.com", flagUserPresent))
+	assert.Equal(t, ErrClonedCredential, err)
+}
+
+func TestKitVerifyUserVerification(t *testing.T) {
+	k, _, a := newKit(t, SetUserVerification(true))
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	_, err := k.Verify(r, a.assert(challenge(t, k), "https://example.com", "example.com", flagUserPresent))
+	assert.Equal(t, ErrInvalidAssertion, err)
+
+	flags := byte(flagUserPresent | flagUserVerified)
+	_, err = k.Verify(r, a.assert(challenge(t, k), "https://example.com", "example.com", flags))
+	assert.NoError(t, err)
+}
+
+func TestKitEd25519(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	k, creds, a := newKit(t, SetOrigins("https://login.example.com"))
+
+	creds.creds["cred-1"].PublicKey = pub
+	a.sign = func(data []byte) []byte { return ed25519.Sign(priv, data) }
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	as := a.assert(challenge(t, k), "https://login.example.com", "example.com", flagUserPresent)
+
+	_, err := k.Verify(r, as)
+	assert.NoError(t, err)
+}
+
+func TestKitHandlers(t *testing.T) {
+	k, _, a := newKit(t)
+
+	w := httptest.NewRecorder()
+	k.Begin(w, httptest.NewRequest(http.MethodPost, "/passkey/begin", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body := struct {
+		PublicKey Options `json:"publicKey"`
+	}{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "example.com", body.PublicKey.RPID)
+	assert.Equal(t, "preferred", body.PublicKey.UserVerification)
+	assert.Empty(t, body.PublicKey.AllowCredentials)
+
+	as, _ := json.Marshal(
+		a.assert(body.PublicKey.Challenge, "https://example.com", "example.com", flagUserPresent),
+	)
+
+	w = httptest.NewRecorder()
+	k.Finish(w, httptest.NewRequest(http.MethodPost, "/passkey/finish", bytes.NewReader(as)))
+	assert.Equal(t, "alice", w.Header().Get("X-User"))
+
+	// replayed assertion.
+	w = httptest.NewRecorder()
+	k.Finish(w, httptest.NewRequest(http.MethodPost, "/passkey/finish", bytes.NewReader(as)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	k.Finish(w, httptest.NewRequest(http.MethodGet, "/passkey/finish", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestSessionSuccessHandler(t *testing.T) {
+	codec, _ := cookie.NewCodec([][]byte{bytes.Repeat([]byte("k"), 32)})
+	m := session.New(store.New(0))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	SessionSuccessHandler(m, codec, "session")(w, r, auth.NewUserInfo("alice", "1", nil, nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	c := w.Result().Cookies()
+	assert.Len(t, c, 1)
+
+	id, err := codec.Decode("session", c[0].Value)
+	assert.NoError(t, err)
+
+	info, err := m.Authenticate(r.Context(), r, string(id))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+}
@@ -0,0 +1,149 @@
+package passkey
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+
+	// authDataSize is the authenticator data min size, rpIdHash (32) + flags (1) + signCount (4).
+	authDataSize = 37
+)
+
+// Assertion represents the PublicKeyCredential returned by navigator.credentials.get(),
+// with the binary values encoded as base64 URL encoding without padding.
+type Assertion struct {
+	ID       string            `json:"id"`
+	RawID    string            `json:"rawId"`
+	Type     string            `json:"type"`
+	Response AssertionResponse `json:"response"`
+}
+
+// AssertionResponse represents the AuthenticatorAssertionResponse.
+type AssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle"`
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// Verify verifies the given assertion as described in WebAuthn section 7.2,
+// and return the user info of its credential.
+// The assertion challenge consumed, so a replayed assertion fails with ErrInvalidChallenge.
+func (k *Kit) Verify(r *http.Request, a *Assertion) (auth.Info, error) {
+	enc := base64.RawURLEncoding
+
+	rawID, err1 := enc.DecodeString(a.RawID)
+	rawClientData, err2 := enc.DecodeString(a.Response.ClientDataJSON)
+	authData, err3 := enc.DecodeString(a.Response.AuthenticatorData)
+	sig, err4 := enc.DecodeString(a.Response.Signature)
+	userHandle, err5 := enc.DecodeString(a.Response.UserHandle)
+
+	for _, err := range []error{err1, err2, err3, err4, err5} {
+		if err != nil {
+			return nil, ErrInvalidAssertion
+		}
+	}
+
+	if a.Type != "public-key" || len(rawID) == 0 || len(userHandle) == 0 || len(authData) < authDataSize {
+		return nil, ErrInvalidAssertion
+	}
+
+	cd := clientData{}
+	if err := json.Unmarshal(rawClientData, &cd); err != nil || cd.Type != "webauthn.get" {
+		return nil, ErrInvalidAssertion
+	}
+
+	if _, ok := k.origins[cd.Origin]; !ok {
+		return nil, ErrInvalidAssertion
+	}
+
+	if !k.consume(r, cd.Challenge) {
+		return nil, ErrInvalidChallenge
+	}
+
+	cred, err := k.creds.Credential(r.Context(), rawID)
+	if err != nil {
+		return nil, err
+	}
+
+	// discoverable credentials identify the user by the user handle.
+	if subtle.ConstantTimeCompare(cred.UserHandle, userHandle) != 1 {
+		return nil, ErrInvalidAssertion
+	}
+
+	rpIDHash := sha256.Sum256([]byte(k.rpID))
+	if !bytes.Equal(authData[:32], rpIDHash[:]) {
+		return nil, ErrInvalidAssertion
+	}
+
+	flags := authData[32]
+	if flags&flagUserPresent == 0 || (k.verification && flags&flagUserVerified == 0) {
+		return nil, ErrInvalidAssertion
+	}
+
+	clientDataHash := sha256.Sum256(rawClientData)
+	signed := append(append([]byte(nil), authData...), clientDataHash[:]...)
+
+	if !verifySignature(cred.PublicKey, signed, sig) {
+		return nil, ErrInvalidAssertion
+	}
+
+	count := binary.BigEndian.Uint32(authData[33:37])
+	if (count != 0 || cred.SignCount != 0) && count <= cred.SignCount {
+		return nil, ErrClonedCredential
+	}
+
+	if count != 0 {
+		if err := k.creds.UpdateSignCount(r.Context(), rawID, count); err != nil {
+			return nil, err
+		}
+	}
+
+	return cred.Info, nil
+}
+
+func verifySignature(pub crypto.PublicKey, signed, sig []byte) bool {
+	switch pub := pub.(type) {
+	case *ecdsa.PublicKey:
+		var es struct {
+			R, S *big.Int
+		}
+
+		if rest, err := asn1.Unmarshal(sig, &es); err != nil || len(rest) > 0 {
+			return false
+		}
+
+		sum := sha256.Sum256(signed)
+		return ecdsa.Verify(pub, sum[:], es.R, es.S)
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(signed)
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(pub, signed, sig)
+	}
+
+	return false
+}
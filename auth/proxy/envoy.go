@@ -0,0 +1,43 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// Envoy return HTTP handler implements the envoy external authorization HTTP service contract,
+// where envoy forwards the original request method, headers, and path prefixed by the configured path prefix.
+// The handler strip the given prefix to recover the original path before authenticating the request,
+// and respond 200 to allow the request or an error response to deny it.
+// Configure envoy allowed_upstream_headers to inject the identity headers into the upstream request,
+// and allowed_client_headers to pass WWW-Authenticate header to the client.
+//
+// Use EnvoyGRPC for the envoy grpc_service configuration.
+//
+// See https://www.envoyproxy.io/docs/envoy/latest/configuration/http/http_filters/ext_authz_filter
+func Envoy(a auth.Authenticator, prefix string, opts ...auth.Option) http.Handler {
+	prefix = "/" + strings.Trim(prefix, "/")
+	original := func(r *http.Request) *http.Request {
+		if prefix == "/" {
+			return r
+		}
+
+		req := r.Clone(r.Context())
+		req.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+		req.RequestURI = "/" + strings.TrimPrefix(strings.TrimPrefix(r.RequestURI, prefix), "/")
+		return req
+	}
+
+	opts = append([]auth.Option{setOriginal(original)}, opts...)
+	return New(a, opts...)
+}
+
+func setOriginal(fn func(r *http.Request) *http.Request) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*handler); ok {
+			h.original = fn
+		}
+	})
+}
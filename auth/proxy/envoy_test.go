@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestEnvoy(t *testing.T) {
+	a := auth.New("/health")
+	a.EnableStrategy("test", mockStrategy{})
+
+	table := []struct {
+		name   string
+		prefix string
+		path   string
+		code   int
+	}{
+		{
+			name:   "it strip prefix to recover original path",
+			prefix: "/authz",
+			path:   "/authz/health",
+			code:   http.StatusOK,
+		},
+		{
+			name: "it use path as is when prefix empty",
+			path: "/health",
+			code: http.StatusOK,
+		},
+		{
+			name:   "it deny request when authentication fail",
+			prefix: "/authz/",
+			path:   "/authz/api",
+			code:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+
+			Envoy(a, tt.prefix).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
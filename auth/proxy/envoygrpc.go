@@ -0,0 +1,310 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// EnvoyCheckMethod is the envoy gRPC ext_authz Check method path.
+const EnvoyCheckMethod = "/envoy.service.auth.v3.Authorization/Check"
+
+// gRPC status codes used by the Check handler.
+const (
+	grpcOK              = 0
+	grpcInvalidArgument = 3
+	grpcUnimplemented   = 12
+	grpcUnauthenticated = 16
+)
+
+// maxCheckRequest is the max CheckRequest message size.
+const maxCheckRequest = 4 << 20
+
+// EnvoyGRPC return HTTP handler implements the envoy gRPC external authorization service,
+// i.e envoy.service.auth.v3.Authorization/Check, decoding the CheckRequest attributes,
+// into the original request method, path, host, headers, and source address before authenticating it.
+//
+// On success the CheckResponse carries the identity headers to set on the upstream request,
+// overriding the client supplied values, Otherwise, it carries a denied response,
+// built by the error handler along with the WWW-Authenticate challenges.
+//
+// gRPC requires HTTP/2, serve the handler using an HTTP/2 server,
+// e.g http.Server with TLS, or an h2c handler for cleartext connections,
+// and configure the envoy ext_authz filter grpc_service with transport_api_version V3.
+//
+// See https://www.envoyproxy.io/docs/envoy/latest/api-v3/service/auth/v3/external_auth.proto
+func EnvoyGRPC(a auth.Authenticator, opts ...auth.Option) http.Handler {
+	return &envoyGRPC{handler: New(a, opts...).(*handler)}
+}
+
+type envoyGRPC struct {
+	*handler
+}
+
+func (h *envoyGRPC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/grpc")
+
+	if r.Method != http.MethodPost || r.URL.Path != EnvoyCheckMethod {
+		grpcStatus(w, grpcUnimplemented, "unknown method "+r.URL.Path)
+		return
+	}
+
+	msg, err := readGRPCMessage(r.Body)
+	if err != nil {
+		grpcStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+
+	req, err := checkRequest(r.Context(), msg)
+	if err != nil {
+		grpcStatus(w, grpcInvalidArgument, err.Error())
+		return
+	}
+
+	res := h.check(req)
+
+	frame := make([]byte, 5, 5+len(res))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(res)))
+
+	w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(append(frame, res...))
+	grpcStatus(w, grpcOK, "")
+}
+
+// check authenticate the given request, and return the encoded CheckResponse.
+func (h *envoyGRPC) check(r *http.Request) []byte {
+	info, err := h.authenticator.Authenticate(r)
+
+	if err == auth.ErrDisabledPath {
+		// no identity, so all the identity headers removed.
+		info, err = auth.NewUserInfo("", "", nil, nil), nil
+	}
+
+	if err != nil {
+		rec := &recorder{header: make(http.Header), code: http.StatusOK}
+		auth.SetWWWAuthenticate(rec, h.realm, auth.StrategiesOf(h.authenticator)...)
+		h.errHandler(rec, r, err)
+
+		denied := appendProtoBytes(nil, 1, appendProtoVarint(nil, 1, uint64(rec.code)))
+		for k, vv := range rec.header {
+			for _, v := range vv {
+				denied = appendProtoBytes(denied, 2, headerOption(k, v))
+			}
+		}
+		denied = appendProtoBytes(denied, 3, rec.body.Bytes())
+
+		return checkResponse(grpcUnauthenticated, 2, denied)
+	}
+
+	ok := []byte{}
+	for _, hv := range [][2]string{
+		{h.userHeader, info.UserName()},
+		{h.idHeader, info.ID()},
+		{h.groupsHeader, strings.Join(info.Groups(), ",")},
+	} {
+		switch {
+		case len(hv[0]) == 0:
+		case len(hv[1]) == 0:
+			// headers_to_remove, so a client can not supply the identity header.
+			ok = appendProtoBytes(ok, 5, []byte(hv[0]))
+		default:
+			ok = appendProtoBytes(ok, 2, headerOption(hv[0], hv[1]))
+		}
+	}
+
+	return checkResponse(grpcOK, 3, ok)
+}
+
+// checkResponse encode CheckResponse of the given status code,
+// and the given http response (denied_response 2, or ok_response 3).
+func checkResponse(code uint64, field int, res []byte) []byte {
+	status := []byte{}
+	if code != grpcOK {
+		status = appendProtoVarint(status, 1, code)
+	}
+
+	b := appendProtoBytes(nil, 1, status)
+	return appendProtoBytes(b, field, res)
+}
+
+// headerOption encode HeaderValueOption that overwrite the existing header value,
+// i.e append set to false.
+func headerOption(key, value string) []byte {
+	hv := appendProtoBytes(nil, 1, []byte(key))
+	hv = appendProtoBytes(hv, 2, []byte(value))
+	b := appendProtoBytes(nil, 1, hv)
+	return appendProtoBytes(b, 2, nil)
+}
+
+// checkRequest decode CheckRequest into the original HTTP request.
+func checkRequest(ctx context.Context, msg []byte) (*http.Request, error) {
+	var attrs, source, req, httpReq []byte
+
+	err := decodeProto(msg, func(field int, _ uint64, b []byte) error {
+		if field == 1 {
+			attrs = b
+		}
+		return nil
+	})
+
+	if err == nil {
+		err = decodeProto(attrs, func(field int, _ uint64, b []byte) error {
+			switch field {
+			case 1:
+				source = b
+			case 4:
+				req = b
+			}
+			return nil
+		})
+	}
+
+	if err == nil {
+		err = decodeProto(req, func(field int, _ uint64, b []byte) error {
+			if field == 2 {
+				httpReq = b
+			}
+			return nil
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	method, path, host := http.MethodGet, "/", ""
+	header := make(http.Header)
+
+	addHeader := func(b []byte) error {
+		k, v, err := decodeProtoPair(b)
+		if err == nil && !strings.HasPrefix(k, ":") {
+			header.Add(k, v)
+		}
+		return err
+	}
+
+	err = decodeProto(httpReq, func(field int, _ uint64, b []byte) error {
+		switch field {
+		case 2:
+			method = string(b)
+		case 3:
+			return addHeader(b)
+		case 4:
+			path = string(b)
+		case 5:
+			host = string(b)
+		case 13:
+			return decodeProto(b, func(field int, _ uint64, b []byte) error {
+				if field == 1 {
+					return addHeader(b)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Header = header
+	r.Host = host
+	r.RequestURI = path
+	r.RemoteAddr, err = sourceAddress(source)
+
+	return r, err
+}
+
+// sourceAddress decode the Peer socket address into host:port.
+func sourceAddress(peer []byte) (string, error) {
+	var addr, socket []byte
+	var host string
+	var port uint64
+
+	err := decodeProto(peer, func(field int, _ uint64, b []byte) error {
+		if field == 1 {
+			addr = b
+		}
+		return nil
+	})
+
+	if err == nil {
+		err = decodeProto(addr, func(field int, _ uint64, b []byte) error {
+			if field == 1 {
+				socket = b
+			}
+			return nil
+		})
+	}
+
+	if err == nil {
+		err = decodeProto(socket, func(field int, v uint64, b []byte) error {
+			switch field {
+			case 2:
+				host = string(b)
+			case 3:
+				port = v
+			}
+			return nil
+		})
+	}
+
+	if err != nil || len(host) == 0 {
+		return "", err
+	}
+
+	return net.JoinHostPort(host, strconv.FormatUint(port, 10)), nil
+}
+
+// readGRPCMessage read a length-prefixed uncompressed gRPC message.
+func readGRPCMessage(body io.Reader) ([]byte, error) {
+	prefix := make([]byte, 5)
+	if _, err := io.ReadFull(body, prefix); err != nil {
+		return nil, errMalformedProto
+	}
+
+	size := binary.BigEndian.Uint32(prefix[1:])
+	if prefix[0] != 0 || size > maxCheckRequest {
+		return nil, errMalformedProto
+	}
+
+	msg, err := ioutil.ReadAll(io.LimitReader(body, int64(size)))
+	if err != nil || len(msg) != int(size) {
+		return nil, errMalformedProto
+	}
+
+	return msg, nil
+}
+
+func grpcStatus(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	if len(msg) > 0 {
+		w.Header().Set("Grpc-Message", msg)
+	}
+}
+
+// recorder records the error handler denied response.
+type recorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header         { return r.header }
+func (r *recorder) Write(b []byte) (int, error) { return r.body.Write(b) }
+func (r *recorder) WriteHeader(code int)        { r.code = code }
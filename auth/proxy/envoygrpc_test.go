@@ -0,0 +1,187 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+type headerStrategy struct{}
+
+func (headerStrategy) Authenticate(_ context.Context, r *http.Request) (auth.Info, error) {
+	if r.Header.Get("Authorization") != "Bearer token" || r.RemoteAddr != "10.0.0.1:4321" {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	return auth.NewUserInfo("alice", "1", nil, nil), nil
+}
+
+func (headerStrategy) Challenge(realm string) string {
+	return `Bearer realm="` + realm + `"`
+}
+
+func checkRequestMessage(path string, headers map[string]string) []byte {
+	socket := appendProtoBytes(nil, 2, []byte("10.0.0.1"))
+	socket = appendProtoVarint(socket, 3, 4321)
+	peer := appendProtoBytes(nil, 1, appendProtoBytes(nil, 1, socket))
+
+	httpReq := appendProtoBytes(nil, 2, []byte(http.MethodGet))
+	for k, v := range headers {
+		entry := appendProtoBytes(appendProtoBytes(nil, 1, []byte(k)), 2, []byte(v))
+		httpReq = appendProtoBytes(httpReq, 3, entry)
+	}
+	httpReq = appendProtoBytes(httpReq, 4, []byte(path))
+	httpReq = appendProtoBytes(httpReq, 5, []byte("example.com"))
+
+	attrs := appendProtoBytes(nil, 1, peer)
+	attrs = appendProtoBytes(attrs, 4, appendProtoBytes(nil, 2, httpReq))
+
+	return appendProtoBytes(nil, 1, attrs)
+}
+
+type checkResult struct {
+	code    uint64
+	status  uint64
+	headers map[string]string
+	remove  []string
+}
+
+func decodeCheckResponse(t *testing.T, msg []byte) checkResult {
+	res := checkResult{headers: make(map[string]string)}
+
+	decodeHeaders := func(b []byte) error {
+		return decodeProto(b, func(field int, _ uint64, b []byte) error {
+			switch field {
+			case 1:
+				return decodeProto(b, func(field int, v uint64, _ []byte) error {
+					if field == 1 {
+						res.status = v
+					}
+					return nil
+				})
+			case 2:
+				return decodeProto(b, func(field int, _ uint64, b []byte) error {
+					if field == 1 {
+						k, v, err := decodeProtoPair(b)
+						res.headers[k] = v
+						return err
+					}
+					return nil
+				})
+			case 5:
+				res.remove = append(res.remove, string(b))
+			}
+			return nil
+		})
+	}
+
+	err := decodeProto(msg, func(field int, _ uint64, b []byte) error {
+		switch field {
+		case 1:
+			return decodeProto(b, func(field int, v uint64, _ []byte) error {
+				if field == 1 {
+					res.code = v
+				}
+				return nil
+			})
+		case 2, 3:
+			return decodeHeaders(b)
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	return res
+}
+
+func TestEnvoyGRPC(t *testing.T) {
+	a := auth.New("/health")
+	a.EnableStrategy("test", headerStrategy{})
+	h := EnvoyGRPC(a, SetRealm("test"))
+
+	table := []struct {
+		name     string
+		path     string
+		headers  map[string]string
+		expected checkResult
+	}{
+		{
+			name:    "it allow request and set identity headers",
+			path:    "/api?page=1",
+			headers: map[string]string{"authorization": "Bearer token", ":authority": "example.com"},
+			expected: checkResult{
+				headers: map[string]string{DefaultUserHeader: "alice", DefaultIDHeader: "1"},
+				remove:  []string{DefaultGroupsHeader},
+			},
+		},
+		{
+			name: "it allow disabled path and remove identity headers",
+			path: "/health",
+			expected: checkResult{
+				headers: map[string]string{},
+				remove:  []string{DefaultUserHeader, DefaultIDHeader, DefaultGroupsHeader},
+			},
+		},
+		{
+			name:    "it deny request when authentication fail",
+			path:    "/api",
+			headers: map[string]string{"authorization": "Bearer invalid"},
+			expected: checkResult{
+				code:   grpcUnauthenticated,
+				status: http.StatusUnauthorized,
+				headers: map[string]string{
+					"Www-Authenticate":       `Bearer realm="test"`,
+					"Content-Type":           "text/plain; charset=utf-8",
+					"X-Content-Type-Options": "nosniff",
+				},
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := checkRequestMessage(tt.path, tt.headers)
+			frame := make([]byte, 5)
+			binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+
+			r := httptest.NewRequest(http.MethodPost, EnvoyCheckMethod, bytes.NewReader(append(frame, msg...)))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			res := w.Result()
+			body := w.Body.Bytes()
+
+			assert.Equal(t, "application/grpc", res.Header.Get("Content-Type"))
+			assert.Equal(t, "0", res.Trailer.Get("Grpc-Status"))
+			assert.Equal(t, int(binary.BigEndian.Uint32(body[1:5])), len(body)-5)
+
+			got := decodeCheckResponse(t, body[5:])
+			for k := range got.headers {
+				if _, ok := tt.expected.headers[k]; !ok {
+					delete(got.headers, k)
+				}
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestEnvoyGRPCInvalidRequest(t *testing.T) {
+	h := EnvoyGRPC(auth.New())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/unknown", nil))
+	assert.Equal(t, "12", w.Header().Get("Grpc-Status"))
+
+	w = httptest.NewRecorder()
+	body := bytes.NewReader([]byte{0, 0, 0, 0, 9, 1})
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, EnvoyCheckMethod, body))
+	assert.Equal(t, "3", w.Header().Get("Grpc-Status"))
+}
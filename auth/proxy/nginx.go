@@ -0,0 +1,65 @@
+package proxy
+
+import (
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// NGINX return HTTP handler tuned for the nginx auth_request module subrequests,
+// where the subrequest carries no body and the original request URI and method,
+// passed in X-Original-URI and X-Original-Method headers, e.g:
+//
+//	location = /auth {
+//		internal;
+//		proxy_pass              http://guardian/auth;
+//		proxy_pass_request_body off;
+//		proxy_set_header        Content-Length "";
+//		proxy_set_header        X-Original-URI $request_uri;
+//		proxy_set_header        X-Original-Method $request_method;
+//		proxy_set_header        X-Real-IP $remote_addr;
+//	}
+//
+// The handler respond 204 with the identity headers on success,
+// to be captured using auth_request_set (e.g $upstream_http_x_auth_user),
+// Otherwise, 401 since nginx treats any status code other than 2xx, 401, and 403 as an error.
+//
+// See https://nginx.org/en/docs/http/ngx_http_auth_request_module.html
+func NGINX(a auth.Authenticator, opts ...auth.Option) http.Handler {
+	opts = append([]auth.Option{
+		setOriginal(original),
+		setCode(http.StatusNoContent),
+		SetErrorHandler(nginxErrorHandler),
+	}, opts...)
+	return New(a, opts...)
+}
+
+func original(r *http.Request) *http.Request {
+	req := forwarded(r)
+
+	if m := r.Header.Get("X-Original-Method"); len(m) > 0 {
+		req.Method = m
+	}
+
+	if uri := r.Header.Get("X-Original-URI"); len(uri) > 0 {
+		setURI(req, uri)
+	}
+
+	if ip := r.Header.Get("X-Real-IP"); len(ip) > 0 {
+		req.RemoteAddr = ip
+	}
+
+	return req
+}
+
+func nginxErrorHandler(w http.ResponseWriter, _ *http.Request, _ error) {
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+func setCode(code int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*handler); ok {
+			h.code = code
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestNGINX(t *testing.T) {
+	var got *http.Request
+
+	a := auth.New()
+	a.EnableStrategy("test", recordStrategy(func(r *http.Request) { got = r }))
+
+	r := httptest.NewRequest("GET", "/auth", nil)
+	r.Header.Set("X-Original-Method", "DELETE")
+	r.Header.Set("X-Original-URI", "/api/users/1")
+	r.Header.Set("X-Real-IP", "10.0.0.1")
+	w := httptest.NewRecorder()
+
+	NGINX(a).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "test", w.Header().Get(DefaultUserHeader))
+	assert.Equal(t, "DELETE", got.Method)
+	assert.Equal(t, "/api/users/1", got.URL.Path)
+	assert.Equal(t, "10.0.0.1", got.RemoteAddr)
+}
+
+func TestNGINXErrorStatus(t *testing.T) {
+	a := auth.New()
+	a.EnableStrategy("test", auth.Decorate(
+		mockStrategy{},
+		auth.RateLimit(limiter(false)),
+	))
+
+	r := httptest.NewRequest("GET", "/auth", nil)
+	w := httptest.NewRecorder()
+
+	NGINX(a).ServeHTTP(w, r)
+
+	// nginx treats 429 as an error, so it must be mapped to 401.
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+type limiter bool
+
+func (l limiter) Allow() bool { return bool(l) }
@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetUserHeader sets the response header name carries the authenticated user name.
+// Empty header name disable it.
+func SetUserHeader(header string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*handler); ok {
+			h.userHeader = header
+		}
+	})
+}
+
+// SetIDHeader sets the response header name carries the authenticated user id.
+// Empty header name disable it.
+func SetIDHeader(header string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*handler); ok {
+			h.idHeader = header
+		}
+	})
+}
+
+// SetGroupsHeader sets the response header name carries the authenticated user groups.
+// Empty header name disable it.
+func SetGroupsHeader(header string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if h, ok := v.(*handler); ok {
+			h.groupsHeader = header
+		}
+	})
+}
+
+// SetRealm sets the realm used in WWW-Authenticate challenges.
+func SetRealm(realm string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		switch h := v.(type) {
+		case *handler:
+			h.realm = realm
+		case *phantom:
+			h.realm = realm
+		}
+	})
+}
+
+// SetErrorHandler sets the handler error handler.
+// Default auth.PlainTextErrorHandler.
+func SetErrorHandler(eh auth.ErrorHandler) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		switch h := v.(type) {
+		case *handler:
+			h.errHandler = eh
+		case *phantom:
+			h.errHandler = eh
+		}
+	})
+}
+
+// SetStripHeaders sets the request headers removed by the phantom handler before forwarding the request,
+// e.g the header carrying the opaque token when authenticated using token.XHeaderParser.
+func SetStripHeaders(headers ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*phantom); ok {
+			p.headers = append(p.headers, headers...)
+		}
+	})
+}
+
+// SetStripCookies sets the request cookies removed by the phantom handler before forwarding the request,
+// e.g the cookie carrying the opaque token when authenticated using token.CookieParser.
+func SetStripCookies(names ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*phantom); ok {
+			for _, n := range names {
+				p.cookies[n] = struct{}{}
+			}
+		}
+	})
+}
+
+// SetStripQuery sets the query parameters removed by the phantom handler before forwarding the request,
+// e.g the parameter carrying the opaque token when authenticated using token.QueryParser.
+func SetStripQuery(keys ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*phantom); ok {
+			p.query = append(p.query, keys...)
+		}
+	})
+}
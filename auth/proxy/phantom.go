@@ -0,0 +1,133 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/jwt"
+)
+
+// Phantom return HTTP handler implements the phantom token pattern,
+// where the edge authenticate opaque tokens using the given authenticator,
+// (e.g a cached token or introspection strategy), and forward the request to the next handler,
+// (e.g httputil.ReverseProxy) with the Authorization header replaced by a short-lived JWT,
+// carrying the authenticated user info, except the internal extensions (See auth.IsInternalExtension),
+// and signed using the given SecretsKeeper.
+// So the opaque tokens never leave the edge, and internal services authenticate
+// the forwarded requests offline using jwt.New with the same keys.
+//
+// The JWT valid for 1 minute by default, jwt.SetExpDuration, jwt.SetIssuer,
+// and jwt.SetAudience options also applies.
+// Requests to disabled paths forwarded without the Authorization header.
+//
+// Only the Authorization header replaced, opaque tokens read from other headers, cookies,
+// or query parameters, e.g token.XHeaderParser, token.CookieParser, or token.QueryParser,
+// still reach the next handler, unless stripped using SetStripHeaders, SetStripCookies,
+// and SetStripQuery options.
+func Phantom(a auth.Authenticator, s jwt.SecretsKeeper, next http.Handler, opts ...auth.Option) http.Handler {
+	p := &phantom{
+		authenticator: a,
+		keeper:        s,
+		next:          next,
+		errHandler:    auth.PlainTextErrorHandler,
+		opts:          append([]auth.Option{jwt.SetExpDuration(time.Minute)}, opts...),
+		cookies:       make(map[string]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt.Apply(p)
+	}
+
+	return p
+}
+
+type phantom struct {
+	authenticator auth.Authenticator
+	keeper        jwt.SecretsKeeper
+	next          http.Handler
+	errHandler    auth.ErrorHandler
+	realm         string
+	opts          []auth.Option
+	headers       []string
+	cookies       map[string]struct{}
+	query         []string
+}
+
+func (p *phantom) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	info, err := p.authenticator.Authenticate(r)
+
+	if err == auth.ErrDisabledPath {
+		req := p.strip(r)
+		req.Header.Del("Authorization")
+		p.next.ServeHTTP(w, req)
+		return
+	}
+
+	if err != nil {
+		auth.SetWWWAuthenticate(w, p.realm, auth.StrategiesOf(p.authenticator)...)
+		p.errHandler(w, r, err)
+		return
+	}
+
+	tk, err := jwt.IssueAccessToken(claimsInfo(info), p.keeper, p.opts...)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	req := p.strip(r)
+	req.Header.Set("Authorization", "Bearer "+tk)
+	p.next.ServeHTTP(w, auth.RequestWithUser(info, req))
+}
+
+// claimsInfo return a copy of the given info without the internal extensions,
+// e.g the basic strategy cached password hash, so they never reach the JWT read by internal services.
+func claimsInfo(info auth.Info) auth.Info {
+	ext := make(map[string][]string, len(info.Extensions()))
+	for k, v := range info.Extensions() {
+		if !auth.IsInternalExtension(k) {
+			ext[k] = v
+		}
+	}
+
+	c := auth.NewUserInfo(info.UserName(), info.ID(), info.Groups(), ext)
+	auth.SetUserRoles(c, auth.UserRoles(info))
+
+	return c
+}
+
+// strip return a clone of the given request without the configured credentials.
+func (p *phantom) strip(r *http.Request) *http.Request {
+	req := r.Clone(r.Context())
+
+	for _, h := range p.headers {
+		req.Header.Del(h)
+	}
+
+	if len(p.cookies) > 0 && len(req.Header["Cookie"]) > 0 {
+		kept := []string{}
+		for _, c := range req.Cookies() {
+			if _, ok := p.cookies[c.Name]; !ok {
+				kept = append(kept, c.String())
+			}
+		}
+
+		req.Header.Del("Cookie")
+		if len(kept) > 0 {
+			req.Header.Set("Cookie", strings.Join(kept, "; "))
+		}
+	}
+
+	if len(p.query) > 0 && len(req.URL.RawQuery) > 0 {
+		q := req.URL.Query()
+		for _, k := range p.query {
+			q.Del(k)
+		}
+		req.URL.RawQuery = q.Encode()
+		req.RequestURI = req.URL.RequestURI()
+	}
+
+	return req
+}
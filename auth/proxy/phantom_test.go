@@ -0,0 +1,137 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestPhantom(t *testing.T) {
+	keeper := jwt.StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	internal := jwt.New(store.New(0), keeper, jwt.SetAudience("internal"))
+
+	table := []struct {
+		name string
+		info auth.Info
+		path string
+		code int
+		user string
+	}{
+		{
+			name: "it forward request with jwt",
+			info: auth.NewUserInfo("alice", "1", []string{"admin"}, nil),
+			path: "/api",
+			code: http.StatusOK,
+			user: "alice",
+		},
+		{
+			name: "it deny request when authentication fail",
+			path: "/api",
+			code: http.StatusUnauthorized,
+		},
+		{
+			name: "it forward disabled path without authorization",
+			path: "/health",
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			a := auth.New("/health")
+			a.EnableStrategy("test", mockStrategy{info: tt.info})
+
+			user := ""
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.user == "" {
+					assert.Empty(t, r.Header.Get("Authorization"))
+					return
+				}
+
+				info, err := internal.Authenticate(r.Context(), r)
+				assert.NoError(t, err)
+				user = info.UserName()
+			})
+
+			r := httptest.NewRequest("GET", tt.path, nil)
+			r.Header.Set("Authorization", "Bearer opaque")
+			w := httptest.NewRecorder()
+
+			Phantom(a, keeper, next, jwt.SetAudience("internal"), SetRealm("test")).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.user, user)
+		})
+	}
+}
+
+func TestPhantomStrip(t *testing.T) {
+	keeper := jwt.StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+
+	for _, path := range []string{"/api", "/health"} {
+		t.Run(path, func(t *testing.T) {
+			a := auth.New("/health")
+			a.EnableStrategy("test", mockStrategy{info: auth.NewUserInfo("alice", "1", nil, nil)})
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Empty(t, r.Header.Get("X-Api-Token"))
+				assert.Equal(t, "keep", r.Header.Get("X-Request-Id"))
+				assert.Equal(t, "theme=dark", r.Header.Get("Cookie"))
+				assert.Equal(t, "page=1", r.URL.RawQuery)
+				assert.Equal(t, path+"?page=1", r.RequestURI)
+			})
+
+			r := httptest.NewRequest("GET", path+"?access_token=opaque&page=1", nil)
+			r.Header.Set("X-Api-Token", "opaque")
+			r.Header.Set("X-Request-Id", "keep")
+			r.Header.Set("Cookie", "session=opaque; theme=dark")
+			w := httptest.NewRecorder()
+
+			Phantom(
+				a, keeper, next,
+				SetStripHeaders("X-Api-Token"),
+				SetStripCookies("session"),
+				SetStripQuery("access_token"),
+			).ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			// the incoming request not mutated.
+			assert.Equal(t, "opaque", r.Header.Get("X-Api-Token"))
+		})
+	}
+}
+
+func TestPhantomInternalExtensions(t *testing.T) {
+	keeper := jwt.StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	internal := jwt.New(store.New(0), keeper)
+
+	info := auth.NewUserInfo("alice", "1", nil, map[string][]string{
+		"tenant":                       {"acme"},
+		"x-go-guardian-basic-password": {"hash"},
+	})
+	auth.SetUserRoles(info, []string{"admin"})
+
+	a := auth.New()
+	a.EnableStrategy("test", mockStrategy{info: info})
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, err := internal.Authenticate(r.Context(), r)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{"tenant": {"acme"}}, got.Extensions())
+		assert.Equal(t, []string{"admin"}, auth.UserRoles(got))
+	})
+
+	r := httptest.NewRequest("GET", "/api", nil)
+	w := httptest.NewRecorder()
+	Phantom(a, keeper, next).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// the authenticated info not mutated.
+	assert.Equal(t, []string{"hash"}, info.Extensions()["x-go-guardian-basic-password"])
+}
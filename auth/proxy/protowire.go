@@ -0,0 +1,104 @@
+package proxy
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// errMalformedProto is returned when a protocol buffers message malformed.
+var errMalformedProto = errors.New("proxy: Malformed protocol buffers message")
+
+// protocol buffers wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// decodeProto invoke fn with each field of the given protocol buffers message,
+// v holds the varint values, and b holds the length delimited values,
+// fixed size values skipped since the decoded messages does not use them.
+func decodeProto(msg []byte, fn func(field int, v uint64, b []byte) error) error {
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return errMalformedProto
+		}
+
+		msg = msg[n:]
+		field := int(tag >> 3)
+
+		var v uint64
+		var b []byte
+
+		switch tag & 7 {
+		case wireVarint:
+			v, n = binary.Uvarint(msg)
+			if n <= 0 {
+				return errMalformedProto
+			}
+			msg = msg[n:]
+		case wireBytes:
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < l {
+				return errMalformedProto
+			}
+			b, msg = msg[n:n+int(l)], msg[n+int(l):]
+		case wireFixed64:
+			if len(msg) < 8 {
+				return errMalformedProto
+			}
+			msg = msg[8:]
+			continue
+		case wireFixed32:
+			if len(msg) < 4 {
+				return errMalformedProto
+			}
+			msg = msg[4:]
+			continue
+		default:
+			return errMalformedProto
+		}
+
+		if err := fn(field, v, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeProtoPair decode a key/value message, e.g a map entry or an envoy HeaderValue.
+func decodeProtoPair(msg []byte) (key, value string, err error) {
+	err = decodeProto(msg, func(field int, _ uint64, b []byte) error {
+		switch field {
+		case 1:
+			key = string(b)
+		case 2, 3:
+			// 3 is the HeaderValue raw_value, set when envoy encodes raw headers.
+			if len(value) == 0 {
+				value = string(b)
+			}
+		}
+		return nil
+	})
+	return
+}
+
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	b = appendUvarint(b, uint64(field)<<3|wireVarint)
+	return appendUvarint(b, v)
+}
+
+func appendProtoBytes(b []byte, field int, v []byte) []byte {
+	b = appendUvarint(b, uint64(field)<<3|wireBytes)
+	b = appendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
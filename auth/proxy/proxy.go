@@ -0,0 +1,89 @@
+// Package proxy provides HTTP handlers that authenticate requests on behalf of,
+// reverse proxies, API gateways and service meshes (e.g Envoy, Traefik, NGINX),
+// So go-guardian can centralize authentication in front of other services.
+//
+// The handlers run the authenticator against the forwarded request,
+// and respond 2xx with identity headers on success, Otherwise, an error response with challenges.
+// Phantom instead forwards the authenticated requests itself, carrying a short-lived JWT.
+package proxy
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+const (
+	// DefaultUserHeader is the default response header carries the authenticated user name.
+	DefaultUserHeader = "X-Auth-User"
+	// DefaultIDHeader is the default response header carries the authenticated user id.
+	DefaultIDHeader = "X-Auth-User-Id"
+	// DefaultGroupsHeader is the default response header carries the authenticated user groups,
+	// as comma separated values.
+	DefaultGroupsHeader = "X-Auth-Groups"
+)
+
+type handler struct {
+	authenticator auth.Authenticator
+	errHandler    auth.ErrorHandler
+	userHeader    string
+	idHeader      string
+	groupsHeader  string
+	realm         string
+	code          int
+	original      func(r *http.Request) *http.Request
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := h.original(r)
+	info, err := h.authenticator.Authenticate(req)
+
+	if err == auth.ErrDisabledPath {
+		w.WriteHeader(h.code)
+		return
+	}
+
+	if err != nil {
+		auth.SetWWWAuthenticate(w, h.realm, auth.StrategiesOf(h.authenticator)...)
+		h.errHandler(w, r, err)
+		return
+	}
+
+	if len(h.userHeader) > 0 {
+		w.Header().Set(h.userHeader, info.UserName())
+	}
+
+	if len(h.idHeader) > 0 {
+		w.Header().Set(h.idHeader, info.ID())
+	}
+
+	if len(h.groupsHeader) > 0 && len(info.Groups()) > 0 {
+		w.Header().Set(h.groupsHeader, strings.Join(info.Groups(), ","))
+	}
+
+	w.WriteHeader(h.code)
+}
+
+// New return HTTP handler that authenticate the received request as is using the given authenticator,
+// Typically used with auth-subrequest based proxies, where the original request headers forwarded.
+// On success the handler respond 200 with the identity headers,
+// Otherwise, set WWW-Authenticate header from the authenticator strategies challenges,
+// and invoke the error handler.
+func New(a auth.Authenticator, opts ...auth.Option) http.Handler {
+	h := &handler{
+		authenticator: a,
+		errHandler:    auth.PlainTextErrorHandler,
+		userHeader:    DefaultUserHeader,
+		idHeader:      DefaultIDHeader,
+		groupsHeader:  DefaultGroupsHeader,
+		code:          http.StatusOK,
+		original:      func(r *http.Request) *http.Request { return r },
+	}
+
+	for _, opt := range opts {
+		opt.Apply(h)
+	}
+
+	return h
+}
@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestNew(t *testing.T) {
+	table := []struct {
+		name    string
+		info    auth.Info
+		opts    []auth.Option
+		path    string
+		code    int
+		headers map[string]string
+	}{
+		{
+			name: "it respond 401 with challenges when authentication fail",
+			code: http.StatusUnauthorized,
+			headers: map[string]string{
+				"WWW-Authenticate": `Bearer realm="test"`,
+				DefaultUserHeader:  "",
+			},
+		},
+		{
+			name: "it respond 200 when path disabled",
+			path: "/health",
+			code: http.StatusOK,
+		},
+		{
+			name: "it respond 200 with identity headers when authentication succeed",
+			info: auth.NewDefaultUser("test", "1", []string{"admin", "dev"}, nil),
+			code: http.StatusOK,
+			headers: map[string]string{
+				DefaultUserHeader:   "test",
+				DefaultIDHeader:     "1",
+				DefaultGroupsHeader: "admin,dev",
+			},
+		},
+		{
+			name: "it respond with custom identity headers",
+			info: auth.NewDefaultUser("test", "1", []string{"admin"}, nil),
+			opts: []auth.Option{
+				SetUserHeader("X-User"),
+				SetIDHeader(""),
+				SetGroupsHeader("X-Groups"),
+			},
+			code: http.StatusOK,
+			headers: map[string]string{
+				"X-User":        "test",
+				"X-Groups":      "admin",
+				DefaultIDHeader: "",
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			a := auth.New("/health")
+			a.EnableStrategy("test", mockStrategy{info: tt.info})
+
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.path != "" {
+				r.RequestURI = tt.path
+			}
+			w := httptest.NewRecorder()
+
+			opts := append([]auth.Option{SetRealm("test")}, tt.opts...)
+			New(a, opts...).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			for k, v := range tt.headers {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+type mockStrategy struct {
+	info auth.Info
+}
+
+func (m mockStrategy) Authenticate(_ context.Context, _ *http.Request) (auth.Info, error) {
+	if m.info == nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	return m.info, nil
+}
+
+func (m mockStrategy) Challenge(realm string) string {
+	return `Bearer realm="` + realm + `"`
+}
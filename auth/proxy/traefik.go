@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// Traefik return HTTP handler implements the traefik ForwardAuth middleware contract,
+// where traefik forwards the original request headers along with,
+// X-Forwarded-Method, X-Forwarded-Proto, X-Forwarded-Host, X-Forwarded-Uri, and X-Forwarded-For headers.
+// The handler reconstruct the original request from the forwarded headers before authenticating it,
+// and respond 200 with the identity headers on success, Otherwise, 401 with challenges.
+// Configure traefik authResponseHeaders to copy the identity headers to the upstream request.
+//
+// See https://doc.traefik.io/traefik/middlewares/http/forwardauth/
+func Traefik(a auth.Authenticator, opts ...auth.Option) http.Handler {
+	opts = append([]auth.Option{setOriginal(forwarded)}, opts...)
+	return New(a, opts...)
+}
+
+func forwarded(r *http.Request) *http.Request {
+	req := r.Clone(r.Context())
+
+	if m := r.Header.Get("X-Forwarded-Method"); len(m) > 0 {
+		req.Method = m
+	}
+
+	if h := r.Header.Get("X-Forwarded-Host"); len(h) > 0 {
+		req.Host = h
+		req.URL.Host = h
+	}
+
+	if p := r.Header.Get("X-Forwarded-Proto"); len(p) > 0 {
+		req.URL.Scheme = p
+	}
+
+	if uri := r.Header.Get("X-Forwarded-Uri"); len(uri) > 0 {
+		setURI(req, uri)
+	}
+
+	if ff := r.Header.Get("X-Forwarded-For"); len(ff) > 0 {
+		req.RemoteAddr = strings.TrimSpace(strings.Split(ff, ",")[0])
+	}
+
+	return req
+}
+
+func setURI(req *http.Request, uri string) {
+	u, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return
+	}
+
+	req.RequestURI = uri
+	req.URL.Path = u.Path
+	req.URL.RawPath = u.RawPath
+	req.URL.RawQuery = u.RawQuery
+}
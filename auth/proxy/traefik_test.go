@@ -0,0 +1,51 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestTraefik(t *testing.T) {
+	var got *http.Request
+
+	a := auth.New("/health")
+	a.EnableStrategy("test", recordStrategy(func(r *http.Request) { got = r }))
+
+	// Round #1 -- original request disabled path.
+	r := httptest.NewRequest("GET", "/auth", nil)
+	r.Header.Set("X-Forwarded-Uri", "/health")
+	w := httptest.NewRecorder()
+	Traefik(a).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, got)
+
+	// Round #2 -- original request reconstructed.
+	r = httptest.NewRequest("GET", "/auth", nil)
+	r.Header.Set("X-Forwarded-Method", "POST")
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Host", "example.com")
+	r.Header.Set("X-Forwarded-Uri", "/api/users?id=1")
+	r.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	w = httptest.NewRecorder()
+	Traefik(a).ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "test", w.Header().Get(DefaultUserHeader))
+	assert.Equal(t, "POST", got.Method)
+	assert.Equal(t, "https://example.com/api/users?id=1", got.URL.String())
+	assert.Equal(t, "/api/users?id=1", got.RequestURI)
+	assert.Equal(t, "10.0.0.1", got.RemoteAddr)
+}
+
+type recordStrategy func(r *http.Request)
+
+func (fn recordStrategy) Authenticate(_ context.Context, r *http.Request) (auth.Info, error) {
+	fn(r)
+	return auth.NewDefaultUser("test", "1", nil, nil), nil
+}
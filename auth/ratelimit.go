@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// RetryAfterError is implemented by errors of requests rejected for a period of time,
+// (e.g rate limit and lockout errors), they mapped to 429 Too Many Requests,
+// along with the Retry-After header, so clients back off correctly, See SetRateLimitHeaders.
+type RetryAfterError interface {
+	error
+	// RetryAfter return the duration after which the request may be retried.
+	RetryAfter() time.Duration
+}
+
+// RateLimitInfo represents a rate limit quota state,
+// exposed to clients through the draft RateLimit-* headers.
+type RateLimitInfo struct {
+	// Limit is the requests quota of the limit window.
+	Limit int
+	// Remaining is the remaining requests quota of the current window.
+	Remaining int
+	// Reset is the duration until the quota resets.
+	Reset time.Duration
+}
+
+// RateLimitReporter is implemented by limiters that report their quota state,
+// the RateLimit decorator reject requests with RateLimitError carrying the state,
+// Otherwise, ErrRateLimited.
+type RateLimitReporter interface {
+	RateLimit() RateLimitInfo
+}
+
+// RateLimitError is returned by RateLimit decorated strategy,
+// when the request exceed the rate limit of a limiter that implements RateLimitReporter.
+// RateLimitError matches ErrRateLimited, so errors.Is(err, ErrRateLimited) reports true.
+type RateLimitError struct {
+	RateLimitInfo
+}
+
+func (e *RateLimitError) Error() string { return ErrRateLimited.Error() }
+
+// Is reports whether target is ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool { return target == ErrRateLimited }
+
+// Reason return the error failure reason, errors.ReasonRateLimited.
+func (e *RateLimitError) Reason() gerrors.Reason { return gerrors.ReasonRateLimited }
+
+// RetryAfter return the duration until the quota resets.
+func (e *RateLimitError) RetryAfter() time.Duration { return e.Reset }
+
+// RetryAfter return the duration after which the request rejected with the given error may be retried.
+// The ok result indicates whether the error implements RetryAfterError.
+func RetryAfter(err error) (time.Duration, bool) {
+	var re RetryAfterError
+	if errors.As(err, &re) {
+		return re.RetryAfter(), true
+	}
+	return 0, false
+}
+
+// SetRateLimitHeaders sets the Retry-After header, and the draft RateLimit-Limit, RateLimit-Remaining,
+// and RateLimit-Reset headers, if the given error carries them, See RetryAfterError and RateLimitError.
+// The error handlers of the package call it,
+// custom error handlers should call it before writing the response status.
+func SetRateLimitHeaders(w http.ResponseWriter, err error) {
+	d, ok := RetryAfter(err)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Retry-After", seconds(d))
+
+	var re *RateLimitError
+	if !errors.As(err, &re) {
+		return
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(re.Limit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(re.Remaining))
+	w.Header().Set("RateLimit-Reset", seconds(re.Reset))
+}
+
+// seconds return the given duration in seconds rounded up, so clients never retry too early.
+func seconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return strconv.FormatInt(int64((d+time.Second-1)/time.Second), 10)
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+func TestRateLimitReporter(t *testing.T) {
+	l := &mockReporter{mockLimiter: mockLimiter{allow: 0}}
+	s := Decorate(strategy{id: "1"}, RateLimit(l))
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	_, err := s.Authenticate(r.Context(), r)
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	assert.Equal(t, ErrRateLimited.Error(), err.Error())
+
+	d, ok := RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second*30, d)
+}
+
+func TestRetryAfter(t *testing.T) {
+	table := []struct {
+		name     string
+		err      error
+		expected time.Duration
+		ok       bool
+	}{
+		{
+			name: "it return false when error does not implement RetryAfterError",
+			err:  ErrRateLimited,
+		},
+		{
+			name:     "it return retry after of wrapped error",
+			err:      fmt.Errorf("wrapped: %w", retryError(time.Minute)),
+			expected: time.Minute,
+			ok:       true,
+		},
+		{
+			name:     "it return retry after of multi error",
+			err:      gerrors.MultiError{ErrNoMatch, retryError(time.Second)},
+			expected: time.Second,
+			ok:       true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := RetryAfter(tt.err)
+			assert.Equal(t, tt.expected, d)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestSetRateLimitHeaders(t *testing.T) {
+	table := []struct {
+		name     string
+		err      error
+		expected http.Header
+	}{
+		{
+			name:     "it set no headers when error does not carry retry after",
+			err:      ErrRateLimited,
+			expected: http.Header{},
+		},
+		{
+			name: "it set retry after rounded up",
+			err:  retryError(time.Millisecond * 1500),
+			expected: http.Header{
+				"Retry-After": {"2"},
+			},
+		},
+		{
+			name: "it set rate limit headers",
+			err:  &RateLimitError{RateLimitInfo{Limit: 10, Remaining: 0, Reset: time.Second * 30}},
+			expected: http.Header{
+				"Retry-After":         {"30"},
+				"Ratelimit-Limit":     {"10"},
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {"30"},
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			SetRateLimitHeaders(w, tt.err)
+			assert.Equal(t, tt.expected, w.Header())
+		})
+	}
+}
+
+func TestErrorHandlersRetryAfter(t *testing.T) {
+	for _, h := range []ErrorHandler{ProblemJSONErrorHandler, PlainTextErrorHandler} {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/", nil)
+
+		h(w, r, gerrors.MultiError{ErrNoMatch, retryError(time.Minute)})
+
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get("Retry-After"))
+	}
+}
+
+type retryError time.Duration
+
+func (e retryError) Error() string             { return "locked out" }
+func (e retryError) RetryAfter() time.Duration { return time.Duration(e) }
+
+type mockReporter struct {
+	mockLimiter
+}
+
+func (m *mockReporter) RateLimit() RateLimitInfo {
+	return RateLimitInfo{Limit: 10, Reset: time.Second * 30}
+}
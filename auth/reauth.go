@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+var (
+	// ErrCredentialExpired is returned by Reauthenticate and Authenticator,
+	// when the credential the user information originated from expired.
+	ErrCredentialExpired = gerrors.WithReason(errors.New("reauth: Credential expired"), gerrors.ReasonExpired)
+
+	// ErrIdentityChanged is returned by Reauthenticate,
+	// when the re-validated credential resolves to a different user.
+	ErrIdentityChanged = errors.New("reauth: Credential identity changed")
+)
+
+// RevalidateFunc define function signature to re-validate the credential,
+// a user information originated from, and return the fresh user information.
+type RevalidateFunc func(ctx context.Context) (Info, error)
+
+// TokenRevalidator return RevalidateFunc that re-validate the given token using the strategy,
+// See AuthenticateToken.
+func TokenRevalidator(s Strategy, token string) RevalidateFunc {
+	return func(ctx context.Context) (Info, error) {
+		return AuthenticateToken(ctx, s, token)
+	}
+}
+
+// RequestRevalidator return RevalidateFunc that re-authenticate the originating request,
+// using the authenticator, Typically the request that upgraded to a WebSocket or opened a stream.
+// The request re-authenticated even if it carries a memoization slot, See RequestWithMemo.
+func RequestRevalidator(a Authenticator, r *http.Request) RevalidateFunc {
+	return func(ctx context.Context) (Info, error) {
+		return a.Authenticate(r.Clone(withoutMemo(ctx)))
+	}
+}
+
+// SetExpiresAt sets the time the credential expires at,
+// once reached Reauthenticate signal ErrCredentialExpired, even if the credential still re-validates.
+func SetExpiresAt(t time.Time) Option {
+	return OptionFunc(func(v interface{}) {
+		if r, ok := v.(*reauth); ok {
+			r.expiresAt = t
+		}
+	})
+}
+
+// Reauthenticate periodically re-validate the credential the given user information originated from,
+// for long-lived connections (e.g WebSocket or gRPC streams) that authenticated once at start.
+// The returned channel receive a single error and closed, once the re-validation fails,
+// the credential expired, or it resolves to a different user ID, to signal the application
+// to terminate the connection. The channel closed without an error once ctx done.
+// Reauthenticate panics if interval is not greater than zero.
+func Reauthenticate(
+	ctx context.Context,
+	info Info,
+	fn RevalidateFunc,
+	interval time.Duration,
+	opts ...Option,
+) <-chan error {
+	if interval <= 0 {
+		panic("Reauthenticate interval must be greater than zero")
+	}
+
+	r := &reauth{
+		info:     info,
+		fn:       fn,
+		interval: interval,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(r)
+	}
+
+	c := make(chan error, 1)
+
+	go func() {
+		defer close(c)
+		if err := r.run(ctx); err != nil {
+			c <- err
+		}
+	}()
+
+	return c
+}
+
+type reauth struct {
+	info      Info
+	fn        RevalidateFunc
+	interval  time.Duration
+	expiresAt time.Time
+}
+
+func (r *reauth) run(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	var expired <-chan time.Time
+	if !r.expiresAt.IsZero() {
+		timer := time.NewTimer(time.Until(r.expiresAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-expired:
+			return ErrCredentialExpired
+		case <-ticker.C:
+			if err := r.revalidate(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *reauth) revalidate(ctx context.Context) error {
+	info, err := r.fn(ctx)
+	if err != nil {
+		// connection closed while re-validating.
+		if ctx.Err() != nil {
+			return nil
+		}
+		return err
+	}
+
+	if r.info != nil && info != nil && info.ID() != r.info.ID() {
+		return ErrIdentityChanged
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReauthenticate(t *testing.T) {
+	errRevoked := errors.New("revoked")
+
+	table := []struct {
+		name     string
+		fn       RevalidateFunc
+		opts     []Option
+		expected error
+	}{
+		{
+			name:     "it signal error when revalidation fails",
+			fn:       func(context.Context) (Info, error) { return nil, errRevoked },
+			expected: errRevoked,
+		},
+		{
+			name:     "it signal error when identity changed",
+			fn:       func(context.Context) (Info, error) { return NewUser("test", "2"), nil },
+			expected: ErrIdentityChanged,
+		},
+		{
+			name:     "it signal error when credential expired",
+			fn:       func(context.Context) (Info, error) { return NewUser("test", "1"), nil },
+			opts:     []Option{SetExpiresAt(time.Now().Add(time.Millisecond * 20))},
+			expected: ErrCredentialExpired,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			c := Reauthenticate(ctx, NewUser("test", "1"), tt.fn, time.Millisecond*5, tt.opts...)
+
+			select {
+			case err := <-c:
+				assert.Equal(t, tt.expected, err)
+			case <-time.After(time.Second):
+				t.Fatal("expected re-authentication error")
+			}
+
+			_, ok := <-c
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestReauthenticateContextDone(t *testing.T) {
+	var calls int32
+	fn := func(context.Context) (Info, error) {
+		atomic.AddInt32(&calls, 1)
+		return NewUser("test", "1"), nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := Reauthenticate(ctx, NewUser("test", "1"), fn, time.Millisecond)
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) > 2 }, time.Second, time.Millisecond)
+	cancel()
+
+	err, ok := <-c
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRevalidators(t *testing.T) {
+	s := tokenStrategy{strategy: strategy{id: "1"}, token: "token"}
+
+	info, err := TokenRevalidator(s, "token")(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+
+	a := New()
+	a.EnableStrategy("test", s)
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	info, err = RequestRevalidator(a, r)(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+}
+
+type revokedStrategy struct {
+	calls int32
+}
+
+func (s *revokedStrategy) Authenticate(_ context.Context, _ *http.Request) (Info, error) {
+	// valid only once, then revoked.
+	if atomic.AddInt32(&s.calls, 1) > 1 {
+		return nil, errors.New("revoked")
+	}
+	return NewUser("test", "1"), nil
+}
+
+func TestRequestRevalidatorMiddleware(t *testing.T) {
+	s := new(revokedStrategy)
+	a := New()
+	a.EnableStrategy("test", s)
+
+	var err error
+	h := Middleware(a)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+		defer cancel()
+		err = <-Reauthenticate(ctx, User(r), RequestRevalidator(a, r), time.Millisecond)
+	}))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Error(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&s.calls))
+}
+
+func TestReauthenticateInvalidInterval(t *testing.T) {
+	fn := func(context.Context) (Info, error) { return nil, nil }
+	assert.Panics(t, func() { Reauthenticate(context.Background(), nil, fn, 0) })
+}
@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal/singleflight"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ErrMissingTenant is returned by Registry,
+// when failed to extract tenant id from the request.
+var ErrMissingTenant = errors.New("registry: Request missing tenant id")
+
+// TenantFunc define function signature to extract tenant id from HTTP request.
+type TenantFunc func(r *http.Request) (string, error)
+
+// AuthenticatorFactory define function signature to create a new Authenticator for the given tenant.
+// Strategies caches can be shared between tenants by capturing them within the factory.
+type AuthenticatorFactory func(tenant string) (Authenticator, error)
+
+// HeaderTenant return TenantFunc, where tenant id extracted from the given HTTP header.
+func HeaderTenant(header string) TenantFunc {
+	return func(r *http.Request) (string, error) {
+		v := strings.TrimSpace(r.Header.Get(header))
+		if v == "" {
+			return "", ErrMissingTenant
+		}
+		return v, nil
+	}
+}
+
+// SubdomainTenant return TenantFunc, where tenant id is the first label of request host.
+// e.g tenant.example.com return tenant.
+func SubdomainTenant() TenantFunc {
+	return func(r *http.Request) (string, error) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		labels := strings.Split(host, ".")
+		if len(labels) < 3 || labels[0] == "" || net.ParseIP(host) != nil {
+			return "", ErrMissingTenant
+		}
+
+		return labels[0], nil
+	}
+}
+
+// Registry manages independent Authenticator instance per tenant,
+// The tenant Authenticator created lazily using the factory on first lookup,
+// and kept in the registry cache, so the authenticators lifecycle and eviction
+// is driven by the cache mechanism (e.g LRU max entries or FIFO TTL).
+// Evicted authenticators closed to release their background resources, See Authenticator.Close.
+//
+// Registry safe for concurrent usage, as long as the factory and cache are.
+type Registry struct {
+	flight  *singleflight.Group
+	factory AuthenticatorFactory
+	tenant  TenantFunc
+	cache   store.Cache
+	// hooked reports whether the cache closes the authenticators it evicts.
+	hooked bool
+}
+
+// NewRegistry return new Registry.
+// if cache nil, the registry keeps tenants authenticators until they evicted explicitly.
+// The OnEvicted callback of store.LRU and store.FIFO caches wrapped to close the evicted authenticators,
+// Therefore, the cache must not be used before passed to the registry.
+func NewRegistry(f AuthenticatorFactory, t TenantFunc, c store.Cache) *Registry {
+	if f == nil {
+		panic("Authenticator Factory required and can't be nil")
+	}
+
+	if t == nil {
+		panic("Tenant Function required and can't be nil")
+	}
+
+	if c == nil {
+		c = store.New(0)
+	}
+
+	r := &Registry{
+		flight:  new(singleflight.Group),
+		factory: f,
+		tenant:  t,
+		cache:   c,
+	}
+
+	switch c := c.(type) {
+	case *store.LRU:
+		c.OnEvicted = closeOnEvicted(c.OnEvicted)
+		r.hooked = true
+	case *store.FIFO:
+		c.OnEvicted = closeOnEvicted(c.OnEvicted)
+		r.hooked = true
+	}
+
+	return r
+}
+
+// Authenticator return tenant Authenticator, and create one using the factory if it's not exist.
+// Concurrent lookups of the same tenant share a single factory invocation,
+// while lookups of other tenants never wait for it.
+func (r *Registry) Authenticator(tenant string) (Authenticator, error) {
+	if a, ok, err := r.load(tenant); ok || err != nil {
+		return a, err
+	}
+
+	v, err, _ := r.flight.Do(tenant, func() (interface{}, error) {
+		if a, ok, err := r.load(tenant); ok || err != nil {
+			return a, err
+		}
+
+		a, err := r.factory(tenant)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.cache.Store(tenant, a, nil); err != nil {
+			_ = a.Close()
+			return nil, err
+		}
+
+		return a, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(Authenticator), nil
+}
+
+func (r *Registry) load(tenant string) (Authenticator, bool, error) {
+	v, ok, err := r.cache.Load(tenant, nil)
+	if err != nil && err != store.ErrCachedExp {
+		return nil, false, err
+	}
+
+	if !ok || err != nil {
+		return nil, false, nil
+	}
+
+	a, ok := v.(Authenticator)
+	if !ok {
+		return nil, false, gerrors.NewInvalidType((*Authenticator)(nil), v)
+	}
+
+	return a, true, nil
+}
+
+// Authenticate extract tenant id from request,
+// and dispatch the request to the tenant Authenticator.
+func (r *Registry) Authenticate(req *http.Request) (Info, error) {
+	tenant, err := r.tenant(req)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := r.Authenticator(tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Authenticate(req)
+}
+
+// Tenant return the tenant id of the given request.
+func (r *Registry) Tenant(req *http.Request) (string, error) {
+	return r.tenant(req)
+}
+
+// Evict remove tenant Authenticator from the registry and close it,
+// the next lookup for the tenant create a new one using the factory.
+func (r *Registry) Evict(tenant string) error {
+	if r.hooked {
+		return r.cache.Delete(tenant, nil)
+	}
+
+	v, _, _ := r.cache.Load(tenant, nil)
+
+	if err := r.cache.Delete(tenant, nil); err != nil {
+		return err
+	}
+
+	if a, ok := v.(Authenticator); ok {
+		return a.Close()
+	}
+
+	return nil
+}
+
+// Close evict and close the tenants authenticators,
+// and close the cache if it owns background resources.
+// The registry must not be used once closed.
+func (r *Registry) Close() error {
+	errs := gerrors.MultiError{}
+
+	for _, tenant := range r.cache.Keys() {
+		if err := r.Evict(tenant); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if c, ok := r.cache.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Tenants return the ids of the tenants that have a live Authenticator.
+func (r *Registry) Tenants() []string {
+	return r.cache.Keys()
+}
+
+func closeOnEvicted(next store.OnEvicted) store.OnEvicted {
+	return func(key string, v interface{}) {
+		if a, ok := v.(Authenticator); ok {
+			_ = a.Close()
+		}
+
+		if next != nil {
+			next(key, v)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestRegistry(t *testing.T) {
+	calls := map[string]int{}
+	factory := func(tenant string) (Authenticator, error) {
+		calls[tenant]++
+		if tenant == "error" {
+			return nil, fmt.Errorf("factory error")
+		}
+		a := New()
+		a.EnableStrategy("test", strategy{id: tenant})
+		return a, nil
+	}
+
+	registry := NewRegistry(factory, HeaderTenant("X-Tenant"), nil)
+
+	table := []struct {
+		name        string
+		tenant      string
+		expectedErr bool
+	}{
+		{
+			name:        "it return error when tenant missing",
+			expectedErr: true,
+		},
+		{
+			name:        "it return error when factory return error",
+			tenant:      "error",
+			expectedErr: true,
+		},
+		{
+			name:   "it authenticate request using tenant authenticator",
+			tenant: "tenant1",
+		},
+		{
+			name:   "it reuse tenant authenticator",
+			tenant: "tenant1",
+		},
+		{
+			name:   "it create authenticator per tenant",
+			tenant: "tenant2",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("X-Tenant", tt.tenant)
+
+			info, err := registry.Authenticate(r)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.tenant, info.ID())
+			assert.Equal(t, 1, calls[tt.tenant])
+		})
+	}
+
+	assert.ElementsMatch(t, []string{"tenant1", "tenant2"}, registry.Tenants())
+
+	_ = registry.Evict("tenant1")
+	_, _ = registry.Authenticator("tenant1")
+	assert.Equal(t, 2, calls["tenant1"])
+}
+
+func TestRegistryCacheEviction(t *testing.T) {
+	registry := NewRegistry(
+		func(tenant string) (Authenticator, error) { return New(), nil },
+		HeaderTenant("X-Tenant"),
+		store.New(1),
+	)
+
+	_, _ = registry.Authenticator("tenant1")
+	_, _ = registry.Authenticator("tenant2")
+
+	assert.Equal(t, []string{"tenant2"}, registry.Tenants())
+}
+
+func TestRegistryClose(t *testing.T) {
+	table := []struct {
+		name  string
+		cache store.Cache
+	}{
+		{
+			name:  "it close authenticators evicted by lru cache",
+			cache: store.New(2),
+		},
+		{
+			name:  "it close authenticators evicted by custom cache",
+			cache: struct{ store.Cache }{store.New(2)},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			closers := map[string]*mockCloser{}
+			factory := func(tenant string) (Authenticator, error) {
+				closers[tenant] = new(mockCloser)
+				a := New()
+				a.EnableStrategy("test", closers[tenant])
+				return a, nil
+			}
+
+			registry := NewRegistry(factory, HeaderTenant("X-Tenant"), tt.cache)
+
+			_, _ = registry.Authenticator("tenant1")
+			_, _ = registry.Authenticator("tenant2")
+
+			err := registry.Evict("tenant1")
+			assert.NoError(t, err)
+			assert.True(t, closers["tenant1"].closed)
+			assert.False(t, closers["tenant2"].closed)
+
+			err = registry.Close()
+			assert.NoError(t, err)
+			assert.True(t, closers["tenant2"].closed)
+			assert.Empty(t, registry.Tenants())
+		})
+	}
+}
+
+func TestRegistryCacheEvictionClose(t *testing.T) {
+	closers := map[string]*mockCloser{}
+	factory := func(tenant string) (Authenticator, error) {
+		closers[tenant] = new(mockCloser)
+		a := New()
+		a.EnableStrategy("test", closers[tenant])
+		return a, nil
+	}
+
+	registry := NewRegistry(factory, HeaderTenant("X-Tenant"), store.New(1))
+
+	_, _ = registry.Authenticator("tenant1")
+	_, _ = registry.Authenticator("tenant2")
+
+	assert.True(t, closers["tenant1"].closed)
+	assert.False(t, closers["tenant2"].closed)
+}
+
+func TestRegistrySlowFactory(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	factory := func(tenant string) (Authenticator, error) {
+		if tenant == "slow" {
+			close(started)
+			<-release
+		}
+		return New(), nil
+	}
+
+	registry := NewRegistry(factory, HeaderTenant("X-Tenant"), nil)
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = registry.Authenticator("slow")
+		close(done)
+	}()
+	<-started
+
+	fast := make(chan error)
+	go func() {
+		_, err := registry.Authenticator("fast")
+		fast <- err
+	}()
+
+	select {
+	case err := <-fast:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("slow tenant factory blocked other tenants lookups")
+	}
+
+	close(release)
+	<-done
+}
+
+func TestSubdomainTenant(t *testing.T) {
+	table := []struct {
+		host        string
+		tenant      string
+		expectedErr bool
+	}{
+		{host: "tenant.example.com", tenant: "tenant"},
+		{host: "tenant.example.com:8080", tenant: "tenant"},
+		{host: "example.com", expectedErr: true},
+		{host: "localhost:8080", expectedErr: true},
+		{host: "10.0.0.1:8080", expectedErr: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.host, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Host = tt.host
+
+			tenant, err := SubdomainTenant()(r)
+
+			assert.Equal(t, tt.expectedErr, err != nil)
+			assert.Equal(t, tt.tenant, tenant)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/textproto"
+)
+
+// Request represents the transport agnostic view of an authentication request,
+// exposing only the data strategies consume, so the core is decoupled from one HTTP stack.
+// Use HTTPRequest to obtain *http.Request accepted by strategies and Authenticator,
+// or AuthenticateRequest to authenticate it directly.
+type Request interface {
+	// Context return the request context.
+	Context() context.Context
+	// Header return the request headers (or metadata) keyed by canonical header key.
+	Header() http.Header
+	// Cookie return the named cookie provided in the request or http.ErrNoCookie if not found.
+	Cookie(name string) (*http.Cookie, error)
+	// TLS return the request TLS connection state or nil if the connection not over TLS.
+	TLS() *tls.ConnectionState
+	// RemoteAddr return the network address of the request sender.
+	RemoteAddr() string
+}
+
+// FastHTTPHeader is implemented by fasthttp RequestHeader,
+// and allow adapting fasthttp requests without importing fasthttp.
+type FastHTTPHeader interface {
+	VisitAll(f func(key, value []byte))
+}
+
+// NetHTTPRequest return Request adapter of the given net/http request.
+func NetHTTPRequest(r *http.Request) Request {
+	return netHTTPRequest{r}
+}
+
+// MetadataRequest return Request adapter of gRPC incoming metadata,
+// md typically metadata.MD, addr and state typically obtained from the peer within ctx.
+// Metadata keys canonicalized, e.g "authorization" accessible as "Authorization".
+func MetadataRequest(
+	ctx context.Context,
+	md map[string][]string,
+	addr net.Addr,
+	state *tls.ConnectionState,
+) Request {
+	h := make(http.Header, len(md))
+	for k, v := range md {
+		key := textproto.CanonicalMIMEHeaderKey(k)
+		h[key] = append(h[key], v...)
+	}
+
+	return newRequest(ctx, h, addr, state)
+}
+
+// FastHTTPRequest return Request adapter of fasthttp request,
+// h typically &ctx.Request.Header, addr and state typically ctx.RemoteAddr() and ctx.TLSConnectionState().
+func FastHTTPRequest(
+	ctx context.Context,
+	h FastHTTPHeader,
+	addr net.Addr,
+	state *tls.ConnectionState,
+) Request {
+	header := make(http.Header)
+	h.VisitAll(func(key, value []byte) {
+		header.Add(string(key), string(value))
+	})
+
+	return newRequest(ctx, header, addr, state)
+}
+
+// RequestStrategy is implemented by strategies consuming the transport agnostic Request directly,
+// e.g basic, token, and jwt strategies.
+type RequestStrategy interface {
+	Strategy
+	// AuthenticateRequest authenticate the given request and return user info or an appropriate error.
+	AuthenticateRequest(ctx context.Context, r Request) (Info, error)
+}
+
+// AuthenticateRequest authenticate the given transport agnostic request using the authenticator.
+func AuthenticateRequest(a Authenticator, r Request) (Info, error) {
+	return a.Authenticate(HTTPRequest(r))
+}
+
+// AuthenticateWith authenticate the given transport agnostic request using the strategy,
+// directly if the strategy implements RequestStrategy, Otherwise, via HTTPRequest.
+func AuthenticateWith(s Strategy, r Request) (Info, error) {
+	if rs, ok := s.(RequestStrategy); ok {
+		return rs.AuthenticateRequest(r.Context(), r)
+	}
+
+	return s.Authenticate(r.Context(), HTTPRequest(r))
+}
+
+// HTTPRequest return *http.Request carrying the given request data,
+// to be passed to Authenticator or strategies.
+// If the given request adapts a net/http request, the underlying request returned.
+func HTTPRequest(r Request) *http.Request {
+	if n, ok := r.(netHTTPRequest); ok {
+		return n.r
+	}
+
+	req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, "/", nil)
+	req.Header = r.Header()
+	req.TLS = r.TLS()
+	req.RemoteAddr = r.RemoteAddr()
+
+	return req
+}
+
+type netHTTPRequest struct {
+	r *http.Request
+}
+
+func (n netHTTPRequest) Context() context.Context                 { return n.r.Context() }
+func (n netHTTPRequest) Header() http.Header                      { return n.r.Header }
+func (n netHTTPRequest) Cookie(name string) (*http.Cookie, error) { return n.r.Cookie(name) }
+func (n netHTTPRequest) TLS() *tls.ConnectionState                { return n.r.TLS }
+func (n netHTTPRequest) RemoteAddr() string                       { return n.r.RemoteAddr }
+
+type request struct {
+	ctx    context.Context
+	header http.Header
+	addr   string
+	state  *tls.ConnectionState
+}
+
+func newRequest(ctx context.Context, h http.Header, addr net.Addr, state *tls.ConnectionState) *request {
+	r := &request{
+		ctx:    ctx,
+		header: h,
+		state:  state,
+	}
+
+	if addr != nil {
+		r.addr = addr.String()
+	}
+
+	return r
+}
+
+func (r *request) Context() context.Context  { return r.ctx }
+func (r *request) Header() http.Header       { return r.header }
+func (r *request) TLS() *tls.ConnectionState { return r.state }
+func (r *request) RemoteAddr() string        { return r.addr }
+
+func (r *request) Cookie(name string) (*http.Cookie, error) {
+	req := http.Request{Header: r.header}
+	return req.Cookie(name)
+}
@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fastHTTPHeader map[string]string
+
+func (f fastHTTPHeader) VisitAll(fn func(key, value []byte)) {
+	for k, v := range f {
+		fn([]byte(k), []byte(v))
+	}
+}
+
+func TestRequestAdapters(t *testing.T) {
+	type ctxKey struct{}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080}
+	state := &tls.ConnectionState{}
+
+	r, _ := http.NewRequestWithContext(ctx, "GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer token")
+	r.Header.Set("Cookie", "session=abc")
+	r.RemoteAddr = addr.String()
+	r.TLS = state
+
+	table := []struct {
+		name string
+		req  Request
+	}{
+		{
+			name: "net/http",
+			req:  NetHTTPRequest(r),
+		},
+		{
+			name: "gRPC metadata",
+			req: MetadataRequest(ctx, map[string][]string{
+				"authorization": {"Bearer token"},
+				"cookie":        {"session=abc"},
+			}, addr, state),
+		},
+		{
+			name: "fasthttp",
+			req: FastHTTPRequest(ctx, fastHTTPHeader{
+				"authorization": "Bearer token",
+				"Cookie":        "session=abc",
+			}, addr, state),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, "value", tt.req.Context().Value(ctxKey{}))
+			assert.Equal(t, "Bearer token", tt.req.Header().Get("Authorization"))
+			assert.Equal(t, "127.0.0.1:8080", tt.req.RemoteAddr())
+			assert.Equal(t, state, tt.req.TLS())
+
+			c, err := tt.req.Cookie("session")
+			assert.NoError(t, err)
+			assert.Equal(t, "abc", c.Value)
+
+			_, err = tt.req.Cookie("unknown")
+			assert.Equal(t, http.ErrNoCookie, err)
+
+			hr := HTTPRequest(tt.req)
+			assert.Equal(t, "Bearer token", hr.Header.Get("Authorization"))
+			assert.Equal(t, "127.0.0.1:8080", hr.RemoteAddr)
+			assert.Equal(t, state, hr.TLS)
+			assert.Equal(t, "value", hr.Context().Value(ctxKey{}))
+		})
+	}
+
+	assert.Equal(t, r, HTTPRequest(NetHTTPRequest(r)))
+}
+
+func TestAuthenticateRequest(t *testing.T) {
+	a := New()
+	a.EnableStrategy("test", strategy{id: "1"})
+
+	req := MetadataRequest(context.Background(), map[string][]string{}, nil, nil)
+	info, err := AuthenticateRequest(a, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+	assert.Equal(t, "", req.RemoteAddr())
+}
+
+type requestStrategy struct {
+	strategy
+}
+
+func (s requestStrategy) AuthenticateRequest(ctx context.Context, r Request) (Info, error) {
+	return NewDefaultUser("", r.Header().Get("X-Id"), nil, nil), nil
+}
+
+func TestAuthenticateWith(t *testing.T) {
+	req := MetadataRequest(context.Background(), map[string][]string{
+		"x-id": {"2"},
+	}, nil, nil)
+
+	info, err := AuthenticateWith(strategy{id: "1"}, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+
+	info, err = AuthenticateWith(requestStrategy{strategy{id: "1"}}, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", info.ID())
+}
@@ -0,0 +1,230 @@
+package risk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ReasonNewDevice reports the login from a device the user never logged in from.
+const ReasonNewDevice = "new_device"
+
+// ErrUnknownDevice is returned by DeviceTracker.Forget,
+// when the device is not a user known device.
+var ErrUnknownDevice = errors.New("risk: Unknown device")
+
+func init() {
+	gob.Register(&knownDevices{})
+}
+
+// Device represents a user known device.
+type Device struct {
+	// ID is the device fingerprint.
+	ID string `json:"id"`
+	// UserAgent is the device last seen user agent.
+	UserAgent string `json:"user_agent"`
+	// IP is the device last seen IP address.
+	IP string `json:"ip"`
+	// FirstSeen is the device first login time.
+	FirstSeen time.Time `json:"first_seen"`
+	// LastSeen is the device last login time.
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// NewDeviceFunc define function signature invoked when a user logs in from a new device.
+type NewDeviceFunc func(ctx context.Context, r *http.Request, info auth.Info, d Device)
+
+// DefaultFingerprint return the request device fingerprint derived from the User-Agent,
+// and Accept-Language headers.
+// The fingerprint is weak and shared by similar devices,
+// prefer a fingerprint derived from a long-lived device cookie where possible, See SetFingerprint.
+func DefaultFingerprint(r *http.Request) (string, error) {
+	sum := sha256.Sum256([]byte(r.UserAgent() + "\n" + r.Header.Get("Accept-Language")))
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+// DeviceTracker track the devices users logs in from, and implements Engine,
+// to record the devices of the logins assessed by Hook.
+type DeviceTracker struct {
+	mu          *sync.Mutex
+	cache       store.Cache
+	fingerprint func(r *http.Request) (string, error)
+	hook        NewDeviceFunc
+	action      Action
+	max         int
+}
+
+// NewDeviceTracker return DeviceTracker that keep the users known devices in the given cache by the user id,
+// Thus the cache entries lifetime defines how long the devices remembered.
+//
+// A login from a new device flagged and the new device hook invoked, See SetNewDeviceHook,
+// unless it's the user first known device.
+func NewDeviceTracker(c store.Cache, opts ...auth.Option) *DeviceTracker {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	t := &DeviceTracker{
+		mu:          new(sync.Mutex),
+		cache:       c,
+		fingerprint: DefaultFingerprint,
+		hook:        func(context.Context, *http.Request, auth.Info, Device) {},
+		action:      Flag,
+		max:         20,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(t)
+	}
+
+	return t
+}
+
+// Assess record the request device as a user known device,
+// and return the tracker action if the device is new.
+func (t *DeviceTracker) Assess(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error) {
+	id, err := t.fingerprint(r)
+	if err != nil {
+		return Assessment{}, err
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	now := time.Now()
+	d := Device{
+		ID:        id,
+		UserAgent: r.UserAgent(),
+		IP:        host,
+		FirstSeen: now,
+		LastSeen:  now,
+	}
+
+	t.mu.Lock()
+	devices, err := t.load(r, info.ID())
+	if err != nil {
+		t.mu.Unlock()
+		return Assessment{}, err
+	}
+
+	i := devices.index(id)
+	isNew := i < 0 && len(devices) > 0
+
+	if i < 0 {
+		devices = append(devices, d)
+	} else {
+		d.FirstSeen = devices[i].FirstSeen
+		devices[i] = d
+	}
+
+	if len(devices) > t.max {
+		sort.Slice(devices, func(i, j int) bool {
+			return devices[i].LastSeen.After(devices[j].LastSeen)
+		})
+		devices = devices[:t.max]
+	}
+
+	err = t.cache.Store(deviceKey(info.ID()), &knownDevices{Devices: devices}, r)
+	t.mu.Unlock()
+
+	if err != nil || !isNew {
+		return Assessment{}, err
+	}
+
+	t.hook(ctx, r, info, d)
+
+	if t.action == Allow {
+		return Assessment{}, nil
+	}
+
+	return Assessment{Action: t.action, Reasons: []string{ReasonNewDevice}}, nil
+}
+
+// Devices return the user of the given id known devices, sorted by the last seen time, most recent first.
+func (t *DeviceTracker) Devices(r *http.Request, userID string) ([]Device, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	devices, err := t.load(r, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].LastSeen.After(devices[j].LastSeen)
+	})
+
+	return devices, nil
+}
+
+// Forget remove the device of the given id from the user known devices,
+// so the user next login from it considered from a new device.
+func (t *DeviceTracker) Forget(r *http.Request, userID, deviceID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	devices, err := t.load(r, userID)
+	if err != nil {
+		return err
+	}
+
+	i := devices.index(deviceID)
+	if i < 0 {
+		return ErrUnknownDevice
+	}
+
+	devices = append(devices[:i], devices[i+1:]...)
+
+	return t.cache.Store(deviceKey(userID), &knownDevices{Devices: devices}, r)
+}
+
+// ForgetAll remove all the user known devices.
+func (t *DeviceTracker) ForgetAll(r *http.Request, userID string) error {
+	return t.cache.Delete(deviceKey(userID), r)
+}
+
+// load return a copy of the user known devices, since the cache may share it.
+func (t *DeviceTracker) load(r *http.Request, userID string) (deviceList, error) {
+	v, ok, err := t.cache.Load(deviceKey(userID), r)
+	if err != nil || !ok {
+		return deviceList{}, err
+	}
+
+	kd, _ := v.(*knownDevices)
+	if kd == nil {
+		return deviceList{}, nil
+	}
+
+	return append(deviceList{}, kd.Devices...), nil
+}
+
+type knownDevices struct {
+	Devices []Device
+}
+
+type deviceList []Device
+
+func (ds deviceList) index(id string) int {
+	for i, d := range ds {
+		if d.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func deviceKey(userID string) string {
+	return "devices:" + userID
+}
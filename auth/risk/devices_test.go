@@ -0,0 +1,79 @@
+package risk
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestDeviceTracker(t *testing.T) {
+	events := []Device{}
+	hook := func(ctx context.Context, r *http.Request, info auth.Info, d Device) {
+		events = append(events, d)
+	}
+
+	tracker := NewDeviceTracker(store.New(0), SetNewDeviceHook(hook), SetMaxDevices(2))
+	info := auth.NewUserInfo("alice", "1", nil, nil)
+
+	login := func(ua string) Assessment {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("User-Agent", ua)
+		r.RemoteAddr = "10.0.0.1:1234"
+		a, err := tracker.Assess(context.Background(), r, info)
+		assert.NoError(t, err)
+		return a
+	}
+
+	// first known device never reported.
+	assert.Equal(t, Assessment{}, login("laptop"))
+	assert.Equal(t, Assessment{}, login("laptop"))
+	assert.Empty(t, events)
+
+	a := login("phone")
+	assert.Equal(t, Flag, a.Action)
+	assert.Equal(t, []string{ReasonNewDevice}, a.Reasons)
+	assert.Len(t, events, 1)
+	assert.Equal(t, "phone", events[0].UserAgent)
+	assert.Equal(t, "10.0.0.1", events[0].IP)
+
+	devices, err := tracker.Devices(nil, "1")
+	assert.NoError(t, err)
+	assert.Len(t, devices, 2)
+	assert.Equal(t, "phone", devices[0].UserAgent)
+
+	// least recently seen device forgotten when max exceeded.
+	login("tablet")
+	devices, _ = tracker.Devices(nil, "1")
+	assert.Len(t, devices, 2)
+	assert.Equal(t, "tablet", devices[0].UserAgent)
+	assert.Equal(t, "phone", devices[1].UserAgent)
+
+	err = tracker.Forget(nil, "1", devices[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, ErrUnknownDevice, tracker.Forget(nil, "1", devices[0].ID))
+
+	devices, _ = tracker.Devices(nil, "1")
+	assert.Len(t, devices, 1)
+
+	assert.NoError(t, tracker.ForgetAll(nil, "1"))
+	devices, _ = tracker.Devices(nil, "1")
+	assert.Empty(t, devices)
+}
+
+func TestDeviceTrackerAction(t *testing.T) {
+	tracker := NewDeviceTracker(store.New(0), SetAction(ReasonNewDevice, Allow))
+	info := auth.NewUserInfo("alice", "1", nil, nil)
+
+	for _, ua := range []string{"laptop", "phone"} {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("User-Agent", ua)
+		a, err := tracker.Assess(context.Background(), r, info)
+		assert.NoError(t, err)
+		assert.Equal(t, Assessment{}, a)
+	}
+}
@@ -0,0 +1,209 @@
+package risk
+
+import (
+	"context"
+	"encoding/gob"
+	"math"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// Reasons of the GeoIP engine assessments.
+const (
+	// ReasonImpossibleTravel reports the user traveled from the last login location,
+	// faster than the engine max speed.
+	ReasonImpossibleTravel = "impossible_travel"
+	// ReasonNewASN reports the login from an autonomous system the user never logged in from.
+	ReasonNewASN = "new_asn"
+	// ReasonTorExitNode reports the login from a TOR exit node.
+	ReasonTorExitNode = "tor_exit_node"
+)
+
+// maxKnownASNs is the max number of autonomous systems remembered per user.
+const maxKnownASNs = 16
+
+func init() {
+	gob.Register(&lastLogin{})
+}
+
+// Location represents an IP address geolocation.
+type Location struct {
+	// Latitude is the location latitude in degrees.
+	Latitude float64
+	// Longitude is the location longitude in degrees.
+	Longitude float64
+	// ASN is the autonomous system number the IP address belongs to.
+	ASN uint
+	// TorExitNode reports whether the IP address is a known TOR exit node.
+	TorExitNode bool
+}
+
+// Locator resolve IP addresses geolocation,
+// Typically backed by a geo-IP database such as MaxMind GeoIP2 and a TOR exit nodes list.
+type Locator interface {
+	Locate(ip net.IP) (Location, error)
+}
+
+// LocatorFunc is an adapter to allow the use of ordinary functions as Locator.
+type LocatorFunc func(ip net.IP) (Location, error)
+
+// Locate calls fn(ip).
+func (fn LocatorFunc) Locate(ip net.IP) (Location, error) {
+	return fn(ip)
+}
+
+// NewGeoIP return reference Engine that locates the request remote address using the given Locator,
+// and assess the login against the user last login location, kept in the cache by the user id.
+// Thus the cache entries lifetime defines how long the user logins history remembered.
+func NewGeoIP(l Locator, c store.Cache, opts ...auth.Option) Engine {
+	if l == nil {
+		panic("Locator object required and can't be nil")
+	}
+
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	g := &geoIP{
+		locator:     l,
+		cache:       c,
+		maxSpeed:    1000,
+		minDistance: 500,
+		actions: map[string]Action{
+			ReasonImpossibleTravel: Flag,
+			ReasonNewASN:           Flag,
+			ReasonTorExitNode:      Deny,
+		},
+	}
+
+	for _, opt := range opts {
+		opt.Apply(g)
+	}
+
+	return g
+}
+
+type lastLogin struct {
+	Latitude  float64
+	Longitude float64
+	ASNs      []uint
+	Time      time.Time
+}
+
+type geoIP struct {
+	locator     Locator
+	cache       store.Cache
+	maxSpeed    float64
+	minDistance float64
+	actions     map[string]Action
+}
+
+func (g *geoIP) Assess(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	loc, err := g.locator.Locate(net.ParseIP(host))
+	if err != nil {
+		return Assessment{}, err
+	}
+
+	now := time.Now()
+	a := Assessment{}
+	reasons := []string{}
+
+	if loc.TorExitNode {
+		reasons = append(reasons, ReasonTorExitNode)
+	}
+
+	v, ok, err := g.cache.Load(info.ID(), r)
+	if err != nil {
+		return Assessment{}, err
+	}
+
+	last := new(lastLogin)
+	if prev, _ := v.(*lastLogin); ok && prev != nil {
+		// copy the record, since the cache may share it with concurrent logins.
+		*last = *prev
+		last.ASNs = append([]uint(nil), prev.ASNs...)
+
+		if g.impossibleTravel(last, loc, now) {
+			reasons = append(reasons, ReasonImpossibleTravel)
+		}
+
+		if !containsASN(last.ASNs, loc.ASN) {
+			reasons = append(reasons, ReasonNewASN)
+		}
+	}
+
+	for _, reason := range reasons {
+		if g.actions[reason] > a.Action {
+			a.Action = g.actions[reason]
+		}
+	}
+
+	for _, reason := range reasons {
+		if g.actions[reason] == a.Action && a.Action != Allow {
+			a.Reasons = append(a.Reasons, reason)
+		}
+	}
+
+	// denied logins never become the user known locations.
+	if a.Action == Deny {
+		return a, nil
+	}
+
+	if !containsASN(last.ASNs, loc.ASN) {
+		last.ASNs = append(last.ASNs, loc.ASN)
+		if len(last.ASNs) > maxKnownASNs {
+			last.ASNs = last.ASNs[len(last.ASNs)-maxKnownASNs:]
+		}
+	}
+
+	last.Latitude = loc.Latitude
+	last.Longitude = loc.Longitude
+	last.Time = now
+
+	return a, g.cache.Store(info.ID(), last, r)
+}
+
+func (g *geoIP) impossibleTravel(last *lastLogin, loc Location, now time.Time) bool {
+	km := distance(last.Latitude, last.Longitude, loc.Latitude, loc.Longitude)
+	if km <= g.minDistance {
+		return false
+	}
+
+	hours := now.Sub(last.Time).Hours()
+	if hours <= 0 {
+		return true
+	}
+
+	return km/hours > g.maxSpeed
+}
+
+// distance return the great-circle distance in km between two points using the haversine formula.
+func distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371
+
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat, dLon := rad(lat2-lat1), rad(lon2-lon1)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+func containsASN(asns []uint, asn uint) bool {
+	for _, v := range asns {
+		if v == asn {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestGeoIP(t *testing.T) {
+	locations := map[string]Location{
+		"10.0.0.1": {Latitude: 51.50, Longitude: -0.12, ASN: 1},  // London.
+		"10.0.0.2": {Latitude: 51.51, Longitude: -0.13, ASN: 2},  // London, other network.
+		"10.0.0.3": {Latitude: 35.68, Longitude: 139.69, ASN: 1}, // Tokyo.
+		"10.0.0.4": {Latitude: 51.50, Longitude: -0.12, ASN: 1, TorExitNode: true},
+	}
+
+	locator := LocatorFunc(func(ip net.IP) (Location, error) {
+		l, ok := locations[ip.String()]
+		if !ok {
+			return Location{}, errors.New("unknown ip")
+		}
+		return l, nil
+	})
+
+	info := auth.NewUserInfo("alice", "1", nil, nil)
+	e := NewGeoIP(locator, store.New(0))
+
+	assess := func(addr string) (Assessment, error) {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = addr + ":1234"
+		return e.Assess(context.Background(), r, info)
+	}
+
+	table := []struct {
+		name   string
+		addr   string
+		action Action
+		reason string
+		err    bool
+	}{
+		{name: "it allow first login", addr: "10.0.0.1", action: Allow},
+		{name: "it allow same location", addr: "10.0.0.1", action: Allow},
+		{name: "it flag new asn", addr: "10.0.0.2", action: Flag, reason: ReasonNewASN},
+		{name: "it allow known asn", addr: "10.0.0.1", action: Allow},
+		{name: "it deny tor exit node", addr: "10.0.0.4", action: Deny, reason: ReasonTorExitNode},
+		{name: "it flag impossible travel", addr: "10.0.0.3", action: Flag, reason: ReasonImpossibleTravel},
+		{name: "it return error when locator fails", addr: "10.0.0.9", err: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := assess(tt.addr)
+			assert.Equal(t, tt.err, err != nil, err)
+			assert.Equal(t, tt.action, a.Action)
+			if tt.reason != "" {
+				assert.Equal(t, []string{tt.reason}, a.Reasons)
+			}
+		})
+	}
+}
+
+func TestGeoIPOptions(t *testing.T) {
+	locator := LocatorFunc(func(ip net.IP) (Location, error) {
+		return Location{TorExitNode: true}, nil
+	})
+
+	opts := []auth.Option{SetAction(ReasonTorExitNode, Flag), SetMaxSpeed(10), SetMinDistance(1)}
+	e := NewGeoIP(locator, store.New(0), opts...)
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	a, err := e.Assess(context.Background(), r, auth.NewUserInfo("alice", "1", nil, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, Flag, a.Action)
+	assert.Equal(t, float64(10), e.(*geoIP).maxSpeed)
+	assert.Equal(t, float64(1), e.(*geoIP).minDistance)
+}
+
+func TestImpossibleTravel(t *testing.T) {
+	g := &geoIP{maxSpeed: 1000, minDistance: 500}
+	now := time.Now()
+	london := &lastLogin{Latitude: 51.50, Longitude: -0.12, Time: now.Add(-time.Hour)}
+	paris := Location{Latitude: 48.85, Longitude: 2.35}
+	tokyo := Location{Latitude: 35.68, Longitude: 139.69}
+
+	assert.False(t, g.impossibleTravel(london, paris, now))
+	assert.False(t, g.impossibleTravel(london, paris, now.Add(-time.Hour)))
+	assert.True(t, g.impossibleTravel(london, tokyo, now))
+	assert.False(t, g.impossibleTravel(london, tokyo, now.Add(time.Hour*24)))
+	assert.InDelta(t, 344, distance(51.50, -0.12, 48.85, 2.35), 5)
+}
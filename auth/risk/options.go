@@ -0,0 +1,75 @@
+package risk
+
+import (
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetMaxSpeed sets the GeoIP engine max travel speed in km/h between two logins,
+// above it the login considered impossible travel.
+// Default 1000 km/h.
+func SetMaxSpeed(kmh float64) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if g, ok := v.(*geoIP); ok {
+			g.maxSpeed = kmh
+		}
+	})
+}
+
+// SetMinDistance sets the GeoIP engine min distance in km between two logins,
+// below it the travel never considered impossible, to tolerate the geo-IP databases inaccuracy.
+// Default 500 km.
+func SetMinDistance(km float64) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if g, ok := v.(*geoIP); ok {
+			g.minDistance = km
+		}
+	})
+}
+
+// SetAction sets the GeoIP engine or the DeviceTracker action of the given reason.
+// Default Deny for ReasonTorExitNode, and Flag for ReasonImpossibleTravel, ReasonNewASN, and ReasonNewDevice.
+func SetAction(reason string, a Action) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		switch t := v.(type) {
+		case *geoIP:
+			t.actions[reason] = a
+		case *DeviceTracker:
+			if reason == ReasonNewDevice {
+				t.action = a
+			}
+		}
+	})
+}
+
+// SetFingerprint sets the DeviceTracker function returns the request device fingerprint,
+// Default DefaultFingerprint.
+func SetFingerprint(fn func(r *http.Request) (string, error)) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*DeviceTracker); ok {
+			t.fingerprint = fn
+		}
+	})
+}
+
+// SetNewDeviceHook sets the DeviceTracker function invoked when a user logs in from a new device,
+// e.g to notify the user or to record an audit event.
+func SetNewDeviceHook(fn NewDeviceFunc) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*DeviceTracker); ok {
+			t.hook = fn
+		}
+	})
+}
+
+// SetMaxDevices sets the DeviceTracker max number of known devices per user,
+// the least recently seen device forgotten when the limit exceeded.
+// Default 20.
+func SetMaxDevices(n int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if t, ok := v.(*DeviceTracker); ok {
+			t.max = n
+		}
+	})
+}
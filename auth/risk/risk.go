@@ -0,0 +1,146 @@
+// Package risk provides a post-authentication hook to assess the risk of logins,
+// such as impossible travel, new network, or TOR exit nodes,
+// to veto risky logins or flag them for adaptive decisions, e.g step-up MFA.
+// It also tracks the users known devices, to notify users of logins from new devices.
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// ExtensionReasons is the auth.Info extension key carries the reasons of a flagged login.
+const ExtensionReasons = "risk_reasons"
+
+// ErrDenied is returned by the Hook decorated strategy, when an engine denies the login.
+var ErrDenied = errors.New("risk: Login denied")
+
+// Action represents the action taken on an assessed login.
+type Action int
+
+const (
+	// Allow allows the login.
+	Allow Action = iota
+	// Flag allows the login and flags it, See ExtensionReasons.
+	Flag
+	// Deny vetoes the login.
+	Deny
+)
+
+// String return the action name.
+func (a Action) String() string {
+	switch a {
+	case Allow:
+		return "allow"
+	case Flag:
+		return "flag"
+	case Deny:
+		return "deny"
+	}
+
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// Assessment represents an engine result of a login risk assessment.
+type Assessment struct {
+	// Action is the action to take on the login.
+	Action Action
+	// Reasons is the reasons of the action, e.g ReasonImpossibleTravel.
+	Reasons []string
+}
+
+// Engine assess the risk of an authenticated request.
+type Engine interface {
+	// Assess return the assessment of the request authenticated as the given user,
+	// or an error if the risk can't be assessed.
+	Assess(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error)
+}
+
+// EngineFunc is an adapter to allow the use of ordinary functions as Engine.
+type EngineFunc func(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error)
+
+// Assess calls fn(ctx, r, info).
+func (fn EngineFunc) Assess(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error) {
+	return fn(ctx, r, info)
+}
+
+// Hook return auth.Decorator that assess each successful authentication using the given engines,
+// the strictest action of the engines applied.
+// A denied login return an error wraps ErrDenied and the denial reasons,
+// and a flagged login return the user info with the flag reasons set under ExtensionReasons.
+// Engines errors fail the authentication, so logins never pass unassessed.
+func Hook(engines ...Engine) auth.Decorator {
+	return auth.DecoratorFunc(func(ctx context.Context, r *http.Request, next auth.Strategy) (auth.Info, error) {
+		info, err := next.Authenticate(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		a, err := assess(ctx, r, info, engines)
+		if err != nil {
+			return nil, err
+		}
+
+		if a.Action == Deny {
+			return nil, fmt.Errorf("%w: %s", ErrDenied, strings.Join(a.Reasons, ", "))
+		}
+
+		ext := make(map[string][]string, len(info.Extensions())+1)
+		for k, v := range info.Extensions() {
+			ext[k] = v
+		}
+
+		delete(ext, ExtensionReasons)
+
+		if a.Action == Flag {
+			ext[ExtensionReasons] = a.Reasons
+		}
+
+		return withExtensions(info, ext), nil
+	})
+}
+
+// withExtensions return a copy of the given info carrying the given extensions,
+// the info never mutated, since it may be shared by a cached strategy.
+func withExtensions(info auth.Info, ext map[string][]string) auth.Info {
+	c := auth.NewUserInfo(info.UserName(), info.ID(), info.Groups(), ext)
+	auth.SetUserRoles(c, auth.UserRoles(info))
+
+	nbf, _ := auth.UserNotBefore(info)
+	exp, _ := auth.UserExpiry(info)
+	auth.SetUserValidity(c, nbf, exp)
+
+	return c
+}
+
+// Reasons return the reasons the given user login flagged for, Otherwise, nil.
+func Reasons(info auth.Info) []string {
+	return info.Extensions()[ExtensionReasons]
+}
+
+func assess(ctx context.Context, r *http.Request, info auth.Info, engines []Engine) (Assessment, error) {
+	result := Assessment{}
+
+	for _, e := range engines {
+		a, err := e.Assess(ctx, r, info)
+		if err != nil {
+			return Assessment{}, err
+		}
+
+		if a.Action > result.Action {
+			result.Action = a.Action
+			result.Reasons = nil
+		}
+
+		if a.Action == result.Action && a.Action != Allow {
+			result.Reasons = append(result.Reasons, a.Reasons...)
+		}
+	}
+
+	return result, nil
+}
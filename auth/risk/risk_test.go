@@ -0,0 +1,142 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestHook(t *testing.T) {
+	engine := func(a Action, reasons ...string) Engine {
+		return EngineFunc(func(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error) {
+			return Assessment{Action: a, Reasons: reasons}, nil
+		})
+	}
+
+	failing := EngineFunc(func(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error) {
+		return Assessment{}, errors.New("engine failed")
+	})
+
+	table := []struct {
+		name    string
+		engines []Engine
+		err     error
+		reasons []string
+	}{
+		{
+			name:    "it allow login",
+			engines: []Engine{engine(Allow)},
+		},
+		{
+			name:    "it flag login",
+			engines: []Engine{engine(Allow), engine(Flag, "a"), engine(Flag, "b")},
+			reasons: []string{"a", "b"},
+		},
+		{
+			name:    "it deny login",
+			engines: []Engine{engine(Flag, "a"), engine(Deny, "b")},
+			err:     ErrDenied,
+		},
+		{
+			name:    "it return error when engine fails",
+			engines: []Engine{failing},
+			err:     errors.New("engine failed"),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			info := auth.NewUserInfo("alice", "1", nil, map[string][]string{ExtensionReasons: {"stale"}})
+			s := auth.Decorate(staticStrategy{info}, Hook(tt.engines...))
+			r, _ := http.NewRequest("GET", "/", nil)
+
+			got, err := s.Authenticate(r.Context(), r)
+
+			if tt.err != nil {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.err.Error())
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.reasons, Reasons(got))
+		})
+	}
+}
+
+func TestHookCachedInfo(t *testing.T) {
+	authFunc := func(ctx context.Context, r *http.Request, tk string) (auth.Info, error) {
+		info := auth.NewUserInfo("alice", "1", nil, nil)
+		auth.SetUserRoles(info, []string{"admin"})
+		auth.SetUserExpiry(info, time.Now().Add(time.Hour))
+		return info, nil
+	}
+
+	// flag only the requests carrying X-Risky header.
+	engine := EngineFunc(func(ctx context.Context, r *http.Request, info auth.Info) (Assessment, error) {
+		if r.Header.Get("X-Risky") != "" {
+			return Assessment{Action: Flag, Reasons: []string{"risky"}}, nil
+		}
+		return Assessment{Action: Allow}, nil
+	})
+
+	s := auth.Decorate(token.New(authFunc, store.New(0)), Hook(engine))
+
+	request := func(risky bool) *http.Request {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer token")
+		if risky {
+			r.Header.Set("X-Risky", "true")
+		}
+		return r
+	}
+
+	flagged, err := s.Authenticate(context.Background(), request(true))
+	assert.NoError(t, err)
+
+	allowed, err := s.Authenticate(context.Background(), request(false))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"risky"}, Reasons(flagged))
+	assert.Nil(t, Reasons(allowed))
+	assert.Equal(t, []string{"admin"}, auth.UserRoles(flagged))
+
+	exp, ok := auth.UserExpiry(flagged)
+	assert.True(t, ok)
+	assert.True(t, exp.After(time.Now()))
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(risky bool) {
+			defer wg.Done()
+			_, _ = s.Authenticate(context.Background(), request(risky))
+		}(i%2 == 0)
+	}
+	wg.Wait()
+}
+
+func TestAction(t *testing.T) {
+	assert.Equal(t, "allow", Allow.String())
+	assert.Equal(t, "flag", Flag.String())
+	assert.Equal(t, "deny", Deny.String())
+	assert.Equal(t, "Action(7)", Action(7).String())
+}
+
+type staticStrategy struct {
+	info auth.Info
+}
+
+func (s staticStrategy) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	return s.info, nil
+}
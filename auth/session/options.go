@@ -0,0 +1,51 @@
+package session
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetMaxSessions sets the max active sessions per user,
+// and the eviction policy applied when a user reaches it, e.g for licensing or security requirements.
+// Default 0, means unlimited sessions.
+func SetMaxSessions(max int, e Eviction) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.max = max
+			m.eviction = e
+		}
+	})
+}
+
+// SetIdleTimeout sets the sliding session idle timeout,
+// extended on each authenticated request, See ErrSessionIdle.
+// Default 0, means sessions never idle out.
+func SetIdleTimeout(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.idle = d
+		}
+	})
+}
+
+// SetAbsoluteTimeout sets the session hard lifetime since its creation,
+// regardless of the session activity, See ErrSessionExpired.
+// Default 0, means sessions live as long as the cache keeps them.
+func SetAbsoluteTimeout(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if m, ok := v.(*Manager); ok {
+			m.absolute = d
+		}
+	})
+}
+
+// SetCookieName sets the remember-me cookie name,
+// Default "remember_me".
+func SetCookieName(name string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if rm, ok := v.(*RememberMe); ok {
+			rm.name = name
+		}
+	})
+}
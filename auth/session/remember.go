@@ -0,0 +1,299 @@
+package session
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/gob"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/cookie"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+var (
+	// ErrInvalidRememberMe is returned by RememberMe.Login,
+	// when the remember-me cookie malformed, or its series does not exist.
+	ErrInvalidRememberMe = gerrors.WithReason(
+		errors.New("session: Invalid remember-me cookie"),
+		gerrors.ReasonInvalidCredentials,
+	)
+
+	// ErrRememberMeTheft is returned by RememberMe.Login,
+	// when an old token presented for a known series, which means the cookie stolen and used,
+	// the user remember-me series and sessions revoked.
+	ErrRememberMeTheft = gerrors.WithReason(
+		errors.New("session: Remember-me cookie theft detected"),
+		gerrors.ReasonRevoked,
+	)
+)
+
+func init() {
+	gob.Register(&series{})
+}
+
+type series struct {
+	ID        string
+	TokenHash []byte
+	Info      auth.Info
+	CreatedAt time.Time
+}
+
+// RememberMe implements the series and token persistent login scheme,
+// where the long-lived cookie carries a fixed series id and a token rotated on each login.
+// Presenting an old token for a known series means the cookie was stolen,
+// so all the user remember-me series and sessions revoked.
+//
+// The cookie encoded by the given codec, and its max age is the remember-me lifetime,
+// e.g cookie.SetMaxAge(time.Hour * 24 * 30).
+// Series kept in the cache keyed by their ids,
+// thus the cache entries lifetime should not be less than the codec max age.
+type RememberMe struct {
+	mu       *sync.Mutex
+	cache    store.Cache
+	sessions *Manager
+	codec    *cookie.Codec
+	name     string
+}
+
+// NewRememberMe return RememberMe that log in users to sessions created by the given manager.
+func NewRememberMe(c store.Cache, m *Manager, codec *cookie.Codec, opts ...auth.Option) *RememberMe {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	if m == nil {
+		panic("Manager object required and can't be nil")
+	}
+
+	if codec == nil {
+		panic("Codec object required and can't be nil")
+	}
+
+	rm := &RememberMe{
+		mu:       new(sync.Mutex),
+		cache:    c,
+		sessions: m,
+		codec:    codec,
+		name:     "remember_me",
+	}
+
+	for _, opt := range opts {
+		opt.Apply(rm)
+	}
+
+	return rm
+}
+
+// Issue start a new remember-me series for the given user,
+// and return its cookie, to be written on an explicit login with "remember me" checked.
+func (rm *RememberMe) Issue(r *http.Request, info auth.Info) (*http.Cookie, error) {
+	id, err := random()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &series{
+		ID:        id,
+		Info:      info,
+		CreatedAt: time.Now(),
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	ids, err := rm.ids(r, info.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := rm.rotate(r, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, rm.cache.Store(rememberUserKey(info.ID()), append(ids, s.ID), r)
+}
+
+// Login authenticate the request remember-me cookie, and create a new session of its user,
+// along with the cookie carrying the rotated token, to be written to the response.
+// If the cookie carries an old token of its series, ErrRememberMeTheft returned,
+// after all the user remember-me series and sessions revoked.
+func (rm *RememberMe) Login(r *http.Request) (*Session, *http.Cookie, error) {
+	v, err := rm.codec.Read(r, rm.name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parts := strings.Split(string(v), ":")
+	if len(parts) != 2 {
+		return nil, nil, ErrInvalidRememberMe
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	s, err := rm.load(r, parts[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if subtle.ConstantTimeCompare(s.TokenHash, hash(parts[1])) != 1 {
+		if err := rm.revoke(r, s.Info.ID()); err != nil {
+			return nil, nil, err
+		}
+
+		if err := rm.sessions.DestroyAll(r, s.Info.ID()); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, ErrRememberMeTheft
+	}
+
+	c, err := rm.rotate(r, s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sess, err := rm.sessions.Create(r, s.Info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sess, c, nil
+}
+
+// Forget delete the request remember-me cookie series, e.g on logout,
+// and return a cookie that clears the remember-me cookie, to be written to the response.
+func (rm *RememberMe) Forget(r *http.Request) (*http.Cookie, error) {
+	expired := &http.Cookie{Name: rm.name, Path: "/", MaxAge: -1}
+
+	v, err := rm.codec.Read(r, rm.name)
+	if err != nil {
+		return expired, nil
+	}
+
+	id := strings.Split(string(v), ":")[0]
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	s, err := rm.load(r, id)
+	if err == ErrInvalidRememberMe {
+		return expired, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rm.cache.Delete(seriesKey(id), r); err != nil {
+		return nil, err
+	}
+
+	ids, err := rm.ids(r, s.Info.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	for i, v := range ids {
+		if v == id {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+
+	return expired, rm.cache.Store(rememberUserKey(s.Info.ID()), ids, r)
+}
+
+// ForgetAll delete all the remember-me series of the user of the given id,
+// e.g after a password change.
+func (rm *RememberMe) ForgetAll(r *http.Request, userID string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	return rm.revoke(r, userID)
+}
+
+// rotate generate a new token for the given series, and return the series cookie.
+func (rm *RememberMe) rotate(r *http.Request, s *series) (*http.Cookie, error) {
+	token, err := random()
+	if err != nil {
+		return nil, err
+	}
+
+	rotated := *s
+	rotated.TokenHash = hash(token)
+
+	if err := rm.cache.Store(seriesKey(s.ID), &rotated, r); err != nil {
+		return nil, err
+	}
+
+	return rm.codec.Cookie(rm.name, []byte(s.ID+":"+token))
+}
+
+func (rm *RememberMe) revoke(r *http.Request, userID string) error {
+	ids, err := rm.ids(r, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := rm.cache.Delete(seriesKey(id), r); err != nil {
+			return err
+		}
+	}
+
+	return rm.cache.Delete(rememberUserKey(userID), r)
+}
+
+func (rm *RememberMe) load(r *http.Request, id string) (*series, error) {
+	v, ok, err := rm.cache.Load(seriesKey(id), r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrInvalidRememberMe
+	}
+
+	s, ok := v.(*series)
+	if !ok {
+		return nil, gerrors.NewInvalidType((*series)(nil), v)
+	}
+
+	return s, nil
+}
+
+func (rm *RememberMe) ids(r *http.Request, userID string) ([]string, error) {
+	v, ok, err := rm.cache.Load(rememberUserKey(userID), r)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	ids, ok := v.([]string)
+	if !ok {
+		return nil, gerrors.NewInvalidType([]string(nil), v)
+	}
+
+	// copy, so the cached value never mutated.
+	return append([]string(nil), ids...), nil
+}
+
+func hash(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+func seriesKey(id string) string {
+	return "remember_me:" + id
+}
+
+func rememberUserKey(userID string) string {
+	return "user_remember_me:" + userID
+}
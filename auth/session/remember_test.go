@@ -0,0 +1,89 @@
+package session
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/cookie"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestRememberMe(t *testing.T) {
+	codec, _ := cookie.NewCodec([][]byte{bytes.Repeat([]byte{1}, 32)}, cookie.SetMaxAge(time.Hour))
+	m := New(store.New(0))
+	rm := NewRememberMe(store.New(0), m, codec, SetCookieName("rm"))
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+
+	request := func(c *http.Cookie) *http.Request {
+		r, _ := http.NewRequest("GET", "/", nil)
+		if c != nil {
+			r.AddCookie(c)
+		}
+		return r
+	}
+
+	issued, err := rm.Issue(nil, alice)
+	assert.NoError(t, err)
+	assert.Equal(t, "rm", issued.Name)
+
+	sess, rotated, err := rm.Login(request(issued))
+	assert.NoError(t, err)
+	assert.Equal(t, alice, sess.Info)
+	assert.NotEqual(t, issued.Value, rotated.Value)
+
+	sess, rotated, err = rm.Login(request(rotated))
+	assert.NoError(t, err)
+
+	// another series of the same user.
+	other, _ := rm.Issue(nil, alice)
+
+	// the stolen cookie replayed.
+	_, _, err = rm.Login(request(issued))
+	assert.Equal(t, ErrRememberMeTheft, err)
+
+	_, err = m.Get(nil, sess.ID)
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	for _, c := range []*http.Cookie{rotated, other} {
+		_, _, err = rm.Login(request(c))
+		assert.Equal(t, ErrInvalidRememberMe, err)
+	}
+
+	_, _, err = rm.Login(request(nil))
+	assert.Equal(t, http.ErrNoCookie, err)
+}
+
+func TestRememberMeForget(t *testing.T) {
+	codec, _ := cookie.NewCodec([][]byte{bytes.Repeat([]byte{1}, 32)})
+	rm := NewRememberMe(store.New(0), New(store.New(0)), codec)
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+
+	c1, _ := rm.Issue(nil, alice)
+	c2, _ := rm.Issue(nil, alice)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.AddCookie(c1)
+
+	expired, err := rm.Forget(r)
+	assert.NoError(t, err)
+	assert.Equal(t, -1, expired.MaxAge)
+
+	_, _, err = rm.Login(r)
+	assert.Equal(t, ErrInvalidRememberMe, err)
+
+	// forgetting twice is a no-op.
+	_, err = rm.Forget(r)
+	assert.NoError(t, err)
+
+	assert.NoError(t, rm.ForgetAll(nil, "1"))
+
+	r, _ = http.NewRequest("GET", "/", nil)
+	r.AddCookie(c2)
+	_, _, err = rm.Login(r)
+	assert.Equal(t, ErrInvalidRememberMe, err)
+}
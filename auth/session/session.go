@@ -0,0 +1,384 @@
+// Package session provides server side sessions management,
+// to create, list, and destroy the users sessions kept in a store.Cache,
+// limit the number of active sessions per user, expire idle and long-lived sessions,
+// and log users in by remember-me cookies, See RememberMe.
+//
+// Manager Authenticate plugs directly into the token strategy as a session lookup,
+// with the session id kept in a cookie encoded by the cookie package codec:
+//
+//	s := token.New(m.Authenticate, store.NoCache{},
+//		token.SetParser(codec.Parser("session")),
+//	)
+//
+// and the session cookie written on login using the same codec:
+//
+//	c, err := codec.Cookie("session", []byte(sess.ID))
+//	http.SetCookie(w, c)
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+var (
+	// ErrSessionNotFound is returned by Manager,
+	// when the session does not exist, destroyed, or evicted.
+	ErrSessionNotFound = gerrors.WithReason(
+		errors.New("session: Session does not exist"),
+		gerrors.ReasonInvalidCredentials,
+	)
+
+	// ErrMaxSessions is returned by Manager.Create,
+	// when the user reached the max active sessions and the eviction policy is RejectNew.
+	ErrMaxSessions = errors.New("session: Max active sessions reached")
+
+	// ErrSessionExpired is returned by Manager.Authenticate,
+	// when the session reached its absolute lifetime, See SetAbsoluteTimeout.
+	ErrSessionExpired error = expiredError{}
+
+	// ErrSessionIdle is returned by Manager.Authenticate,
+	// when the session reached its idle timeout, See SetIdleTimeout.
+	ErrSessionIdle error = idleError{}
+)
+
+// expiredError implements auth.LocalizableError,
+// so middleware can tell the user to log in again.
+type expiredError struct{}
+
+func (expiredError) Error() string              { return "session: Session expired" }
+func (expiredError) MessageKey() string         { return auth.MessageSessionExpired }
+func (expiredError) MessageArgs() []interface{} { return nil }
+func (expiredError) Reason() gerrors.Reason     { return gerrors.ReasonExpired }
+
+// idleError implements auth.LocalizableError,
+// so middleware can tell the user the session timed out due to inactivity.
+type idleError struct{}
+
+func (idleError) Error() string              { return "session: Session idle timeout" }
+func (idleError) MessageKey() string         { return auth.MessageSessionIdle }
+func (idleError) MessageArgs() []interface{} { return nil }
+func (idleError) Reason() gerrors.Reason     { return gerrors.ReasonExpired }
+
+func init() {
+	gob.Register(&Session{})
+	gob.Register([]string{})
+}
+
+// Eviction represents the policy applied when a user reaches the max active sessions.
+type Eviction int
+
+const (
+	// EvictOldest destroys the user oldest sessions to make room for the new session.
+	EvictOldest Eviction = iota
+	// RejectNew rejects the new session with ErrMaxSessions.
+	RejectNew
+)
+
+// Session represents a user session.
+type Session struct {
+	// ID is the session unique id.
+	ID string
+	// Info is the session user info.
+	Info auth.Info
+	// CreatedAt is the session creation time.
+	CreatedAt time.Time
+	// LastSeen is the session last authenticated request time.
+	LastSeen time.Time
+}
+
+// Manager create and authenticate sessions.
+// Sessions kept in the cache keyed by their ids,
+// thus the cache entries lifetime defines the sessions max lifetime.
+type Manager struct {
+	mu       *sync.Mutex
+	cache    store.Cache
+	max      int
+	eviction Eviction
+	idle     time.Duration
+	absolute time.Duration
+}
+
+// New return new Manager, that does not limit the active sessions,
+// nor expire them by default.
+func New(c store.Cache, opts ...auth.Option) *Manager {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	m := &Manager{
+		mu:    new(sync.Mutex),
+		cache: c,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(m)
+	}
+
+	return m
+}
+
+// Create create a new session for the given user.
+// If the user reached the max active sessions, the eviction policy applied, See SetMaxSessions.
+// The limit enforced under the manager lock, so concurrent logins never exceed it,
+// as long as all the sessions of the cache created by the same manager.
+func (m *Manager) Create(r *http.Request, info auth.Info) (*Session, error) {
+	id, err := random()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	s := &Session{
+		ID:        id,
+		Info:      info,
+		CreatedAt: now,
+		LastSeen:  now,
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions, err := m.list(r, info.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	if m.max > 0 && len(sessions) >= m.max {
+		if m.eviction == RejectNew {
+			return nil, ErrMaxSessions
+		}
+
+		for _, old := range sessions[:len(sessions)-m.max+1] {
+			if err := m.cache.Delete(sessionKey(old.ID), r); err != nil {
+				return nil, err
+			}
+		}
+
+		sessions = sessions[len(sessions)-m.max+1:]
+	}
+
+	if err := m.cache.Store(sessionKey(s.ID), s, r); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(sessions)+1)
+	for _, v := range sessions {
+		ids = append(ids, v.ID)
+	}
+
+	return s, m.cache.Store(userKey(info.ID()), append(ids, s.ID), r)
+}
+
+// Get return the session of the given id, regardless of its timeouts.
+func (m *Manager) Get(r *http.Request, id string) (*Session, error) {
+	return m.load(r, id)
+}
+
+// List return the user of the given id active sessions, sorted by creation time, oldest first,
+// timed out sessions destroyed and never returned.
+func (m *Manager) List(r *http.Request, userID string) ([]*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.list(r, userID)
+}
+
+// Destroy destroy the session of the given id.
+func (m *Manager) Destroy(r *http.Request, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, err := m.load(r, id)
+	if err != nil {
+		return err
+	}
+
+	if err := m.cache.Delete(sessionKey(id), r); err != nil {
+		return err
+	}
+
+	ids, err := m.ids(r, s.Info.ID())
+	if err != nil {
+		return err
+	}
+
+	for i, v := range ids {
+		if v == id {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+
+	return m.cache.Store(userKey(s.Info.ID()), ids, r)
+}
+
+// DestroyAll destroy all the sessions of the user of the given id,
+// e.g after a password change or a detected credential theft.
+func (m *Manager) DestroyAll(r *http.Request, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids, err := m.ids(r, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := m.cache.Delete(sessionKey(id), r); err != nil {
+			return err
+		}
+	}
+
+	return m.cache.Delete(userKey(userID), r)
+}
+
+// Authenticate return the user info of the session of the given id,
+// and extend the session idle timeout.
+// Timed out sessions destroyed, and ErrSessionExpired or ErrSessionIdle returned.
+// Authenticate signature matches token.AuthenticateFunc.
+func (m *Manager) Authenticate(ctx context.Context, r *http.Request, id string) (auth.Info, error) {
+	s, err := m.load(r, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if err := m.validate(s, now); err != nil {
+		if err := m.Destroy(r, id); err != nil && err != ErrSessionNotFound {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	if m.idle <= 0 {
+		return s.Info, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// reload under the lock, so a concurrently destroyed session never stored again.
+	s, err = m.load(r, id)
+	if err != nil {
+		return nil, err
+	}
+
+	touched := *s
+	touched.LastSeen = now
+
+	return s.Info, m.cache.Store(sessionKey(id), &touched, r)
+}
+
+// list return the user active sessions sorted by creation time, oldest first,
+// ids of sessions evicted by the cache skipped, and timed out sessions deleted.
+func (m *Manager) list(r *http.Request, userID string) ([]*Session, error) {
+	ids, err := m.ids(r, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]*Session, 0, len(ids))
+
+	for _, id := range ids {
+		s, err := m.load(r, id)
+		if err == ErrSessionNotFound {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if m.validate(s, now) != nil {
+			if err := m.cache.Delete(sessionKey(id), r); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		sessions = append(sessions, s)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+// validate return ErrSessionExpired or ErrSessionIdle if the session timed out at the given time.
+func (m *Manager) validate(s *Session, now time.Time) error {
+	if m.absolute > 0 && now.After(s.CreatedAt.Add(m.absolute)) {
+		return ErrSessionExpired
+	}
+
+	if m.idle > 0 && now.After(s.LastSeen.Add(m.idle)) {
+		return ErrSessionIdle
+	}
+
+	return nil
+}
+
+func (m *Manager) load(r *http.Request, id string) (*Session, error) {
+	v, ok, err := m.cache.Load(sessionKey(id), r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	s, ok := v.(*Session)
+	if !ok {
+		return nil, gerrors.NewInvalidType((*Session)(nil), v)
+	}
+
+	return s, nil
+}
+
+func (m *Manager) ids(r *http.Request, userID string) ([]string, error) {
+	v, ok, err := m.cache.Load(userKey(userID), r)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	ids, ok := v.([]string)
+	if !ok {
+		return nil, gerrors.NewInvalidType([]string(nil), v)
+	}
+
+	// copy, so the cached value never mutated.
+	return append([]string(nil), ids...), nil
+}
+
+func random() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func userKey(userID string) string {
+	return "user_sessions:" + userID
+}
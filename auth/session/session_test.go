@@ -0,0 +1,177 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestManager(t *testing.T) {
+	m := New(store.New(0))
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+
+	s1, err := m.Create(nil, alice)
+	assert.NoError(t, err)
+	s2, err := m.Create(nil, alice)
+	assert.NoError(t, err)
+	assert.NotEqual(t, s1.ID, s2.ID)
+
+	_, err = m.Create(nil, auth.NewUserInfo("bob", "2", nil, nil))
+	assert.NoError(t, err)
+
+	info, err := m.Authenticate(context.Background(), nil, s1.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, alice, info)
+
+	sessions, err := m.List(nil, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*Session{s1, s2}, sessions)
+
+	assert.NoError(t, m.Destroy(nil, s1.ID))
+	assert.Equal(t, ErrSessionNotFound, m.Destroy(nil, s1.ID))
+
+	_, err = m.Get(nil, s1.ID)
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	sessions, _ = m.List(nil, "1")
+	assert.Equal(t, []*Session{s2}, sessions)
+
+	assert.NoError(t, m.DestroyAll(nil, "1"))
+	_, err = m.Authenticate(context.Background(), nil, s2.ID)
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	sessions, _ = m.List(nil, "2")
+	assert.Len(t, sessions, 1)
+}
+
+func TestManagerMaxSessions(t *testing.T) {
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+
+	// evict oldest.
+	m := New(store.New(0), SetMaxSessions(2, EvictOldest))
+	s1, _ := m.Create(nil, alice)
+	s2, _ := m.Create(nil, alice)
+	s3, err := m.Create(nil, alice)
+	assert.NoError(t, err)
+
+	sessions, _ := m.List(nil, "1")
+	assert.Equal(t, []*Session{s2, s3}, sessions)
+
+	_, err = m.Get(nil, s1.ID)
+	assert.Equal(t, ErrSessionNotFound, err)
+
+	// reject new.
+	m = New(store.New(0), SetMaxSessions(2, RejectNew))
+	s1, _ = m.Create(nil, alice)
+	s2, _ = m.Create(nil, alice)
+	_, err = m.Create(nil, alice)
+	assert.Equal(t, ErrMaxSessions, err)
+
+	sessions, _ = m.List(nil, "1")
+	assert.Equal(t, []*Session{s1, s2}, sessions)
+}
+
+func TestManagerMaxSessionsConcurrent(t *testing.T) {
+	m := New(store.New(0), SetMaxSessions(3, RejectNew))
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+	wg := new(sync.WaitGroup)
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = m.Create(nil, alice)
+		}()
+	}
+
+	wg.Wait()
+
+	sessions, err := m.List(nil, "1")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 3)
+}
+
+func TestManagerTimeouts(t *testing.T) {
+	c := store.New(0)
+	m := New(c, SetIdleTimeout(time.Minute), SetAbsoluteTimeout(time.Hour))
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+
+	table := []struct {
+		name      string
+		createdAt time.Duration
+		lastSeen  time.Duration
+		err       error
+	}{
+		{
+			name:      "it authenticate active session",
+			createdAt: -time.Minute * 30,
+			lastSeen:  -time.Second,
+		},
+		{
+			name:      "it return idle error",
+			createdAt: -time.Minute * 30,
+			lastSeen:  -time.Minute * 2,
+			err:       ErrSessionIdle,
+		},
+		{
+			name:      "it return expired error",
+			createdAt: -time.Hour * 2,
+			lastSeen:  -time.Second,
+			err:       ErrSessionExpired,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := m.Create(nil, alice)
+			s.CreatedAt = time.Now().Add(tt.createdAt)
+			s.LastSeen = time.Now().Add(tt.lastSeen)
+			_ = c.Store(sessionKey(s.ID), s, nil)
+
+			info, err := m.Authenticate(context.Background(), nil, s.ID)
+			assert.Equal(t, tt.err, err)
+
+			if tt.err != nil {
+				assert.Nil(t, info)
+				_, err = m.Get(nil, s.ID)
+				assert.Equal(t, ErrSessionNotFound, err)
+				return
+			}
+
+			got, _ := m.Get(nil, s.ID)
+			assert.Equal(t, alice, info)
+			assert.True(t, got.LastSeen.After(s.LastSeen))
+		})
+	}
+
+	var le auth.LocalizableError
+	assert.True(t, errors.As(ErrSessionIdle, &le))
+	assert.Equal(t, auth.MessageSessionIdle, le.MessageKey())
+	assert.True(t, errors.As(ErrSessionExpired, &le))
+	assert.Equal(t, auth.MessageSessionExpired, le.MessageKey())
+}
+
+func TestManagerListSkipsTimedOut(t *testing.T) {
+	c := store.New(0)
+	m := New(c, SetIdleTimeout(time.Minute), SetMaxSessions(1, RejectNew))
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+
+	s, _ := m.Create(nil, alice)
+	s.LastSeen = time.Now().Add(-time.Hour)
+	_ = c.Store(sessionKey(s.ID), s, nil)
+
+	// the timed out session does not count against the limit.
+	_, err := m.Create(nil, alice)
+	assert.NoError(t, err)
+
+	sessions, _ := m.List(nil, "1")
+	assert.Len(t, sessions, 1)
+	assert.NotEqual(t, s.ID, sessions[0].ID)
+}
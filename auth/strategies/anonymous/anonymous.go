@@ -0,0 +1,115 @@
+// Package anonymous provides authentication strategy,
+// to authenticate HTTP requests as a guest (anonymous) user,
+// to allow unauthenticated users to access public resources.
+//
+// The strategy must be ordered explicitly after the other strategies,
+// using Fallback or auth.Compose().FallbackAnonymous(anonymous.New()), and never enabled using
+// Authenticator EnableStrategy, since the authenticator strategies order not guaranteed,
+// and the guest user may win before the other strategies tried.
+package anonymous
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// StrategyKey export identifier for the anonymous strategy,
+// commonly used when enable/add strategy to go-guardian authenticator.
+const StrategyKey = auth.StrategyKey("Anonymous.Strategy")
+
+// DefaultUserName represents the default guest user name.
+const DefaultUserName = "anonymous"
+
+// InfoFunc define function signature to create guest user info per request.
+type InfoFunc func(ctx context.Context, r *http.Request) (auth.Info, error)
+
+type anonymous struct {
+	name   string
+	id     string
+	groups []string
+	exts   map[string][]string
+	fn     InfoFunc
+}
+
+func (a *anonymous) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	if a.fn != nil {
+		return a.fn(ctx, r)
+	}
+
+	return auth.NewUserInfo(a.name, a.id, a.groups, a.copyExtensions()), nil
+}
+
+func (a *anonymous) copyExtensions() map[string][]string {
+	if a.exts == nil {
+		return nil
+	}
+
+	exts := make(map[string][]string, len(a.exts))
+	for k, v := range a.exts {
+		exts[k] = v
+	}
+
+	return exts
+}
+
+type fallback struct {
+	auth.Strategy
+	guest auth.Strategy
+}
+
+func (f fallback) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	info, err := f.Strategy.Authenticate(ctx, r)
+	if err != nil {
+		return f.guest.Authenticate(ctx, r)
+	}
+	return info, nil
+}
+
+func (f fallback) Unwrap() auth.Strategy {
+	return f.Strategy
+}
+
+// New return anonymous strategy that authenticate every request as a guest user.
+// The guest user configured centrally using SetUserName, SetID, SetGroups,
+// and SetExtensions or per request using SetInfoFunc.
+// Use Fallback to order it after another strategy, See the package doc.
+func New(opts ...auth.Option) auth.Strategy {
+	a := &anonymous{
+		name: DefaultUserName,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(a)
+	}
+
+	return a
+}
+
+// Fallback return strategy that authenticate request using the given strategy,
+// and fallback to the guest user when the given strategy return an error.
+//
+// WARNING: Fallback treats any strategy error as unauthenticated request,
+// including invalid credentials, so protected resources must check the guest user explicitly.
+func Fallback(s auth.Strategy, opts ...auth.Option) auth.Strategy {
+	return fallback{
+		Strategy: s,
+		guest:    New(opts...),
+	}
+}
+
+// RemoteAddrInfo return InfoFunc that creates guest user per request,
+// where the user id is the request remote IP address,
+// Typically used to rate limit or audit guests by their address.
+func RemoteAddrInfo(opts ...auth.Option) InfoFunc {
+	a := New(opts...).(*anonymous)
+	return func(ctx context.Context, r *http.Request) (auth.Info, error) {
+		id, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			id = r.RemoteAddr
+		}
+		return auth.NewUserInfo(a.name, id, a.groups, a.copyExtensions()), nil
+	}
+}
@@ -0,0 +1,80 @@
+package anonymous
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestNew(t *testing.T) {
+	table := []struct {
+		name     string
+		opts     []auth.Option
+		expected auth.Info
+	}{
+		{
+			name:     "it return default guest user",
+			expected: auth.NewDefaultUser(DefaultUserName, "", nil, nil),
+		},
+		{
+			name: "it return configured guest user",
+			opts: []auth.Option{
+				SetUserName("guest"),
+				SetID("0"),
+				SetGroups([]string{"guests"}),
+				SetExtensions(map[string][]string{"tier": {"free"}}),
+			},
+			expected: auth.NewDefaultUser("guest", "0", []string{"guests"}, map[string][]string{"tier": {"free"}}),
+		},
+		{
+			name: "it return guest user from info func",
+			opts: []auth.Option{
+				SetUserName("guest"),
+				SetInfoFunc(RemoteAddrInfo(SetUserName("ip-guest"))),
+			},
+			expected: auth.NewDefaultUser("ip-guest", "127.0.0.1", nil, nil),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.RemoteAddr = "127.0.0.1:8080"
+
+			info, err := New(tt.opts...).Authenticate(r.Context(), r)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, info)
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	user := auth.NewDefaultUser("user", "1", nil, nil)
+
+	s := Fallback(strategyFunc(func(context.Context, *http.Request) (auth.Info, error) {
+		return user, nil
+	}))
+	info, err := s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, user, info)
+
+	s = Fallback(strategyFunc(func(context.Context, *http.Request) (auth.Info, error) {
+		return nil, fmt.Errorf("error")
+	}), SetUserName("guest"))
+	info, err = s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "guest", info.UserName())
+}
+
+type strategyFunc func(ctx context.Context, r *http.Request) (auth.Info, error)
+
+func (fn strategyFunc) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	return fn(ctx, r)
+}
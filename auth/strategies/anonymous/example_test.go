@@ -0,0 +1,22 @@
+package anonymous
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+)
+
+func ExampleFallback() {
+	strategy := Fallback(
+		token.NewStatic(nil),
+		SetUserName("guest"),
+		SetGroups([]string{"public"}),
+	)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	info, err := strategy.Authenticate(r.Context(), r)
+	fmt.Println(info.UserName(), info.Groups(), err)
+	// Output:
+	// guest [public] <nil>
+}
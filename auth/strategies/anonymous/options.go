@@ -0,0 +1,50 @@
+package anonymous
+
+import "github.com/shaj13/go-guardian/auth"
+
+// SetUserName sets the guest user name.
+// Default "anonymous".
+func SetUserName(name string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*anonymous); ok {
+			a.name = name
+		}
+	})
+}
+
+// SetID sets the guest user id.
+func SetID(id string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*anonymous); ok {
+			a.id = id
+		}
+	})
+}
+
+// SetGroups sets the guest user groups.
+func SetGroups(groups []string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*anonymous); ok {
+			a.groups = groups
+		}
+	})
+}
+
+// SetExtensions sets the guest user extensions.
+func SetExtensions(exts map[string][]string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*anonymous); ok {
+			a.exts = exts
+		}
+	})
+}
+
+// SetInfoFunc sets function to create guest user info per request,
+// it takes precedence over the static guest user configuration.
+func SetInfoFunc(fn InfoFunc) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*anonymous); ok {
+			a.fn = fn
+		}
+	})
+}
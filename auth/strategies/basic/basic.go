@@ -5,22 +5,32 @@ package basic
 import (
 	"context"
 	"crypto"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/shaj13/go-guardian/auth"
 	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal"
 	"github.com/shaj13/go-guardian/store"
 )
 
 // ErrMissingPrams is returned by Authenticate Strategy method,
 // when failed to retrieve user credentials from request.
-var ErrMissingPrams = errors.New("basic: Request missing BasicAuth")
+var ErrMissingPrams = gerrors.WithReason(
+	errors.New("basic: Request missing BasicAuth"),
+	gerrors.ReasonMissingCredentials,
+)
 
 // ErrInvalidCredentials is returned by Authenticate Strategy method,
 // when user password is invalid.
-var ErrInvalidCredentials = errors.New("basic: Invalid user credentials")
+var ErrInvalidCredentials = gerrors.WithReason(
+	errors.New("basic: Invalid user credentials"),
+	gerrors.ReasonInvalidCredentials,
+)
 
 // StrategyKey export identifier for the basic strategy,
 // commonly used when enable/add strategy to go-guardian authenticator.
@@ -38,7 +48,7 @@ type AuthenticateFunc func(ctx context.Context, r *http.Request, userName, passw
 
 // Authenticate implement Authenticate Strategy method, and return user info or an appropriate error.
 func (auth AuthenticateFunc) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
-	user, pass, err := auth.credentials(r)
+	user, pass, err := auth.credentials(r.Header)
 
 	if err != nil {
 		return nil, err
@@ -47,14 +57,27 @@ func (auth AuthenticateFunc) Authenticate(ctx context.Context, r *http.Request)
 	return auth(ctx, r, user, pass)
 }
 
+// AuthenticateRequest behaves as Authenticate,
+// but extract user credentials from the transport agnostic request,
+// the authenticate function receive auth.HTTPRequest of it.
+func (auth AuthenticateFunc) AuthenticateRequest(ctx context.Context, r auth.Request) (auth.Info, error) {
+	user, pass, err := auth.credentials(r.Header())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return auth(ctx, httpRequest(r), user, pass)
+}
+
 // Challenge returns string indicates the authentication scheme.
 // Typically used to adds a HTTP WWW-Authenticate header.
 func (auth AuthenticateFunc) Challenge(realm string) string {
 	return fmt.Sprintf(`Basic realm="%s", title="'Basic' HTTP Authentication Scheme"`, realm)
 }
 
-func (auth AuthenticateFunc) credentials(r *http.Request) (string, string, error) {
-	user, pass, ok := r.BasicAuth()
+func (auth AuthenticateFunc) credentials(h http.Header) (string, string, error) {
+	user, pass, ok := parseBasicAuth(h.Get("Authorization"))
 
 	if !ok {
 		return "", "", ErrMissingPrams
@@ -63,14 +86,83 @@ func (auth AuthenticateFunc) credentials(r *http.Request) (string, string, error
 	return user, pass, nil
 }
 
+// httpRequest is auth.HTTPRequest, since AuthenticateFunc methods receiver shadows the auth package.
+var httpRequest = auth.HTTPRequest
+
+// maxPooledBuffer is the max capacity of a decode buffer returned to the pool,
+// to not pin memory of oversized headers.
+const maxPooledBuffer = 1 << 10
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 128)
+		return &b
+	},
+}
+
+// parseBasicAuth parses an HTTP Basic Authentication header value,
+// it behaves as http.Request BasicAuth, but decodes the credentials into a pooled buffer,
+// so the only allocation is the returned credentials string.
+func parseBasicAuth(header string) (user, pass string, ok bool) {
+	const prefix = "Basic "
+
+	if len(header) < len(prefix) || !equalFoldASCII(header[:len(prefix)], prefix) {
+		return "", "", false
+	}
+
+	src := header[len(prefix):]
+	bp := bufPool.Get().(*[]byte)
+	defer func() {
+		if cap(*bp) <= maxPooledBuffer {
+			bufPool.Put(bp)
+		}
+	}()
+
+	if n := base64.StdEncoding.DecodedLen(len(src)); cap(*bp) < n {
+		*bp = make([]byte, n)
+	}
+
+	buf := (*bp)[:cap(*bp)]
+	n, err := base64.StdEncoding.Decode(buf, []byte(src))
+	if err != nil {
+		return "", "", false
+	}
+
+	cs := string(buf[:n])
+	i := strings.IndexByte(cs, ':')
+	if i < 0 {
+		return "", "", false
+	}
+
+	return cs[:i], cs[i+1:], true
+}
+
+func equalFoldASCII(s, t string) bool {
+	for i := 0; i < len(s); i++ {
+		a, b := s[i], t[i]
+		if 'A' <= a && a <= 'Z' {
+			a += 'a' - 'A'
+		}
+		if 'A' <= b && b <= 'Z' {
+			b += 'a' - 'A'
+		}
+		if a != b {
+			return false
+		}
+	}
+	return true
+}
+
 type cachedBasic struct {
 	AuthenticateFunc
 	comparator Comparator
 	cache      store.Cache
+	cost       int
+	hashKeys   bool
 }
 
 func (c *cachedBasic) authenticate(ctx context.Context, r *http.Request, userName, pass string) (auth.Info, error) { // nolint:lll
-	v, ok, err := c.cache.Load(userName, r)
+	v, ok, err := c.cache.Load(c.key(userName), r)
 
 	if err != nil {
 		return nil, err
@@ -107,46 +199,140 @@ func (c *cachedBasic) authenticatAndHash(ctx context.Context, r *http.Request, u
 		ext = make(map[string][]string)
 	}
 
-	hashedPass, _ := c.comparator.Hash(pass)
+	hashedPass, err := c.comparator.Hash(pass)
+	if err != nil {
+		return nil, err
+	}
+
 	ext[ExtensionKey] = []string{hashedPass}
 	info.SetExtensions(ext)
 
 	// cache result
-	if err := c.cache.Store(userName, info, r); err != nil {
+	if err := c.cache.Store(c.key(userName), info, r); err != nil {
 		return nil, err
 	}
 
 	return info, nil
 }
 
+// key return the cache key of the given user name.
+func (c *cachedBasic) key(userName string) string {
+	if c.hashKeys {
+		return internal.HashKey(userName)
+	}
+	return userName
+}
+
 // New return new auth.Strategy.
-// The returned strategy, caches the invocation result of authenticate function.
-func New(f AuthenticateFunc, cache store.Cache) auth.Strategy {
-	return NewWithOptions(f, cache)
+// The returned strategy, caches the invocation result of authenticate function,
+// along with a salted hash of the user password, SHA256 by default,
+// so the cache never holds plaintext equivalent credentials.
+func New(f AuthenticateFunc, cache store.Cache, opts ...auth.Option) auth.Strategy {
+	return NewWithOptions(f, cache, opts...)
 }
 
-// NewWithOptions return new auth.Strategy.
-// The returned strategy, caches the invocation result of authenticate function.
+// NewWithOptions return new auth.Strategy, See New.
+//
+// Deprecated: New accepts options, use New instead.
 func NewWithOptions(f AuthenticateFunc, cache store.Cache, opts ...auth.Option) auth.Strategy {
 	cb := &cachedBasic{
 		AuthenticateFunc: f,
 		cache:            cache,
-		comparator:       plainText{},
+		comparator:       basicHashing{h: crypto.SHA256},
+	}
+
+	st := &strategy{
+		AuthenticateFunc: cb.authenticate,
 	}
 
 	for _, opt := range opts {
 		opt.Apply(cb)
+		opt.Apply(st)
+	}
+
+	if b, ok := cb.comparator.(basicHashing); ok && cb.cost > 0 {
+		b.cost = cb.cost
+		cb.comparator = b
 	}
 
-	return AuthenticateFunc(cb.authenticate)
+	return st
+}
+
+type strategy struct {
+	AuthenticateFunc
+	realm   string
+	charset string
+}
+
+// Challenge returns string indicates the authentication scheme,
+// using the strategy realm when the given realm empty,
+// and the strategy charset auth-param as defined in RFC 7617.
+func (s *strategy) Challenge(realm string) string {
+	if len(realm) == 0 {
+		realm = s.realm
+	}
+
+	c := s.AuthenticateFunc.Challenge(realm)
+
+	if len(s.charset) > 0 {
+		c += fmt.Sprintf(`, charset="%s"`, s.charset)
+	}
+
+	return c
+}
+
+// SetRealm sets the strategy protection space realm,
+// used in the challenge when the realm passed at challenge time empty.
+// Typically used by deployments having multiple realms, so each strategy challenge its own realm.
+func SetRealm(realm string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*strategy); ok {
+			v.realm = realm
+		}
+	})
+}
+
+// SetCharset sets the charset auth-param advertised in the challenge,
+// to let clients know the server expects the user name and password encoded using it.
+// RFC 7617 only allows "UTF-8".
+func SetCharset(charset string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*strategy); ok {
+			v.charset = charset
+		}
+	})
 }
 
-// SetHash set the hashing algorithm to hash the user password.
+// SetHash set the hashing algorithm to hash the user password,
+// the password hashed using PBKDF2 with a random salt, See SetHashCost.
 func SetHash(h crypto.Hash) auth.Option {
-	b := basicHashing{h}
+	b := basicHashing{h: h}
 	return SetComparator(b)
 }
 
+// SetHashCost set the PBKDF2 iterations count used to hash the user password,
+// Higher cost slows down brute forcing of leaked cache entries,
+// at the expense of hashing the password on every cache hit.
+// Default DefaultCacheHashCost.
+func SetHashCost(cost int) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedBasic); ok {
+			v.cost = cost
+		}
+	})
+}
+
+// SetHashedKeys enable or disable keying cache entries by the SHA-256 of the user name,
+// instead of the raw user name, so user names never sit as plaintext keys in shared caches (e.g Redis, Disk).
+// Default disabled.
+func SetHashedKeys(enabled bool) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedBasic); ok {
+			v.hashKeys = enabled
+		}
+	})
+}
+
 // SetComparator set password comparator.
 func SetComparator(c Comparator) auth.Option {
 	return auth.OptionFunc(func(v interface{}) {
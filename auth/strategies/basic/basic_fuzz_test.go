@@ -0,0 +1,40 @@
+//go:build go1.18
+// +build go1.18
+
+package basic
+
+import (
+	"net/http"
+	"testing"
+)
+
+func FuzzParseBasicAuth(f *testing.F) {
+	seeds := []string{
+		"",
+		"Basic dGVzdDpwYXNz",
+		"basic dGVzdDpwOmFzcw==",
+		"Basic Og==",
+		"Basic dGVzdA==",
+		"Basic !!!",
+		"Basic dGVz\ndDpwYXNz",
+		"Baſic dGVzdDpwYXNz",
+		"Bearer dGVzdDpwYXNz",
+	}
+
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, header string) {
+		r := &http.Request{Header: http.Header{"Authorization": {header}}}
+		wantUser, wantPass, wantOK := r.BasicAuth()
+		user, pass, ok := parseBasicAuth(header)
+
+		if ok != wantOK || user != wantUser || pass != wantPass {
+			t.Fatalf(
+				"parseBasicAuth(%q) = %q, %q, %v, want %q, %q, %v",
+				header, user, pass, ok, wantUser, wantPass, wantOK,
+			)
+		}
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
 )
 
 //nolint:goconst
@@ -76,6 +77,46 @@ func TestChallenge(t *testing.T) {
 	assert.Equal(t, expected, got)
 }
 
+func TestStrategyChallenge(t *testing.T) {
+	table := []struct {
+		name     string
+		opts     []auth.Option
+		realm    string
+		expected string
+	}{
+		{
+			name:     "it use the given realm",
+			realm:    "Test Realm",
+			expected: `Basic realm="Test Realm", title="'Basic' HTTP Authentication Scheme"`,
+		},
+		{
+			name:     "it use the strategy realm when given realm empty",
+			opts:     []auth.Option{SetRealm("Strategy Realm")},
+			expected: `Basic realm="Strategy Realm", title="'Basic' HTTP Authentication Scheme"`,
+		},
+		{
+			name:     "it prefer the given realm over the strategy realm",
+			opts:     []auth.Option{SetRealm("Strategy Realm")},
+			realm:    "Test Realm",
+			expected: `Basic realm="Test Realm", title="'Basic' HTTP Authentication Scheme"`,
+		},
+		{
+			name:     "it add charset auth-param",
+			opts:     []auth.Option{SetRealm("Strategy Realm"), SetCharset("UTF-8")},
+			expected: `Basic realm="Strategy Realm", title="'Basic' HTTP Authentication Scheme", charset="UTF-8"`,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(exampleAuthFunc, store.New(0), tt.opts...)
+			got, ok := auth.Challenge(s, tt.realm)
+			assert.True(t, ok)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
 //nolint:goconst
 func TestNewCached(t *testing.T) {
 	authFunc := func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
@@ -133,6 +174,10 @@ func TestNewCached(t *testing.T) {
 		},
 	}
 
+	// salted PBKDF2-SHA256 of "test" password.
+	hash := "1$000102030405060708090a0b0c0d0e0f$" +
+		"621f794bcc55e1061d6c46cdbe8fe251e34b21bfdfd798a1bdf78f0541fac18e"
+
 	for _, tt := range table {
 		t.Run(tt.name, func(t *testing.T) {
 			r, _ := http.NewRequest("GET", "/", nil)
@@ -145,13 +190,13 @@ func TestNewCached(t *testing.T) {
 				"10",
 				nil,
 				map[string][]string{
-					ExtensionKey: {"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"},
+					ExtensionKey: {hash},
 				},
 			)
 			c.cache["predefined3"] = auth.NewDefaultUser("predefined3", "10", nil, nil)
 
 			opt := SetHash(crypto.SHA256)
-			info, err := NewWithOptions(authFunc, c, opt).Authenticate(r.Context(), r)
+			info, err := New(authFunc, c, opt).Authenticate(r.Context(), r)
 
 			assert.Equal(t, tt.expectedErr, err != nil, "%s: Got Unexpected error %v", tt.name, err)
 			assert.Equal(t, !tt.expectedErr, info != nil, "%s: Expected info object, got nil", tt.name)
@@ -159,6 +204,74 @@ func TestNewCached(t *testing.T) {
 	}
 }
 
+type errComparator struct{}
+
+func (errComparator) Hash(string) (string, error) { return "", fmt.Errorf("hash error") }
+func (errComparator) Verify(string, string) error { return nil }
+
+func TestNewCachedHashError(t *testing.T) {
+	authFunc := func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		return auth.NewDefaultUser("test", "10", nil, nil), nil
+	}
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("test", "test")
+
+	c := newMockCache()
+	info, err := New(authFunc, c, SetComparator(errComparator{})).Authenticate(r.Context(), r)
+
+	assert.EqualError(t, err, "hash error")
+	assert.Nil(t, info)
+	assert.Empty(t, c.cache, "user must not be cached without password hash")
+}
+
+func TestParseBasicAuth(t *testing.T) {
+	table := []struct {
+		name   string
+		header string
+		user   string
+		pass   string
+		ok     bool
+	}{
+		{name: "it return credentials", header: "Basic dGVzdDpwYXNz", user: "test", pass: "pass", ok: true},
+		{name: "it fold scheme case", header: "bAsIc dGVzdDpwYXNz", user: "test", pass: "pass", ok: true},
+		{name: "it keep password colons", header: "Basic dGVzdDpwOmFzcw==", user: "test", pass: "p:ass", ok: true},
+		{name: "it return empty credentials", header: "Basic Og==", ok: true},
+		{name: "it return false when header missing"},
+		{name: "it return false when scheme mismatch", header: "Bearer dGVzdDpwYXNz"},
+		{name: "it return false when credentials not base64", header: "Basic !!!"},
+		{name: "it return false when colon missing", header: "Basic dGVzdA=="},
+		{name: "it return false for non ascii scheme", header: "Baſic dGVzdDpwYXNz"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			user, pass, ok := parseBasicAuth(tt.header)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.user, user)
+			assert.Equal(t, tt.pass, pass)
+		})
+	}
+}
+
+func TestSetHashedKeys(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("test", "test")
+
+	cache := store.New(0)
+	strategy := New(exampleAuthFunc, cache, SetHashedKeys(true))
+
+	_, err := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	// SHA-256 of "test" user name.
+	key := "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	assert.Equal(t, []string{key}, cache.Keys())
+
+	_, err = strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+}
+
 func BenchmarkCachedBasic(b *testing.B) {
 	r, _ := http.NewRequest("GET", "/", nil)
 	r.SetBasicAuth("test", "test")
@@ -232,3 +345,28 @@ func newMockCache() mockCache {
 		mu:    new(sync.Mutex),
 	}
 }
+
+func TestAuthenticateRequest(t *testing.T) {
+	fn := AuthenticateFunc(func(_ context.Context, r *http.Request, user, pass string) (auth.Info, error) {
+		if user == "test" && pass == "test" && r.Header.Get("X-Id") == "10" {
+			return auth.NewDefaultUser("test", "10", nil, nil), nil
+		}
+		return nil, ErrInvalidCredentials
+	})
+
+	s := auth.Strategy(fn)
+	assert.Implements(t, (*auth.RequestStrategy)(nil), s)
+
+	req := auth.MetadataRequest(context.Background(), map[string][]string{
+		"authorization": {"Basic dGVzdDp0ZXN0"},
+		"x-id":          {"10"},
+	}, nil, nil)
+
+	info, err := auth.AuthenticateWith(s, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "10", info.ID())
+
+	req = auth.MetadataRequest(context.Background(), map[string][]string{}, nil, nil)
+	_, err = auth.AuthenticateWith(s, req)
+	assert.Equal(t, ErrMissingPrams, err)
+}
@@ -2,8 +2,14 @@ package basic
 
 import (
 	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	_ "crypto/sha256" // register sha256 for the default comparator.
 	"crypto/subtle"
+	"encoding/binary"
 	"encoding/hex"
+	"strconv"
+	"strings"
 )
 
 // Comparator is the interface implemented by types,
@@ -14,39 +20,82 @@ type Comparator interface {
 	Verify(hashedPassword, password string) error
 }
 
+// DefaultCacheHashCost represents the PBKDF2 iterations count used to hash the cached user passwords,
+// See SetHashCost.
+const DefaultCacheHashCost = 10000
+
+// basicHashing hash password using PBKDF2 with a random salt,
+// and encode the hash as cost$salt$sum hex encoded.
 type basicHashing struct {
-	h crypto.Hash
+	h    crypto.Hash
+	cost int
 }
 
 func (b basicHashing) Hash(password string) (string, error) {
-	hasher := b.h.New()
-	_, _ = hasher.Write([]byte(password))
-	sum := hasher.Sum(nil)
-	return hex.EncodeToString(sum), nil
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	cost := b.cost
+	if cost < 1 {
+		cost = DefaultCacheHashCost
+	}
+
+	sum := pbkdf2(b.h, []byte(password), salt, cost)
+
+	return strconv.Itoa(cost) + "$" + hex.EncodeToString(salt) + "$" + hex.EncodeToString(sum), nil
 }
 
 func (b basicHashing) Verify(hashedPassword, password string) error {
-	hash, err := b.Hash(password)
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 3 {
+		return ErrInvalidCredentials
+	}
+
+	cost, err := strconv.Atoi(parts[0])
+	if err != nil || cost < 1 {
+		return ErrInvalidCredentials
+	}
+
+	salt, err := hex.DecodeString(parts[1])
 	if err != nil {
-		return err
+		return ErrInvalidCredentials
 	}
 
-	if subtle.ConstantTimeCompare([]byte(hash), []byte(hashedPassword)) == 1 {
+	sum := hex.EncodeToString(pbkdf2(b.h, []byte(password), salt, cost))
+
+	if subtle.ConstantTimeCompare([]byte(sum), []byte(parts[2])) == 1 {
 		return nil
 	}
 
 	return ErrInvalidCredentials
 }
 
-type plainText struct{}
+// pbkdf2 derive a key as defined in RFC 8018 section 5.2,
+// with a key length equal to the hash size, so only the first block computed.
+func pbkdf2(h crypto.Hash, password, salt []byte, iter int) []byte {
+	prf := hmac.New(h.New, password)
 
-func (p plainText) Hash(password string) (string, error) {
-	return password, nil
-}
+	buf := make([]byte, 4)
+	binary.BigEndian.PutUint32(buf, 1)
 
-func (p plainText) Verify(hashedPassword, password string) error {
-	if subtle.ConstantTimeCompare([]byte(hashedPassword), []byte(password)) == 1 {
-		return nil
+	_, _ = prf.Write(salt)
+	_, _ = prf.Write(buf)
+	u := prf.Sum(nil)
+
+	t := make([]byte, len(u))
+	copy(t, u)
+
+	for i := 1; i < iter; i++ {
+		prf.Reset()
+		_, _ = prf.Write(u)
+		u = prf.Sum(u[:0])
+
+		for j := range t {
+			t[j] ^= u[j]
+		}
 	}
-	return ErrInvalidCredentials
+
+	return t
 }
@@ -2,6 +2,8 @@ package basic
 
 import (
 	"crypto"
+	"encoding/hex"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,17 +20,30 @@ func TestBasicHashing(t *testing.T) {
 	assert.NotEqual(t, hash, pass)
 	assert.NoError(t, match)
 	assert.Equal(t, ErrInvalidCredentials, missmatch)
+
+	assert.True(t, strings.HasPrefix(hash, "10000$"), "hash must use the default cost")
+
+	again, _ := b.Hash(pass)
+	assert.NotEqual(t, hash, again, "hash must be salted")
+	assert.Equal(t, ErrInvalidCredentials, b.Verify("malformed", pass))
 }
 
-func TestPlainText(t *testing.T) {
-	p := plainText{}
-	pass := "password"
-	hash, err := p.Hash(pass)
-	match := p.Verify(hash, pass)
-	missmatch := p.Verify(hash, "pass")
+func TestPBKDF2(t *testing.T) {
+	// RFC 7914 section 11 PBKDF2-HMAC-SHA256 test vector truncated to the hash size.
+	key := pbkdf2(crypto.SHA256, []byte("passwd"), []byte("salt"), 1)
+	assert.Equal(t, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc", hex.EncodeToString(key))
 
-	assert.NoError(t, err)
-	assert.Equal(t, hash, pass)
-	assert.NoError(t, match)
-	assert.Equal(t, ErrInvalidCredentials, missmatch)
+	key = pbkdf2(crypto.SHA256, []byte("Password"), []byte("NaCl"), 80000)
+	assert.Equal(t, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56", hex.EncodeToString(key))
+}
+
+func TestSetHashCost(t *testing.T) {
+	cb := &cachedBasic{}
+	SetHashCost(10).Apply(cb)
+	assert.Equal(t, 10, cb.cost)
+
+	b := basicHashing{h: crypto.SHA256, cost: 10}
+	hash, _ := b.Hash("password")
+	assert.True(t, strings.HasPrefix(hash, "10$"))
+	assert.NoError(t, b.Verify(hash, "password"))
 }
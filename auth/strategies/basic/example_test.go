@@ -58,7 +58,7 @@ func Example_second() {
 func ExampleSetHash() {
 	opt := SetHash(crypto.SHA256) // import _ crypto/sha256
 	cache := store.New(2)
-	NewWithOptions(exampleAuthFunc, cache, opt)
+	New(exampleAuthFunc, cache, opt)
 }
 
 func exampleAuthFunc(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
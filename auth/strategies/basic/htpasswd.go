@@ -0,0 +1,111 @@
+package basic
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/sha1" // nolint:gosec
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+const (
+	// htpasswdPBKDF2 prefix the user files entries hashed using HashPassword.
+	htpasswdPBKDF2 = "{PBKDF2-SHA256}"
+	// htpasswdSHA prefix the apache htpasswd -s entries.
+	htpasswdSHA = "{SHA}"
+	// DefaultHashCost represents the PBKDF2 iterations count used by HashPassword.
+	DefaultHashCost = 100000
+)
+
+// HashPassword hash the password for user files read by Htpasswd,
+// using PBKDF2-SHA256 with a random salt and the given iterations count,
+// DefaultHashCost used when cost less than 1.
+func HashPassword(password string, cost int) (string, error) {
+	if cost < 1 {
+		cost = DefaultHashCost
+	}
+
+	h, err := basicHashing{h: crypto.SHA256, cost: cost}.Hash(password)
+	if err != nil {
+		return "", err
+	}
+
+	return htpasswdPBKDF2 + h, nil
+}
+
+// HtpasswdFromFile return AuthenticateFunc that authenticate users against the given htpasswd file,
+// See Htpasswd.
+func HtpasswdFromFile(path string) (AuthenticateFunc, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Htpasswd(f)
+}
+
+// Htpasswd return AuthenticateFunc that authenticate users against the htpasswd entries read from r.
+// Each line holds an entry in the form `user:hash`, empty lines and lines starting with # ignored.
+// Supported hashes are PBKDF2-SHA256 produced by HashPassword, and SHA-1 produced by apache htpasswd -s.
+// Other formats (e.g bcrypt, MD5, crypt) rejected since their implementations not available.
+// The returned function authenticate users as user info where both name and id are the user name.
+func Htpasswd(r io.Reader) (AuthenticateFunc, error) {
+	users := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.IndexByte(line, ':')
+		if i < 1 {
+			return nil, fmt.Errorf("basic: htpasswd entry must be in the form user:hash, Line: %d", n)
+		}
+
+		user, hash := line[:i], line[i+1:]
+		if !strings.HasPrefix(hash, htpasswdPBKDF2) && !strings.HasPrefix(hash, htpasswdSHA) {
+			return nil, fmt.Errorf("basic: htpasswd entry has unsupported hash format, Line: %d", n)
+		}
+
+		users[user] = hash
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		hash, ok := users[userName]
+		if !ok || verifyHtpasswd(hash, password) != nil {
+			return nil, ErrInvalidCredentials
+		}
+
+		return auth.NewUserInfo(userName, userName, nil, nil), nil
+	}, nil
+}
+
+func verifyHtpasswd(hash, password string) error {
+	if strings.HasPrefix(hash, htpasswdPBKDF2) {
+		return basicHashing{h: crypto.SHA256}.Verify(strings.TrimPrefix(hash, htpasswdPBKDF2), password)
+	}
+
+	sum := sha1.Sum([]byte(password)) // nolint:gosec
+	encoded := base64.StdEncoding.EncodeToString(sum[:])
+
+	if subtle.ConstantTimeCompare([]byte(encoded), []byte(strings.TrimPrefix(hash, htpasswdSHA))) == 1 {
+		return nil
+	}
+
+	return ErrInvalidCredentials
+}
@@ -0,0 +1,58 @@
+package basic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHtpasswd(t *testing.T) {
+	hash, err := HashPassword("secret", 10)
+	assert.NoError(t, err)
+
+	file := "# users\n\n" +
+		"alice:" + hash + "\n" +
+		// htpasswd -bns bob password
+		"bob:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n"
+
+	fn, err := Htpasswd(strings.NewReader(file))
+	assert.NoError(t, err)
+
+	table := []struct {
+		name        string
+		user        string
+		password    string
+		expectedErr bool
+	}{
+		{name: "it authenticate pbkdf2 entry", user: "alice", password: "secret"},
+		{name: "it authenticate sha entry", user: "bob", password: "password"},
+		{name: "it return error when password invalid", user: "alice", password: "invalid", expectedErr: true},
+		{name: "it return error when user not found", user: "eve", password: "secret", expectedErr: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := fn(context.Background(), nil, tt.user, tt.password)
+			if tt.expectedErr {
+				assert.Equal(t, ErrInvalidCredentials, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.user, info.ID())
+		})
+	}
+}
+
+func TestHtpasswdInvalidEntries(t *testing.T) {
+	for _, file := range []string{
+		"alice",
+		"alice:$2y$05$bcrypt",
+		":{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+	} {
+		_, err := Htpasswd(strings.NewReader(file))
+		assert.Error(t, err)
+	}
+}
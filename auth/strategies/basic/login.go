@@ -0,0 +1,160 @@
+package basic
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// maxLoginBody is the max login request body size.
+const maxLoginBody = 1 << 20
+
+// ErrMissingLoginFields is returned by the login handler,
+// when the request missing the user name or the password fields.
+var ErrMissingLoginFields = gerrors.WithReason(
+	errors.New("basic: Login request missing user name or password"),
+	gerrors.ReasonMissingCredentials,
+)
+
+// SuccessHandler define function signature invoked by the login handler,
+// when the user successfully logged in, typically used to establish a session,
+// or issue a go-guardian token, and redirect the user to the application.
+type SuccessHandler func(w http.ResponseWriter, r *http.Request, info auth.Info)
+
+// TokenIssuer define function signature to issue a token for the authenticated user,
+// e.g a JWT issued by jwt.IssueAccessToken, or an opaque token stored by token.NewStatic.
+type TokenIssuer func(r *http.Request, info auth.Info) (string, error)
+
+// TokenSuccessHandler return SuccessHandler that issue a token for the logged in user,
+// and writes it as a JSON bearer token response, i.e {"access_token":"<token>","token_type":"Bearer"}.
+func TokenSuccessHandler(issue TokenIssuer) SuccessHandler {
+	return func(w http.ResponseWriter, r *http.Request, info auth.Info) {
+		tk, err := issue(r, info)
+		if err != nil {
+			http.Error(w, auth.StatusMessage(r, http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": tk,
+			"token_type":   "Bearer",
+		})
+	}
+}
+
+// SetLoginFields sets the login request user name and password fields names,
+// Default username and password.
+func SetLoginFields(userName, password string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if l, ok := v.(*loginHandler); ok {
+			l.userField = userName
+			l.passField = password
+		}
+	})
+}
+
+// SetLoginErrorHandler sets the login handler error handler,
+// invoked when the request malformed or the credentials invalid.
+// Default auth.PlainTextErrorHandler.
+func SetLoginErrorHandler(h auth.ErrorHandler) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if l, ok := v.(*loginHandler); ok {
+			l.errHandler = h
+		}
+	})
+}
+
+// LoginHandler return HTTP handler that accepts the user name and password,
+// from a POST HTML form or JSON body, authenticate them using the given function,
+// and invoke the success handler with the user information stored in the request context,
+// bridging browser login pages to the header-oriented strategies.
+// Credentials never read from the URL query, to not leak them in access logs.
+// The handler must be wrapped with a CSRF protection middleware when used with HTML forms.
+func LoginHandler(fn AuthenticateFunc, success SuccessHandler, opts ...auth.Option) http.Handler {
+	if fn == nil {
+		panic("Authenticate Func required and can't be nil")
+	}
+
+	if success == nil {
+		panic("Success Handler required and can't be nil")
+	}
+
+	l := &loginHandler{
+		fn:         fn,
+		success:    success,
+		userField:  "username",
+		passField:  "password",
+		errHandler: auth.PlainTextErrorHandler,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(l)
+	}
+
+	return l
+}
+
+type loginHandler struct {
+	fn         AuthenticateFunc
+	success    SuccessHandler
+	userField  string
+	passField  string
+	errHandler auth.ErrorHandler
+}
+
+func (l *loginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, auth.StatusMessage(r, http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	user, pass, err := l.credentials(w, r)
+	if err != nil {
+		l.errHandler(w, r, err)
+		return
+	}
+
+	info, err := l.fn(r.Context(), r, user, pass)
+	if err != nil {
+		l.errHandler(w, r, err)
+		return
+	}
+
+	l.success(w, auth.RequestWithUser(info, r), info)
+}
+
+func (l *loginHandler) credentials(w http.ResponseWriter, r *http.Request) (string, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxLoginBody)
+
+	var user, pass string
+
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "application/json" {
+		fields := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			return "", "", ErrMissingLoginFields
+		}
+
+		user, _ = fields[l.userField].(string)
+		pass, _ = fields[l.passField].(string)
+	} else {
+		if err := r.ParseForm(); err != nil {
+			return "", "", ErrMissingLoginFields
+		}
+
+		user = r.PostForm.Get(l.userField)
+		pass = r.PostForm.Get(l.passField)
+	}
+
+	if user == "" || pass == "" {
+		return "", "", ErrMissingLoginFields
+	}
+
+	return user, pass, nil
+}
@@ -0,0 +1,133 @@
+package basic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestLoginHandler(t *testing.T) {
+	fn := func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		if userName == "test" && password == "test" {
+			return auth.NewDefaultUser("test", "10", nil, nil), nil
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	success := func(w http.ResponseWriter, r *http.Request, info auth.Info) {
+		_, _ = w.Write([]byte(auth.User(r).ID()))
+	}
+
+	form := func(v url.Values) func() *http.Request {
+		return func() *http.Request {
+			r, _ := http.NewRequest("POST", "/login", strings.NewReader(v.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return r
+		}
+	}
+
+	table := []struct {
+		name    string
+		request func() *http.Request
+		opts    []auth.Option
+		code    int
+		body    string
+	}{
+		{
+			name:    "it login user using form",
+			request: form(url.Values{"username": {"test"}, "password": {"test"}}),
+			code:    http.StatusOK,
+			body:    "10",
+		},
+		{
+			name: "it login user using json body",
+			request: func() *http.Request {
+				r, _ := http.NewRequest("POST", "/login", strings.NewReader(`{"email":"test","secret":"test"}`))
+				r.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return r
+			},
+			opts: []auth.Option{SetLoginFields("email", "secret")},
+			code: http.StatusOK,
+			body: "10",
+		},
+		{
+			name:    "it return 401 when credentials invalid",
+			request: form(url.Values{"username": {"test"}, "password": {"invalid"}}),
+			code:    http.StatusUnauthorized,
+			body:    "Unauthorized\n",
+		},
+		{
+			name: "it ignore query credentials",
+			request: func() *http.Request {
+				r, _ := http.NewRequest("POST", "/login?username=test&password=test", nil)
+				return r
+			},
+			code: http.StatusUnauthorized,
+			body: "Unauthorized\n",
+		},
+		{
+			name: "it return 405 when method not post",
+			request: func() *http.Request {
+				r, _ := http.NewRequest("GET", "/login", nil)
+				return r
+			},
+			code: http.StatusMethodNotAllowed,
+			body: "Method Not Allowed\n",
+		},
+		{
+			name:    "it invoke error handler",
+			request: form(url.Values{"username": {"test"}}),
+			opts: []auth.Option{
+				SetLoginErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(err.Error()))
+				}),
+			},
+			code: http.StatusBadRequest,
+			body: ErrMissingLoginFields.Error(),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			LoginHandler(fn, success, tt.opts...).ServeHTTP(w, tt.request())
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func TestTokenSuccessHandler(t *testing.T) {
+	info := auth.NewDefaultUser("test", "10", nil, nil)
+	r, _ := http.NewRequest("POST", "/login", nil)
+
+	w := httptest.NewRecorder()
+	TokenSuccessHandler(func(r *http.Request, info auth.Info) (string, error) {
+		return "token-" + info.ID(), nil
+	})(w, r, info)
+
+	body := map[string]string{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, map[string]string{"access_token": "token-10", "token_type": "Bearer"}, body)
+
+	w = httptest.NewRecorder()
+	TokenSuccessHandler(func(r *http.Request, info auth.Info) (string, error) {
+		return "", errors.New("issue error")
+	})(w, r, info)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,247 @@
+package basic
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" // nolint:gosec
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// PwnedPasswordsURL is the Have I Been Pwned passwords range API URL.
+const PwnedPasswordsURL = "https://api.pwnedpasswords.com/range/"
+
+var (
+	// ErrPasswordTooShort is returned by PasswordPolicy,
+	// when the password shorter than the policy min length.
+	ErrPasswordTooShort = errors.New("basic: Password too short")
+
+	// ErrPasswordTooLong is returned by PasswordPolicy,
+	// when the password longer than the policy max length.
+	ErrPasswordTooLong = errors.New("basic: Password too long")
+
+	// ErrPasswordMissingUpper is returned by PasswordPolicy,
+	// when the password does not contain an upper case letter.
+	ErrPasswordMissingUpper = errors.New("basic: Password must contain an upper case letter")
+
+	// ErrPasswordMissingLower is returned by PasswordPolicy,
+	// when the password does not contain a lower case letter.
+	ErrPasswordMissingLower = errors.New("basic: Password must contain a lower case letter")
+
+	// ErrPasswordMissingDigit is returned by PasswordPolicy,
+	// when the password does not contain a digit.
+	ErrPasswordMissingDigit = errors.New("basic: Password must contain a digit")
+
+	// ErrPasswordMissingSymbol is returned by PasswordPolicy,
+	// when the password does not contain a symbol.
+	ErrPasswordMissingSymbol = errors.New("basic: Password must contain a symbol")
+
+	// ErrPasswordContainsUserName is returned by PasswordPolicy,
+	// when the password contains the user name.
+	ErrPasswordContainsUserName = errors.New("basic: Password must not contain the user name")
+
+	// ErrPasswordDenied is returned by PasswordPolicy,
+	// when the password found in the policy denylist.
+	ErrPasswordDenied = errors.New("basic: Password is too common")
+
+	// ErrPasswordBreached is returned by PasswordPolicy,
+	// when the password found in a known data breach.
+	ErrPasswordBreached = errors.New("basic: Password found in a data breach")
+)
+
+// BreachChecker checks whether a password exposed in a known data breach.
+type BreachChecker interface {
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+// BreachCheckerFunc is an adapter to allow the use of ordinary functions as BreachChecker.
+type BreachCheckerFunc func(ctx context.Context, password string) (bool, error)
+
+// Breached return fn(ctx, password).
+func (fn BreachCheckerFunc) Breached(ctx context.Context, password string) (bool, error) {
+	return fn(ctx, password)
+}
+
+// PwnedPasswords implements BreachChecker using the Have I Been Pwned passwords range API,
+// where only the first 5 hex chars of the password SHA-1 hash sent (k-anonymity),
+// so neither the password nor its full hash ever leaves the process.
+type PwnedPasswords struct {
+	// Client used to send the API requests, Default http.DefaultClient.
+	Client *http.Client
+	// URL is the range API URL, Default PwnedPasswordsURL.
+	URL string
+}
+
+// Breached reports whether the password found in the pwned passwords.
+func (p PwnedPasswords) Breached(ctx context.Context, password string) (bool, error) {
+	c, u := p.Client, p.URL
+
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	if u == "" {
+		u = PwnedPasswordsURL
+	}
+
+	sum := sha1.Sum([]byte(password)) // nolint:gosec
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, u+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// padding responses, hide the prefix from network observers by the response size.
+	r.Header.Set("Add-Padding", "true")
+
+	resp, err := c.Do(r)
+	if err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("basic: Pwned passwords API respond with status code %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.IndexByte(line, ':')
+
+		// padding entries have zero count.
+		if i < 0 || line[i+1:] == "0" {
+			continue
+		}
+
+		if strings.EqualFold(line[:i], suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// PasswordPolicy validates passwords at registration or password change time,
+// complementing HashPassword.
+// The zero value accepts any password, use NewPasswordPolicy for the recommended defaults.
+type PasswordPolicy struct {
+	// MinLength is the min password length in characters.
+	MinLength int
+	// MaxLength is the max password length in characters, zero means no limit.
+	MaxLength int
+	// RequireUpper requires an upper case letter.
+	RequireUpper bool
+	// RequireLower requires a lower case letter.
+	RequireLower bool
+	// RequireDigit requires a digit.
+	RequireDigit bool
+	// RequireSymbol requires a symbol, i.e a char that is neither a letter, a digit, nor a space.
+	RequireSymbol bool
+	// DisallowUserName rejects passwords containing the user name, compared case-insensitively.
+	DisallowUserName bool
+	// Denylist holds the rejected passwords, e.g commonly used or context specific passwords,
+	// compared case-insensitively.
+	Denylist []string
+	// Breach checks the password against known data breaches, nil means no check.
+	Breach BreachChecker
+}
+
+// NewPasswordPolicy return PasswordPolicy following NIST SP 800-63B recommendations,
+// a min length of 8, a max length of 64, and rejecting passwords containing the user name,
+// without character classes requirements. Set Breach (e.g PwnedPasswords{}) to reject breached passwords.
+func NewPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        64,
+		DisallowUserName: true,
+	}
+}
+
+// Validate validates the password of the given user against the policy,
+// and return gerrors.MultiError of all the local violations,
+// the breach checked only once the password satisfies the local rules.
+func (p *PasswordPolicy) Validate(ctx context.Context, userName, password string) error {
+	errs := gerrors.MultiError{}
+	n := utf8.RuneCountInString(password)
+
+	if n < p.MinLength {
+		errs = append(errs, ErrPasswordTooShort)
+	}
+
+	if p.MaxLength > 0 && n > p.MaxLength {
+		errs = append(errs, ErrPasswordTooLong)
+	}
+
+	var upper, lower, digit, symbol bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	classes := []struct {
+		required, found bool
+		err             error
+	}{
+		{p.RequireUpper, upper, ErrPasswordMissingUpper},
+		{p.RequireLower, lower, ErrPasswordMissingLower},
+		{p.RequireDigit, digit, ErrPasswordMissingDigit},
+		{p.RequireSymbol, symbol, ErrPasswordMissingSymbol},
+	}
+
+	for _, c := range classes {
+		if c.required && !c.found {
+			errs = append(errs, c.err)
+		}
+	}
+
+	if p.DisallowUserName && userName != "" &&
+		strings.Contains(strings.ToLower(password), strings.ToLower(userName)) {
+		errs = append(errs, ErrPasswordContainsUserName)
+	}
+
+	for _, d := range p.Denylist {
+		if strings.EqualFold(d, password) {
+			errs = append(errs, ErrPasswordDenied)
+			break
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if p.Breach == nil {
+		return nil
+	}
+
+	breached, err := p.Breach.Breached(ctx, password)
+	if err != nil {
+		return err
+	}
+
+	if breached {
+		return ErrPasswordBreached
+	}
+
+	return nil
+}
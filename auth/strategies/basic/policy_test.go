@@ -0,0 +1,150 @@
+package basic
+
+import (
+	"context"
+	"crypto/sha1" // nolint:gosec
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	table := []struct {
+		name     string
+		policy   *PasswordPolicy
+		user     string
+		password string
+		errs     []error
+	}{
+		{
+			name:     "it accept password satisfying the default policy",
+			policy:   NewPasswordPolicy(),
+			user:     "alice",
+			password: "correct horse battery staple",
+		},
+		{
+			name:     "it reject short password containing user name",
+			policy:   NewPasswordPolicy(),
+			user:     "alice",
+			password: "Alice1",
+			errs:     []error{ErrPasswordTooShort, ErrPasswordContainsUserName},
+		},
+		{
+			name:     "it reject long password",
+			policy:   &PasswordPolicy{MaxLength: 4},
+			password: "12345",
+			errs:     []error{ErrPasswordTooLong},
+		},
+		{
+			name: "it count length in characters",
+			policy: &PasswordPolicy{
+				MinLength: 4,
+				MaxLength: 4,
+			},
+			password: "ññññ",
+		},
+		{
+			name: "it reject password missing character classes",
+			policy: &PasswordPolicy{
+				RequireUpper:  true,
+				RequireLower:  true,
+				RequireDigit:  true,
+				RequireSymbol: true,
+			},
+			password: "password with space",
+			errs:     []error{ErrPasswordMissingUpper, ErrPasswordMissingDigit, ErrPasswordMissingSymbol},
+		},
+		{
+			name: "it accept password having all character classes",
+			policy: &PasswordPolicy{
+				RequireUpper:  true,
+				RequireLower:  true,
+				RequireDigit:  true,
+				RequireSymbol: true,
+			},
+			password: "Pa55-word",
+		},
+		{
+			name:     "it reject denied password",
+			policy:   &PasswordPolicy{Denylist: []string{"password", "qwerty"}},
+			password: "QWERTY",
+			errs:     []error{ErrPasswordDenied},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(context.Background(), tt.user, tt.password)
+
+			if len(tt.errs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Equal(t, gerrors.MultiError(tt.errs), err)
+		})
+	}
+}
+
+func TestPasswordPolicyBreach(t *testing.T) {
+	calls := 0
+	breached := BreachCheckerFunc(func(ctx context.Context, password string) (bool, error) {
+		calls++
+		return password == "breached password", nil
+	})
+
+	p := NewPasswordPolicy()
+	p.Breach = breached
+
+	err := p.Validate(context.Background(), "", "breached password")
+	assert.Equal(t, ErrPasswordBreached, err)
+
+	err = p.Validate(context.Background(), "", "safe password")
+	assert.NoError(t, err)
+
+	// the breach not checked when local rules violated.
+	err = p.Validate(context.Background(), "", "short")
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+
+	p.Breach = BreachCheckerFunc(func(ctx context.Context, password string) (bool, error) {
+		return false, errors.New("network error")
+	})
+
+	err = p.Validate(context.Background(), "", "safe password")
+	assert.EqualError(t, err, "network error")
+}
+
+func TestPwnedPasswords(t *testing.T) {
+	sum := sha1.Sum([]byte("password")) // nolint:gosec
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.TrimPrefix(r.URL.Path, "/range/") != hash[:5] || r.Header.Get("Add-Padding") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write([]byte("0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" +
+			"00D4F6E8FA6EECAD2A3AA415EEC418D38EC:0\r\n" +
+			strings.ToLower(hash[5:]) + ":3303003\r\n"))
+	}))
+	defer srv.Close()
+
+	p := PwnedPasswords{Client: srv.Client(), URL: srv.URL + "/range/"}
+
+	ok, err := p.Breached(context.Background(), "password")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = p.Breached(context.Background(), "P4ssw0rd")
+	assert.Error(t, err)
+	assert.False(t, ok)
+}
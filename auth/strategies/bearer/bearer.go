@@ -47,20 +47,21 @@ func Token(r *http.Request) (string, error) {
 }
 
 // NewStaticFromFile returns static auth.Strategy, populated from a CSV file.
-func NewStaticFromFile(path string) (auth.Strategy, error) {
-	return token.NewStaticFromFile(path)
+func NewStaticFromFile(path string, opts ...auth.Option) (auth.Strategy, error) {
+	return token.NewStaticFromFile(path, opts...)
 }
 
 // NewStatic returns static auth.Strategy, populated from a map.
-func NewStatic(tokens map[string]auth.Info) auth.Strategy {
-	return token.NewStatic(tokens)
+func NewStatic(tokens map[string]auth.Info, opts ...auth.Option) auth.Strategy {
+	return token.NewStatic(tokens, opts...)
 }
 
 // New return new auth.Strategy.
 // The returned strategy, caches the invocation result of authenticate function, See AuthenticateFunc.
 // Use NoOpAuthenticate to refresh/mangae token directly using cache or Append function, See NoOpAuthenticate.
-func New(auth AuthenticateFunc, c store.Cache) auth.Strategy {
-	return token.New(auth, c)
+// The options applied to the underlying token strategy, See token.New.
+func New(fn AuthenticateFunc, c store.Cache, opts ...auth.Option) auth.Strategy {
+	return token.New(fn, c, opts...)
 }
 
 // NoOpAuthenticate implements Authenticate function, it return nil, auth.ErrNOOP,
@@ -0,0 +1,124 @@
+// Package bitbucket provide auth strategy to authenticate,
+// incoming HTTP requests using Bitbucket Cloud username and app password,
+// by validating the credentials against Bitbucket API.
+// Typically used by webhook receivers and internal developer tooling,
+// that trusts Bitbucket identities.
+package bitbucket
+
+import (
+	"context"
+	"crypto"
+	_ "crypto/sha256" // register sha256 for the cached credentials hashing.
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ErrUnauthorized is returned by bitbucket strategy,
+// when Bitbucket API rejects the credentials.
+var ErrUnauthorized = gerrors.WithReason(
+	errors.New("strategies/bitbucket: Invalid credentials"),
+	gerrors.ReasonInvalidCredentials,
+)
+
+type user struct {
+	UUID        string `json:"uuid"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	AccountID   string `json:"account_id"`
+}
+
+type client struct {
+	addr   string
+	client *http.Client
+}
+
+func (c *client) authenticate(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) { //nolint:lll
+	req, err := http.NewRequest(http.MethodGet, c.addr+"/2.0/user", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.WithContext(ctx)
+	req.SetBasicAuth(userName, password)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, ErrUnauthorized
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("strategies/bitbucket: Unexpected API response status %d", resp.StatusCode)
+	}
+
+	u := user{}
+	if err := json.NewDecoder(resp.Body).Decode(&u); err != nil {
+		return nil, err
+	}
+
+	ext := map[string][]string{
+		"display_name": {u.DisplayName},
+		"account_id":   {u.AccountID},
+	}
+
+	return auth.NewUserInfo(u.Username, u.UUID, nil, ext), nil
+}
+
+// GetAuthenticateFunc return function to authenticate request using Bitbucket app password.
+// The returned function typically used with the basic strategy.
+func GetAuthenticateFunc(opts ...auth.Option) basic.AuthenticateFunc {
+	c := &client{
+		addr:   "https://api.bitbucket.org",
+		client: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(c)
+	}
+
+	c.addr = strings.TrimSuffix(c.addr, "/")
+	return c.authenticate
+}
+
+// New return strategy authenticate request using Bitbucket username and app password,
+// carried by the basic authorization header.
+// The app password cached hashed using SHA256, use basic.SetHash or basic.SetComparator to override it.
+// New is similar to basic.New().
+func New(c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetAuthenticateFunc(opts...)
+	opts = append([]auth.Option{basic.SetHash(crypto.SHA256)}, opts...)
+	return basic.New(fn, c, opts...)
+}
+
+// SetAddress sets Bitbucket API address.
+// Default https://api.bitbucket.org.
+func SetAddress(addr string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*client); ok {
+			c.addr = addr
+		}
+	})
+}
+
+// SetHTTPClient sets underlying http client.
+func SetHTTPClient(hc *http.Client) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*client); ok {
+			c.client = hc
+		}
+	})
+}
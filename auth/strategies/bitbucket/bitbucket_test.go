@@ -0,0 +1,82 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestBitbucket(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if u, p, _ := r.BasicAuth(); r.URL.Path != "/2.0/user" || u != "alice" || p != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"uuid":"{1}","username":"alice","display_name":"Alice","account_id":"a1"}`))
+	}))
+	defer srv.Close()
+
+	s := New(store.New(0), SetAddress(srv.URL))
+
+	table := []struct {
+		name  string
+		pass  string
+		err   error
+		calls int
+	}{
+		{
+			name:  "it authenticate app password",
+			pass:  "app-password",
+			calls: 1,
+		},
+		{
+			name:  "it authenticate app password from cache",
+			pass:  "app-password",
+			calls: 1,
+		},
+		{
+			name:  "it return error when app password invalid",
+			pass:  "invalid",
+			err:   basic.ErrInvalidCredentials,
+			calls: 1,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.SetBasicAuth("alice", tt.pass)
+
+			info, err := s.Authenticate(context.Background(), r)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.calls, calls)
+			if err == nil {
+				assert.Equal(t, "alice", info.UserName())
+				assert.Equal(t, "{1}", info.ID())
+				assert.Equal(t, []string{"Alice"}, info.Extensions()["display_name"])
+			}
+		})
+	}
+}
+
+func TestBitbucketInvalidCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("alice", "invalid")
+
+	_, err := GetAuthenticateFunc(SetAddress(srv.URL)).Authenticate(context.Background(), r)
+	assert.Equal(t, ErrUnauthorized, err)
+}
@@ -0,0 +1,140 @@
+// Package strategies provides helpers shared across authentication strategies.
+package strategies
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal"
+	"github.com/shaj13/go-guardian/internal/singleflight"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ErrNoCacheKey is returned by KeyFunc,
+// when the request does not carry the data the cache key derived from.
+var ErrNoCacheKey = errors.New("strategies: Request missing cache key")
+
+// KeyFunc define function signature to derive a cache key from HTTP request,
+// requests deriving the same key must represent the same credentials,
+// Otherwise, a user may be authenticated using another user cached result.
+type KeyFunc func(r *http.Request) (string, error)
+
+// HeaderKey return KeyFunc, where the cache key is the given HTTP header value.
+// Typically used to cache strategies authenticating a request using a single header,
+// such as an API key or HMAC signature.
+func HeaderKey(header string) KeyFunc {
+	return func(r *http.Request) (string, error) {
+		return internal.ParseHeader(header, r, ErrNoCacheKey)
+	}
+}
+
+// BasicKey return KeyFunc, where the cache key is the request basic auth user name and password.
+// Typically used to cache strategies authenticating users against a remote directory, such as LDAP.
+func BasicKey() KeyFunc {
+	return func(r *http.Request) (string, error) {
+		user, pass, ok := r.BasicAuth()
+		if !ok {
+			return "", ErrNoCacheKey
+		}
+		return user + ":" + pass, nil
+	}
+}
+
+// CertificateKey return KeyFunc, where the cache key is the request client leaf certificate.
+// Typically used to cache the x509 strategy chain verification result.
+func CertificateKey() KeyFunc {
+	return func(r *http.Request) (string, error) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			return "", ErrNoCacheKey
+		}
+		return string(r.TLS.PeerCertificates[0].Raw), nil
+	}
+}
+
+// Cached return new auth.Strategy that caches the given strategy authentication results,
+// keyed by the SHA-256 of the key derived from the request, so raw credentials never used as cache keys.
+// Failed authentication attempts never cached, and concurrent requests deriving the same uncached key
+// share a single authentication attempt.
+// When the key can't be derived from the request, the request authenticated by the strategy uncached.
+//
+// The returned strategy still exposes the wrapped strategy Append, Revoke, Renew, and Challenge
+// through the auth package functions, See auth.Unwrap.
+func Cached(s auth.Strategy, c store.Cache, fn KeyFunc) auth.Strategy {
+	if s == nil {
+		panic("Strategy required and can't be nil")
+	}
+
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	if fn == nil {
+		panic("Key Function required and can't be nil")
+	}
+
+	return &cached{
+		next:   s,
+		cache:  c,
+		key:    fn,
+		flight: new(singleflight.Group),
+	}
+}
+
+type cached struct {
+	next   auth.Strategy
+	cache  store.Cache
+	key    KeyFunc
+	flight *singleflight.Group
+}
+
+func (c *cached) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	raw, err := c.key(r)
+	if err != nil {
+		return c.next.Authenticate(ctx, r)
+	}
+
+	key := internal.HashKey(raw)
+
+	v, ok, err := c.cache.Load(key, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		v, err, _ = c.flight.Do(key, func() (interface{}, error) {
+			info, err := c.next.Authenticate(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			// cache result
+			return info, c.cache.Store(key, info, r)
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, ok := v.(auth.Info)
+	if !ok {
+		return nil, gerrors.NewInvalidType((*auth.Info)(nil), v)
+	}
+
+	return info, nil
+}
+
+// Close close the cache if it owns background resources, the wrapped strategy closed by auth.Close.
+func (c *cached) Close() error {
+	if cl, ok := c.cache.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+func (c *cached) Unwrap() auth.Strategy {
+	return c.next
+}
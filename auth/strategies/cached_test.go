@@ -0,0 +1,164 @@
+package strategies
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+type strategy struct {
+	calls int32
+	err   error
+}
+
+func (s *strategy) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	n := atomic.AddInt32(&s.calls, 1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return auth.NewUser("test", string(rune('0'+n))), nil
+}
+
+func (s *strategy) Challenge(realm string) string {
+	return "Test realm=" + realm
+}
+
+func TestCached(t *testing.T) {
+	errAuth := errors.New("auth failed")
+
+	table := []struct {
+		name     string
+		fn       KeyFunc
+		err      error
+		set      func(r *http.Request)
+		calls    int32
+		expected string
+		keys     int
+	}{
+		{
+			name:     "it cache result keyed by header",
+			fn:       HeaderKey("X-API-Key"),
+			set:      func(r *http.Request) { r.Header.Set("X-API-Key", "secret") },
+			calls:    1,
+			expected: "1",
+			keys:     1,
+		},
+		{
+			name:     "it cache result keyed by basic credentials",
+			fn:       BasicKey(),
+			set:      func(r *http.Request) { r.SetBasicAuth("test", "test") },
+			calls:    1,
+			expected: "1",
+			keys:     1,
+		},
+		{
+			name: "it cache result keyed by client certificate",
+			fn:   CertificateKey(),
+			set: func(r *http.Request) {
+				r.TLS = &tls.ConnectionState{
+					PeerCertificates: []*x509.Certificate{{Raw: []byte("cert")}},
+				}
+			},
+			calls:    1,
+			expected: "1",
+			keys:     1,
+		},
+		{
+			name:     "it authenticate uncached when key missing",
+			fn:       HeaderKey("X-API-Key"),
+			set:      func(r *http.Request) {},
+			calls:    2,
+			expected: "2",
+		},
+		{
+			name:  "it does not cache errors",
+			fn:    HeaderKey("X-API-Key"),
+			err:   errAuth,
+			set:   func(r *http.Request) { r.Header.Set("X-API-Key", "secret") },
+			calls: 2,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &strategy{err: tt.err}
+			c := store.New(0)
+			cached := Cached(s, c, tt.fn)
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			tt.set(r)
+
+			var (
+				info auth.Info
+				err  error
+			)
+
+			for i := 0; i < 2; i++ {
+				info, err = cached.Authenticate(r.Context(), r)
+			}
+
+			assert.Equal(t, tt.calls, s.calls)
+			assert.Len(t, c.Keys(), tt.keys)
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, info.ID())
+		})
+	}
+}
+
+func TestCachedHashKeys(t *testing.T) {
+	c := store.New(0)
+	cached := Cached(&strategy{}, c, HeaderKey("X-API-Key"))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("X-API-Key", "token")
+
+	_, err := cached.Authenticate(r.Context(), r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"3c469e9d6c5875d37a43f353d4f88e61fcf812c66eee3457465a40b0da4153e0"}, c.Keys())
+}
+
+func TestCachedInvalidType(t *testing.T) {
+	c := store.New(0)
+	cached := Cached(&strategy{}, c, HeaderKey("X-API-Key"))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("X-API-Key", "token")
+	_ = c.Store("3c469e9d6c5875d37a43f353d4f88e61fcf812c66eee3457465a40b0da4153e0", "invalid", nil)
+
+	_, err := cached.Authenticate(r.Context(), r)
+
+	assert.Error(t, err)
+}
+
+func TestCachedUnwrap(t *testing.T) {
+	s := &strategy{}
+	cached := Cached(s, store.New(0), BasicKey())
+
+	challenge, ok := auth.Challenge(cached, "test")
+
+	assert.Equal(t, s, auth.Unwrap(cached))
+	assert.True(t, ok)
+	assert.Equal(t, "Test realm=test", challenge)
+}
+
+func TestCachedPanic(t *testing.T) {
+	assert.Panics(t, func() { Cached(nil, store.New(0), BasicKey()) })
+	assert.Panics(t, func() { Cached(&strategy{}, nil, BasicKey()) })
+	assert.Panics(t, func() { Cached(&strategy{}, store.New(0), nil) })
+}
@@ -0,0 +1,126 @@
+// Package cloudflare provides authentication strategy,
+// to authenticate HTTP requests for apps fronted by Cloudflare Access,
+// by validating the Cf-Access-Jwt-Assertion header against the team certs,
+// and the application audience tag.
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// HeaderName is the header Cloudflare Access carry the application token in.
+const HeaderName = "Cf-Access-Jwt-Assertion"
+
+// ErrMissingToken is returned by strategy when request missing Cf-Access-Jwt-Assertion header.
+var ErrMissingToken = gerrors.WithReason(
+	errors.New("strategies/cloudflare: Missing Cf-Access-Jwt-Assertion header"),
+	gerrors.ReasonMissingCredentials,
+)
+
+type claims struct {
+	jwt.Claims
+	Email         string `json:"email"`
+	CommonName    string `json:"common_name"`
+	Type          string `json:"type"`
+	Country       string `json:"country"`
+	IdentityNonce string `json:"identity_nonce"`
+}
+
+type access struct {
+	keys     *jwt.RemoteKeySet
+	certs    string
+	client   *http.Client
+	issuer   string
+	audience []string
+	leeway   time.Duration
+}
+
+func (a *access) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	token := r.Header.Get(HeaderName)
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	c := claims{}
+
+	if _, err := a.keys.Verify(ctx, token, &c); err != nil {
+		return nil, err
+	}
+
+	err := c.Validate(jwt.Expected{
+		Issuer:   a.issuer,
+		Audience: a.audience,
+		Leeway:   a.leeway,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return info(c), nil
+}
+
+// info map identity claims to auth.Info,
+// service token identities does not have email or subject,
+// therefore the service token client id (common_name) used instead.
+func info(c claims) auth.Info {
+	name, id := c.Email, c.Subject
+
+	if name == "" {
+		name = c.CommonName
+	}
+
+	if id == "" {
+		id = c.CommonName
+	}
+
+	ext := make(map[string][]string)
+
+	for k, v := range map[string]string{
+		"type":           c.Type,
+		"country":        c.Country,
+		"identity_nonce": c.IdentityNonce,
+		"common_name":    c.CommonName,
+	} {
+		if v != "" {
+			ext[k] = []string{v}
+		}
+	}
+
+	return auth.NewUserInfo(name, id, nil, ext)
+}
+
+// New return strategy authenticate request using Cloudflare Access application token.
+// team is the Cloudflare Zero Trust team name (e.g myteam),
+// or team domain (e.g myteam.cloudflareaccess.com),
+// and aud is the application audience (AUD) tag.
+func New(team, aud string, opts ...auth.Option) auth.Strategy {
+	domain := strings.TrimSuffix(strings.TrimPrefix(team, "https://"), "/")
+	if !strings.Contains(domain, ".") {
+		domain += ".cloudflareaccess.com"
+	}
+
+	a := &access{
+		issuer:   "https://" + domain,
+		certs:    "https://" + domain + "/cdn-cgi/access/certs",
+		audience: []string{aud},
+		client:   http.DefaultClient,
+		leeway:   time.Minute,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(a)
+	}
+
+	a.keys = jwt.NewRemoteKeySet(a.certs, a.client)
+
+	return a
+}
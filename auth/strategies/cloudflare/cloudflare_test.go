@@ -0,0 +1,117 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+func TestStrategy(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	jwk, _ := jwt.NewJSONWebKey(&key.PublicKey, "kid", jwt.RS256)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwt.KeySet{Keys: []jwt.JSONWebKey{jwk}})
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	valid := jwt.Claims{
+		Issuer:   "https://test.cloudflareaccess.com",
+		Subject:  "1",
+		Audience: jwt.Audience{"aud"},
+		Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+	}
+
+	table := []struct {
+		name   string
+		claims interface{}
+		header bool
+		err    bool
+		user   string
+		id     string
+	}{
+		{
+			name: "it return error when header missing",
+			err:  true,
+		},
+		{
+			name:   "it return error when audience invalid",
+			header: true,
+			claims: claims{Claims: jwt.Claims{
+				Issuer:   valid.Issuer,
+				Audience: jwt.Audience{"other"},
+				Expiry:   valid.Expiry,
+			}},
+			err: true,
+		},
+		{
+			name:   "it return error when token expired",
+			header: true,
+			claims: claims{Claims: jwt.Claims{
+				Issuer:   valid.Issuer,
+				Audience: valid.Audience,
+				Expiry:   jwt.NewNumericDate(now.Add(-time.Hour)),
+			}},
+			err: true,
+		},
+		{
+			name:   "it authenticate user identity",
+			header: true,
+			claims: claims{Claims: valid, Email: "test@example.com", Type: "app"},
+			user:   "test@example.com",
+			id:     "1",
+		},
+		{
+			name:   "it authenticate service token identity",
+			header: true,
+			claims: claims{
+				Claims:     jwt.Claims{Issuer: valid.Issuer, Audience: valid.Audience, Expiry: valid.Expiry},
+				CommonName: "client.access",
+			},
+			user: "client.access",
+			id:   "client.access",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New("test", "aud", SetCertsURL(srv.URL), SetHTTPClient(srv.Client()))
+			r, _ := http.NewRequest("GET", "/", nil)
+
+			if tt.header {
+				token, _ := jwt.Sign(jwt.RS256, "kid", key, tt.claims)
+				r.Header.Set(HeaderName, token)
+			}
+
+			info, err := s.Authenticate(context.Background(), r)
+
+			assert.Equal(t, tt.err, err != nil)
+
+			if !tt.err {
+				assert.Equal(t, tt.user, info.UserName())
+				assert.Equal(t, tt.id, info.ID())
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, team := range []string{"test", "test.cloudflareaccess.com", "https://test.cloudflareaccess.com/"} {
+		a := New(team, "aud").(*access)
+		assert.Equal(t, "https://test.cloudflareaccess.com", a.issuer)
+		assert.Equal(t, "https://test.cloudflareaccess.com/cdn-cgi/access/certs", a.certs)
+	}
+
+	var _ auth.Strategy = New("test", "aud")
+}
@@ -0,0 +1,47 @@
+package cloudflare
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetHTTPClient sets underlying http client used to fetch the team certs.
+func SetHTTPClient(c *http.Client) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*access); ok {
+			a.client = c
+		}
+	})
+}
+
+// SetCertsURL sets the team certs url.
+// Default https://<team>.cloudflareaccess.com/cdn-cgi/access/certs.
+func SetCertsURL(url string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*access); ok {
+			a.certs = url
+		}
+	})
+}
+
+// SetAudience sets the accepted application audience tags,
+// Typically used when multiple Access applications front the same origin.
+func SetAudience(aud ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*access); ok {
+			a.audience = aud
+		}
+	})
+}
+
+// SetLeeway sets the allowed clock skew when validating token exp and nbf.
+// Default 1 minute.
+func SetLeeway(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if a, ok := v.(*access); ok {
+			a.leeway = d
+		}
+	})
+}
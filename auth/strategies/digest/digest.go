@@ -17,7 +17,10 @@ import (
 const StrategyKey = auth.StrategyKey("Digest.Strategy")
 
 // ErrInvalidResponse is returned by Strategy when client authz response does not match server hash.
-var ErrInvalidResponse = errors.New("Digest: Invalid Response")
+var ErrInvalidResponse = errors.WithReason(
+	errors.New("Digest: Invalid Response"),
+	errors.ReasonInvalidCredentials,
+)
 
 // Strategy implements auth.Strategy and represents digest authentication as described in RFC 7616.
 type Strategy struct {
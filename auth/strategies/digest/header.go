@@ -10,7 +10,10 @@ import (
 )
 
 // ErrInavlidHeader is returned by Header parse when  authz header is not digest.
-var ErrInavlidHeader = errors.New("Digest: Invalid Authorization Header")
+var ErrInavlidHeader = errors.WithReason(
+	errors.New("Digest: Invalid Authorization Header"),
+	errors.ReasonMalformed,
+)
 
 const (
 	username  = "username"
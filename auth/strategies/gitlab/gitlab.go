@@ -0,0 +1,189 @@
+// Package gitlab provide auth strategies to authenticate,
+// incoming HTTP requests using GitLab personal access tokens or CI job tokens,
+// by validating the token against GitLab API.
+// Typically used by webhook receivers and internal developer tooling,
+// that trusts GitLab identities.
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ErrUnauthorized is returned by gitlab strategies,
+// when GitLab API rejects the token.
+var ErrUnauthorized = errors.New("strategies/gitlab: Token Unauthorized")
+
+// ErrMissingScope is returned by gitlab personal access token strategy,
+// when the token does not grant the required scopes.
+var ErrMissingScope = errors.New("strategies/gitlab: Token missing required scope")
+
+const (
+	// PrivateTokenHeader is the HTTP header carrying GitLab personal access token.
+	PrivateTokenHeader = "PRIVATE-TOKEN"
+	// JobTokenHeader is the HTTP header carrying GitLab CI job token.
+	JobTokenHeader = "JOB-TOKEN"
+)
+
+type user struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	IsAdmin  bool   `json:"is_admin"`
+}
+
+type job struct {
+	ID       int64  `json:"id"`
+	Ref      string `json:"ref"`
+	User     user   `json:"user"`
+	Pipeline struct {
+		ID        int64 `json:"id"`
+		ProjectID int64 `json:"project_id"`
+	} `json:"pipeline"`
+}
+
+type client struct {
+	addr   string
+	scopes []string
+	client *http.Client
+}
+
+func (c *client) get(ctx context.Context, path, header, tkn string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.addr+"/api/v4"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set(header, tkn)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return ErrUnauthorized
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("strategies/gitlab: Unexpected API response status %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *client) authenticate(ctx context.Context, r *http.Request, tkn string) (auth.Info, error) {
+	if len(c.scopes) > 0 {
+		self := struct {
+			Scopes []string `json:"scopes"`
+		}{}
+
+		if err := c.get(ctx, "/personal_access_tokens/self", PrivateTokenHeader, tkn, &self); err != nil {
+			return nil, err
+		}
+
+		granted := make(map[string]struct{}, len(self.Scopes))
+		for _, s := range self.Scopes {
+			granted[s] = struct{}{}
+		}
+
+		for _, s := range c.scopes {
+			if _, ok := granted[s]; !ok {
+				return nil, ErrMissingScope
+			}
+		}
+	}
+
+	u := user{}
+	if err := c.get(ctx, "/user", PrivateTokenHeader, tkn, &u); err != nil {
+		return nil, err
+	}
+
+	ext := map[string][]string{
+		"name":     {u.Name},
+		"is_admin": {strconv.FormatBool(u.IsAdmin)},
+	}
+
+	if u.Email != "" {
+		ext["email"] = []string{u.Email}
+	}
+
+	return auth.NewUserInfo(u.Username, strconv.FormatInt(u.ID, 10), nil, ext), nil
+}
+
+func (c *client) authenticateJob(ctx context.Context, r *http.Request, tkn string) (auth.Info, error) {
+	j := job{}
+	if err := c.get(ctx, "/job", JobTokenHeader, tkn, &j); err != nil {
+		return nil, err
+	}
+
+	ext := map[string][]string{
+		"job_id":      {strconv.FormatInt(j.ID, 10)},
+		"pipeline_id": {strconv.FormatInt(j.Pipeline.ID, 10)},
+		"project_id":  {strconv.FormatInt(j.Pipeline.ProjectID, 10)},
+		"ref":         {j.Ref},
+	}
+
+	return auth.NewUserInfo(j.User.Username, strconv.FormatInt(j.User.ID, 10), nil, ext), nil
+}
+
+// GetAuthenticateFunc return function to authenticate request using GitLab personal access token.
+// The returned function typically used with the token strategy.
+func GetAuthenticateFunc(opts ...auth.Option) token.AuthenticateFunc {
+	return newClient(opts...).authenticate
+}
+
+// GetJobAuthenticateFunc return function to authenticate request using GitLab CI job token,
+// The returned info represents the user who triggered the job,
+// and the job, pipeline, and project ids are in the info extensions.
+// The returned function typically used with the token strategy.
+func GetJobAuthenticateFunc(opts ...auth.Option) token.AuthenticateFunc {
+	return newClient(opts...).authenticateJob
+}
+
+// New return strategy authenticate request using GitLab personal access token,
+// carried by PRIVATE-TOKEN header, use token.SetParser to override it.
+// New is similar to token.New().
+func New(c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetAuthenticateFunc(opts...)
+	opts = append([]auth.Option{token.SetParser(token.XHeaderParser(PrivateTokenHeader))}, opts...)
+	return token.New(fn, c, opts...)
+}
+
+// NewJob return strategy authenticate request using GitLab CI job token,
+// carried by JOB-TOKEN header, use token.SetParser to override it.
+// Job tokens valid only while the job running, so prefer short cache ttl.
+// NewJob is similar to token.New().
+func NewJob(c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetJobAuthenticateFunc(opts...)
+	opts = append([]auth.Option{token.SetParser(token.XHeaderParser(JobTokenHeader))}, opts...)
+	return token.New(fn, c, opts...)
+}
+
+func newClient(opts ...auth.Option) *client {
+	c := &client{
+		addr:   "https://gitlab.com",
+		client: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(c)
+	}
+
+	c.addr = strings.TrimSuffix(c.addr, "/")
+	return c
+}
@@ -0,0 +1,103 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func testServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v4/user" && r.Header.Get(PrivateTokenHeader) == "pat":
+			_, _ = w.Write([]byte(`{"id":1,"username":"alice","name":"Alice","email":"alice@example.com"}`))
+		case r.URL.Path == "/api/v4/personal_access_tokens/self" && r.Header.Get(PrivateTokenHeader) == "pat":
+			_, _ = w.Write([]byte(`{"scopes":["read_api"]}`))
+		case r.URL.Path == "/api/v4/job" && r.Header.Get(JobTokenHeader) == "job":
+			_, _ = w.Write([]byte(`{"id":7,"ref":"main","user":{"id":1,"username":"alice"},"pipeline":{"id":3,"project_id":5}}`)) // nolint:lll
+		case r.URL.Path == "/api/v4/job":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+}
+
+func TestGitLab(t *testing.T) {
+	srv := testServer()
+	defer srv.Close()
+
+	table := []struct {
+		name     string
+		strategy func() auth.Strategy
+		header   string
+		token    string
+		err      error
+		info     auth.Info
+	}{
+		{
+			name:     "it authenticate personal access token",
+			strategy: func() auth.Strategy { return New(store.New(0), SetAddress(srv.URL+"/")) },
+			header:   PrivateTokenHeader,
+			token:    "pat",
+			info: auth.NewUserInfo("alice", "1", nil, map[string][]string{
+				"name":     {"Alice"},
+				"email":    {"alice@example.com"},
+				"is_admin": {"false"},
+			}),
+		},
+		{
+			name:     "it return error when personal access token invalid",
+			strategy: func() auth.Strategy { return New(store.New(0), SetAddress(srv.URL)) },
+			header:   PrivateTokenHeader,
+			token:    "invalid",
+			err:      ErrUnauthorized,
+		},
+		{
+			name: "it return error when personal access token missing required scope",
+			strategy: func() auth.Strategy {
+				return New(store.New(0), SetAddress(srv.URL), SetScopes("read_api", "api"))
+			},
+			header: PrivateTokenHeader,
+			token:  "pat",
+			err:    ErrMissingScope,
+		},
+		{
+			name:     "it authenticate job token",
+			strategy: func() auth.Strategy { return NewJob(store.New(0), SetAddress(srv.URL)) },
+			header:   JobTokenHeader,
+			token:    "job",
+			info: auth.NewUserInfo("alice", "1", nil, map[string][]string{
+				"job_id":      {"7"},
+				"pipeline_id": {"3"},
+				"project_id":  {"5"},
+				"ref":         {"main"},
+			}),
+		},
+		{
+			name:     "it return error when job token invalid",
+			strategy: func() auth.Strategy { return NewJob(store.New(0), SetAddress(srv.URL)) },
+			header:   JobTokenHeader,
+			token:    "invalid",
+			err:      ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set(tt.header, tt.token)
+
+			info, err := tt.strategy().Authenticate(context.Background(), r)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.info, info)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package gitlab
+
+import (
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetAddress sets GitLab instance address.
+// Default https://gitlab.com.
+func SetAddress(addr string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*client); ok {
+			c.addr = addr
+		}
+	})
+}
+
+// SetHTTPClient sets underlying http client.
+func SetHTTPClient(hc *http.Client) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*client); ok {
+			c.client = hc
+		}
+	})
+}
+
+// SetScopes sets the personal access token required scopes (e.g read_api),
+// tokens that does not grant all of them rejected with ErrMissingScope.
+func SetScopes(scopes ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*client); ok {
+			c.scopes = scopes
+		}
+	})
+}
@@ -0,0 +1,206 @@
+// Package hashicorp provide auth strategies to authenticate,
+// incoming HTTP requests using HashiCorp Consul or Nomad ACL tokens,
+// by resolving the token against the cluster ACL API,
+// where the token policies and roles mapped to the user groups.
+// Typically used by infrastructure tooling already speaking those tokens.
+package hashicorp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ErrUnauthorized is returned by hashicorp strategies,
+// when the ACL API rejects the token.
+var ErrUnauthorized = gerrors.WithReason(
+	errors.New("strategies/hashicorp: ACL token not found"),
+	gerrors.ReasonInvalidCredentials,
+)
+
+const (
+	// ConsulTokenHeader is the HTTP header carrying Consul ACL token.
+	ConsulTokenHeader = "X-Consul-Token"
+	// NomadTokenHeader is the HTTP header carrying Nomad ACL token.
+	NomadTokenHeader = "X-Nomad-Token"
+)
+
+type link struct {
+	Name string `json:"Name"`
+}
+
+type consulToken struct {
+	AccessorID  string `json:"AccessorID"`
+	Description string `json:"Description"`
+	Policies    []link `json:"Policies"`
+	Roles       []link `json:"Roles"`
+	Local       bool   `json:"Local"`
+}
+
+type nomadToken struct {
+	AccessorID string   `json:"AccessorID"`
+	Name       string   `json:"Name"`
+	Type       string   `json:"Type"`
+	Policies   []string `json:"Policies"`
+	Roles      []link   `json:"Roles"`
+	Global     bool     `json:"Global"`
+}
+
+type client struct {
+	addr   string
+	header string
+	client *http.Client
+}
+
+func (c *client) self(ctx context.Context, tkn string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.addr+"/v1/acl/token/self", nil)
+	if err != nil {
+		return err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set(c.header, tkn)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return json.NewDecoder(resp.Body).Decode(v)
+	case http.StatusForbidden, http.StatusUnauthorized, http.StatusNotFound:
+		return ErrUnauthorized
+	default:
+		return fmt.Errorf("strategies/hashicorp: Unexpected ACL API response status %d", resp.StatusCode)
+	}
+}
+
+func (c *client) authenticateConsul(ctx context.Context, r *http.Request, tkn string) (auth.Info, error) {
+	t := consulToken{}
+	if err := c.self(ctx, tkn, &t); err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(t.Policies)+len(t.Roles))
+	for _, p := range t.Policies {
+		groups = append(groups, p.Name)
+	}
+
+	groups = appendRoles(groups, t.Roles)
+	ext := map[string][]string{"local": {strconv.FormatBool(t.Local)}}
+
+	return auth.NewUserInfo(userName(t.Description, t.AccessorID), t.AccessorID, groups, ext), nil
+}
+
+func (c *client) authenticateNomad(ctx context.Context, r *http.Request, tkn string) (auth.Info, error) {
+	t := nomadToken{}
+	if err := c.self(ctx, tkn, &t); err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(t.Policies)+len(t.Roles))
+	groups = append(groups, t.Policies...)
+	groups = appendRoles(groups, t.Roles)
+	ext := map[string][]string{
+		"type":   {t.Type},
+		"global": {strconv.FormatBool(t.Global)},
+	}
+
+	return auth.NewUserInfo(userName(t.Name, t.AccessorID), t.AccessorID, groups, ext), nil
+}
+
+// appendRoles append the token roles to groups in the form of role:<name>.
+func appendRoles(groups []string, roles []link) []string {
+	for _, r := range roles {
+		groups = append(groups, "role:"+r.Name)
+	}
+	return groups
+}
+
+func userName(name, accessor string) string {
+	if name != "" {
+		return name
+	}
+	return accessor
+}
+
+// GetConsulAuthenticateFunc return function to authenticate request using Consul ACL token.
+// The returned info id is the token accessor id, and the groups are the token policies names,
+// and the token roles names in the form of role:<name>.
+// The returned function typically used with the token strategy.
+func GetConsulAuthenticateFunc(opts ...auth.Option) token.AuthenticateFunc {
+	return newClient("http://127.0.0.1:8500", ConsulTokenHeader, opts...).authenticateConsul
+}
+
+// GetNomadAuthenticateFunc return function to authenticate request using Nomad ACL token.
+// The returned info id is the token accessor id, and the groups are the token policies names,
+// and the token roles names in the form of role:<name>.
+// The returned function typically used with the token strategy.
+func GetNomadAuthenticateFunc(opts ...auth.Option) token.AuthenticateFunc {
+	return newClient("http://127.0.0.1:4646", NomadTokenHeader, opts...).authenticateNomad
+}
+
+// NewConsul return strategy authenticate request using Consul ACL token,
+// carried by X-Consul-Token header, use token.SetParser to override it.
+// NewConsul is similar to token.New().
+func NewConsul(c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetConsulAuthenticateFunc(opts...)
+	opts = append([]auth.Option{token.SetParser(token.XHeaderParser(ConsulTokenHeader))}, opts...)
+	return token.New(fn, c, opts...)
+}
+
+// NewNomad return strategy authenticate request using Nomad ACL token,
+// carried by X-Nomad-Token header, use token.SetParser to override it.
+// NewNomad is similar to token.New().
+func NewNomad(c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetNomadAuthenticateFunc(opts...)
+	opts = append([]auth.Option{token.SetParser(token.XHeaderParser(NomadTokenHeader))}, opts...)
+	return token.New(fn, c, opts...)
+}
+
+func newClient(addr, header string, opts ...auth.Option) *client {
+	c := &client{
+		addr:   addr,
+		header: header,
+		client: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(c)
+	}
+
+	c.addr = strings.TrimSuffix(c.addr, "/")
+	return c
+}
+
+// SetAddress sets Consul or Nomad HTTP API address.
+// Default the local agent address http://127.0.0.1:8500 for Consul, and http://127.0.0.1:4646 for Nomad.
+func SetAddress(addr string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*client); ok {
+			c.addr = addr
+		}
+	})
+}
+
+// SetHTTPClient sets underlying http client.
+func SetHTTPClient(hc *http.Client) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*client); ok {
+			c.client = hc
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package hashicorp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestACL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/v1/acl/token/self":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Header.Get(ConsulTokenHeader) == "consul":
+			_, _ = w.Write([]byte(`{"AccessorID":"a1","Description":"ci","Policies":[{"Name":"read"}],"Roles":[{"Name":"ops"}]}`)) // nolint:lll
+		case r.Header.Get(NomadTokenHeader) == "nomad":
+			_, _ = w.Write([]byte(`{"AccessorID":"a2","Type":"client","Policies":["deploy"],"Global":true}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	table := []struct {
+		name     string
+		strategy auth.Strategy
+		header   string
+		token    string
+		err      error
+		info     auth.Info
+	}{
+		{
+			name:     "it authenticate consul token",
+			strategy: NewConsul(store.New(0), SetAddress(srv.URL+"/")),
+			header:   ConsulTokenHeader,
+			token:    "consul",
+			info: auth.NewUserInfo("ci", "a1", []string{"read", "role:ops"}, map[string][]string{
+				"local": {"false"},
+			}),
+		},
+		{
+			name:     "it return error when consul token invalid",
+			strategy: NewConsul(store.New(0), SetAddress(srv.URL)),
+			header:   ConsulTokenHeader,
+			token:    "invalid",
+			err:      ErrUnauthorized,
+		},
+		{
+			name:     "it authenticate nomad token",
+			strategy: NewNomad(store.New(0), SetAddress(srv.URL)),
+			header:   NomadTokenHeader,
+			token:    "nomad",
+			info: auth.NewUserInfo("a2", "a2", []string{"deploy"}, map[string][]string{
+				"type":   {"client"},
+				"global": {"true"},
+			}),
+		},
+		{
+			name:     "it return error when nomad token invalid",
+			strategy: NewNomad(store.New(0), SetAddress(srv.URL)),
+			header:   NomadTokenHeader,
+			token:    "invalid",
+			err:      ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set(tt.header, tt.token)
+
+			info, err := tt.strategy.Authenticate(context.Background(), r)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.info, info)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Package iap provides authentication strategy,
+// to authenticate HTTP requests for apps behind Google Cloud Identity-Aware Proxy,
+// by validating the x-goog-iap-jwt-assertion header against the IAP public keys,
+// and the expected audience.
+package iap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+const (
+	// HeaderName is the header IAP carry the signed identity assertion in.
+	HeaderName = "X-Goog-Iap-Jwt-Assertion"
+	// Issuer is the IAP assertions issuer.
+	Issuer = "https://cloud.google.com/iap"
+	// KeysURL is the IAP public keys JWK set url.
+	KeysURL = "https://www.gstatic.com/iap/verify/public_key-jwk"
+)
+
+// ErrMissingToken is returned by strategy when request missing x-goog-iap-jwt-assertion header.
+var ErrMissingToken = gerrors.WithReason(
+	errors.New("strategies/iap: Missing x-goog-iap-jwt-assertion header"),
+	gerrors.ReasonMissingCredentials,
+)
+
+type claims struct {
+	jwt.Claims
+	Email        string `json:"email"`
+	HostedDomain string `json:"hd"`
+	Google       struct {
+		AccessLevels []string `json:"access_levels"`
+	} `json:"google"`
+}
+
+type proxy struct {
+	keys     *jwt.RemoteKeySet
+	url      string
+	client   *http.Client
+	audience []string
+	leeway   time.Duration
+}
+
+func (p *proxy) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	token := r.Header.Get(HeaderName)
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	c := claims{}
+	if _, err := p.keys.Verify(ctx, token, &c); err != nil {
+		return nil, err
+	}
+
+	err := c.Validate(jwt.Expected{
+		Issuer:   Issuer,
+		Audience: p.audience,
+		Leeway:   p.leeway,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return info(c), nil
+}
+
+// info map the assertion claims to auth.Info,
+// the IAP subject and email prefixed by the identity source (e.g accounts.google.com:),
+// therefore the prefix trimmed from the user name.
+func info(c claims) auth.Info {
+	name := c.Email
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	ext := make(map[string][]string)
+
+	if c.HostedDomain != "" {
+		ext["hd"] = []string{c.HostedDomain}
+	}
+
+	if len(c.Google.AccessLevels) > 0 {
+		ext["access_levels"] = c.Google.AccessLevels
+	}
+
+	return auth.NewUserInfo(name, c.Subject, nil, ext)
+}
+
+// New return strategy authenticate request using IAP signed header assertion,
+// aud is the expected audience of the IAP protected resource,
+// i.e /projects/PROJECT_NUMBER/global/backendServices/SERVICE_ID for Compute Engine and GKE backends,
+// or /projects/PROJECT_NUMBER/apps/PROJECT_ID for App Engine.
+func New(aud string, opts ...auth.Option) auth.Strategy {
+	p := &proxy{
+		url:      KeysURL,
+		audience: []string{aud},
+		client:   http.DefaultClient,
+		leeway:   time.Minute,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(p)
+	}
+
+	p.keys = jwt.NewRemoteKeySet(p.url, p.client)
+
+	return p
+}
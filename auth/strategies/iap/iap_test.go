@@ -0,0 +1,107 @@
+package iap
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+func TestStrategy(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	jwk, _ := jwt.NewJSONWebKey(&key.PublicKey, "kid", jwt.ES256)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwt.KeySet{Keys: []jwt.JSONWebKey{jwk}})
+	}))
+	defer srv.Close()
+
+	aud := "/projects/1/global/backendServices/2"
+	now := time.Now()
+	valid := jwt.Claims{
+		Issuer:   Issuer,
+		Subject:  "accounts.google.com:1",
+		Audience: jwt.Audience{aud},
+		Expiry:   jwt.NewNumericDate(now.Add(time.Minute * 10)),
+		IssuedAt: jwt.NewNumericDate(now),
+	}
+
+	table := []struct {
+		name   string
+		claims interface{}
+		header bool
+		err    bool
+	}{
+		{
+			name: "it return error when header missing",
+			err:  true,
+		},
+		{
+			name:   "it return error when audience invalid",
+			header: true,
+			claims: claims{Claims: jwt.Claims{Issuer: Issuer, Audience: jwt.Audience{"other"}, Expiry: valid.Expiry}},
+			err:    true,
+		},
+		{
+			name:   "it return error when issuer invalid",
+			header: true,
+			claims: claims{Claims: jwt.Claims{Issuer: "other", Audience: valid.Audience, Expiry: valid.Expiry}},
+			err:    true,
+		},
+		{
+			name:   "it return error when token expired",
+			header: true,
+			claims: claims{Claims: jwt.Claims{
+				Issuer:   Issuer,
+				Audience: valid.Audience,
+				Expiry:   jwt.NewNumericDate(now.Add(-time.Hour)),
+			}},
+			err: true,
+		},
+		{
+			name:   "it authenticate user identity",
+			header: true,
+			claims: claims{Claims: valid, Email: "accounts.google.com:alice@example.com", HostedDomain: "example.com"},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(aud, SetKeysURL(srv.URL), SetHTTPClient(srv.Client()))
+			r, _ := http.NewRequest("GET", "/", nil)
+
+			if tt.header {
+				token, _ := jwt.Sign(jwt.ES256, "kid", key, tt.claims)
+				r.Header.Set(HeaderName, token)
+			}
+
+			info, err := s.Authenticate(context.Background(), r)
+
+			assert.Equal(t, tt.err, err != nil)
+
+			if !tt.err {
+				assert.Equal(t, "alice@example.com", info.UserName())
+				assert.Equal(t, "accounts.google.com:1", info.ID())
+				assert.Equal(t, []string{"example.com"}, info.Extensions()["hd"])
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	p := New("aud", SetAudience("a", "b"), SetLeeway(time.Second)).(*proxy)
+	assert.Equal(t, KeysURL, p.url)
+	assert.Equal(t, []string{"a", "b"}, p.audience)
+	assert.Equal(t, time.Second, p.leeway)
+
+	var _ auth.Strategy = New("aud")
+}
@@ -0,0 +1,47 @@
+package iap
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetHTTPClient sets underlying http client used to fetch the IAP public keys.
+func SetHTTPClient(c *http.Client) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*proxy); ok {
+			p.client = c
+		}
+	})
+}
+
+// SetKeysURL sets the IAP public keys url.
+// Default https://www.gstatic.com/iap/verify/public_key-jwk.
+func SetKeysURL(url string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*proxy); ok {
+			p.url = url
+		}
+	})
+}
+
+// SetAudience sets the accepted audiences,
+// Typically used when multiple IAP protected resources route to the same service.
+func SetAudience(aud ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*proxy); ok {
+			p.audience = aud
+		}
+	})
+}
+
+// SetLeeway sets the allowed clock skew when validating token exp and nbf.
+// Default 1 minute.
+func SetLeeway(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if p, ok := v.(*proxy); ok {
+			p.leeway = d
+		}
+	})
+}
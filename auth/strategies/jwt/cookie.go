@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ErrInvalidCSRF is returned by the cookie strategy,
+// when an unsafe request CSRF header missing, or does not match the CSRF cookie and the token.
+var ErrInvalidCSRF = errors.New("strategies/jwt: Invalid CSRF token")
+
+// CSRFToken return the CSRF token bound to the given JWT using an HMAC of the given key,
+// so a CSRF token is valid only along with the token it issued for.
+func CSRFToken(key []byte, tk string) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(tk))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Cookies return the cookies to be written to the response on login, carrying the given JWT,
+// in an HttpOnly cookie, and its CSRF token in a cookie readable by the SPA scripts,
+// to be echoed in the CSRF header of unsafe requests.
+// The cookies lifetime is the token exp duration, See SetExpDuration.
+func Cookies(tk string, csrfKey []byte, opts ...auth.Option) (*http.Cookie, *http.Cookie) {
+	cfg := newConfig(opts...)
+	maxAge := int(cfg.exp.Seconds())
+
+	jwtCookie := &http.Cookie{
+		Name:     cfg.cookie,
+		Value:    tk,
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	csrfCookie := &http.Cookie{
+		Name:     cfg.csrfCookie,
+		Value:    CSRFToken(csrfKey, tk),
+		Path:     "/",
+		MaxAge:   maxAge,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	return jwtCookie, csrfCookie
+}
+
+// NewCookie return strategy authenticate request using jwt token carried in an HttpOnly cookie,
+// covering the SPA deployment model, See Cookies.
+// Unsafe requests (i.e not GET, HEAD, OPTIONS, or TRACE) must carry the CSRF token in the CSRF header,
+// matching the CSRF cookie (double-submit) and bound to the token using the given key.
+// NewCookie is similar to New().
+func NewCookie(c store.Cache, s SecretsKeeper, csrfKey []byte, opts ...auth.Option) auth.Strategy {
+	cfg := newConfig(opts...)
+	opts = append([]auth.Option{token.SetParser(token.CookieParser(cfg.cookie))}, opts...)
+
+	csrf := func(ctx context.Context, r *http.Request, next auth.Strategy) (auth.Info, error) {
+		if err := checkCSRF(r, cfg, csrfKey); err != nil {
+			return nil, err
+		}
+		return next.Authenticate(ctx, r)
+	}
+
+	return auth.Decorate(New(c, s, opts...), auth.DecoratorFunc(csrf))
+}
+
+func checkCSRF(r *http.Request, cfg *config, key []byte) error {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+
+	tk, err := r.Cookie(cfg.cookie)
+	if err != nil {
+		// no token, let the strategy report it.
+		return nil
+	}
+
+	header := r.Header.Get(cfg.csrfHeader)
+	ck, err := r.Cookie(cfg.csrfCookie)
+
+	if err != nil || header == "" ||
+		!hmac.Equal([]byte(header), []byte(ck.Value)) ||
+		!hmac.Equal([]byte(header), []byte(CSRFToken(key, tk.Value))) {
+		return ErrInvalidCSRF
+	}
+
+	return nil
+}
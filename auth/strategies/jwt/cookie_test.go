@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestCookieStrategy(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	key := []byte("csrf-key")
+	info := auth.NewUserInfo("alice", "1", nil, nil)
+	tk, _ := IssueAccessToken(info, keeper)
+	jwtCookie, csrfCookie := Cookies(tk, key)
+
+	table := []struct {
+		name    string
+		method  string
+		cookies []*http.Cookie
+		header  string
+		err     error
+	}{
+		{
+			name:    "it authenticate safe request without csrf header",
+			method:  http.MethodGet,
+			cookies: []*http.Cookie{jwtCookie},
+		},
+		{
+			name:    "it authenticate unsafe request with csrf header",
+			method:  http.MethodPost,
+			cookies: []*http.Cookie{jwtCookie, csrfCookie},
+			header:  csrfCookie.Value,
+		},
+		{
+			name:    "it return error when csrf header missing",
+			method:  http.MethodPost,
+			cookies: []*http.Cookie{jwtCookie, csrfCookie},
+			err:     ErrInvalidCSRF,
+		},
+		{
+			name:    "it return error when csrf cookie missing",
+			method:  http.MethodDelete,
+			cookies: []*http.Cookie{jwtCookie},
+			header:  csrfCookie.Value,
+			err:     ErrInvalidCSRF,
+		},
+		{
+			name:    "it return error when csrf token bound to another token",
+			method:  http.MethodPut,
+			cookies: []*http.Cookie{jwtCookie, {Name: "csrf_token", Value: CSRFToken(key, "other")}},
+			header:  CSRFToken(key, "other"),
+			err:     ErrInvalidCSRF,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewCookie(store.New(0), keeper, key)
+			r, _ := http.NewRequest(tt.method, "/", nil)
+			for _, c := range tt.cookies {
+				r.AddCookie(c)
+			}
+
+			if tt.header != "" {
+				r.Header.Set("X-CSRF-Token", tt.header)
+			}
+
+			got, err := s.Authenticate(context.Background(), r)
+			assert.Equal(t, tt.err, err)
+
+			if tt.err == nil {
+				assert.Equal(t, "alice", got.UserName())
+			}
+		})
+	}
+
+	// no token cookie.
+	r, _ := http.NewRequest(http.MethodPost, "/", nil)
+	_, err := NewCookie(store.New(0), keeper, key).Authenticate(context.Background(), r)
+	assert.Error(t, err)
+}
+
+func TestCookies(t *testing.T) {
+	jwtCookie, csrfCookie := Cookies("token", []byte("key"), SetCookieName("at"), SetCSRFCookieName("xsrf"))
+
+	assert.Equal(t, "at", jwtCookie.Name)
+	assert.Equal(t, "token", jwtCookie.Value)
+	assert.True(t, jwtCookie.HttpOnly)
+	assert.Equal(t, 300, jwtCookie.MaxAge)
+
+	assert.Equal(t, "xsrf", csrfCookie.Name)
+	assert.Equal(t, CSRFToken([]byte("key"), "token"), csrfCookie.Value)
+	assert.False(t, csrfCookie.HttpOnly)
+}
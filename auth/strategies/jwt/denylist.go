@@ -0,0 +1,162 @@
+package jwt
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+var (
+	// ErrTokenRevoked is returned by strategy when the token found in the denylist.
+	ErrTokenRevoked = gerrors.WithReason(errors.New("strategies/jwt: Token revoked"), gerrors.ReasonRevoked)
+
+	// ErrMissingJTI is returned by Denylist RevokeToken when the token does not carry a jti.
+	ErrMissingJTI = errors.New("strategies/jwt: Token missing jti")
+)
+
+func init() {
+	gob.Register(&denied{})
+}
+
+type denied struct {
+	RevokedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Denylist records revoked tokens until their natural expiry,
+// so stateless tokens can still be revoked on logout or compromise.
+// Tokens revoked by their jti and subject pair, or all the subject tokens issued before a point in time.
+// Entries kept in the cache, thus the cache entries lifetime should not be less than the tokens lifetime,
+// and a shared cache (e.g Redis) should be used when the tokens verified by multiple instances.
+type Denylist struct {
+	cache store.Cache
+}
+
+// NewDenylist return new Denylist backed by the given cache.
+func NewDenylist(c store.Cache) *Denylist {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	return &Denylist{cache: c}
+}
+
+// Revoke revokes the token of the given jti and subject until its expiry time.
+func (d *Denylist) Revoke(r *http.Request, jti, subject string, exp time.Time) error {
+	v := &denied{RevokedAt: time.Now(), ExpiresAt: exp}
+	return d.cache.Store(jtiKey(jti, subject), v, r)
+}
+
+// RevokeToken verifies the given token using the SecretsKeeper and revoke it until its expiry time,
+// typically used on logout. Tokens without a jti can only be revoked using RevokeSubject.
+func (d *Denylist) RevokeToken(r *http.Request, s SecretsKeeper, tk string) error {
+	t, err := verify(s, tk)
+	if err != nil {
+		return err
+	}
+
+	c := jwt.Claims{}
+	if err := t.Decode(&c); err != nil {
+		return err
+	}
+
+	if c.ID == "" {
+		return ErrMissingJTI
+	}
+
+	var exp time.Time
+	if c.Expiry != nil {
+		exp = c.Expiry.Time()
+	}
+
+	return d.Revoke(r, c.ID, c.Subject, exp)
+}
+
+// RevokeSubject revokes all the tokens of the given subject issued before now,
+// lifetime is the max issued tokens lifetime, after which the entry no longer needed.
+// Tokens without an iat are revoked regardless of their issue time.
+func (d *Denylist) RevokeSubject(r *http.Request, subject string, lifetime time.Duration) error {
+	now := time.Now()
+	v := &denied{RevokedAt: now, ExpiresAt: now.Add(lifetime)}
+	return d.cache.Store(subjectKey(subject), v, r)
+}
+
+// Revoked reports whether the token of the given jti, subject, and issue time revoked.
+func (d *Denylist) Revoked(r *http.Request, jti, subject string, iat time.Time) (bool, error) {
+	if jti != "" {
+		v, err := d.load(r, jtiKey(jti, subject))
+		if err != nil || v != nil {
+			return v != nil, err
+		}
+	}
+
+	v, err := d.load(r, subjectKey(subject))
+	if err != nil || v == nil {
+		return false, err
+	}
+
+	// iat has seconds precision.
+	return iat.IsZero() || !iat.After(v.RevokedAt.Truncate(time.Second)), nil
+}
+
+// RevocationCheck reports whether the given verified token revoked,
+// its signature matches token.RevocationCheckFunc, used by the cached jwt strategy,
+// so revoked tokens stop working even when their results cached.
+func (d *Denylist) RevocationCheck(_ context.Context, r *http.Request, tk string, _ auth.Info) (bool, error) {
+	// the token verified before its result cached.
+	t, err := jwt.Parse(tk)
+	if err != nil {
+		return false, err
+	}
+
+	c := jwt.Claims{}
+	if err := t.Decode(&c); err != nil {
+		return false, err
+	}
+
+	return d.revoked(r, &c)
+}
+
+func (d *Denylist) revoked(r *http.Request, c *jwt.Claims) (bool, error) {
+	var iat time.Time
+	if c.IssuedAt != nil {
+		iat = c.IssuedAt.Time()
+	}
+
+	return d.Revoked(r, c.ID, c.Subject, iat)
+}
+
+// load return the entry of the given key, or nil if the entry does not exist or expired.
+func (d *Denylist) load(r *http.Request, key string) (*denied, error) {
+	v, ok, err := d.cache.Load(key, r)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	e, ok := v.(*denied)
+	if !ok {
+		return nil, gerrors.NewInvalidType((*denied)(nil), v)
+	}
+
+	if !e.ExpiresAt.IsZero() && time.Now().After(e.ExpiresAt) {
+		_ = d.cache.Delete(key, r)
+		return nil, nil
+	}
+
+	return e, nil
+}
+
+func jtiKey(jti, subject string) string {
+	return "jwt:denylist:jti:" + subject + ":" + jti
+}
+
+func subjectKey(subject string) string {
+	return "jwt:denylist:sub:" + subject
+}
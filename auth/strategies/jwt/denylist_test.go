@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestDenylist(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	info := auth.NewUserInfo("test", "1", nil, nil)
+
+	table := []struct {
+		name   string
+		revoke func(d *Denylist, r *http.Request, tk string) error
+		err    error
+	}{
+		{
+			name: "it authenticate token not in denylist",
+			revoke: func(d *Denylist, r *http.Request, tk string) error {
+				return nil
+			},
+		},
+		{
+			name: "it return error when token revoked",
+			revoke: func(d *Denylist, r *http.Request, tk string) error {
+				return d.RevokeToken(r, keeper, tk)
+			},
+			err: ErrTokenRevoked,
+		},
+		{
+			name: "it return error when subject revoked",
+			revoke: func(d *Denylist, r *http.Request, tk string) error {
+				return d.RevokeSubject(r, "1", time.Hour)
+			},
+			err: ErrTokenRevoked,
+		},
+		{
+			name: "it authenticate token when revocation expired",
+			revoke: func(d *Denylist, r *http.Request, tk string) error {
+				return d.RevokeSubject(r, "1", -time.Second)
+			},
+		},
+		{
+			name: "it authenticate token when other subject revoked",
+			revoke: func(d *Denylist, r *http.Request, tk string) error {
+				return d.RevokeSubject(r, "2", time.Hour)
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDenylist(store.New(0))
+			tk, _ := IssueAccessToken(info, keeper)
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+tk)
+
+			assert.NoError(t, tt.revoke(d, r, tk))
+
+			fn := GetAuthenticateFunc(keeper, SetDenylist(d))
+			_, err := fn(r.Context(), r, tk)
+
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestDenylistCachedStrategy(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	d := NewDenylist(store.New(0))
+	s := New(store.New(0), keeper, SetDenylist(d))
+
+	tk, _ := IssueAccessToken(auth.NewUserInfo("test", "1", nil, nil), keeper)
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tk)
+
+	_, err := s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	assert.NoError(t, d.RevokeToken(r, keeper, tk))
+
+	// cached token result rejected once revoked.
+	_, err = s.Authenticate(r.Context(), r)
+	assert.Error(t, err)
+
+	// other tokens of the subject not affected.
+	other, _ := IssueAccessToken(auth.NewUserInfo("test", "1", nil, nil), keeper)
+	r.Header.Set("Authorization", "Bearer "+other)
+	_, err = s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+}
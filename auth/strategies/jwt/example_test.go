@@ -0,0 +1,30 @@
+package jwt
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func Example() {
+	s := StaticSecret{
+		ID:        "id",
+		Secret:    []byte("secret"),
+		Algorithm: "HS256",
+	}
+
+	info := auth.NewUserInfo("example", "1", nil, nil)
+	token, _ := IssueAccessToken(info, s)
+
+	strategy := New(store.New(0), s)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	info, err := strategy.Authenticate(r.Context(), r)
+	fmt.Println(info.UserName(), err)
+	// Output:
+	// example <nil>
+}
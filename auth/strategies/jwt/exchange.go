@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+)
+
+// Token exchange (RFC 8693) grant and token types.
+const (
+	TokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+	AccessTokenType        = "urn:ietf:params:oauth:token-type:access_token"
+	JWTTokenType           = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+// ErrActorNotAllowed is returned by Exchanger,
+// when the subject token may_act claim does not allow the actor to act on behalf of the subject.
+var ErrActorNotAllowed = errors.New("strategies/jwt: Actor not allowed to act on behalf of the subject")
+
+// ErrAudienceNotAllowed is returned by Exchanger,
+// when the audience policy does not allow the requested audience, or no audience policy set.
+var ErrAudienceNotAllowed = errors.New("strategies/jwt: Audience not allowed")
+
+// AudiencePolicy declare a function signature to authorize the audience requested in a token exchange,
+// for the validated subject, r typically carries the authenticated client, See auth.User.
+type AudiencePolicy func(r *http.Request, subject auth.Info, audience []string) bool
+
+// AllowAudiences return AudiencePolicy allowing only the given audiences to be requested.
+func AllowAudiences(aud ...string) AudiencePolicy {
+	allowed := make(map[string]struct{}, len(aud))
+	for _, a := range aud {
+		allowed[a] = struct{}{}
+	}
+
+	return func(_ *http.Request, _ auth.Info, audience []string) bool {
+		for _, a := range audience {
+			if _, ok := allowed[a]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Exchanger implements OAuth 2.0 token exchange (RFC 8693),
+// it exchange an inbound validated token for a downstream token issued by the SecretsKeeper,
+// typically used in multi-hop microservice calls.
+//
+// Without an actor token the exchange is an impersonation,
+// the issued token represents the subject and preserve the subject token act claim.
+// With an actor token the exchange is a delegation,
+// the issued token represents the subject and the actor become the current actor (act claim),
+// nesting the subject token act claim as prior actors,
+// and the actor must match the subject token may_act claim if exists.
+//
+// The issued token carries the subject groups, roles, and extensions.
+// Requested audiences denied by default, and allowed only by the audience policy,
+// See SetAudiencePolicy.
+type Exchanger struct {
+	validate token.AuthenticateFunc
+	keeper   SecretsKeeper
+	policy   AudiencePolicy
+	opts     []auth.Option
+}
+
+// NewExchanger return new Exchanger.
+// validate authenticates the inbound subject and actor tokens, e.g GetAuthenticateFunc,
+// opts applied to the issued token, See IssueAccessToken, and SetAudiencePolicy.
+func NewExchanger(validate token.AuthenticateFunc, keeper SecretsKeeper, opts ...auth.Option) *Exchanger {
+	return &Exchanger{
+		validate: validate,
+		keeper:   keeper,
+		policy:   newConfig(opts...).audiencePolicy,
+		opts:     opts,
+	}
+}
+
+// Exchange validates the subject token and the optional actor token,
+// and issue a downstream token for the given audience.
+// if audience empty the issued token audience is the exchanger options audience,
+// Otherwise, the audience must be allowed by the audience policy or ErrAudienceNotAllowed returned.
+func (e *Exchanger) Exchange(r *http.Request, subject, actor string, audience ...string) (string, error) {
+	sub, err := e.validate(r.Context(), r, subject)
+	if err != nil {
+		return "", err
+	}
+
+	if len(audience) > 0 && (e.policy == nil || !e.policy(r, sub, audience)) {
+		return "", ErrAudienceNotAllowed
+	}
+
+	ext := make(map[string][]string, len(sub.Extensions()))
+	for k, v := range sub.Extensions() {
+		ext[k] = v
+	}
+
+	if actor != "" {
+		act, err := e.validate(r.Context(), r, actor)
+		if err != nil {
+			return "", err
+		}
+
+		if may := ext[ExtensionMayAct]; len(may) > 0 && may[0] != act.ID() {
+			return "", ErrActorNotAllowed
+		}
+
+		ext[ExtensionActor] = append([]string{act.ID()}, ext[ExtensionActor]...)
+	}
+
+	delete(ext, ExtensionMayAct)
+
+	info := auth.NewUserInfo(sub.UserName(), sub.ID(), sub.Groups(), ext)
+	auth.SetUserRoles(info, auth.UserRoles(sub))
+	opts := e.opts
+
+	if len(audience) > 0 {
+		opts = append(append([]auth.Option{}, e.opts...), SetAudience(audience...))
+	}
+
+	return IssueAccessToken(info, e.keeper, opts...)
+}
+
+// Handler return the token exchange endpoint HTTP handler.
+// The handler does not authenticate the client,
+// therefore it should be wrapped with auth.Middleware (or equivalent).
+func (e *Exchanger) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			exchangeError(w, http.StatusMethodNotAllowed, "invalid_request")
+			return
+		}
+
+		if r.PostFormValue("grant_type") != TokenExchangeGrantType {
+			exchangeError(w, http.StatusBadRequest, "unsupported_grant_type")
+			return
+		}
+
+		subject, actor := r.PostFormValue("subject_token"), r.PostFormValue("actor_token")
+
+		if subject == "" ||
+			!supportedTokenType(r.PostFormValue("subject_token_type")) ||
+			(actor != "" && !supportedTokenType(r.PostFormValue("actor_token_type"))) {
+			exchangeError(w, http.StatusBadRequest, "invalid_request")
+			return
+		}
+
+		if t := r.PostFormValue("requested_token_type"); t != "" && !supportedTokenType(t) {
+			exchangeError(w, http.StatusBadRequest, "invalid_target")
+			return
+		}
+
+		tk, err := e.Exchange(r, subject, actor, r.PostForm["audience"]...)
+		if err == ErrAudienceNotAllowed {
+			exchangeError(w, http.StatusBadRequest, "invalid_target")
+			return
+		}
+
+		if err != nil {
+			exchangeError(w, http.StatusBadRequest, "invalid_grant")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":      tk,
+			"issued_token_type": AccessTokenType,
+			"token_type":        "Bearer",
+			"expires_in":        int64(newConfig(e.opts...).exp / time.Second),
+		})
+	})
+}
+
+func supportedTokenType(t string) bool {
+	return t == AccessTokenType || t == JWTTokenType
+}
+
+func exchangeError(w http.ResponseWriter, code int, e string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": e})
+}
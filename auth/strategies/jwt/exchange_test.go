@@ -0,0 +1,198 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestExchange(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	validate := GetAuthenticateFunc(keeper)
+
+	issue := func(id string, ext map[string][]string) string {
+		info := auth.NewUserInfo(id, id, nil, ext)
+		auth.SetUserRoles(info, []string{id + "-role"})
+		tk, _ := IssueAccessToken(info, keeper)
+		return tk
+	}
+
+	table := []struct {
+		name    string
+		subject string
+		actor   string
+		err     error
+		act     []string
+	}{
+		{
+			name:    "it impersonate subject and preserve act claim",
+			subject: issue("alice", map[string][]string{ExtensionActor: {"gateway"}}),
+			act:     []string{"gateway"},
+		},
+		{
+			name:    "it delegate and nest prior actors",
+			subject: issue("alice", map[string][]string{ExtensionActor: {"gateway"}}),
+			actor:   issue("orders", nil),
+			act:     []string{"orders", "gateway"},
+		},
+		{
+			name:    "it delegate when may_act allow actor",
+			subject: issue("alice", map[string][]string{ExtensionMayAct: {"orders"}}),
+			actor:   issue("orders", nil),
+			act:     []string{"orders"},
+		},
+		{
+			name:    "it return error when may_act does not allow actor",
+			subject: issue("alice", map[string][]string{ExtensionMayAct: {"billing"}}),
+			actor:   issue("orders", nil),
+			err:     ErrActorNotAllowed,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExchanger(validate, keeper, SetAudiencePolicy(AllowAudiences("downstream")))
+			r, _ := http.NewRequest("POST", "/", nil)
+
+			tk, err := e.Exchange(r, tt.subject, tt.actor, "downstream")
+			assert.Equal(t, tt.err, err)
+
+			if err != nil {
+				return
+			}
+
+			info, err := GetAuthenticateFunc(keeper, SetAudience("downstream"))(context.Background(), r, tk)
+			assert.NoError(t, err)
+			assert.Equal(t, "alice", info.ID())
+			assert.Equal(t, tt.act, info.Extensions()[ExtensionActor])
+			assert.Nil(t, info.Extensions()[ExtensionMayAct])
+			assert.Equal(t, []string{"alice-role"}, auth.UserRoles(info))
+		})
+	}
+}
+
+func TestExchangeAudiencePolicy(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	validate := GetAuthenticateFunc(keeper)
+	subject, _ := IssueAccessToken(auth.NewUserInfo("alice", "1", nil, nil), keeper)
+
+	table := []struct {
+		name     string
+		opts     []auth.Option
+		audience []string
+		err      error
+	}{
+		{
+			name:     "it deny audience when no policy set",
+			audience: []string{"downstream"},
+			err:      ErrAudienceNotAllowed,
+		},
+		{
+			name:     "it deny audience not allowed by policy",
+			opts:     []auth.Option{SetAudiencePolicy(AllowAudiences("downstream"))},
+			audience: []string{"downstream", "billing"},
+			err:      ErrAudienceNotAllowed,
+		},
+		{
+			name:     "it allow audience allowed by policy",
+			opts:     []auth.Option{SetAudiencePolicy(AllowAudiences("downstream", "billing"))},
+			audience: []string{"downstream", "billing"},
+		},
+		{
+			name: "it issue token for the exchanger audience when audience not requested",
+			opts: []auth.Option{SetAudience("default")},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("POST", "/", nil)
+			_, err := NewExchanger(validate, keeper, tt.opts...).Exchange(r, subject, "", tt.audience...)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestExchangerHandler(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	subject, _ := IssueAccessToken(auth.NewUserInfo("alice", "1", nil, nil), keeper)
+	policy := SetAudiencePolicy(AllowAudiences("downstream"))
+	h := NewExchanger(GetAuthenticateFunc(keeper), keeper, policy).Handler()
+
+	table := []struct {
+		name string
+		form url.Values
+		code int
+	}{
+		{
+			name: "it return error when grant type unsupported",
+			form: url.Values{"grant_type": {"password"}},
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "it return error when subject token type unsupported",
+			form: url.Values{
+				"grant_type":         {TokenExchangeGrantType},
+				"subject_token":      {subject},
+				"subject_token_type": {"urn:ietf:params:oauth:token-type:saml2"},
+			},
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "it return error when subject token invalid",
+			form: url.Values{
+				"grant_type":         {TokenExchangeGrantType},
+				"subject_token":      {"invalid"},
+				"subject_token_type": {AccessTokenType},
+			},
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "it return error when audience not allowed",
+			form: url.Values{
+				"grant_type":         {TokenExchangeGrantType},
+				"subject_token":      {subject},
+				"subject_token_type": {AccessTokenType},
+				"audience":           {"billing"},
+			},
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "it issue token",
+			form: url.Values{
+				"grant_type":         {TokenExchangeGrantType},
+				"subject_token":      {subject},
+				"subject_token_type": {AccessTokenType},
+				"audience":           {"downstream"},
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("POST", "/", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+
+			if tt.code == http.StatusOK {
+				body := map[string]interface{}{}
+				_ = json.Unmarshal(w.Body.Bytes(), &body)
+				assert.Equal(t, AccessTokenType, body["issued_token_type"])
+				assert.NotEmpty(t, body["access_token"])
+			}
+		})
+	}
+}
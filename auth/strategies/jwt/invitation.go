@@ -0,0 +1,230 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// InvitationType is the typ header of the invitation tokens,
+// so invitations never authenticate as access tokens and vice versa.
+const InvitationType = "invitation+jwt"
+
+const (
+	// ExtensionTenant is the auth.Info extension key carries the invitation tenant.
+	ExtensionTenant = "tenant"
+	// ExtensionInvitationID is the auth.Info extension key carries the invitation id (jti),
+	// which can be passed to auth.Revoke to invalidate the invitation.
+	ExtensionInvitationID = "invitation_id"
+)
+
+var (
+	// ErrInvitationUsed is returned by the invitation strategy,
+	// when the invitation already authenticated a request or revoked.
+	ErrInvitationUsed = errors.New("strategies/jwt: Invitation already used or revoked")
+
+	// ErrMissingInvitationID is returned by the invitation strategy,
+	// when the invitation token does not carry an id (jti).
+	ErrMissingInvitationID = errors.New("strategies/jwt: Invitation token missing jti")
+)
+
+// Invitation represents the pre-assigned attributes of an invited user.
+type Invitation struct {
+	// Email is the invited user email address, used as the user name and id.
+	Email string
+	// Groups is the invited user pre-assigned groups.
+	Groups []string
+	// Roles is the invited user pre-assigned roles.
+	Roles []string
+	// Tenant is the invited user tenant, See ExtensionTenant.
+	Tenant string
+	// Extensions is the invited user additional attributes.
+	Extensions map[string][]string
+}
+
+type invitationClaims struct {
+	claims
+	Email  string `json:"email,omitempty"`
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// IssueInvitation issue a single-use invitation token for the given invitation,
+// signed using the SecretsKeeper current kid secret.
+// The invitation valid for 7 days by default, use SetExpDuration to override it,
+// SetIssuer and SetAudience also applies.
+func IssueInvitation(inv Invitation, s SecretsKeeper, opts ...auth.Option) (string, error) {
+	opts = append([]auth.Option{SetExpDuration(time.Hour * 24 * 7)}, opts...)
+	cfg := newConfig(opts...)
+	kid := s.KID()
+
+	secret, alg, err := s.Get(kid)
+	if err != nil {
+		return "", err
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	c := invitationClaims{
+		claims: claims{
+			Claims: jwt.Claims{
+				Issuer:    cfg.issuer,
+				Subject:   inv.Email,
+				Audience:  cfg.audience,
+				Expiry:    jwt.NewNumericDate(now.Add(cfg.exp)),
+				NotBefore: jwt.NewNumericDate(now),
+				IssuedAt:  jwt.NewNumericDate(now),
+				ID:        base64.RawURLEncoding.EncodeToString(b),
+			},
+			UserName:   inv.Email,
+			Groups:     inv.Groups,
+			Roles:      inv.Roles,
+			Extensions: inv.Extensions,
+		},
+		Email:  inv.Email,
+		Tenant: inv.Tenant,
+	}
+
+	return jwt.SignWithType(alg, kid, InvitationType, secret, c)
+}
+
+// SetInvitationParser sets the invitation strategy token parser,
+// Default token.QueryParser("invitation").
+func SetInvitationParser(p token.Parser) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if i, ok := v.(*invitation); ok {
+			i.parser = p
+		}
+	})
+}
+
+// NewInvitation return strategy authenticate the registration endpoint requests,
+// using the invitation tokens issued by IssueInvitation.
+// Each invitation authenticates exactly one request,
+// the returned info carries the invitation pre-assigned groups, roles, and tenant,
+// to be persisted by the registration handler.
+//
+// Used invitations ids kept in the cache until the invitation expires,
+// thus the cache entries lifetime should not be less than the invitations lifetime.
+// Use a dedicated SecretsKeeper for invitations when possible.
+func NewInvitation(c store.Cache, s SecretsKeeper, opts ...auth.Option) auth.Strategy {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	i := &invitation{
+		mu:     new(sync.Mutex),
+		cache:  c,
+		keeper: s,
+		cfg:    newConfig(opts...),
+		parser: token.QueryParser("invitation"),
+	}
+
+	for _, opt := range opts {
+		opt.Apply(i)
+	}
+
+	return i
+}
+
+type invitation struct {
+	mu     *sync.Mutex
+	cache  store.Cache
+	keeper SecretsKeeper
+	cfg    *config
+	parser token.Parser
+}
+
+func (i *invitation) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	tk, err := i.parser.Token(r)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := verify(i.keeper, tk)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Header.Type != InvitationType {
+		return nil, ErrInvalidType
+	}
+
+	c := invitationClaims{}
+	if err := t.Decode(&c); err != nil {
+		return nil, err
+	}
+
+	err = c.Validate(jwt.Expected{
+		Issuer:   i.cfg.issuer,
+		Audience: i.cfg.audience,
+		Time:     time.Now(),
+		Leeway:   i.cfg.leeway,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if c.ID == "" {
+		return nil, ErrMissingInvitationID
+	}
+
+	if err := i.consume(c.ID, r); err != nil {
+		return nil, err
+	}
+
+	ext := make(map[string][]string, len(c.Extensions)+2)
+	for k, v := range c.Extensions {
+		ext[k] = v
+	}
+
+	ext[ExtensionInvitationID] = []string{c.ID}
+
+	if c.Tenant != "" {
+		ext[ExtensionTenant] = []string{c.Tenant}
+	}
+
+	info := auth.NewUserInfo(c.Email, c.Subject, c.Groups, ext)
+	auth.SetUserRoles(info, c.Roles)
+	c.setValidity(info)
+
+	return info, nil
+}
+
+// Revoke invalidate the invitation of the given id, See ExtensionInvitationID.
+func (i *invitation) Revoke(id string, r *http.Request) error {
+	return i.cache.Store(invitationKey(id), true, r)
+}
+
+// consume marks the invitation used, or return ErrInvitationUsed if it's already used.
+func (i *invitation) consume(id string, r *http.Request) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	k := invitationKey(id)
+
+	if _, ok, err := i.cache.Load(k, r); err != nil {
+		return err
+	} else if ok {
+		return ErrInvitationUsed
+	}
+
+	return i.cache.Store(k, true, r)
+}
+
+func invitationKey(id string) string {
+	return "invitation:" + id
+}
@@ -0,0 +1,111 @@
+package jwt
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestInvitation(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	inv := Invitation{
+		Email:      "jane@example.com",
+		Roles:      []string{"admin"},
+		Tenant:     "acme",
+		Extensions: map[string][]string{"k": {"v"}},
+	}
+	access, _ := IssueAccessToken(auth.NewUserInfo("jane", "1", nil, nil), keeper)
+
+	table := []struct {
+		name  string
+		opts  []auth.Option
+		vopts []auth.Option
+		token string
+		err   bool
+	}{
+		{
+			name: "it authenticate invitation token",
+		},
+		{
+			name:  "it return error when token is an access token",
+			token: access,
+			err:   true,
+		},
+		{
+			name: "it return error when invitation expired",
+			opts: []auth.Option{SetExpDuration(-time.Hour)},
+			err:  true,
+		},
+		{
+			name:  "it return error when audience does not match",
+			opts:  []auth.Option{SetAudience("other")},
+			vopts: []auth.Option{SetAudience("signup")},
+			err:   true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			tk := tt.token
+			if tk == "" {
+				tk, _ = IssueInvitation(inv, keeper, tt.opts...)
+			}
+
+			s := NewInvitation(store.New(0), keeper, tt.vopts...)
+			r, _ := http.NewRequest("POST", "/signup?invitation="+tk, nil)
+			info, err := s.Authenticate(r.Context(), r)
+
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, inv.Email, info.UserName())
+			assert.Equal(t, inv.Email, info.ID())
+			assert.Equal(t, inv.Roles, auth.UserRoles(info))
+			assert.Equal(t, inv.Tenant, info.Extensions()[ExtensionTenant][0])
+			assert.Equal(t, "v", info.Extensions()["k"][0])
+			assert.Len(t, info.Extensions()[ExtensionInvitationID], 1)
+			_, ok := auth.UserExpiry(info)
+			assert.True(t, ok)
+			_, ok = auth.UserNotBefore(info)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestInvitationSingleUse(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	s := NewInvitation(store.New(0), keeper, SetInvitationParser(token.XHeaderParser("X-Invitation")))
+	tk, err := IssueInvitation(Invitation{Email: "jane@example.com"}, keeper)
+	assert.NoError(t, err)
+
+	r, _ := http.NewRequest("POST", "/signup", nil)
+	r.Header.Set("X-Invitation", tk)
+	r.Header.Set("Authorization", "Bearer "+tk)
+
+	info, err := s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	_, err = s.Authenticate(r.Context(), r)
+	assert.Equal(t, ErrInvitationUsed, err)
+
+	// invitations never authenticate as access tokens.
+	_, err = New(store.New(0), keeper).Authenticate(r.Context(), r)
+	assert.Equal(t, ErrInvalidType, err)
+
+	// revoked invitations does not authenticate.
+	s = NewInvitation(store.New(0), keeper, SetInvitationParser(token.XHeaderParser("X-Invitation")))
+	err = auth.Revoke(s, info.Extensions()[ExtensionInvitationID][0], r)
+	assert.NoError(t, err)
+
+	_, err = s.Authenticate(r.Context(), r)
+	assert.Equal(t, ErrInvitationUsed, err)
+}
@@ -0,0 +1,44 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// IssueAccessToken issue jwt access token for the provided user info,
+// signed using the SecretsKeeper current kid secret.
+// Each token carries a unique jti, so it can be revoked using Denylist.
+func IssueAccessToken(info auth.Info, s SecretsKeeper, opts ...auth.Option) (string, error) {
+	cfg := newConfig(opts...)
+	kid := s.KID()
+
+	secret, alg, err := s.Get(kid)
+	if err != nil {
+		return "", err
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	c := claims{
+		Claims: jwt.Claims{
+			Issuer:    cfg.issuer,
+			Audience:  cfg.audience,
+			Expiry:    jwt.NewNumericDate(now.Add(cfg.exp)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        base64.RawURLEncoding.EncodeToString(jti),
+		},
+	}
+
+	c.setInfo(info)
+
+	return jwt.Sign(alg, kid, secret, c)
+}
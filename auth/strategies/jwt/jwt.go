@@ -0,0 +1,260 @@
+// Package jwt provides authentication strategy,
+// to authenticate HTTP requests based on JSON Web Token,
+// and an issuer to issue access tokens for authenticated users,
+// and single-use invitation tokens for users registration.
+// Tokens may also be carried in HttpOnly cookies with double-submit CSRF protection, See NewCookie.
+//
+// Tokens signed using the SecretsKeeper secrets,
+// a secret can be an HMAC []byte, or a crypto.Signer,
+// so signing keys backed by PKCS#11/HSM, TPM, or cloud KMS supported,
+// without ever exposing the private key bytes to the process.
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+const (
+	// ExtensionActor is the auth.Info extension key carries the token act claim chain,
+	// as actors subjects ordered from the current actor to the original actor.
+	ExtensionActor = "act"
+	// ExtensionMayAct is the auth.Info extension key carries the token may_act claim subject.
+	ExtensionMayAct = "may_act"
+)
+
+var (
+	// ErrInvalidKID is returned by SecretsKeeper when the kid does not exist.
+	ErrInvalidKID = errors.New("strategies/jwt: Invalid token kid")
+
+	// ErrInvalidAlg is returned by strategy when token alg does not match the kid algorithm.
+	ErrInvalidAlg = errors.New("strategies/jwt: Invalid token alg")
+
+	// ErrInvalidType is returned by strategy when token typ does not match the expected type,
+	// e.g an invitation token presented as an access token or vice versa.
+	ErrInvalidType = errors.New("strategies/jwt: Invalid token typ")
+)
+
+// SecretsKeeper hold all secrets/keys to sign and parse JWT token.
+type SecretsKeeper interface {
+	// KID return's secret/key id.
+	// KID must return the most recently used id if more than one secret/key exists.
+	// https://tools.ietf.org/html/rfc7515#section-4.1.4
+	KID() string
+	// Get return's secret/key and the corresponding sign algorithm.
+	// secret can be []byte for HMAC algorithms,
+	// crypto.Signer to sign and verify tokens,
+	// or crypto.PublicKey to only verify tokens.
+	Get(kid string) (secret interface{}, algorithm string, err error)
+}
+
+// StaticSecret implements the SecretsKeeper and holds only a single secret.
+type StaticSecret struct {
+	ID        string
+	Secret    interface{}
+	Algorithm string
+}
+
+// KID return's secret/key id.
+func (s StaticSecret) KID() string {
+	return s.ID
+}
+
+// Get return's secret/key and the corresponding sign algorithm.
+func (s StaticSecret) Get(kid string) (interface{}, string, error) {
+	if kid != s.ID {
+		return nil, "", ErrInvalidKID
+	}
+	return s.Secret, s.Algorithm, nil
+}
+
+// GetAuthenticateFunc return function to authenticate request using jwt token.
+// The returned function typically used with the token strategy.
+func GetAuthenticateFunc(s SecretsKeeper, opts ...auth.Option) token.AuthenticateFunc {
+	cfg := newConfig(opts...)
+	return func(ctx context.Context, r *http.Request, tk string) (auth.Info, error) {
+		return parse(r, s, cfg, tk)
+	}
+}
+
+// New return strategy authenticate request using jwt token.
+// New is similar to token.New().
+// The cache entries lifetime should not exceed the tokens expiry duration.
+func New(c store.Cache, s SecretsKeeper, opts ...auth.Option) auth.Strategy {
+	fn := GetAuthenticateFunc(s, opts...)
+
+	if d := newConfig(opts...).denylist; d != nil {
+		opts = append([]auth.Option{token.SetRevocationCheck(d.RevocationCheck)}, opts...)
+	}
+
+	return token.New(fn, c, opts...)
+}
+
+func parse(r *http.Request, s SecretsKeeper, cfg *config, tk string) (auth.Info, error) {
+	t, err := verify(s, tk)
+	if err != nil {
+		return nil, err
+	}
+
+	// invitations authenticate only the registration endpoint, See NewInvitation.
+	if t.Header.Type == InvitationType {
+		return nil, ErrInvalidType
+	}
+
+	c := claims{}
+	if err := t.Decode(&c); err != nil {
+		return nil, err
+	}
+
+	err = c.Validate(jwt.Expected{
+		Issuer:   cfg.issuer,
+		Audience: cfg.audience,
+		Time:     time.Now(),
+		Leeway:   cfg.leeway,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.denylist != nil {
+		revoked, err := cfg.denylist.revoked(r, &c.Claims)
+		if err != nil {
+			return nil, err
+		}
+
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	if cfg.mapper == nil {
+		return c.info(), nil
+	}
+
+	info, err := cfg.mapper.MapJSON(t.Payload())
+	if err != nil {
+		return nil, err
+	}
+
+	c.setValidity(info)
+
+	return info, nil
+}
+
+// verify parse the token and verify its signature using the SecretsKeeper kid secret.
+func verify(s SecretsKeeper, tk string) (*jwt.Token, error) {
+	t, err := jwt.Parse(tk)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, alg, err := s.Get(t.Header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Header.Algorithm != alg {
+		return nil, ErrInvalidAlg
+	}
+
+	if signer, ok := secret.(crypto.Signer); ok {
+		secret = signer.Public()
+	}
+
+	if err := t.Verify(secret); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+type actor struct {
+	Subject string `json:"sub"`
+	Actor   *actor `json:"act,omitempty"`
+}
+
+type claims struct {
+	jwt.Claims
+	UserName   string              `json:"preferred_username,omitempty"`
+	Groups     []string            `json:"groups,omitempty"`
+	Roles      []string            `json:"roles,omitempty"`
+	Extensions map[string][]string `json:"ext,omitempty"`
+	Actor      *actor              `json:"act,omitempty"`
+	MayAct     *actor              `json:"may_act,omitempty"`
+}
+
+func (c *claims) info() auth.Info {
+	var ext map[string][]string
+
+	if c.Actor != nil || c.MayAct != nil {
+		ext = make(map[string][]string, len(c.Extensions)+2)
+		for k, v := range c.Extensions {
+			ext[k] = v
+		}
+	} else {
+		ext = c.Extensions
+	}
+
+	for a := c.Actor; a != nil; a = a.Actor {
+		ext[ExtensionActor] = append(ext[ExtensionActor], a.Subject)
+	}
+
+	if c.MayAct != nil {
+		ext[ExtensionMayAct] = []string{c.MayAct.Subject}
+	}
+
+	info := auth.NewUserInfo(c.UserName, c.Subject, c.Groups, ext)
+	auth.SetUserRoles(info, c.Roles)
+	c.setValidity(info)
+
+	return info
+}
+
+// setValidity sets the user validity window to the token nbf and exp claims.
+func (c *claims) setValidity(info auth.Info) {
+	if c.NotBefore != nil {
+		auth.SetUserNotBefore(info, c.NotBefore.Time())
+	}
+
+	if c.Expiry != nil {
+		auth.SetUserExpiry(info, c.Expiry.Time())
+	}
+}
+
+func (c *claims) setInfo(info auth.Info) {
+	c.Subject = info.ID()
+	c.UserName = info.UserName()
+	c.Groups = info.Groups()
+	c.Roles = auth.UserRoles(info)
+	c.Extensions = info.Extensions()
+
+	acts, mayAct := c.Extensions[ExtensionActor], c.Extensions[ExtensionMayAct]
+	if len(acts) == 0 && len(mayAct) == 0 {
+		return
+	}
+
+	c.Extensions = make(map[string][]string, len(info.Extensions()))
+	for k, v := range info.Extensions() {
+		if k != ExtensionActor && k != ExtensionMayAct {
+			c.Extensions[k] = v
+		}
+	}
+
+	// the current actor is the outermost act claim.
+	for i := len(acts) - 1; i >= 0; i-- {
+		c.Actor = &actor{Subject: acts[i], Actor: c.Actor}
+	}
+
+	if len(mayAct) > 0 {
+		c.MayAct = &actor{Subject: mayAct[0]}
+	}
+}
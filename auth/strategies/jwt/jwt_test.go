@@ -0,0 +1,162 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	gclaims "github.com/shaj13/go-guardian/auth/claims"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestStrategy(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	info := auth.NewUserInfo("test", "1", []string{"admin"}, map[string][]string{"k": {"v"}})
+
+	table := []struct {
+		name   string
+		issuer SecretsKeeper
+		keeper SecretsKeeper
+		opts   []auth.Option
+		vopts  []auth.Option
+		err    bool
+	}{
+		{
+			name:   "it authenticate token signed by hmac secret",
+			issuer: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+			keeper: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+		},
+		{
+			name:   "it authenticate token signed by opaque rsa signer",
+			issuer: StaticSecret{ID: "1", Secret: opaqueSigner{rsaKey}, Algorithm: "PS256"},
+			keeper: StaticSecret{ID: "1", Secret: opaqueSigner{rsaKey}, Algorithm: "PS256"},
+		},
+		{
+			name:   "it authenticate token signed by opaque ec signer using public key",
+			issuer: StaticSecret{ID: "1", Secret: opaqueSigner{ecKey}, Algorithm: "ES256"},
+			keeper: StaticSecret{ID: "1", Secret: &ecKey.PublicKey, Algorithm: "ES256"},
+		},
+		{
+			name:   "it return error when kid unknown",
+			issuer: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+			keeper: StaticSecret{ID: "2", Secret: []byte("secret"), Algorithm: "HS256"},
+			err:    true,
+		},
+		{
+			name:   "it return error when alg does not match kid alg",
+			issuer: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+			keeper: StaticSecret{ID: "1", Secret: &rsaKey.PublicKey, Algorithm: "RS256"},
+			err:    true,
+		},
+		{
+			name:   "it return error when token expired",
+			issuer: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+			keeper: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+			opts:   []auth.Option{SetExpDuration(-time.Hour)},
+			err:    true,
+		},
+		{
+			name:   "it return error when audience invalid",
+			issuer: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+			keeper: StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"},
+			opts:   []auth.Option{SetAudience("a"), SetIssuer("iss")},
+			vopts:  []auth.Option{SetAudience("b"), SetIssuer("iss")},
+			err:    true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			tk, err := IssueAccessToken(info, tt.issuer, tt.opts...)
+			assert.NoError(t, err)
+
+			s := New(store.New(0), tt.keeper, tt.vopts...)
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+tk)
+
+			got, err := s.Authenticate(context.Background(), r)
+
+			assert.Equal(t, tt.err, err != nil, err)
+
+			if !tt.err {
+				exp, ok := auth.UserExpiry(got)
+				assert.True(t, ok)
+				assert.True(t, exp.After(time.Now()))
+
+				// the validity window populated from the token nbf and exp claims.
+				auth.SetUserExpiry(got, time.Time{})
+				auth.SetUserNotBefore(got, time.Time{})
+				assert.Equal(t, info, got)
+			}
+		})
+	}
+}
+
+func TestStrategyAuthenticateRequest(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	info := auth.NewUserInfo("test", "1", nil, nil)
+
+	tk, err := IssueAccessToken(info, keeper)
+	assert.NoError(t, err)
+
+	s := New(store.New(0), keeper)
+	req := auth.MetadataRequest(context.Background(), map[string][]string{
+		"authorization": {"Bearer " + tk},
+	}, nil, nil)
+
+	got, err := auth.AuthenticateWith(s, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "test", got.UserName())
+}
+
+func TestStrategyClaimsMapper(t *testing.T) {
+	keeper := StaticSecret{ID: "1", Secret: []byte("secret"), Algorithm: "HS256"}
+	info := auth.NewUserInfo("test", "1", []string{"admin"}, map[string][]string{"tenant": {"acme"}})
+
+	tk, err := IssueAccessToken(info, keeper)
+	assert.NoError(t, err)
+
+	m := &gclaims.Mapper{
+		UserName:   []string{"{{.preferred_username}}@{{index .ext.tenant 0}}"},
+		Roles:      []string{"groups"},
+		Extensions: map[string]string{"org": "ext.tenant"},
+	}
+
+	s := New(store.New(0), keeper, SetClaimsMapper(m))
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tk)
+
+	got, err := s.Authenticate(context.Background(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@acme", got.UserName())
+	assert.Equal(t, "1", got.ID())
+	assert.Equal(t, []string{"admin"}, auth.UserRoles(got))
+	assert.Equal(t, []string{"acme"}, got.Extensions()["org"])
+
+	_, ok := auth.UserExpiry(got)
+	assert.True(t, ok)
+}
+
+// opaqueSigner mimics an HSM backed key, only the public key and sign operation exposed.
+type opaqueSigner struct {
+	s crypto.Signer
+}
+
+func (o opaqueSigner) Public() crypto.PublicKey {
+	return o.s.Public()
+}
+
+func (o opaqueSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return o.s.Sign(rand, digest, opts)
+}
@@ -0,0 +1,302 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// ErrMissingKeyGenerator is returned by KeyRing Rotate, when the key ring does not have a key generator.
+var ErrMissingKeyGenerator = errors.New("strategies/jwt: Key ring generator not set")
+
+// KeyGenerator define function signature to generate a new signing key for the key ring rotation.
+// secret can be []byte for HMAC algorithms or crypto.Signer, See SecretsKeeper.
+type KeyGenerator func() (secret interface{}, algorithm string, err error)
+
+// ECDSAKeyGenerator return KeyGenerator that generates ES256 P-256 keys.
+func ECDSAKeyGenerator() KeyGenerator {
+	return func() (interface{}, string, error) {
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		return k, "ES256", err
+	}
+}
+
+// RSAKeyGenerator return KeyGenerator that generates RS256 keys of the given size in bits.
+func RSAKeyGenerator(bits int) KeyGenerator {
+	return func() (interface{}, string, error) {
+		k, err := rsa.GenerateKey(rand.Reader, bits)
+		return k, "RS256", err
+	}
+}
+
+// SetRotationInterval sets the key ring scheduled rotation interval,
+// once the current key older than the interval a new key generated and become the current key.
+// zero disables the scheduled rotation.
+// Default 24 hours.
+func SetRotationInterval(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if k, ok := v.(*KeyRing); ok {
+			k.interval = d
+		}
+	})
+}
+
+// SetRotationOverlap sets how long a replaced key kept by the key ring to verify tokens,
+// and published by the JWKS handler, after it stop signing.
+// The overlap should not be less than the max issued tokens lifetime,
+// plus the downstream consumers JWKS cache duration.
+// Default 1 hour.
+func SetRotationOverlap(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if k, ok := v.(*KeyRing); ok {
+			k.overlap = d
+		}
+	})
+}
+
+type ringKey struct {
+	id        string
+	secret    interface{}
+	alg       string
+	createdAt time.Time
+	// retiredAt is the time the key replaced, zero for the current key.
+	retiredAt time.Time
+}
+
+// KeyRing implements SecretsKeeper and holds multiple signing keys identified by their kids,
+// the most recently added key signs new tokens,
+// while the replaced keys remain valid for verification during the rotation overlap window.
+// Rotation happens either manually using Rotate and Add,
+// or scheduled and performed lazily once the current key exceeds the rotation interval.
+type KeyRing struct {
+	mu       sync.RWMutex
+	keys     []*ringKey
+	generate KeyGenerator
+	interval time.Duration
+	overlap  time.Duration
+}
+
+// NewKeyRing return new KeyRing, that generate its keys using the given generator,
+// and holds an initial generated key.
+// if gen is nil, the key ring does not rotate its keys and they must be added using Add.
+func NewKeyRing(gen KeyGenerator, opts ...auth.Option) (*KeyRing, error) {
+	k := &KeyRing{
+		generate: gen,
+		interval: time.Hour * 24,
+		overlap:  time.Hour,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(k)
+	}
+
+	if gen == nil {
+		return k, nil
+	}
+
+	if err := k.Rotate(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// KID return's the current signing key id,
+// and rotate the current key first if the rotation interval exceeded.
+func (k *KeyRing) KID() string {
+	k.mu.RLock()
+	cur := k.current()
+	k.mu.RUnlock()
+
+	if k.generate != nil && (cur == nil || k.interval > 0 && time.Since(cur.createdAt) >= k.interval) {
+		// a failed rotation keep signing using the current key.
+		_ = k.rotate(cur)
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if cur := k.current(); cur != nil {
+		return cur.id
+	}
+
+	return ""
+}
+
+// Get return's the secret/key of the given kid and the corresponding sign algorithm,
+// if the key exists and its rotation overlap window not yet elapsed.
+func (k *KeyRing) Get(kid string) (interface{}, string, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	for _, key := range k.keys {
+		if key.id == kid && k.active(key) {
+			return key.secret, key.alg, nil
+		}
+	}
+
+	return nil, "", ErrInvalidKID
+}
+
+// Rotate generate a new key using the key ring generator and make it the current signing key.
+func (k *KeyRing) Rotate() error {
+	return k.rotate(nil)
+}
+
+// Add adds the given key and make it the current signing key,
+// typically used with externally managed keys, e.g KMS keys versions.
+func (k *KeyRing) Add(kid string, secret interface{}, alg string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.add(kid, secret, alg)
+}
+
+// rotate generate a new key, if prev not nil,
+// the key added only if prev is still the current key, so concurrent rotations rotate once.
+func (k *KeyRing) rotate(prev *ringKey) error {
+	if k.generate == nil {
+		return ErrMissingKeyGenerator
+	}
+
+	secret, alg, err := k.generate()
+	if err != nil {
+		return err
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if prev != nil && prev != k.current() {
+		return nil
+	}
+
+	k.add(hex.EncodeToString(b), secret, alg)
+
+	return nil
+}
+
+func (k *KeyRing) add(kid string, secret interface{}, alg string) {
+	now := time.Now()
+
+	if cur := k.current(); cur != nil {
+		cur.retiredAt = now
+	}
+
+	keys := []*ringKey{}
+	for _, key := range k.keys {
+		if key.id != kid && k.active(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	k.keys = append(keys, &ringKey{
+		id:        kid,
+		secret:    secret,
+		alg:       alg,
+		createdAt: now,
+	})
+}
+
+// Remove removes the key of the given kid immediately, e.g when the key compromised,
+// tokens signed by the removed key no longer verified.
+// Once the current key removed, a new key generated on the next KID call,
+// or the key ring does not sign until a key added if it does not have a generator.
+func (k *KeyRing) Remove(kid string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for i, key := range k.keys {
+		if key.id == kid {
+			k.keys = append(k.keys[:i], k.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// JWKS return the JSON Web Key Set of the key ring asymmetric keys public keys,
+// including the replaced keys still in their rotation overlap window.
+// HMAC secrets never published.
+func (k *KeyRing) JWKS() ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := jwt.KeySet{Keys: []jwt.JSONWebKey{}}
+
+	for _, key := range k.keys {
+		if !k.active(key) {
+			continue
+		}
+
+		var pub crypto.PublicKey
+
+		switch s := key.secret.(type) {
+		case crypto.Signer:
+			pub = s.Public()
+		case []byte:
+			continue
+		default:
+			pub = s
+		}
+
+		jwk, err := jwt.NewJSONWebKey(pub, key.id, key.alg)
+		if err != nil {
+			return nil, err
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return json.Marshal(set)
+}
+
+// JWKSHandler return HTTP handler that publish the key ring JWKS,
+// for downstream consumers to verify the issued tokens.
+// Responses cacheable for maxAge, which should be less than the rotation overlap.
+func (k *KeyRing) JWKSHandler(maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, auth.StatusMessage(r, http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		b, err := k.JWKS()
+		if err != nil {
+			http.Error(w, auth.StatusMessage(r, http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge.Seconds())))
+		_, _ = w.Write(b)
+	})
+}
+
+// current return the signing key, or nil if the key ring empty or the current key removed.
+func (k *KeyRing) current() *ringKey {
+	if len(k.keys) == 0 || !k.keys[len(k.keys)-1].retiredAt.IsZero() {
+		return nil
+	}
+	return k.keys[len(k.keys)-1]
+}
+
+func (k *KeyRing) active(key *ringKey) bool {
+	return key.retiredAt.IsZero() || time.Since(key.retiredAt) < k.overlap
+}
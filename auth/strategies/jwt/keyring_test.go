@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestKeyRingRotation(t *testing.T) {
+	info := auth.NewUserInfo("test", "1", nil, nil)
+	k, err := NewKeyRing(ECDSAKeyGenerator())
+	assert.NoError(t, err)
+
+	old := k.KID()
+	tk, err := IssueAccessToken(info, k)
+	assert.NoError(t, err)
+
+	assert.NoError(t, k.Rotate())
+	assert.NotEqual(t, old, k.KID())
+
+	// tokens signed by the replaced key verified within the overlap window.
+	s := New(store.New(0), k)
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+tk)
+	_, err = s.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+
+	keys := jwt.KeySet{}
+	b, _ := k.JWKS()
+	_ = json.Unmarshal(b, &keys)
+	assert.Len(t, keys.Keys, 2)
+
+	k.Remove(old)
+
+	_, _, err = k.Get(old)
+	assert.Equal(t, ErrInvalidKID, err)
+}
+
+func TestKeyRingOverlap(t *testing.T) {
+	k, _ := NewKeyRing(ECDSAKeyGenerator(), SetRotationOverlap(0))
+	old := k.KID()
+
+	assert.NoError(t, k.Rotate())
+
+	_, _, err := k.Get(old)
+	assert.Equal(t, ErrInvalidKID, err)
+
+	b, _ := k.JWKS()
+	keys := jwt.KeySet{}
+	_ = json.Unmarshal(b, &keys)
+	assert.Len(t, keys.Keys, 1)
+	assert.Equal(t, k.KID(), keys.Keys[0].KeyID)
+}
+
+func TestKeyRingScheduledRotation(t *testing.T) {
+	k, _ := NewKeyRing(ECDSAKeyGenerator(), SetRotationInterval(time.Nanosecond))
+	old := k.KID()
+	time.Sleep(time.Millisecond)
+	assert.NotEqual(t, old, k.KID())
+
+	k, _ = NewKeyRing(nil)
+	assert.Equal(t, "", k.KID())
+	assert.Equal(t, ErrMissingKeyGenerator, k.Rotate())
+
+	k.Add("hmac", []byte("secret"), "HS256")
+	assert.Equal(t, "hmac", k.KID())
+
+	// HMAC secrets never published.
+	b, _ := k.JWKS()
+	assert.JSONEq(t, `{"keys":[]}`, string(b))
+
+	k.Remove("hmac")
+	assert.Equal(t, "", k.KID())
+}
+
+func TestKeyRingJWKSHandler(t *testing.T) {
+	k, _ := NewKeyRing(RSAKeyGenerator(2048))
+	h := k.JWKSHandler(time.Minute)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	h.ServeHTTP(w, r)
+
+	keys := jwt.KeySet{}
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=60", w.Header().Get("Cache-Control"))
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &keys))
+	assert.Len(t, keys.Keys, 1)
+	assert.Equal(t, k.KID(), keys.Keys[0].KeyID)
+	assert.Equal(t, "RS256", keys.Keys[0].Algorithm)
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest("POST", "/.well-known/jwks.json", nil)
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,67 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+)
+
+// AWSClient is the subset of AWS KMS API used by the AWS signer.
+type AWSClient interface {
+	// Sign calls KMS Sign API with MessageType DIGEST and the given SigningAlgorithmSpec,
+	// and return the signature.
+	Sign(ctx context.Context, keyID string, digest []byte, algorithm string) ([]byte, error)
+	// GetPublicKey calls KMS GetPublicKey API and return the DER encoded public key.
+	GetPublicKey(ctx context.Context, keyID string) ([]byte, error)
+}
+
+// NewAWSSigner return crypto.Signer backed by AWS KMS asymmetric key.
+// pss reports whether RSA keys sign using RSASSA-PSS instead of PKCS #1 v1.5.
+func NewAWSSigner(ctx context.Context, c AWSClient, keyID string, pss bool) (*Signer, error) {
+	der, err := c.GetPublicKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newSigner(pub, pss)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sign = func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+		alg, err := awsAlgorithm(s.pub, opts)
+		if err != nil {
+			return nil, err
+		}
+		return c.Sign(ctx, keyID, digest, alg)
+	}
+
+	return s, nil
+}
+
+// awsAlgorithm map key type and signer opts to AWS KMS SigningAlgorithmSpec.
+func awsAlgorithm(pub crypto.PublicKey, opts crypto.SignerOpts) (string, error) {
+	h, err := hashName(opts.HashFunc())
+	if err != nil {
+		return "", err
+	}
+
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		if isPSS(opts) {
+			return "RSASSA_PSS_SHA_" + h, nil
+		}
+		return "RSASSA_PKCS1_V1_5_SHA_" + h, nil
+	case *ecdsa.PublicKey:
+		return "ECDSA_SHA_" + h, nil
+	}
+
+	return "", ErrUnsupportedKey
+}
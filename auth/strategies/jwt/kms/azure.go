@@ -0,0 +1,80 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// AzureClient is the subset of Azure Key Vault keys API used by the Azure signer.
+type AzureClient interface {
+	// Sign calls Key Vault sign operation with the given JSON web signature algorithm,
+	// and return the signature.
+	Sign(ctx context.Context, name, version, algorithm string, digest []byte) ([]byte, error)
+	// GetKey calls Key Vault get key operation and return the key JSON web key.
+	GetKey(ctx context.Context, name, version string) ([]byte, error)
+}
+
+// NewAzureSigner return crypto.Signer backed by Azure Key Vault key.
+// pss reports whether RSA keys sign using RSASSA-PSS instead of PKCS #1 v1.5.
+func NewAzureSigner(ctx context.Context, c AzureClient, name, version string, pss bool) (*Signer, error) {
+	b, err := c.GetKey(ctx, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	jwk := jwt.JSONWebKey{}
+	if err := json.Unmarshal(b, &jwk); err != nil {
+		return nil, err
+	}
+
+	s, err := newSigner(jwk.Key, pss)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sign = func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+		alg, err := azureAlgorithm(s.pub, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err := c.Sign(ctx, name, version, alg, digest)
+		if err != nil {
+			return nil, err
+		}
+
+		// key vault return ECDSA signatures as r||s.
+		if _, ok := s.pub.(*ecdsa.PublicKey); ok {
+			return rawToDER(sig)
+		}
+
+		return sig, nil
+	}
+
+	return s, nil
+}
+
+// azureAlgorithm map key type and signer opts to Key Vault JSON web signature algorithm.
+func azureAlgorithm(pub crypto.PublicKey, opts crypto.SignerOpts) (string, error) {
+	h, err := hashName(opts.HashFunc())
+	if err != nil {
+		return "", err
+	}
+
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		if isPSS(opts) {
+			return "PS" + h, nil
+		}
+		return "RS" + h, nil
+	case *ecdsa.PublicKey:
+		return "ES" + h, nil
+	}
+
+	return "", ErrUnsupportedKey
+}
@@ -0,0 +1,50 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+)
+
+// GCPClient is the subset of GCP Cloud KMS API used by the GCP signer.
+type GCPClient interface {
+	// AsymmetricSign calls Cloud KMS AsymmetricSign API,
+	// with the digest set to the field of the given hash function, and return the signature.
+	AsymmetricSign(ctx context.Context, name string, digest []byte, hash crypto.Hash) ([]byte, error)
+	// GetPublicKey calls Cloud KMS GetPublicKey API and return the PEM encoded public key.
+	GetPublicKey(ctx context.Context, name string) ([]byte, error)
+}
+
+// NewGCPSigner return crypto.Signer backed by GCP Cloud KMS asymmetric key version,
+// name is the crypto key version resource name.
+// Cloud KMS key version has a fixed algorithm,
+// therefore pss must reports whether the RSA key version uses RSASSA-PSS.
+func NewGCPSigner(ctx context.Context, c GCPClient, name string, pss bool) (*Signer, error) {
+	b, err := c.GetPublicKey(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, err := parsePEM(b)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := newSigner(pub, pss)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sign = func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+		if _, err := hashName(opts.HashFunc()); err != nil {
+			return nil, err
+		}
+
+		if isPSS(opts) != s.pss {
+			return nil, ErrUnsupportedKey
+		}
+
+		return c.AsymmetricSign(ctx, name, digest, opts.HashFunc())
+	}
+
+	return s, nil
+}
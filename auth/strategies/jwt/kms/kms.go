@@ -0,0 +1,129 @@
+// Package kms provides crypto.Signer adapters for managed keys,
+// hosted by AWS KMS, GCP Cloud KMS, and Azure Key Vault,
+// to sign JWT issued by the jwt package without the private key leaving the KMS.
+//
+// The package does not depend on the cloud providers SDKs,
+// instead each provider defines the minimal client interface it needs,
+// which can be implemented on top of the official SDK client in a few lines.
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+)
+
+// ErrUnsupportedKey is returned when the KMS key type or algorithm not supported.
+var ErrUnsupportedKey = errors.New("kms: Unsupported key type or algorithm")
+
+// DefaultTimeout is the default max duration of a KMS sign call.
+const DefaultTimeout = time.Second * 10
+
+// Signer implements crypto.Signer using a remote KMS key.
+// The public key fetched once when the signer created and cached for its lifetime,
+// since a KMS key version public key never changes.
+type Signer struct {
+	// Timeout is the max duration of a KMS sign call,
+	// since crypto.Signer does not accept a context.
+	Timeout time.Duration
+
+	pub  crypto.PublicKey
+	pss  bool
+	sign func(ctx context.Context, digest []byte, opts crypto.SignerOpts) ([]byte, error)
+}
+
+// Public return the cached public key of the KMS key.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign signs digest using the KMS key.
+// ECDSA signatures always returned ASN.1 DER encoded as described in crypto.Signer.
+func (s *Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	return s.sign(ctx, digest, opts)
+}
+
+// Algorithm return the JWS algorithm of the KMS key,
+// to be used as the jwt.StaticSecret Algorithm.
+func (s *Signer) Algorithm() string {
+	switch k := s.pub.(type) {
+	case *rsa.PublicKey:
+		if s.pss {
+			return "PS256"
+		}
+		return "RS256"
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return "ES256"
+		case 384:
+			return "ES384"
+		case 521:
+			return "ES512"
+		}
+	}
+	return ""
+}
+
+func newSigner(pub crypto.PublicKey, pss bool) (*Signer, error) {
+	switch pub.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	return &Signer{
+		Timeout: DefaultTimeout,
+		pub:     pub,
+		pss:     pss,
+	}, nil
+}
+
+func parsePEM(b []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("kms: Invalid PEM public key")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func hashName(h crypto.Hash) (string, error) {
+	switch h {
+	case crypto.SHA256:
+		return "256", nil
+	case crypto.SHA384:
+		return "384", nil
+	case crypto.SHA512:
+		return "512", nil
+	}
+	return "", fmt.Errorf("kms: Unsupported hash function %v", h)
+}
+
+func isPSS(opts crypto.SignerOpts) bool {
+	_, ok := opts.(*rsa.PSSOptions)
+	return ok
+}
+
+// rawToDER convert ECDSA r||s signature to ASN.1 DER.
+func rawToDER(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, errors.New("kms: Invalid ECDSA signature")
+	}
+
+	size := len(sig) / 2
+	return asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(sig[:size]),
+		S: new(big.Int).SetBytes(sig[size:]),
+	})
+}
@@ -0,0 +1,195 @@
+package kms
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/jwt"
+	ijwt "github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestSigners(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	ctx := context.Background()
+
+	table := []struct {
+		name string
+		key  crypto.Signer
+		pss  bool
+		alg  string
+		new  func(k crypto.Signer, pss bool) (*Signer, error)
+	}{
+		{
+			name: "aws rsa pkcs1",
+			key:  rsaKey,
+			alg:  "RS256",
+			new: func(k crypto.Signer, pss bool) (*Signer, error) {
+				return NewAWSSigner(ctx, &awsClient{key: k}, "key", pss)
+			},
+		},
+		{
+			name: "aws rsa pss",
+			key:  rsaKey,
+			pss:  true,
+			alg:  "PS256",
+			new: func(k crypto.Signer, pss bool) (*Signer, error) {
+				return NewAWSSigner(ctx, &awsClient{key: k}, "key", pss)
+			},
+		},
+		{
+			name: "aws ecdsa",
+			key:  ecKey,
+			alg:  "ES384",
+			new: func(k crypto.Signer, pss bool) (*Signer, error) {
+				return NewAWSSigner(ctx, &awsClient{key: k}, "key", pss)
+			},
+		},
+		{
+			name: "gcp ecdsa",
+			key:  ecKey,
+			alg:  "ES384",
+			new: func(k crypto.Signer, pss bool) (*Signer, error) {
+				return NewGCPSigner(ctx, gcpClient{key: k}, "key", pss)
+			},
+		},
+		{
+			name: "azure rsa",
+			key:  rsaKey,
+			alg:  "RS256",
+			new: func(k crypto.Signer, pss bool) (*Signer, error) {
+				return NewAzureSigner(ctx, azureClient{key: k}, "key", "1", pss)
+			},
+		},
+		{
+			name: "azure ecdsa",
+			key:  ecKey,
+			alg:  "ES384",
+			new: func(k crypto.Signer, pss bool) (*Signer, error) {
+				return NewAzureSigner(ctx, azureClient{key: k}, "key", "1", pss)
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := tt.new(tt.key, tt.pss)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.alg, s.Algorithm())
+
+			keeper := jwt.StaticSecret{ID: "key", Secret: s, Algorithm: s.Algorithm()}
+			info := auth.NewUserInfo("test", "1", nil, nil)
+
+			token, err := jwt.IssueAccessToken(info, keeper)
+			assert.NoError(t, err)
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+token)
+
+			got, err := jwt.New(store.New(0), keeper).Authenticate(ctx, r)
+			assert.NoError(t, err)
+			exp, ok := auth.UserExpiry(got)
+			assert.True(t, ok)
+			assert.True(t, exp.After(time.Now()))
+
+			// the validity window populated from the token nbf and exp claims.
+			auth.SetUserExpiry(got, time.Time{})
+			auth.SetUserNotBefore(got, time.Time{})
+			assert.Equal(t, info, got)
+		})
+	}
+}
+
+func TestAlgorithms(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pss := &rsa.PSSOptions{Hash: crypto.SHA512}
+
+	alg, _ := awsAlgorithm(&rsaKey.PublicKey, pss)
+	assert.Equal(t, "RSASSA_PSS_SHA_512", alg)
+
+	alg, _ = azureAlgorithm(&rsaKey.PublicKey, crypto.SHA384)
+	assert.Equal(t, "RS384", alg)
+
+	_, err := awsAlgorithm(&rsaKey.PublicKey, crypto.SHA1)
+	assert.Error(t, err)
+}
+
+type awsClient struct {
+	key crypto.Signer
+}
+
+func (a *awsClient) Sign(_ context.Context, _ string, digest []byte, alg string) ([]byte, error) {
+	var opts crypto.SignerOpts = crypto.SHA256
+	if alg == "RSASSA_PSS_SHA_256" {
+		opts = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}
+	}
+	if alg == "ECDSA_SHA_384" {
+		opts = crypto.SHA384
+	}
+	return a.key.Sign(rand.Reader, digest, opts)
+}
+
+func (a *awsClient) GetPublicKey(context.Context, string) ([]byte, error) {
+	return x509.MarshalPKIXPublicKey(a.key.Public())
+}
+
+type gcpClient struct {
+	key crypto.Signer
+}
+
+func (g gcpClient) AsymmetricSign(_ context.Context, _ string, digest []byte, h crypto.Hash) ([]byte, error) {
+	return g.key.Sign(rand.Reader, digest, h)
+}
+
+func (g gcpClient) GetPublicKey(context.Context, string) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(g.key.Public())
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), err
+}
+
+type azureClient struct {
+	key crypto.Signer
+}
+
+func (a azureClient) Sign(_ context.Context, _, _, alg string, digest []byte) ([]byte, error) {
+	var h crypto.Hash = crypto.SHA256
+	if alg == "ES384" {
+		h = crypto.SHA384
+	}
+
+	sig, err := a.key.Sign(rand.Reader, digest, h)
+	if err != nil || alg[0] != 'E' {
+		return sig, err
+	}
+
+	var v struct{ R, S *big.Int }
+	_, _ = asn1.Unmarshal(sig, &v)
+	raw := make([]byte, 96)
+	r, s := v.R.Bytes(), v.S.Bytes()
+	copy(raw[48-len(r):48], r)
+	copy(raw[96-len(s):], s)
+	return raw, nil
+}
+
+func (a azureClient) GetKey(context.Context, string, string) ([]byte, error) {
+	jwk, err := ijwt.NewJSONWebKey(a.key.Public(), "1", "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jwk)
+}
@@ -0,0 +1,141 @@
+package jwt
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gclaims "github.com/shaj13/go-guardian/auth/claims"
+)
+
+type config struct {
+	issuer     string
+	audience   []string
+	exp        time.Duration
+	leeway     time.Duration
+	mapper     *gclaims.Mapper
+	denylist   *Denylist
+	cookie     string
+	csrfCookie string
+	csrfHeader string
+
+	audiencePolicy AudiencePolicy
+}
+
+func newConfig(opts ...auth.Option) *config {
+	cfg := &config{
+		exp:        time.Minute * 5,
+		leeway:     time.Second * 30,
+		cookie:     "access_token",
+		csrfCookie: "csrf_token",
+		csrfHeader: "X-CSRF-Token",
+	}
+
+	for _, opt := range opts {
+		opt.Apply(cfg)
+	}
+
+	return cfg
+}
+
+// SetAudience sets token audience(aud),
+// no default value.
+func SetAudience(aud ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.audience = aud
+		}
+	})
+}
+
+// SetAudiencePolicy sets the Exchanger policy authorizing the requested audiences,
+// no default value, therefore requested audiences denied.
+func SetAudiencePolicy(p AudiencePolicy) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.audiencePolicy = p
+		}
+	})
+}
+
+// SetIssuer sets token issuer(iss),
+// no default value.
+func SetIssuer(iss string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.issuer = iss
+		}
+	})
+}
+
+// SetExpDuration sets token exp duration,
+// Default Value 5 min.
+func SetExpDuration(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.exp = d
+		}
+	})
+}
+
+// SetLeeway sets the allowed clock skew when validating token exp and nbf,
+// Default Value 30 sec.
+func SetLeeway(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.leeway = d
+		}
+	})
+}
+
+// SetClaimsMapper sets the mapper used to translate the token claims into auth.Info,
+// Typically used to authenticate tokens issued by a third party identity provider.
+// Default the token preferred_username, sub, groups, roles, and ext claims used.
+func SetClaimsMapper(m *gclaims.Mapper) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.mapper = m
+		}
+	})
+}
+
+// SetDenylist sets the denylist consulted by the strategy to reject revoked tokens,
+// the cached strategy consults the denylist on cache hits too,
+// unless token.SetRevocationCheck passed after SetDenylist.
+// No default value.
+func SetDenylist(d *Denylist) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.denylist = d
+		}
+	})
+}
+
+// SetCookieName sets the cookie name carries the token, used by the cookie strategy and Cookies,
+// Default "access_token".
+func SetCookieName(name string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.cookie = name
+		}
+	})
+}
+
+// SetCSRFCookieName sets the cookie name carries the CSRF token, used by the cookie strategy and Cookies,
+// Default "csrf_token".
+func SetCSRFCookieName(name string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.csrfCookie = name
+		}
+	})
+}
+
+// SetCSRFHeader sets the header carries the CSRF token of unsafe requests, used by the cookie strategy,
+// Default "X-CSRF-Token".
+func SetCSRFHeader(header string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if c, ok := v.(*config); ok {
+			c.csrfHeader = header
+		}
+	})
+}
@@ -2,6 +2,8 @@
 // incoming HTTP requests using a Kubernetes Service Account Token.
 // This authentication strategy makes it easy to introduce apps,
 // into a Kubernetes Pod and make Pod authenticate Pod.
+// The package also provides a token review webhook server handler,
+// to let go-guardian act as a cluster authenticator.
 package kubernetes
 
 import (
@@ -18,6 +20,7 @@ import (
 
 	"github.com/shaj13/go-guardian/auth"
 	"github.com/shaj13/go-guardian/auth/strategies/token"
+	gerrors "github.com/shaj13/go-guardian/errors"
 	"github.com/shaj13/go-guardian/store"
 )
 
@@ -73,7 +76,7 @@ func (k *kubeReview) authenticate(ctx context.Context, r *http.Request, token st
 	status := &kubemeta.Status{}
 	err = json.Unmarshal(body, status)
 	if err == nil && status.Status != kubemeta.StatusSuccess {
-		return nil, fmt.Errorf("strategies/kubernetes: %s", status.Message)
+		return nil, fmt.Errorf("strategies/kubernetes: %s", gerrors.Redact(status.Message))
 	}
 
 	tr = &kubeauth.TokenReview{}
@@ -86,7 +89,7 @@ func (k *kubeReview) authenticate(ctx context.Context, r *http.Request, token st
 	}
 
 	if len(tr.Status.Error) > 0 {
-		return nil, fmt.Errorf("strategies/kubernetes: %s", tr.Status.Error)
+		return nil, fmt.Errorf("strategies/kubernetes: %s", gerrors.Redact(tr.Status.Error))
 	}
 
 	if !tr.Status.Authenticated {
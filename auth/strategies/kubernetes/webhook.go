@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+
+	kubeauth "k8s.io/api/authentication/v1"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+type webhook struct {
+	strategy auth.Strategy
+}
+
+func (wh webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		code := http.StatusMethodNotAllowed
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	tr := &kubeauth.TokenReview{}
+	if err := json.NewDecoder(r.Body).Decode(tr); err != nil {
+		code := http.StatusBadRequest
+		http.Error(w, http.StatusText(code), code)
+		return
+	}
+
+	if len(tr.APIVersion) == 0 {
+		tr.APIVersion = "authentication.k8s.io/v1"
+	}
+
+	tr.Kind = "TokenReview"
+	tr.Status = wh.review(r, tr.Spec)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tr)
+}
+
+func (wh webhook) review(r *http.Request, spec kubeauth.TokenReviewSpec) kubeauth.TokenReviewStatus {
+	status := kubeauth.TokenReviewStatus{}
+
+	if len(spec.Token) == 0 {
+		status.Error = "strategies/kubernetes: Token missing"
+		return status
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, "/", nil)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+
+	req.RemoteAddr = r.RemoteAddr
+	req.Header.Set("Authorization", "Bearer "+spec.Token)
+
+	info, err := wh.strategy.Authenticate(req.Context(), req)
+	if err != nil {
+		status.Error = "strategies/kubernetes: Token Unauthorized"
+		return status
+	}
+
+	extra := make(map[string]kubeauth.ExtraValue)
+	for k, v := range info.Extensions() {
+		extra[k] = kubeauth.ExtraValue(v)
+	}
+
+	status.Authenticated = true
+	status.Audiences = spec.Audiences
+	status.User = kubeauth.UserInfo{
+		Username: info.UserName(),
+		UID:      info.ID(),
+		Groups:   info.Groups(),
+		Extra:    extra,
+	}
+
+	return status
+}
+
+// NewWebhookHandler return HTTP handler implements the kubernetes token review webhook server contract,
+// the handler delegate the token validation to the given strategy by passing the token,
+// as a Bearer token in authorization header, and translate the user info to token review UserInfo.
+// This allow go-guardian to act as a cluster webhook token authenticator.
+//
+// See https://kubernetes.io/docs/reference/access-authn-authz/authentication/#webhook-token-authentication
+func NewWebhookHandler(s auth.Strategy) http.Handler {
+	return webhook{strategy: s}
+}
@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kubeauth "k8s.io/api/authentication/v1"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	strategy := token.NewStatic(map[string]auth.Info{
+		"valid": auth.NewDefaultUser("test", "1", []string{"group"}, map[string][]string{"ext": {"1"}}),
+	})
+
+	table := []struct {
+		name          string
+		method        string
+		body          string
+		code          int
+		authenticated bool
+		user          kubeauth.UserInfo
+	}{
+		{
+			name:   "it return 405 when method not POST",
+			method: http.MethodGet,
+			code:   http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "it return 400 when body invalid",
+			method: http.MethodPost,
+			body:   "invalid",
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "it return unauthenticated review when token missing",
+			method: http.MethodPost,
+			body:   `{"apiVersion":"authentication.k8s.io/v1","kind":"TokenReview","spec":{}}`,
+			code:   http.StatusOK,
+		},
+		{
+			name:   "it return unauthenticated review when token invalid",
+			method: http.MethodPost,
+			body:   `{"apiVersion":"authentication.k8s.io/v1","kind":"TokenReview","spec":{"token":"invalid"}}`,
+			code:   http.StatusOK,
+		},
+		{
+			name:          "it return authenticated review when token valid",
+			method:        http.MethodPost,
+			body:          `{"apiVersion":"authentication.k8s.io/v1","kind":"TokenReview","spec":{"token":"valid"}}`,
+			code:          http.StatusOK,
+			authenticated: true,
+			user: kubeauth.UserInfo{
+				Username: "test",
+				UID:      "1",
+				Groups:   []string{"group"},
+				Extra:    map[string]kubeauth.ExtraValue{"ext": {"1"}},
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			NewWebhookHandler(strategy).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+
+			if tt.code != http.StatusOK {
+				return
+			}
+
+			tr := &kubeauth.TokenReview{}
+			err := json.Unmarshal(w.Body.Bytes(), tr)
+
+			assert.NoError(t, err)
+			assert.Equal(t, "TokenReview", tr.Kind)
+			assert.Equal(t, tt.authenticated, tr.Status.Authenticated)
+			assert.Equal(t, tt.user, tr.Status.User)
+		})
+	}
+}
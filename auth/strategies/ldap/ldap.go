@@ -121,6 +121,7 @@ func (c client) authenticate(ctx context.Context, r *http.Request, userName, pas
 	}
 
 	id := ""
+	var groups []string
 	ext := map[string][]string{}
 
 	for _, attr := range result.Entries[0].Attributes {
@@ -132,10 +133,15 @@ func (c client) authenticate(ctx context.Context, r *http.Request, userName, pas
 			continue
 		}
 
+		// the memberOf attribute also kept in the extensions for backward compatibility.
+		if name == "memberOf" {
+			groups = values
+		}
+
 		ext[name] = values
 	}
 
-	return auth.NewUserInfo(userName, id, nil, ext), nil
+	return auth.NewUserInfo(userName, id, groups, ext), nil
 }
 
 func (c client) Challenge(realm string) string {
@@ -153,10 +159,11 @@ func New(cfg *Config) auth.Strategy {
 
 // NewCached return new auth.Strategy.
 // The returned strategy, caches the authentication decision.
-func NewCached(cfg *Config, c store.Cache) auth.Strategy {
+// The options applied to the underlying basic strategy, See basic.New.
+func NewCached(cfg *Config, c store.Cache, opts ...auth.Option) auth.Strategy {
 	cl := new(client)
 	cl.dial = dial
 	cl.cfg = cfg
-	cl.Strategy = basic.New(cl.authenticate, c)
+	cl.Strategy = basic.New(cl.authenticate, c, opts...)
 	return cl
 }
@@ -17,6 +17,7 @@ func TestLdap(t *testing.T) {
 		expectedErr bool
 		cfg         *Config
 		user        string
+		groups      []string
 		id          string
 		prepare     func(m *mockConn)
 	}{
@@ -108,6 +109,7 @@ func TestLdap(t *testing.T) {
 			cfg:         &Config{},
 			id:          "1",
 			user:        "test",
+			groups:      []string{"cn=admins,dc=example,dc=com"},
 			prepare: func(m *mockConn) {
 				m.On("mockDial").Return(nil, nil)
 				m.On("Bind").Return(nil)
@@ -117,6 +119,7 @@ func TestLdap(t *testing.T) {
 					DN: "test",
 					Attributes: []*ldap.EntryAttribute{
 						ldap.NewEntryAttribute("uid", []string{"1"}),
+						ldap.NewEntryAttribute("memberOf", []string{"cn=admins,dc=example,dc=com"}),
 					},
 				}
 				result := &ldap.SearchResult{
@@ -149,6 +152,7 @@ func TestLdap(t *testing.T) {
 			if !tt.expectedErr {
 				assert.Equal(t, tt.id, info.ID())
 				assert.Equal(t, tt.user, info.UserName())
+				assert.Equal(t, tt.groups, info.Groups())
 			}
 		})
 	}
@@ -0,0 +1,66 @@
+package oidc
+
+import (
+	"strconv"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// AppleIssuer is Sign in with Apple identity tokens issuer.
+const AppleIssuer = "https://appleid.apple.com"
+
+// AppleProvider return Sign in with Apple provider metadata,
+// without fetching the discovery document.
+func AppleProvider() *Provider {
+	return &Provider{
+		Issuer:                AppleIssuer,
+		AuthorizationEndpoint: AppleIssuer + "/auth/authorize",
+		TokenEndpoint:         AppleIssuer + "/auth/token",
+		JWKSURI:               AppleIssuer + "/auth/keys",
+		RevocationEndpoint:    AppleIssuer + "/auth/revoke",
+	}
+}
+
+// AppleInfo implements InfoFunc and map Apple identity token claims to auth.Info.
+// Apple identity tokens does not carry the user name, and the email might be a private relay email,
+// therefore user name is the email, or the sub when email not shared,
+// and the extensions contains is_private_email and real_user_status claims,
+// Apple encode booleans as strings, they normalized to "true" or "false".
+func AppleInfo(c *Claims) (auth.Info, error) {
+	info, err := ClaimsInfo(c)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := info.Extensions()
+	ext["is_private_email"] = []string{strconv.FormatBool(parseBool(c.Raw["is_private_email"]))}
+
+	if v, ok := c.Raw["real_user_status"]; ok {
+		ext["real_user_status"] = []string{toString(v)}
+	}
+
+	info.SetExtensions(ext)
+
+	return info, nil
+}
+
+// NewApple return strategy authenticate request using Sign in with Apple identity token.
+// clientID is the app bundle id or the services id, use SetAudience to accept tokens issued
+// for multiple apps of the same team (e.g iOS app and website).
+func NewApple(c store.Cache, clientID string, opts ...auth.Option) auth.Strategy {
+	opts = append([]auth.Option{SetInfoFunc(AppleInfo)}, opts...)
+	return New(AppleProvider(), clientID, c, opts...)
+}
+
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case interface{ String() string }:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestApple(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	p := AppleProvider()
+	p.Issuer = tp.URL
+	p.JWKSURI = tp.URL + "/jwks"
+	p.SetHTTPClient(tp.Client())
+
+	s := New(p, "client", store.New(0), SetInfoFunc(AppleInfo))
+
+	token := tp.token(t, map[string]interface{}{
+		"preferred_username": "",
+		"email":              "x@privaterelay.appleid.com",
+		"is_private_email":   "true",
+		"real_user_status":   json.Number("2"),
+	})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	info, err := s.Authenticate(context.Background(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "x@privaterelay.appleid.com", info.UserName())
+	assert.Equal(t, []string{"true"}, info.Extensions()["is_private_email"])
+	assert.Equal(t, []string{"true"}, info.Extensions()["email_verified"])
+	assert.Equal(t, []string{"2"}, info.Extensions()["real_user_status"])
+}
+
+func TestNewApple(t *testing.T) {
+	s := NewApple(store.New(0), "com.example.app")
+	assert.NotNil(t, s)
+}
@@ -0,0 +1,128 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// ErrUnknownIssuer is returned by Federation,
+// when the token issuer is not trusted.
+var ErrUnknownIssuer = errors.New("strategies/oidc: Unknown token issuer")
+
+// Federation trusts multiple OpenID providers simultaneously,
+// and dispatch each token to the issuer verifier selected by the token iss claim,
+// so every issuer keeps its own JWKS, audience, and claims mapping.
+// Typically used in federated environments with several identity providers (e.g behind Dex).
+//
+// Federation safe for concurrent usage.
+type Federation struct {
+	mu        sync.RWMutex
+	verifiers map[string]*Verifier
+}
+
+// NewFederation return new Federation trusting the given verifiers issuers.
+func NewFederation(verifiers ...*Verifier) *Federation {
+	f := &Federation{
+		verifiers: make(map[string]*Verifier),
+	}
+
+	for _, v := range verifiers {
+		f.Trust(v)
+	}
+
+	return f
+}
+
+// Trust register verifier for its provider issuer,
+// replacing any verifier previously registered for the same issuer.
+func (f *Federation) Trust(v *Verifier) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.verifiers[v.provider.Issuer] = v
+}
+
+// Distrust unregister the given issuer.
+func (f *Federation) Distrust(issuer string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.verifiers, issuer)
+}
+
+// Issuers return the trusted issuers.
+func (f *Federation) Issuers() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	issuers := make([]string, 0, len(f.verifiers))
+	for iss := range f.verifiers {
+		issuers = append(issuers, iss)
+	}
+
+	return issuers
+}
+
+// Verifier return the token issuer verifier, from the unverified token iss claim.
+func (f *Federation) Verifier(token string) (*Verifier, error) {
+	t, err := jwt.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	c := jwt.Claims{}
+	if err := t.Decode(&c); err != nil {
+		return nil, jwt.ErrMalformed
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	v, ok := f.verifiers[c.Issuer]
+	if !ok {
+		return nil, ErrUnknownIssuer
+	}
+
+	return v, nil
+}
+
+// Verify verifies the token using its issuer verifier, and return its claims.
+func (f *Federation) Verify(ctx context.Context, token string) (*Claims, error) {
+	v, err := f.Verifier(token)
+	if err != nil {
+		return nil, err
+	}
+	return v.Verify(ctx, token)
+}
+
+// Info verifies the token using its issuer verifier,
+// and map its claims to auth.Info using the issuer InfoFunc.
+func (f *Federation) Info(ctx context.Context, token string) (auth.Info, error) {
+	v, err := f.Verifier(token)
+	if err != nil {
+		return nil, err
+	}
+	return v.Info(ctx, token)
+}
+
+// GetFederationAuthenticateFunc return function to authenticate request,
+// using tokens issued by any of the federation trusted issuers.
+// The returned function typically used with the token strategy.
+func GetFederationAuthenticateFunc(f *Federation) token.AuthenticateFunc {
+	return func(ctx context.Context, r *http.Request, tk string) (auth.Info, error) {
+		return f.Info(ctx, tk)
+	}
+}
+
+// NewFederated return strategy authenticate request,
+// using tokens issued by any of the federation trusted issuers.
+// NewFederated is similar to token.New().
+func NewFederated(f *Federation, c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetFederationAuthenticateFunc(f)
+	return token.New(fn, c, opts...)
+}
@@ -0,0 +1,81 @@
+package oidc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+func TestFederation(t *testing.T) {
+	tp1 := newTestProvider(t)
+	defer tp1.Close()
+
+	tp2 := newTestProvider(t)
+	defer tp2.Close()
+
+	p1, _ := Discover(context.Background(), tp1.URL, tp1.Client())
+	p2, _ := Discover(context.Background(), tp2.URL, tp2.Client())
+
+	mapping := func(c *Claims) (auth.Info, error) {
+		return auth.NewUserInfo("p2:"+c.Subject, c.Subject, nil, nil), nil
+	}
+
+	f := NewFederation(
+		NewVerifier(p1, "client"),
+		NewVerifier(p2, "client", SetInfoFunc(mapping)),
+	)
+
+	assert.ElementsMatch(t, []string{tp1.URL, tp2.URL}, f.Issuers())
+
+	table := []struct {
+		name  string
+		token string
+		user  string
+		err   error
+	}{
+		{
+			name:  "it verify token using first issuer",
+			token: tp1.token(t, nil),
+			user:  "test",
+		},
+		{
+			name:  "it verify token using second issuer claims mapping",
+			token: tp2.token(t, nil),
+			user:  "p2:1",
+		},
+		{
+			name:  "it return error when issuer unknown",
+			token: tp1.token(t, map[string]interface{}{"iss": "https://unknown.example.com"}),
+			err:   ErrUnknownIssuer,
+		},
+		{
+			name:  "it return error when token signed by other issuer keys",
+			token: tp2.token(t, map[string]interface{}{"iss": tp1.URL}),
+			err:   jwt.ErrInvalidSignature,
+		},
+		{
+			name:  "it return error when token malformed",
+			token: "opaque",
+			err:   jwt.ErrMalformed,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := GetFederationAuthenticateFunc(f)(context.Background(), nil, tt.token)
+
+			assert.Equal(t, tt.err, err)
+			if err == nil {
+				assert.Equal(t, tt.user, info.UserName())
+			}
+		})
+	}
+
+	f.Distrust(tp2.URL)
+	_, err := f.Info(context.Background(), tp2.token(t, nil))
+	assert.Equal(t, ErrUnknownIssuer, err)
+}
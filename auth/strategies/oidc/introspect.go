@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+var (
+	// ErrInactiveToken is returned by Provider Introspect,
+	// when the provider reports the token not active.
+	ErrInactiveToken = errors.New("strategies/oidc: Token not active")
+
+	// ErrMissingIntrospectionEndpoint is returned by Provider Introspect,
+	// when the provider metadata does not advertise an introspection endpoint.
+	ErrMissingIntrospectionEndpoint = errors.New("strategies/oidc: Provider missing introspection endpoint")
+)
+
+// Introspect validates opaque token using the provider RFC 7662 introspection endpoint,
+// authenticated with the given client credentials, and return the token claims.
+// The username member mapped to claims PreferredUsername when preferred_username missing.
+func (p *Provider) Introspect(ctx context.Context, clientID, clientSecret, token string) (*Claims, error) {
+	if p.IntrospectionEndpoint == "" {
+		return nil, ErrMissingIntrospectionEndpoint
+	}
+
+	form := url.Values{
+		"token":           {token},
+		"token_type_hint": {"access_token"},
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		p.IntrospectionEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("strategies/oidc: Introspection respond with status code %d", resp.StatusCode)
+	}
+
+	ic := struct {
+		idClaims
+		Active   bool   `json:"active"`
+		Username string `json:"username"`
+	}{}
+
+	if err := json.Unmarshal(body, &ic); err != nil {
+		return nil, fmt.Errorf("strategies/oidc: Failed to decode introspection response Err: %s", err)
+	}
+
+	if !ic.Active {
+		return nil, ErrInactiveToken
+	}
+
+	if err := ic.Validate(jwt.Expected{}); err != nil {
+		return nil, err
+	}
+
+	c := ic.claims()
+	if c.PreferredUsername == "" {
+		c.PreferredUsername = ic.Username
+	}
+
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+
+	if err := d.Decode(&c.Raw); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
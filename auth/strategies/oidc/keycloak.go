@@ -0,0 +1,148 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// KeycloakProvider return Keycloak realm provider metadata,
+// without fetching the discovery document.
+// realmURL is the realm issuer e.g https://keycloak.example.com/realms/myrealm.
+func KeycloakProvider(realmURL string) *Provider {
+	realmURL = strings.TrimSuffix(realmURL, "/")
+	endpoint := realmURL + "/protocol/openid-connect"
+	return &Provider{
+		Issuer:                      realmURL,
+		AuthorizationEndpoint:       endpoint + "/auth",
+		TokenEndpoint:               endpoint + "/token",
+		UserInfoEndpoint:            endpoint + "/userinfo",
+		JWKSURI:                     endpoint + "/certs",
+		EndSessionEndpoint:          endpoint + "/logout",
+		IntrospectionEndpoint:       endpoint + "/token/introspect",
+		RevocationEndpoint:          endpoint + "/revoke",
+		DeviceAuthorizationEndpoint: endpoint + "/auth/device",
+	}
+}
+
+// KeycloakInfo implements InfoFunc and map Keycloak token claims to auth.Info,
+// Similar to ClaimsInfo, but the info groups also contains the realm roles,
+// and the client roles in the form of <client id>:<role>, the info roles contains the same roles.
+// The authorized party (azp) added to the info extensions.
+func KeycloakInfo(c *Claims) (auth.Info, error) {
+	info, err := ClaimsInfo(c)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := keycloakRoles(c.Raw["realm_access"])
+
+	if resources, ok := c.Raw["resource_access"].(map[string]interface{}); ok {
+		clients := make([]string, 0, len(resources))
+		for client := range resources {
+			clients = append(clients, client)
+		}
+
+		sort.Strings(clients)
+
+		for _, client := range clients {
+			for _, role := range keycloakRoles(resources[client]) {
+				roles = append(roles, client+":"+role)
+			}
+		}
+	}
+
+	info.SetGroups(append(append([]string{}, info.Groups()...), roles...))
+	auth.SetUserRoles(info, append(auth.UserRoles(info), roles...))
+
+	if azp, ok := c.Raw["azp"].(string); ok {
+		ext := info.Extensions()
+		ext["azp"] = []string{azp}
+		info.SetExtensions(ext)
+	}
+
+	return info, nil
+}
+
+func keycloakRoles(v interface{}) []string {
+	access, _ := v.(map[string]interface{})
+	roles, _ := access["roles"].([]interface{})
+	s := make([]string, 0, len(roles))
+
+	for _, role := range roles {
+		if r, ok := role.(string); ok {
+			s = append(s, r)
+		}
+	}
+
+	return s
+}
+
+type keycloak struct {
+	provider     *Provider
+	verifier     *Verifier
+	clientID     string
+	clientSecret string
+	infoFn       InfoFunc
+}
+
+func (k *keycloak) authenticate(ctx context.Context, r *http.Request, tk string) (auth.Info, error) {
+	info, err := k.verifier.Info(ctx, tk)
+	if !errors.Is(err, jwt.ErrMalformed) || k.clientSecret == "" {
+		return info, err
+	}
+
+	c, err := k.provider.Introspect(ctx, k.clientID, k.clientSecret, tk)
+	if err != nil {
+		return nil, err
+	}
+
+	return k.infoFn(c)
+}
+
+// SetIntrospection enables Keycloak strategy to validate opaque tokens,
+// using the realm introspection endpoint, authenticated with the given confidential client credentials.
+func SetIntrospection(clientID, clientSecret string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if k, ok := v.(*keycloak); ok {
+			k.clientID = clientID
+			k.clientSecret = clientSecret
+		}
+	})
+}
+
+// GetKeycloakAuthenticateFunc return function to authenticate request using Keycloak realm issued tokens.
+// The returned function typically used with the token strategy.
+func GetKeycloakAuthenticateFunc(p *Provider, clientID string, opts ...auth.Option) token.AuthenticateFunc {
+	opts = append([]auth.Option{SetInfoFunc(KeycloakInfo)}, opts...)
+	k := &keycloak{
+		provider: p,
+		verifier: NewVerifier(p, clientID, opts...),
+	}
+
+	for _, opt := range opts {
+		opt.Apply(k)
+	}
+
+	k.infoFn = k.verifier.infoFn
+
+	return k.authenticate
+}
+
+// NewKeycloak return strategy authenticate request using Keycloak realm issued access tokens,
+// where the token roles mapped to info groups, See KeycloakInfo.
+// Keycloak access tokens audience does not contain the client id,
+// unless an audience mapper configured, use SetAudience to accept other audience (e.g account).
+// Opaque tokens rejected unless introspection enabled, See SetIntrospection.
+// NewKeycloak is similar to token.New().
+func NewKeycloak(realmURL, clientID string, c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetKeycloakAuthenticateFunc(KeycloakProvider(realmURL), clientID, opts...)
+	return token.New(fn, c, opts...)
+}
@@ -0,0 +1,114 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+func TestKeycloakProvider(t *testing.T) {
+	p := KeycloakProvider("https://kc.example.com/realms/test/")
+	assert.Equal(t, "https://kc.example.com/realms/test", p.Issuer)
+	assert.Equal(t, "https://kc.example.com/realms/test/protocol/openid-connect/certs", p.JWKSURI)
+}
+
+func TestKeycloak(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	introspection := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		id, secret, _ := r.BasicAuth()
+
+		if id != "backend" || secret != "secret" || r.PostForm.Get("token") != "opaque" {
+			_, _ = w.Write([]byte(`{"active":false}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"active":true,"sub":"2","username":"svc","realm_access":{"roles":["user"]}}`))
+	}))
+	defer introspection.Close()
+
+	p := KeycloakProvider(tp.URL)
+	p.JWKSURI = tp.URL + "/jwks"
+	p.IntrospectionEndpoint = introspection.URL
+	p.SetHTTPClient(tp.Client())
+
+	tp.claims = map[string]interface{}{
+		"azp":          "client",
+		"realm_access": map[string]interface{}{"roles": []string{"offline_access", "user"}},
+		"resource_access": map[string]interface{}{
+			"client":  map[string]interface{}{"roles": []string{"editor"}},
+			"account": map[string]interface{}{"roles": []string{"view-profile"}},
+		},
+	}
+
+	table := []struct {
+		name   string
+		token  string
+		opts   []auth.Option
+		err    error
+		user   string
+		groups []string
+		roles  []string
+	}{
+		{
+			name:  "it map realm and client roles to groups",
+			token: tp.token(t, nil),
+			user:  "test",
+			groups: []string{
+				"admin",
+				"offline_access",
+				"user",
+				"account:view-profile",
+				"client:editor",
+			},
+			roles: []string{"offline_access", "user", "account:view-profile", "client:editor"},
+		},
+		{
+			name:  "it reject opaque token when introspection disabled",
+			token: "opaque",
+			err:   jwt.ErrMalformed,
+		},
+		{
+			name:   "it authenticate opaque token using introspection",
+			token:  "opaque",
+			opts:   []auth.Option{SetIntrospection("backend", "secret")},
+			user:   "svc",
+			groups: []string{"user"},
+			roles:  []string{"user"},
+		},
+		{
+			name:  "it return error when introspection reports token inactive",
+			token: "inactive",
+			opts:  []auth.Option{SetIntrospection("backend", "secret")},
+			err:   ErrInactiveToken,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := GetKeycloakAuthenticateFunc(p, "client", tt.opts...)
+			info, err := fn(context.Background(), nil, tt.token)
+
+			assert.Equal(t, tt.err, err)
+			if err == nil {
+				assert.Equal(t, tt.user, info.UserName())
+				assert.Equal(t, tt.groups, info.Groups())
+				assert.Equal(t, tt.roles, auth.UserRoles(info))
+			}
+		})
+	}
+}
+
+func TestNewKeycloak(t *testing.T) {
+	s := NewKeycloak("https://kc.example.com/realms/test", "client", store.New(0))
+	assert.NotNil(t, s)
+}
@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// BackChannelLogoutEvent is the logout token events claim member,
+// as defined in OpenID Connect Back-Channel Logout.
+const BackChannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// ErrInvalidLogoutToken is returned when the logout token claims invalid.
+var ErrInvalidLogoutToken = errors.New("strategies/oidc: Invalid logout token")
+
+// TerminateFunc define function signature to terminate the local sessions,
+// of the given provider subject and/or session id, one of them might be empty.
+type TerminateFunc func(ctx context.Context, sub, sid string) error
+
+// VerifyLogoutToken verifies back-channel logout token and return its claims.
+func (v *Verifier) VerifyLogoutToken(ctx context.Context, token string) (*Claims, error) {
+	c, err := v.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	events, _ := c.Raw["events"].(map[string]interface{})
+
+	if _, ok := events[BackChannelLogoutEvent]; !ok {
+		return nil, ErrInvalidLogoutToken
+	}
+
+	// logout token must not contain nonce, to prevent it from being used as ID token.
+	if _, ok := c.Raw["nonce"]; ok {
+		return nil, ErrInvalidLogoutToken
+	}
+
+	if c.Subject == "" && c.SessionID == "" {
+		return nil, ErrInvalidLogoutToken
+	}
+
+	return c, nil
+}
+
+// BackChannelLogoutHandler return HTTP handler that accepts provider back-channel logout requests,
+// verifies the logout token, and terminate the local sessions of its sub and/or sid.
+func BackChannelLogoutHandler(v *Verifier, fn TerminateFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		if r.Method != http.MethodPost {
+			logoutError(w, http.StatusMethodNotAllowed)
+			return
+		}
+
+		c, err := v.VerifyLogoutToken(r.Context(), r.PostFormValue("logout_token"))
+		if err != nil {
+			logoutError(w, http.StatusBadRequest)
+			return
+		}
+
+		if err := fn(r.Context(), c.Subject, c.SessionID); err != nil {
+			logoutError(w, http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// FrontChannelLogoutHandler return HTTP handler that accepts provider front-channel logout requests,
+// rendered by the provider in an iframe, and terminate the local sessions of the sid query parameter.
+// As front-channel requests are not signed, the session id should be hard to guess,
+// and the iss query parameter, when present, must match the provider issuer.
+func FrontChannelLogoutHandler(p *Provider, fn TerminateFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		q := r.URL.Query()
+		sid := q.Get("sid")
+
+		if iss := q.Get("iss"); (iss != "" && iss != p.Issuer) || sid == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := fn(r.Context(), "", sid); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func logoutError(w http.ResponseWriter, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request"})
+}
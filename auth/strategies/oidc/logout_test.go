@@ -0,0 +1,122 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackChannelLogoutHandler(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	p, _ := Discover(context.Background(), tp.URL, tp.Client())
+	events := map[string]interface{}{BackChannelLogoutEvent: map[string]interface{}{}}
+
+	table := []struct {
+		name   string
+		method string
+		extra  map[string]interface{}
+		code   int
+		sid    string
+	}{
+		{
+			name:   "it reject non POST requests",
+			method: http.MethodGet,
+			code:   http.StatusMethodNotAllowed,
+		},
+		{
+			name:   "it reject token without logout event",
+			method: http.MethodPost,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "it reject token with nonce",
+			method: http.MethodPost,
+			extra:  map[string]interface{}{"events": events, "nonce": "n"},
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "it terminate sessions",
+			method: http.MethodPost,
+			extra:  map[string]interface{}{"events": events, "sid": "s1"},
+			code:   http.StatusOK,
+			sid:    "s1",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			var sub, sid string
+			fn := func(_ context.Context, s, id string) error {
+				sub, sid = s, id
+				return nil
+			}
+
+			form := url.Values{"logout_token": {tp.token(t, tt.extra)}}
+			r, _ := http.NewRequest(tt.method, "/", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+
+			BackChannelLogoutHandler(NewVerifier(p, "client"), fn).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.sid, sid)
+
+			if tt.code == http.StatusOK {
+				assert.Equal(t, "1", sub)
+			}
+		})
+	}
+}
+
+func TestFrontChannelLogoutHandler(t *testing.T) {
+	p := &Provider{Issuer: "https://idp"}
+
+	table := []struct {
+		name  string
+		query string
+		code  int
+		sid   string
+	}{
+		{
+			name:  "it reject request without sid",
+			query: "iss=https://idp",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "it reject request from another issuer",
+			query: "iss=https://other&sid=s1",
+			code:  http.StatusBadRequest,
+		},
+		{
+			name:  "it terminate session",
+			query: "iss=https://idp&sid=s1",
+			code:  http.StatusOK,
+			sid:   "s1",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			var sid string
+			fn := func(_ context.Context, _, id string) error {
+				sid = id
+				return nil
+			}
+
+			r, _ := http.NewRequest("GET", "/?"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			FrontChannelLogoutHandler(p, fn).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.sid, sid)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package oidc provides OpenID Connect relying party building blocks,
+// provider discovery, ID token and JWT access token verification strategy,
+// and HTTP handlers implementing the authorization code flow with PKCE,
+// to add "login with Google/Okta/..." to go-guardian based services.
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+var (
+	// ErrIssuerMismatch is returned by Discover,
+	// when the discovered issuer does not match the requested issuer.
+	ErrIssuerMismatch = errors.New("strategies/oidc: Discovered issuer does not match the requested issuer")
+
+	// ErrInvalidState is returned by callback handler,
+	// when the authorization response state missing, expired, or not bound to the user agent.
+	ErrInvalidState = errors.New("strategies/oidc: Invalid state")
+
+	// ErrInvalidNonce is returned by callback handler,
+	// when the ID token nonce does not match the authorization request nonce.
+	ErrInvalidNonce = errors.New("strategies/oidc: Invalid nonce")
+
+	// ErrMissingIDToken is returned by callback handler,
+	// when the token response does not contain an ID token.
+	ErrMissingIDToken = errors.New("strategies/oidc: Token response missing id_token")
+)
+
+// Provider represents OpenID provider metadata.
+type Provider struct {
+	Issuer                      string   `json:"issuer"`
+	AuthorizationEndpoint       string   `json:"authorization_endpoint"`
+	TokenEndpoint               string   `json:"token_endpoint"`
+	UserInfoEndpoint            string   `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                     string   `json:"jwks_uri"`
+	EndSessionEndpoint          string   `json:"end_session_endpoint,omitempty"`
+	IntrospectionEndpoint       string   `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint          string   `json:"revocation_endpoint,omitempty"`
+	DeviceAuthorizationEndpoint string   `json:"device_authorization_endpoint,omitempty"`
+	ScopesSupported             []string `json:"scopes_supported,omitempty"`
+
+	mu     sync.Mutex
+	client *http.Client
+	keys   *jwt.RemoteKeySet
+}
+
+// Discover fetches the provider metadata from the issuer well-known openid-configuration endpoint.
+// if c nil, http.DefaultClient used.
+func Discover(ctx context.Context, issuer string, c *http.Client) (*Provider, error) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	url := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("strategies/oidc: Discovery respond with status code %d", resp.StatusCode)
+	}
+
+	p := new(Provider)
+	if err := json.NewDecoder(resp.Body).Decode(p); err != nil {
+		return nil, fmt.Errorf("strategies/oidc: Failed to decode provider metadata Err: %s", err)
+	}
+
+	if p.Issuer != issuer {
+		return nil, ErrIssuerMismatch
+	}
+
+	p.client = c
+
+	return p, nil
+}
+
+// SetHTTPClient sets the http client used to fetch provider keys,
+// and to call provider endpoints.
+func (p *Provider) SetHTTPClient(c *http.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.client = c
+	p.keys = nil
+}
+
+func (p *Provider) httpClient() *http.Client {
+	if p.client == nil {
+		return http.DefaultClient
+	}
+	return p.client
+}
+
+func (p *Provider) keySet() *jwt.RemoteKeySet {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.keys == nil {
+		p.keys = jwt.NewRemoteKeySet(p.JWKSURI, p.httpClient())
+	}
+	return p.keys
+}
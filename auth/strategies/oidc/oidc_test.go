@@ -0,0 +1,285 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/claims"
+	"github.com/shaj13/go-guardian/internal/jwt"
+	"github.com/shaj13/go-guardian/store"
+)
+
+type testProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	nonce  string
+	claims map[string]interface{}
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	tp := &testProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 tp.URL,
+			"authorization_endpoint": tp.URL + "/authorize",
+			"token_endpoint":         tp.URL + "/token",
+			"jwks_uri":               tp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jwk, _ := jwt.NewJSONWebKey(&key.PublicKey, "kid", jwt.RS256)
+		_ = json.NewEncoder(w).Encode(jwt.KeySet{Keys: []jwt.JSONWebKey{jwk}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		id, secret, _ := r.BasicAuth()
+
+		if id != "client" || secret != "secret" || r.PostForm.Get("code") != "code" ||
+			challenge(r.PostForm.Get("code_verifier")) != tp.nonce {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(Tokens{
+			AccessToken: "access",
+			TokenType:   "Bearer",
+			IDToken:     tp.token(t, nil),
+			ExpiresIn:   3600,
+		})
+	})
+
+	tp.Server = httptest.NewServer(mux)
+
+	return tp
+}
+
+func (tp *testProvider) token(t *testing.T, extra map[string]interface{}) string {
+	claims := map[string]interface{}{
+		"iss":                tp.URL,
+		"sub":                "1",
+		"aud":                "client",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "test",
+		"email":              "test@example.com",
+		"email_verified":     "true",
+		"groups":             []string{"admin"},
+	}
+
+	for k, v := range tp.claims {
+		claims[k] = v
+	}
+
+	for k, v := range extra {
+		claims[k] = v
+	}
+
+	token, err := jwt.Sign(jwt.RS256, "kid", tp.key, claims)
+	assert.NoError(t, err)
+
+	return token
+}
+
+func TestDiscover(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	p, err := Discover(context.Background(), tp.URL, tp.Client())
+	assert.NoError(t, err)
+	assert.Equal(t, tp.URL+"/token", p.TokenEndpoint)
+
+	_, err = Discover(context.Background(), tp.URL+"/", tp.Client())
+	assert.Equal(t, ErrIssuerMismatch, err)
+}
+
+func TestStrategy(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	p, _ := Discover(context.Background(), tp.URL, tp.Client())
+
+	table := []struct {
+		name  string
+		extra map[string]interface{}
+		opts  []auth.Option
+		err   bool
+	}{
+		{
+			name: "it authenticate valid token",
+		},
+		{
+			name:  "it return error when token expired",
+			extra: map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()},
+			err:   true,
+		},
+		{
+			name:  "it return error when issuer invalid",
+			extra: map[string]interface{}{"iss": "https://example.com"},
+			err:   true,
+		},
+		{
+			name: "it return error when audience invalid",
+			opts: []auth.Option{SetAudience("other")},
+			err:  true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(p, "client", store.New(0), tt.opts...)
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+tp.token(t, tt.extra))
+
+			info, err := s.Authenticate(r.Context(), r)
+
+			assert.Equal(t, tt.err, err != nil, err)
+
+			if !tt.err {
+				assert.Equal(t, "test", info.UserName())
+				assert.Equal(t, "1", info.ID())
+				assert.Equal(t, []string{"admin"}, info.Groups())
+				assert.Equal(t, []string{"true"}, info.Extensions()["email_verified"])
+
+				exp, ok := auth.UserExpiry(info)
+				assert.True(t, ok)
+				assert.True(t, exp.After(time.Now()))
+			}
+		})
+	}
+}
+
+func TestRelyingParty(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	p, _ := Discover(context.Background(), tp.URL, tp.Client())
+
+	var got auth.Info
+	success := func(w http.ResponseWriter, r *http.Request, info auth.Info, t *Tokens) {
+		got = info
+	}
+
+	rp := NewRelyingParty(p, "client", "secret", "https://app/callback", store.New(0), success)
+
+	// login
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/login", nil)
+	rp.LoginHandler().ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+
+	loc, _ := url.Parse(w.Header().Get("Location"))
+	q := loc.Query()
+	cookie := w.Result().Cookies()[0]
+
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, q.Get("state"), cookie.Value)
+	assert.True(t, cookie.Secure)
+
+	tp.nonce = q.Get("code_challenge")
+	tp.claims = map[string]interface{}{"nonce": q.Get("nonce")}
+
+	callback := func(state string, c *http.Cookie) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/callback?code=code&state="+state, nil)
+		if c != nil {
+			r.AddCookie(c)
+		}
+		rp.CallbackHandler().ServeHTTP(w, r)
+		return w
+	}
+
+	// state not bound to user agent.
+	w = callback(q.Get("state"), nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Nil(t, got)
+
+	w = callback(q.Get("state"), cookie)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", got.ID())
+
+	// state is single use.
+	got = nil
+	w = callback(q.Get("state"), cookie)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Nil(t, got)
+}
+
+func TestRelyingPartyInvalidNonce(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	p, _ := Discover(context.Background(), tp.URL, tp.Client())
+	c := store.New(0)
+	success := func(http.ResponseWriter, *http.Request, auth.Info, *Tokens) {}
+	rp := NewRelyingParty(p, "client", "secret", "http://app/callback", c, success)
+
+	_ = c.Store("state", authRequest{Nonce: "nonce", Verifier: "verifier"}, nil)
+	tp.nonce = challenge("verifier")
+	tp.claims = map[string]interface{}{"nonce": "other"}
+
+	r, _ := http.NewRequest("GET", "/callback?code=code&state=state", nil)
+	r.AddCookie(&http.Cookie{Name: StateCookieName, Value: "state"})
+
+	_, _, err := rp.callback(r)
+	assert.Equal(t, ErrInvalidNonce, err)
+}
+
+func TestEndSessionURL(t *testing.T) {
+	p := &Provider{}
+	success := func(http.ResponseWriter, *http.Request, auth.Info, *Tokens) {}
+	rp := NewRelyingParty(p, "client", "", "https://app/callback", store.New(0), success)
+
+	assert.Equal(t, "", rp.EndSessionURL("", ""))
+
+	p.EndSessionEndpoint = "https://idp/logout"
+	assert.Equal(
+		t,
+		"https://idp/logout?client_id=client&id_token_hint=id&post_logout_redirect_uri=https%3A%2F%2Fapp",
+		rp.EndSessionURL("id", "https://app"),
+	)
+}
+
+func TestMapperInfo(t *testing.T) {
+	tp := newTestProvider(t)
+	defer tp.Close()
+
+	p := &Provider{Issuer: tp.URL, JWKSURI: tp.URL + "/jwks"}
+	p.SetHTTPClient(tp.Client())
+
+	m := &claims.Mapper{
+		UserName: []string{"upn", "email"},
+		Roles:    []string{"realm_access.roles"},
+	}
+
+	s := New(p, "client", store.New(0), SetInfoFunc(MapperInfo(m)))
+
+	token := tp.token(t, map[string]interface{}{
+		"realm_access": map[string]interface{}{"roles": []string{"user"}},
+	})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	info, err := s.Authenticate(context.Background(), r)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", info.UserName())
+	assert.Equal(t, "1", info.ID())
+	assert.Equal(t, []string{"user"}, auth.UserRoles(info))
+
+	_, ok := auth.UserExpiry(info)
+	assert.True(t, ok)
+}
@@ -0,0 +1,57 @@
+package oidc
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetAudience sets the accepted token audience,
+// Default the client id.
+func SetAudience(aud ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*Verifier); ok {
+			v.audience = aud
+		}
+	})
+}
+
+// SetLeeway sets the allowed clock skew when validating token exp and nbf,
+// Default 1 minute.
+func SetLeeway(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*Verifier); ok {
+			v.leeway = d
+		}
+	})
+}
+
+// SetInfoFunc sets the function used to map verified claims to auth.Info,
+// Default ClaimsInfo.
+func SetInfoFunc(fn InfoFunc) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*Verifier); ok {
+			v.infoFn = fn
+		}
+	})
+}
+
+// SetScopes sets the relying party authorization request scopes,
+// Default openid, profile, and email.
+func SetScopes(scopes ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if rp, ok := v.(*RelyingParty); ok {
+			rp.scopes = scopes
+		}
+	})
+}
+
+// SetErrorHandler sets the relying party callback error handler,
+// Default auth.PlainTextErrorHandler.
+func SetErrorHandler(h auth.ErrorHandler) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if rp, ok := v.(*RelyingParty); ok {
+			rp.errHandler = h
+		}
+	})
+}
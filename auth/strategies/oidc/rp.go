@@ -0,0 +1,336 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// StateCookieName is the cookie name used to bind the authorization request state to the user agent.
+const StateCookieName = "oidc_state"
+
+// Tokens represents the token endpoint response.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// SuccessHandler define function signature invoked by the callback handler,
+// when the user successfully logged in, typically used to establish a session,
+// or issue a go-guardian token, and redirect the user to the application.
+type SuccessHandler func(w http.ResponseWriter, r *http.Request, info auth.Info, t *Tokens)
+
+func init() {
+	gob.Register(authRequest{})
+}
+
+type authRequest struct {
+	Nonce    string
+	Verifier string
+}
+
+// RelyingParty implements the OpenID Connect authorization code flow with PKCE.
+// The authorization request state, nonce, and code verifier kept in the cache,
+// keyed by the state, therefore the cache entries lifetime bounds the login duration.
+type RelyingParty struct {
+	provider     *Provider
+	verifier     *Verifier
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	cache        store.Cache
+	success      SuccessHandler
+	errHandler   auth.ErrorHandler
+	secure       bool
+}
+
+// NewRelyingParty return new RelyingParty.
+func NewRelyingParty(
+	p *Provider,
+	clientID, clientSecret, redirectURL string,
+	c store.Cache,
+	success SuccessHandler,
+	opts ...auth.Option,
+) *RelyingParty {
+	if c == nil {
+		panic("Cache object required and can't be nil")
+	}
+
+	if success == nil {
+		panic("Success Handler required and can't be nil")
+	}
+
+	rp := &RelyingParty{
+		provider:     p,
+		verifier:     NewVerifier(p, clientID, opts...),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       []string{"openid", "profile", "email"},
+		cache:        c,
+		success:      success,
+		errHandler:   auth.PlainTextErrorHandler,
+		secure:       strings.HasPrefix(redirectURL, "https://"),
+	}
+
+	for _, opt := range opts {
+		opt.Apply(rp)
+	}
+
+	return rp
+}
+
+// AuthCodeURL return the provider authorization endpoint URL,
+// of a new authorization request with the given state, nonce, and PKCE code challenge.
+func (rp *RelyingParty) AuthCodeURL(state, nonce, challenge string) string {
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {rp.clientID},
+		"redirect_uri":          {rp.redirectURL},
+		"scope":                 {strings.Join(rp.scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(rp.provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return rp.provider.AuthorizationEndpoint + sep + q.Encode()
+}
+
+// EndSessionURL return the provider end-session endpoint URL (RP-initiated logout),
+// or empty string if the provider does not support it.
+// idTokenHint and postLogoutRedirect are optional.
+// Typically used with auth.SetEndSession.
+func (rp *RelyingParty) EndSessionURL(idTokenHint, postLogoutRedirect string) string {
+	if rp.provider.EndSessionEndpoint == "" {
+		return ""
+	}
+
+	q := url.Values{"client_id": {rp.clientID}}
+
+	if idTokenHint != "" {
+		q.Set("id_token_hint", idTokenHint)
+	}
+
+	if postLogoutRedirect != "" {
+		q.Set("post_logout_redirect_uri", postLogoutRedirect)
+	}
+
+	sep := "?"
+	if strings.Contains(rp.provider.EndSessionEndpoint, "?") {
+		sep = "&"
+	}
+
+	return rp.provider.EndSessionEndpoint + sep + q.Encode()
+}
+
+// LoginHandler return HTTP handler that start a new authorization request,
+// and redirect the user agent to the provider authorization endpoint.
+func (rp *RelyingParty) LoginHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		state, nonce, verifier := random(), random(), random()
+
+		if err := rp.cache.Store(state, authRequest{nonce, verifier}, r); err != nil {
+			rp.errHandler(w, r, err)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     StateCookieName,
+			Value:    state,
+			Path:     "/",
+			HttpOnly: true,
+			Secure:   rp.secure,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		http.Redirect(w, r, rp.AuthCodeURL(state, nonce, challenge(verifier)), http.StatusFound)
+	})
+}
+
+// CallbackHandler return HTTP handler that handles the provider authorization response,
+// verifies the state, exchange the code for tokens, verifies the ID token and its nonce,
+// and invoke the success handler with the user info.
+func (rp *RelyingParty) CallbackHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, tokens, err := rp.callback(r)
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     StateCookieName,
+			Path:     "/",
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   rp.secure,
+		})
+
+		if err != nil {
+			rp.errHandler(w, r, err)
+			return
+		}
+
+		rp.success(w, r, info, tokens)
+	})
+}
+
+func (rp *RelyingParty) callback(r *http.Request) (auth.Info, *Tokens, error) {
+	q := r.URL.Query()
+
+	if e := q.Get("error"); e != "" {
+		return nil, nil, fmt.Errorf(
+			"strategies/oidc: Authorization failed %s: %s",
+			errors.Redact(e),
+			errors.Redact(q.Get("error_description")),
+		)
+	}
+
+	state := q.Get("state")
+	cookie, err := r.Cookie(StateCookieName)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		return nil, nil, ErrInvalidState
+	}
+
+	v, ok, err := rp.cache.Load(state, r)
+	if err != nil || !ok {
+		return nil, nil, ErrInvalidState
+	}
+
+	// state is single use.
+	_ = rp.cache.Delete(state, r)
+
+	req, ok := v.(authRequest)
+	if !ok {
+		return nil, nil, errors.NewInvalidType(authRequest{}, v)
+	}
+
+	tokens, err := rp.Exchange(r.Context(), url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {q.Get("code")},
+		"redirect_uri":  {rp.redirectURL},
+		"code_verifier": {req.Verifier},
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if tokens.IDToken == "" {
+		return nil, nil, ErrMissingIDToken
+	}
+
+	c, err := rp.verifier.Verify(r.Context(), tokens.IDToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(c.Nonce), []byte(req.Nonce)) != 1 {
+		return nil, nil, ErrInvalidNonce
+	}
+
+	info, err := rp.verifier.infoFn(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return info, tokens, nil
+}
+
+// Exchange sends the given form to the provider token endpoint,
+// authenticated using the client credentials, and return the token response.
+func (rp *RelyingParty) Exchange(ctx context.Context, form url.Values) (*Tokens, error) {
+	if rp.clientSecret == "" {
+		form.Set("client_id", rp.clientID)
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		rp.provider.TokenEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if rp.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(rp.clientID), url.QueryEscape(rp.clientSecret))
+	}
+
+	resp, err := rp.provider.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		e := struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}{}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		return nil, fmt.Errorf(
+			"strategies/oidc: Token endpoint respond with status code %d %s: %s",
+			resp.StatusCode,
+			errors.Redact(e.Error),
+			errors.Redact(e.Description),
+		)
+	}
+
+	t := new(Tokens)
+	if err := json.NewDecoder(resp.Body).Decode(t); err != nil {
+		return nil, fmt.Errorf("strategies/oidc: Failed to decode token response Err: %s", err)
+	}
+
+	return t, nil
+}
+
+// random return 256 bit url safe random string.
+func random() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// challenge return PKCE S256 code challenge of the given verifier.
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ExpiresAt return the access token expiry time relative to now,
+// or zero time if the provider did not return expires_in.
+func (t *Tokens) ExpiresAt() time.Time {
+	if t.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+}
@@ -0,0 +1,27 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// GetAuthenticateFunc return function to authenticate request using provider issued JWT.
+// The returned function typically used with the token strategy.
+func GetAuthenticateFunc(p *Provider, clientID string, opts ...auth.Option) token.AuthenticateFunc {
+	v := NewVerifier(p, clientID, opts...)
+	return func(ctx context.Context, r *http.Request, tk string) (auth.Info, error) {
+		return v.Info(ctx, tk)
+	}
+}
+
+// New return strategy authenticate request using provider issued ID token or JWT access token.
+// New is similar to token.New().
+// The cache entries lifetime should not exceed the tokens expiry duration.
+func New(p *Provider, clientID string, c store.Cache, opts ...auth.Option) auth.Strategy {
+	fn := GetAuthenticateFunc(p, clientID, opts...)
+	return token.New(fn, c, opts...)
+}
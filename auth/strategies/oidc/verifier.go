@@ -0,0 +1,191 @@
+package oidc
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/claims"
+	"github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// Claims represents the verified ID token or JWT access token claims.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Audience          []string
+	Expiry            time.Time
+	IssuedAt          time.Time
+	Nonce             string
+	SessionID         string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Groups            []string
+	Roles             []string
+	// Raw holds all the token claims, numbers decoded as json.Number.
+	Raw map[string]interface{}
+}
+
+// InfoFunc define function signature to map verified claims to auth.Info.
+type InfoFunc func(c *Claims) (auth.Info, error)
+
+// ClaimsInfo implements InfoFunc and map claims to auth.Info,
+// where user name is preferred_username, or email, or sub,
+// user id is the sub, groups is the groups claim,
+// roles is the roles claim, expiry is the exp claim,
+// and extensions contains iss, email, name, and sid claims.
+func ClaimsInfo(c *Claims) (auth.Info, error) {
+	name := c.PreferredUsername
+
+	if name == "" {
+		name = c.Email
+	}
+
+	if name == "" {
+		name = c.Subject
+	}
+
+	ext := map[string][]string{"iss": {c.Issuer}}
+
+	if c.Email != "" {
+		ext["email"] = []string{c.Email}
+		ext["email_verified"] = []string{strconv.FormatBool(c.EmailVerified)}
+	}
+
+	if c.Name != "" {
+		ext["name"] = []string{c.Name}
+	}
+
+	if c.SessionID != "" {
+		ext["sid"] = []string{c.SessionID}
+	}
+
+	info := auth.NewUserInfo(name, c.Subject, c.Groups, ext)
+	auth.SetUserRoles(info, c.Roles)
+	auth.SetUserExpiry(info, c.Expiry)
+
+	return info, nil
+}
+
+// MapperInfo return InfoFunc that map the token claims to auth.Info using the given claims mapper,
+// and sets the user expiry to the exp claim.
+func MapperInfo(m *claims.Mapper) InfoFunc {
+	return func(c *Claims) (auth.Info, error) {
+		info, err := m.Map(c.Raw)
+		if err != nil {
+			return nil, err
+		}
+
+		auth.SetUserExpiry(info, c.Expiry)
+
+		return info, nil
+	}
+}
+
+type idClaims struct {
+	jwt.Claims
+	Nonce             string      `json:"nonce"`
+	SessionID         string      `json:"sid"`
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"`
+	Name              string      `json:"name"`
+	PreferredUsername string      `json:"preferred_username"`
+	Groups            []string    `json:"groups"`
+	Roles             []string    `json:"roles"`
+}
+
+func (ic *idClaims) claims() *Claims {
+	return &Claims{
+		Issuer:            ic.Issuer,
+		Subject:           ic.Subject,
+		Audience:          ic.Audience,
+		Expiry:            ic.Expiry.Time(),
+		IssuedAt:          ic.IssuedAt.Time(),
+		Nonce:             ic.Nonce,
+		SessionID:         ic.SessionID,
+		Email:             ic.Email,
+		EmailVerified:     parseBool(ic.EmailVerified),
+		Name:              ic.Name,
+		PreferredUsername: ic.PreferredUsername,
+		Groups:            ic.Groups,
+		Roles:             ic.Roles,
+	}
+}
+
+// Verifier verifies ID tokens and JWT access tokens,
+// issued by the provider, using the provider JWKS.
+type Verifier struct {
+	provider *Provider
+	audience []string
+	leeway   time.Duration
+	infoFn   InfoFunc
+}
+
+// NewVerifier return new Verifier that accepts tokens issued for the given client id,
+// unless audience overridden using SetAudience.
+func NewVerifier(p *Provider, clientID string, opts ...auth.Option) *Verifier {
+	v := &Verifier{
+		provider: p,
+		audience: []string{clientID},
+		leeway:   time.Minute,
+		infoFn:   ClaimsInfo,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(v)
+	}
+
+	return v
+}
+
+// Verify verifies the token signature, issuer, audience, and expiry,
+// and return its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	ic := idClaims{}
+	t, err := v.provider.keySet().Verify(ctx, token, &ic)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ic.Validate(jwt.Expected{
+		Issuer:   v.provider.Issuer,
+		Audience: v.audience,
+		Leeway:   v.leeway,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := ic.claims()
+
+	if err := t.Decode(&c.Raw); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Info verifies the token and map its claims to auth.Info.
+func (v *Verifier) Info(ctx context.Context, token string) (auth.Info, error) {
+	c, err := v.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return v.infoFn(c)
+}
+
+// parseBool parse boolean claim, some providers encode booleans as strings.
+func parseBool(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		ok, _ := strconv.ParseBool(b)
+		return ok
+	}
+	return false
+}
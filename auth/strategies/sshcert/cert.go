@@ -0,0 +1,146 @@
+package sshcert
+
+import (
+	"crypto"
+	"math"
+	"strings"
+	"time"
+)
+
+// certSuffix is the OpenSSH certificate algorithms suffix.
+const certSuffix = "-cert-v01@openssh.com"
+
+// Certificate types.
+const (
+	UserCert = 1
+	HostCert = 2
+)
+
+// Certificate represents an OpenSSH certificate, as described in PROTOCOL.certkeys.
+type Certificate struct {
+	// Type is the certificate algorithm, e.g ssh-ed25519-cert-v01@openssh.com.
+	Type string
+	// Key is the certified public key, *rsa.PublicKey, *ecdsa.PublicKey, or ed25519.PublicKey.
+	Key             crypto.PublicKey
+	Serial          uint64
+	CertType        uint32
+	KeyID           string
+	ValidPrincipals []string
+	// ValidAfter and ValidBefore are seconds since Unix epoch,
+	// ValidBefore math.MaxUint64 means the certificate does not expire.
+	ValidAfter      uint64
+	ValidBefore     uint64
+	CriticalOptions map[string]string
+	Extensions      map[string]string
+	// SignatureKey is the CA public key that signed the certificate.
+	SignatureKey *PublicKey
+
+	signed    []byte
+	signature []byte
+}
+
+// NotBefore return the certificate valid after time.
+func (c *Certificate) NotBefore() time.Time {
+	return unix(c.ValidAfter)
+}
+
+// NotAfter return the certificate valid before time,
+// or zero time when the certificate does not expire.
+func (c *Certificate) NotAfter() time.Time {
+	if c.ValidBefore == math.MaxUint64 {
+		return time.Time{}
+	}
+	return unix(c.ValidBefore)
+}
+
+// ParseCertificate parse an OpenSSH certificate in the SSH wire format.
+// ParseCertificate does not verify the certificate signature.
+func ParseCertificate(b []byte) (*Certificate, error) {
+	r := &reader{b: b}
+	c := new(Certificate)
+
+	c.Type = string(r.string())
+	if !strings.HasSuffix(c.Type, certSuffix) {
+		return nil, errMalformed
+	}
+
+	_ = r.string() // nonce
+
+	key, err := parseKey(strings.TrimSuffix(c.Type, certSuffix), r)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Key = key
+	c.Serial = r.uint64()
+	c.CertType = r.uint32()
+	c.KeyID = string(r.string())
+	principals := r.string()
+	c.ValidAfter = r.uint64()
+	c.ValidBefore = r.uint64()
+	critical := r.string()
+	extensions := r.string()
+	_ = r.string() // reserved
+	sigKey := r.string()
+	c.signed = b[:len(b)-len(r.b)]
+	c.signature = r.string()
+
+	if r.err || len(r.b) > 0 {
+		return nil, errMalformed
+	}
+
+	pr := &reader{b: principals}
+	for len(pr.b) > 0 && !pr.err {
+		c.ValidPrincipals = append(c.ValidPrincipals, string(pr.string()))
+	}
+
+	if c.CriticalOptions, err = parseOptions(critical); err != nil {
+		return nil, err
+	}
+
+	if c.Extensions, err = parseOptions(extensions); err != nil {
+		return nil, err
+	}
+
+	if c.SignatureKey, err = ParsePublicKey(sigKey); err != nil {
+		return nil, err
+	}
+
+	if pr.err {
+		return nil, errMalformed
+	}
+
+	return c, nil
+}
+
+// parseOptions parse the certificate critical options or extensions,
+// a sequence of names each followed by its string wrapped value, or an empty value.
+func parseOptions(b []byte) (map[string]string, error) {
+	opts := make(map[string]string)
+	r := &reader{b: b}
+
+	for len(r.b) > 0 {
+		name, data := string(r.string()), r.string()
+
+		if len(data) > 0 {
+			dr := &reader{b: data}
+			opts[name] = string(dr.string())
+			r.err = r.err || dr.err
+		} else {
+			opts[name] = ""
+		}
+
+		if r.err {
+			return nil, errMalformed
+		}
+	}
+
+	return opts, nil
+}
+
+func unix(v uint64) time.Time {
+	if v > math.MaxInt64 {
+		v = math.MaxInt64
+	}
+	return time.Unix(int64(v), 0)
+}
@@ -0,0 +1,325 @@
+package sshcert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// ErrUnsupportedKey is returned when the public key algorithm unsupported,
+// the supported algorithms are ssh-ed25519, ecdsa-sha2-nistp256/384/521, and ssh-rsa.
+var ErrUnsupportedKey = errors.New("strategies/sshcert: Unsupported key algorithm")
+
+// errMalformed is returned when the wire encoded value malformed.
+var errMalformed = errors.New("strategies/sshcert: Malformed SSH wire data")
+
+var curves = map[string]elliptic.Curve{
+	"nistp256": elliptic.P256(),
+	"nistp384": elliptic.P384(),
+	"nistp521": elliptic.P521(),
+}
+
+// PublicKey represents an SSH public key.
+type PublicKey struct {
+	// Type is the key algorithm, e.g ssh-ed25519.
+	Type string
+	// Key is *rsa.PublicKey, *ecdsa.PublicKey, or ed25519.PublicKey.
+	Key crypto.PublicKey
+
+	blob []byte
+}
+
+// Marshal return the key in the SSH wire format.
+func (k *PublicKey) Marshal() []byte {
+	return k.blob
+}
+
+// ParsePublicKey parse a public key in the SSH wire format.
+func ParsePublicKey(b []byte) (*PublicKey, error) {
+	r := &reader{b: b}
+	typ := string(r.string())
+
+	key, err := parseKey(typ, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.err || len(r.b) > 0 {
+		return nil, errMalformed
+	}
+
+	return &PublicKey{Type: typ, Key: key, blob: b}, nil
+}
+
+// ParseAuthorizedKey parse a public key in the authorized_keys format,
+// i.e "[@cert-authority] [options] type base64 [comment]", as the content of the CA .pub file.
+func ParseAuthorizedKey(line string) (*PublicKey, error) {
+	fields := strings.Fields(line)
+
+	for i := 0; i+1 < len(fields); i++ {
+		b, err := base64.StdEncoding.DecodeString(fields[i+1])
+		if err != nil {
+			continue
+		}
+
+		k, err := ParsePublicKey(b)
+		if err != nil || k.Type != fields[i] {
+			continue
+		}
+
+		return k, nil
+	}
+
+	return nil, errMalformed
+}
+
+func parseKey(typ string, r *reader) (crypto.PublicKey, error) {
+	switch {
+	case typ == "ssh-ed25519":
+		pk := r.string()
+		if len(pk) != ed25519.PublicKeySize {
+			return nil, errMalformed
+		}
+		return ed25519.PublicKey(pk), nil
+	case typ == "ssh-rsa":
+		e, n := r.mpint(), r.mpint()
+		if r.err || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 || n.Sign() <= 0 {
+			return nil, errMalformed
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case strings.HasPrefix(typ, "ecdsa-sha2-"):
+		name := strings.TrimPrefix(typ, "ecdsa-sha2-")
+		curve, ok := curves[name]
+		if !ok || string(r.string()) != name {
+			return nil, ErrUnsupportedKey
+		}
+
+		x, y := elliptic.Unmarshal(curve, r.string())
+		if x == nil {
+			return nil, errMalformed
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, ErrUnsupportedKey
+}
+
+// marshalKey return the given public key in the SSH wire format.
+func marshalKey(pub crypto.PublicKey) ([]byte, error) {
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		b := appendString(nil, []byte("ssh-ed25519"))
+		return appendString(b, pub), nil
+	case *rsa.PublicKey:
+		b := appendString(nil, []byte("ssh-rsa"))
+		b = appendMPInt(b, big.NewInt(int64(pub.E)))
+		return appendMPInt(b, pub.N), nil
+	case *ecdsa.PublicKey:
+		name := curveName(pub.Curve)
+		if len(name) == 0 {
+			return nil, ErrUnsupportedKey
+		}
+		b := appendString(nil, []byte("ecdsa-sha2-"+name))
+		b = appendString(b, []byte(name))
+		return appendString(b, elliptic.Marshal(pub.Curve, pub.X, pub.Y)), nil
+	}
+
+	return nil, ErrUnsupportedKey
+}
+
+// verify verifies the given signature in the SSH wire format,
+// i.e string format followed by string blob, of the given data.
+func verify(pub crypto.PublicKey, sig, data []byte) bool {
+	r := &reader{b: sig}
+	format, blob := string(r.string()), r.string()
+
+	if r.err || len(r.b) > 0 {
+		return false
+	}
+
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		return format == "ssh-ed25519" && ed25519.Verify(pub, data, blob)
+	case *ecdsa.PublicKey:
+		if format != "ecdsa-sha2-"+curveName(pub.Curve) {
+			return false
+		}
+
+		br := &reader{b: blob}
+		rr, s := br.mpint(), br.mpint()
+		if br.err || len(br.b) > 0 {
+			return false
+		}
+
+		return ecdsa.Verify(pub, ecdsaHash(pub.Curve, data), rr, s)
+	case *rsa.PublicKey:
+		var h crypto.Hash
+		var sum []byte
+
+		switch format {
+		case "ssh-rsa":
+			s := sha1.Sum(data) // nolint:gosec
+			h, sum = crypto.SHA1, s[:]
+		case "rsa-sha2-256":
+			s := sha256.Sum256(data)
+			h, sum = crypto.SHA256, s[:]
+		case "rsa-sha2-512":
+			s := sha512.Sum512(data)
+			h, sum = crypto.SHA512, s[:]
+		default:
+			return false
+		}
+
+		return rsa.VerifyPKCS1v15(pub, h, sum, blob) == nil
+	}
+
+	return false
+}
+
+// sign return the signature of the given data in the SSH wire format,
+// using rsa-sha2-256 for RSA keys.
+func sign(s crypto.Signer, data []byte) ([]byte, error) {
+	var format string
+	var blob []byte
+	var err error
+
+	switch pub := s.Public().(type) {
+	case ed25519.PublicKey:
+		format = "ssh-ed25519"
+		blob, err = s.Sign(rand.Reader, data, crypto.Hash(0))
+	case *rsa.PublicKey:
+		sum := sha256.Sum256(data)
+		format = "rsa-sha2-256"
+		blob, err = s.Sign(rand.Reader, sum[:], crypto.SHA256)
+	case *ecdsa.PublicKey:
+		format = "ecdsa-sha2-" + curveName(pub.Curve)
+
+		var der []byte
+		der, err = s.Sign(rand.Reader, ecdsaHash(pub.Curve, data), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var es struct {
+			R, S *big.Int
+		}
+
+		if _, err := asn1.Unmarshal(der, &es); err != nil {
+			return nil, err
+		}
+
+		blob = appendMPInt(appendMPInt(nil, es.R), es.S)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return appendString(appendString(nil, []byte(format)), blob), nil
+}
+
+func ecdsaHash(c elliptic.Curve, data []byte) []byte {
+	switch c.Params().BitSize {
+	case 256:
+		s := sha256.Sum256(data)
+		return s[:]
+	case 384:
+		s := sha512.Sum384(data)
+		return s[:]
+	}
+
+	s := sha512.Sum512(data)
+	return s[:]
+}
+
+func curveName(c elliptic.Curve) string {
+	for name, curve := range curves {
+		if curve == c {
+			return name
+		}
+	}
+	return ""
+}
+
+// reader reads SSH wire encoded values, and records whether the data was short.
+type reader struct {
+	b   []byte
+	err bool
+}
+
+func (r *reader) uint32() uint32 {
+	if len(r.b) < 4 {
+		r.err = true
+		return 0
+	}
+
+	v := binary.BigEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v
+}
+
+func (r *reader) uint64() uint64 {
+	if len(r.b) < 8 {
+		r.err = true
+		return 0
+	}
+
+	v := binary.BigEndian.Uint64(r.b)
+	r.b = r.b[8:]
+	return v
+}
+
+func (r *reader) string() []byte {
+	n := r.uint32()
+	if r.err || uint64(len(r.b)) < uint64(n) {
+		r.err = true
+		return nil
+	}
+
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+// mpint reads a non negative multiple precision integer.
+func (r *reader) mpint() *big.Int {
+	b := r.string()
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		r.err = true
+	}
+
+	return new(big.Int).SetBytes(b)
+}
+
+func appendString(b, v []byte) []byte {
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(v)))
+	return append(append(b, n[:]...), v...)
+}
+
+func appendMPInt(b []byte, v *big.Int) []byte {
+	buf := v.Bytes()
+	if len(buf) > 0 && buf[0]&0x80 != 0 {
+		buf = append([]byte{0}, buf...)
+	}
+	return appendString(b, buf)
+}
+
+func equalKeys(a, b *PublicKey) bool {
+	return bytes.Equal(a.blob, b.blob)
+}
@@ -0,0 +1,65 @@
+package sshcert
+
+import (
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// SetPrincipals sets the accepted principals,
+// the certificate must be valid for one of them, which is mapped to the user name.
+// Default any principal, the first certificate principal mapped to the user name.
+func SetPrincipals(principals ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			for _, p := range principals {
+				s.principals[p] = struct{}{}
+			}
+		}
+	})
+}
+
+// SetCriticalOptions sets the accepted critical options, in addition to source-address,
+// e.g force-command when the API enforce it,
+// the InfoBuilder can read their values from Certificate.CriticalOptions.
+func SetCriticalOptions(names ...string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			for _, n := range names {
+				s.critical[n] = struct{}{}
+			}
+		}
+	})
+}
+
+// SetInfoBuilder sets the strategy InfoBuilder.
+// Default Builder.
+func SetInfoBuilder(ib InfoBuilder) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			s.builder = ib
+		}
+	})
+}
+
+// SetLeeway sets the allowed clock skew when validating the certificate validity and the request timestamp.
+// Default 1 minute.
+func SetLeeway(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			s.leeway = d
+		}
+	})
+}
+
+// SetRequireSignature sets whether the request signature required,
+// Disable it only when the certificate forwarded by a trusted front-end,
+// that verified the possession of the certificate private key, e.g during an SSH handshake.
+// Default true.
+func SetRequireSignature(required bool) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if s, ok := v.(*strategy); ok {
+			s.signed = required
+		}
+	})
+}
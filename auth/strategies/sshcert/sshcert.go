@@ -0,0 +1,284 @@
+// Package sshcert provides authentication strategy,
+// to authenticate HTTP requests using OpenSSH user certificates signed by a trusted CA,
+// and map the certificate principals to user info,
+// Typically used by developer-tooling APIs in SSH-centric shops.
+//
+// The client presents its certificate in the X-SSH-Certificate header,
+// and proves the possession of the certificate private key,
+// by signing the request timestamp, method, URI, and host, See SignRequest.
+package sshcert
+
+import (
+	"context"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// StrategyKey export identifier for the SSH certificate strategy,
+// commonly used when enable/add strategy to go-guardian authenticator.
+const StrategyKey = auth.StrategyKey("sshcert.Strategy")
+
+// Headers carrying the certificate and the proof of possession.
+const (
+	// CertificateHeader carry the certificate in the authorized_keys format, or base64 encoded.
+	CertificateHeader = "X-SSH-Certificate"
+	// TimestampHeader carry the signature time, in seconds since Unix epoch.
+	TimestampHeader = "X-SSH-Timestamp"
+	// SignatureHeader carry the base64 encoded signature in the SSH wire format.
+	SignatureHeader = "X-SSH-Signature"
+)
+
+var (
+	// ErrMissingCertificate is returned by strategy when request missing X-SSH-Certificate header.
+	ErrMissingCertificate = gerrors.WithReason(
+		errors.New("strategies/sshcert: Missing X-SSH-Certificate header"),
+		gerrors.ReasonMissingCredentials,
+	)
+
+	// ErrInvalidCertificate is returned by strategy,
+	// when the certificate malformed, not a user certificate, not signed by a trusted CA,
+	// or its principals or critical options not accepted.
+	ErrInvalidCertificate = gerrors.WithReason(
+		errors.New("strategies/sshcert: Invalid certificate"),
+		gerrors.ReasonInvalidCredentials,
+	)
+
+	// ErrCertificateExpired is returned by strategy, when the certificate not yet valid or expired.
+	ErrCertificateExpired = gerrors.WithReason(
+		errors.New("strategies/sshcert: Certificate expired or not yet valid"),
+		gerrors.ReasonExpired,
+	)
+
+	// ErrInvalidSignature is returned by strategy,
+	// when the request signature missing, stale, or not signed by the certificate key.
+	ErrInvalidSignature = gerrors.WithReason(
+		errors.New("strategies/sshcert: Invalid request signature"),
+		gerrors.ReasonInvalidCredentials,
+	)
+)
+
+// InfoBuilder declare a function signature for building Info from certificate,
+// and the principal the certificate accepted for.
+type InfoBuilder func(c *Certificate, principal string) (auth.Info, error)
+
+// Builder define default InfoBuilder, where the principal mapped to UserName,
+// the certificate key id to ID, the certificate principals, serial, key id, and extensions,
+// mapped to Extensions, and the certificate validity mapped to the user validity window.
+var Builder = InfoBuilder(func(c *Certificate, principal string) (auth.Info, error) {
+	exts := map[string][]string{
+		"principals": c.ValidPrincipals,
+		"serial":     {strconv.FormatUint(c.Serial, 10)},
+		"key_id":     {c.KeyID},
+	}
+
+	for k := range c.Extensions {
+		exts["extensions"] = append(exts["extensions"], k)
+	}
+
+	info := auth.NewUserInfo(principal, c.KeyID, nil, exts)
+	auth.SetUserValidity(info, c.NotBefore(), c.NotAfter())
+
+	return info, nil
+})
+
+type strategy struct {
+	cas        []*PublicKey
+	principals map[string]struct{}
+	critical   map[string]struct{}
+	builder    InfoBuilder
+	leeway     time.Duration
+	signed     bool
+}
+
+func (s *strategy) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
+	v := r.Header.Get(CertificateHeader)
+	if len(v) == 0 {
+		return nil, ErrMissingCertificate
+	}
+
+	c, err := s.certificate(v)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if now.Add(s.leeway).Before(c.NotBefore()) ||
+		(!c.NotAfter().IsZero() && now.Add(-s.leeway).After(c.NotAfter())) {
+		return nil, ErrCertificateExpired
+	}
+
+	if !s.acceptOptions(r, c) {
+		return nil, ErrInvalidCertificate
+	}
+
+	principal, ok := s.principal(c)
+	if !ok {
+		return nil, ErrInvalidCertificate
+	}
+
+	if s.signed && !s.verifyRequest(r, c, now) {
+		return nil, ErrInvalidSignature
+	}
+
+	return s.builder(c, principal)
+}
+
+// certificate parse the given header value, and verify it's a user certificate signed by a trusted CA.
+func (s *strategy) certificate(v string) (*Certificate, error) {
+	fields := strings.Fields(v)
+	if len(fields) > 1 {
+		v = fields[1]
+	}
+
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, ErrInvalidCertificate
+	}
+
+	c, err := ParseCertificate(b)
+	if err != nil || c.CertType != UserCert {
+		return nil, ErrInvalidCertificate
+	}
+
+	for _, ca := range s.cas {
+		if equalKeys(ca, c.SignatureKey) && verify(ca.Key, c.signature, c.signed) {
+			return c, nil
+		}
+	}
+
+	return nil, ErrInvalidCertificate
+}
+
+// acceptOptions report whether all the certificate critical options understood and satisfied,
+// as required by PROTOCOL.certkeys.
+func (s *strategy) acceptOptions(r *http.Request, c *Certificate) bool {
+	for name, v := range c.CriticalOptions {
+		if name == "source-address" {
+			if !sourceAllowed(r, v) {
+				return false
+			}
+			continue
+		}
+
+		if _, ok := s.critical[name]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// principal return the first certificate principal accepted by the strategy.
+func (s *strategy) principal(c *Certificate) (string, bool) {
+	for _, p := range c.ValidPrincipals {
+		if _, ok := s.principals[p]; ok || len(s.principals) == 0 {
+			return p, true
+		}
+	}
+
+	return "", false
+}
+
+func (s *strategy) verifyRequest(r *http.Request, c *Certificate, now time.Time) bool {
+	ts := r.Header.Get(TimestampHeader)
+	sig, err := base64.StdEncoding.DecodeString(r.Header.Get(SignatureHeader))
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if d := now.Sub(time.Unix(sec, 0)); d > s.leeway || d < -s.leeway {
+		return false
+	}
+
+	return verify(c.Key, sig, signedMessage(r, ts))
+}
+
+// signedMessage return the request data signed by the client,
+// i.e timestamp, method, request URI, and host separated by new lines.
+func signedMessage(r *http.Request, ts string) []byte {
+	return []byte(ts + "\n" + r.Method + "\n" + r.URL.RequestURI() + "\n" + r.Host)
+}
+
+// sourceAllowed report whether the request remote address within the given comma separated CIDRs.
+func sourceAllowed(r *http.Request, v string) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range strings.Split(v, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if !strings.Contains(cidr, "/") {
+			if other := net.ParseIP(cidr); other != nil && other.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		if _, n, err := net.ParseCIDR(cidr); err == nil && n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SignRequest sets the given certificate, and the request signature using the certificate private key,
+// on the given request headers, Typically used by clients.
+// The certificate is in the SSH wire format, e.g the decoded second field of the -cert.pub file.
+func SignRequest(r *http.Request, cert []byte, key crypto.Signer) error {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	sig, err := sign(key, signedMessage(r, ts))
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(CertificateHeader, base64.StdEncoding.EncodeToString(cert))
+	r.Header.Set(TimestampHeader, ts)
+	r.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(sig))
+
+	return nil
+}
+
+// New return strategy authenticate request using OpenSSH user certificates,
+// signed by any of the given CAs public keys, See ParseAuthorizedKey.
+//
+// By default, the strategy accepts any certificate principal, requires the request signature,
+// and rejects certificates carrying critical options other than source-address.
+func New(cas []*PublicKey, opts ...auth.Option) auth.Strategy {
+	s := &strategy{
+		cas:        cas,
+		principals: make(map[string]struct{}),
+		critical:   make(map[string]struct{}),
+		builder:    Builder,
+		leeway:     time.Minute,
+		signed:     true,
+	}
+
+	for _, opt := range opts {
+		opt.Apply(s)
+	}
+
+	return s
+}
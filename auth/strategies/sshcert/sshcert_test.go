@@ -0,0 +1,221 @@
+package sshcert
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestParseCertificate(t *testing.T) {
+	c, err := ParseCertificate(readCert(t, "user-cert.pub"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "ecdsa-sha2-nistp256-cert-v01@openssh.com", c.Type)
+	assert.Equal(t, uint64(42), c.Serial)
+	assert.Equal(t, uint32(UserCert), c.CertType)
+	assert.Equal(t, "alice@example.com", c.KeyID)
+	assert.Equal(t, []string{"alice", "admins"}, c.ValidPrincipals)
+	assert.Equal(t, "127.0.0.1/32,10.0.0.0/8", c.CriticalOptions["source-address"])
+	assert.Contains(t, c.Extensions, "permit-pty")
+	assert.True(t, c.NotAfter().IsZero())
+	assert.Equal(t, "ssh-ed25519", c.SignatureKey.Type)
+
+	_, err = ParseCertificate(c.signed)
+	assert.Error(t, err)
+}
+
+func TestStrategy(t *testing.T) {
+	ca := readCA(t)
+	alice, bob := readKey(t, "user"), readKey(t, "bob")
+
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	blob, _ := marshalKey(other)
+	untrusted, _ := ParsePublicKey(blob)
+
+	table := []struct {
+		name     string
+		cas      []*PublicKey
+		opts     []auth.Option
+		cert     string
+		key      crypto.Signer
+		prepare  func(r *http.Request)
+		expected error
+		user     string
+	}{
+		{
+			name:     "it return error when certificate missing",
+			expected: ErrMissingCertificate,
+		},
+		{
+			name:     "it return error when certificate malformed",
+			prepare:  func(r *http.Request) { r.Header.Set(CertificateHeader, "invalid") },
+			expected: ErrInvalidCertificate,
+		},
+		{
+			name:     "it return error when certificate signed by untrusted ca",
+			cas:      []*PublicKey{untrusted},
+			cert:     "user-cert.pub",
+			key:      alice,
+			expected: ErrInvalidCertificate,
+		},
+		{
+			name:     "it return error when certificate expired",
+			cert:     "expired-cert.pub",
+			key:      bob,
+			expected: ErrCertificateExpired,
+		},
+		{
+			name:     "it return error when source address not allowed",
+			cert:     "user-cert.pub",
+			key:      alice,
+			prepare:  func(r *http.Request) { r.RemoteAddr = "192.0.2.1:1234" },
+			expected: ErrInvalidCertificate,
+		},
+		{
+			name:     "it return error when critical option unknown",
+			cert:     "bob-cert.pub",
+			key:      bob,
+			expected: ErrInvalidCertificate,
+		},
+		{
+			name: "it return user info when critical option accepted",
+			opts: []auth.Option{SetCriticalOptions("force-command")},
+			cert: "bob-cert.pub",
+			key:  bob,
+			user: "bob",
+		},
+		{
+			name:     "it return error when principal not accepted",
+			opts:     []auth.Option{SetPrincipals("root")},
+			cert:     "user-cert.pub",
+			key:      alice,
+			expected: ErrInvalidCertificate,
+		},
+		{
+			name: "it map accepted principal to user name",
+			opts: []auth.Option{SetPrincipals("admins")},
+			cert: "user-cert.pub",
+			key:  alice,
+			user: "admins",
+		},
+		{
+			name:     "it return error when request signed by another key",
+			cert:     "user-cert.pub",
+			key:      bob,
+			expected: ErrInvalidSignature,
+		},
+		{
+			name: "it return error when request signature stale",
+			cert: "user-cert.pub",
+			key:  alice,
+			prepare: func(r *http.Request) {
+				ts := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+				sig, _ := sign(alice, signedMessage(r, ts))
+				r.Header.Set(TimestampHeader, ts)
+				r.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(sig))
+			},
+			expected: ErrInvalidSignature,
+		},
+		{
+			name:     "it return error when request signature missing",
+			cert:     "user-cert.pub",
+			prepare:  func(r *http.Request) { r.Header.Set(CertificateHeader, readLine(t, "user-cert.pub")) },
+			expected: ErrInvalidSignature,
+		},
+		{
+			name:    "it return user info when signature not required",
+			opts:    []auth.Option{SetRequireSignature(false)},
+			prepare: func(r *http.Request) { r.Header.Set(CertificateHeader, readLine(t, "user-cert.pub")) },
+			user:    "alice",
+		},
+		{
+			name: "it return user info",
+			cert: "user-cert.pub",
+			key:  alice,
+			user: "alice",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			cas := tt.cas
+			if cas == nil {
+				cas = []*PublicKey{ca}
+			}
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/repos?page=1", nil)
+			r.RemoteAddr = "127.0.0.1:1234"
+
+			if tt.key != nil {
+				err := SignRequest(r, readCert(t, tt.cert), tt.key)
+				assert.NoError(t, err)
+			}
+
+			if tt.prepare != nil {
+				tt.prepare(r)
+			}
+
+			info, err := New(cas, tt.opts...).Authenticate(r.Context(), r)
+			assert.Equal(t, tt.expected, err)
+
+			if tt.expected == nil {
+				assert.Equal(t, tt.user, info.UserName())
+			}
+		})
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	c, _ := ParseCertificate(readCert(t, "user-cert.pub"))
+
+	info, err := Builder(c, "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", info.UserName())
+	assert.Equal(t, "alice@example.com", info.ID())
+	assert.Equal(t, []string{"alice", "admins"}, info.Extensions()["principals"])
+	assert.Equal(t, []string{"42"}, info.Extensions()["serial"])
+}
+
+func readLine(t *testing.T, name string) string {
+	b, err := ioutil.ReadFile("testdata/" + name)
+	assert.NoError(t, err)
+	return strings.TrimSpace(string(b))
+}
+
+func readCert(t *testing.T, name string) []byte {
+	b, err := base64.StdEncoding.DecodeString(strings.Fields(readLine(t, name))[1])
+	assert.NoError(t, err)
+	return b
+}
+
+func readCA(t *testing.T) *PublicKey {
+	k, err := ParseAuthorizedKey("@cert-authority *.example.com " + readLine(t, "ca.pub"))
+	assert.NoError(t, err)
+	return k
+}
+
+func readKey(t *testing.T, name string) crypto.Signer {
+	block, _ := pem.Decode([]byte(readLine(t, name)))
+
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k
+	}
+
+	k, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	assert.NoError(t, err)
+	return k
+}
@@ -2,10 +2,16 @@ package token
 
 import (
 	"context"
+	"encoding/gob"
+	"io"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/shaj13/go-guardian/auth"
 	"github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal"
+	"github.com/shaj13/go-guardian/internal/singleflight"
 	"github.com/shaj13/go-guardian/store"
 )
 
@@ -36,6 +42,7 @@ func New(auth AuthenticateFunc, c store.Cache, opts ...auth.Option) auth.Strateg
 		cache:    c,
 		typ:      Bearer,
 		parser:   AuthorizationParser(string(Bearer)),
+		flight:   new(singleflight.Group),
 	}
 
 	for _, opt := range opts {
@@ -45,54 +52,293 @@ func New(auth AuthenticateFunc, c store.Cache, opts ...auth.Option) auth.Strateg
 	return cached
 }
 
+// RevocationCheckFunc define function signature to check whether a cached token revoked upstream,
+// e.g using the issuer revocation list or introspection endpoint (RFC 7009).
+// The function invoked on each cache hit, therefore it should be cheap or cache its own results.
+type RevocationCheckFunc func(ctx context.Context, r *http.Request, token string, info auth.Info) (bool, error)
+
+func init() {
+	gob.Register(&cacheEntry{})
+}
+
+// cacheEntry wraps cached info with the time it validated at,
+// used only when max cache age enabled.
+type cacheEntry struct {
+	Info        auth.Info
+	ValidatedAt time.Time
+}
+
 type cachedToken struct {
 	parser   Parser
 	typ      Type
+	realm    string
 	cache    store.Cache
 	authFunc AuthenticateFunc
+	maxAge   time.Duration
+	stale    time.Duration
+	ahead    time.Duration
+	revoked  RevocationCheckFunc
+	hashKeys bool
+
+	flight     *singleflight.Group
+	mu         sync.Mutex
+	refreshing map[string]struct{}
+	wg         sync.WaitGroup
+	closed     bool
+	ctx        context.Context
+	cancel     context.CancelFunc
 }
 
 func (c *cachedToken) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
-	token, err := c.parser.Token(r)
+	return c.AuthenticateRequest(ctx, auth.NetHTTPRequest(r))
+}
+
+// AuthenticateRequest authenticate the given transport agnostic request,
+// the authenticate function and the cache receive auth.HTTPRequest of it.
+func (c *cachedToken) AuthenticateRequest(ctx context.Context, r auth.Request) (auth.Info, error) {
+	token, err := requestToken(c.parser, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.authenticateToken(ctx, auth.HTTPRequest(r), token)
+}
+
+// AuthenticateToken authenticate the given token without an HTTP request,
+// the authenticate function and the cache receive a bare request carrying only ctx.
+func (c *cachedToken) AuthenticateToken(ctx context.Context, token string) (auth.Info, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	info, ok, err := c.cache.Load(token, r)
+	return c.authenticateToken(ctx, r, token)
+}
+
+func (c *cachedToken) authenticateToken(
+	ctx context.Context,
+	r *http.Request,
+	token string,
+) (auth.Info, error) {
+	v, ok, err := c.cache.Load(c.key(token), r)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if ok {
+		v, ok, err = c.checkCached(ctx, r, token, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// if token not found invoke user authenticate function
 	if !ok {
-		info, err = c.authFunc(ctx, r, token)
-		if err == nil {
-			// cache result
-			err = c.cache.Store(token, info, r)
-		}
+		v, err = c.authenticate(ctx, r, token)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	if _, ok := info.(auth.Info); !ok {
-		return nil, errors.NewInvalidType((*auth.Info)(nil), info)
+	info, ok := v.(auth.Info)
+	if !ok {
+		return nil, errors.NewInvalidType((*auth.Info)(nil), v)
+	}
+
+	return info, nil
+}
+
+// authenticate invoke user authenticate function and cache its result,
+// concurrent calls for the same token share a single invocation unless singleflight disabled.
+func (c *cachedToken) authenticate(ctx context.Context, r *http.Request, token string) (interface{}, error) {
+	fn := func() (interface{}, error) {
+		info, err := c.authFunc(ctx, r, token)
+		if err != nil {
+			return nil, err
+		}
+		// cache result
+		return info, c.store(token, info, r)
+	}
+
+	if c.flight == nil {
+		return fn()
+	}
+
+	v, err, _ := c.flight.Do(token, fn)
+	return v, err
+}
+
+// checkCached unwrap cached value and honor max cache age and upstream revocation,
+// the ok result reports whether the cached value still valid.
+func (c *cachedToken) checkCached(
+	ctx context.Context,
+	r *http.Request,
+	token string,
+	v interface{},
+) (interface{}, bool, error) {
+	if e, ok := v.(*cacheEntry); ok {
+		age := time.Since(e.ValidatedAt)
+		switch {
+		case c.maxAge <= 0:
+		case age > c.maxAge+c.stale:
+			return nil, false, nil
+		case age > c.maxAge, c.ahead > 0 && age > c.maxAge-c.ahead:
+			c.revalidate(r, token)
+		}
+		v = e.Info
+	}
+
+	info, ok := v.(auth.Info)
+	if !ok || c.revoked == nil {
+		return v, true, nil
+	}
+
+	revoked, err := c.revoked(ctx, r, token, info)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if revoked {
+		_ = c.cache.Delete(c.key(token), r)
+		return nil, false, ErrTokenRevoked
 	}
 
-	return info.(auth.Info), nil
+	return v, true, nil
+}
+
+// revalidate re-validate the token in the background and refresh or drop its cached result,
+// at most one background validation per token runs at a time.
+// The validation bounded by the staleness budget or the refresh ahead window, whichever longer,
+// since afterwards the token re-validated synchronously, and canceled once the strategy closed.
+// The cached result dropped only if the token rejected, i.e transient upstream failures
+// keep serving the stale result until the staleness budget exceeded.
+func (c *cachedToken) revalidate(r *http.Request, token string) {
+	c.mu.Lock()
+	if c.refreshing == nil {
+		c.refreshing = make(map[string]struct{})
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+	}
+
+	if _, ok := c.refreshing[token]; ok || c.closed {
+		c.mu.Unlock()
+		return
+	}
+
+	c.refreshing[token] = struct{}{}
+	c.wg.Add(1)
+	c.mu.Unlock()
+
+	timeout := c.stale
+	if c.ahead > timeout {
+		timeout = c.ahead
+	}
+
+	// the request context canceled once the request served.
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	r = r.Clone(ctx)
+
+	go func() {
+		defer c.wg.Done()
+		defer cancel()
+		defer func() {
+			c.mu.Lock()
+			delete(c.refreshing, token)
+			c.mu.Unlock()
+		}()
+
+		info, err := c.authFunc(ctx, r, token)
+		if err != nil {
+			if rejected(err) {
+				_ = c.cache.Delete(c.key(token), r)
+			}
+			return
+		}
+
+		_ = c.store(token, info, r)
+	}()
+}
+
+// rejected reports whether err reject the token credentials,
+// as opposed to transient failures, e.g upstream timeouts.
+func rejected(err error) bool {
+	switch errors.ReasonOf(err) {
+	case errors.ReasonMissingCredentials,
+		errors.ReasonMalformed,
+		errors.ReasonInvalidCredentials,
+		errors.ReasonExpired,
+		errors.ReasonRevoked,
+		errors.ReasonLockedOut:
+		return true
+	}
+	return false
+}
+
+// Close stop starting background re-validations, cancel and wait for the running ones,
+// and close the cache if it owns background resources.
+//
+// NOTICE: a cache shared with other strategies closed too.
+func (c *cachedToken) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	if c.cancel != nil {
+		c.cancel()
+	}
+	c.mu.Unlock()
+
+	c.wg.Wait()
+
+	if cl, ok := c.cache.(io.Closer); ok {
+		return cl.Close()
+	}
+
+	return nil
+}
+
+func (c *cachedToken) store(token string, info auth.Info, r *http.Request) error {
+	if c.maxAge > 0 {
+		return c.cache.Store(c.key(token), &cacheEntry{Info: info, ValidatedAt: time.Now()}, r)
+	}
+	return c.cache.Store(c.key(token), info, r)
+}
+
+// key return the cache key of the given token.
+func (c *cachedToken) key(token string) string {
+	if c.hashKeys {
+		return internal.HashKey(token)
+	}
+	return token
 }
 
 func (c *cachedToken) Append(token string, info auth.Info, r *http.Request) error {
-	return c.cache.Store(token, info, r)
+	return c.store(token, info, r)
+}
+
+// Purge drop the token cached validation result,
+// so the next request re-validate the token using the authenticate function.
+func (c *cachedToken) Purge(token string, r *http.Request) error {
+	return c.cache.Delete(c.key(token), r)
 }
 
 func (c *cachedToken) Revoke(token string, r *http.Request) error {
-	return c.cache.Delete(token, r)
+	return c.cache.Delete(c.key(token), r)
+}
+
+func (c *cachedToken) Renew(token string, r *http.Request) error {
+	info, ok, err := c.cache.Load(c.key(token), r)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrTokenNotFound
+	}
+
+	return c.cache.Store(c.key(token), info, r)
 }
 
-func (c *cachedToken) Challenge(realm string) string { return challenge(realm, c.typ) }
+func (c *cachedToken) Challenge(realm string) string { return challenge(realm, c.realm, c.typ) }
 
 // NoOpAuthenticate implements Authenticate function, it return nil, auth.ErrNOOP,
 // commonly used when token refreshed/mangaed directly using cache or Append function,
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -114,6 +117,433 @@ func TestCahcedTokenAppend(t *testing.T) {
 	assert.Equal(t, info, cachedInfo)
 }
 
+func TestCahcedTokenRenew(t *testing.T) {
+	cache := make(mockCache)
+	strategy := &cachedToken{cache: cache}
+	info := auth.NewDefaultUser("1", "2", nil, nil)
+
+	err := strategy.Renew("test-renew", nil)
+	assert.Equal(t, ErrTokenNotFound, err)
+
+	err = strategy.Renew("error", nil)
+	assert.Error(t, err)
+
+	_ = strategy.Append("test-renew", info, nil)
+	err = strategy.Renew("test-renew", nil)
+	assert.NoError(t, err)
+
+	cachedInfo, ok, _ := cache.Load("test-renew", nil)
+	assert.True(t, ok)
+	assert.Equal(t, info, cachedInfo)
+}
+
+func TestCachedTokenRevocation(t *testing.T) {
+	table := []struct {
+		name    string
+		opts    []auth.Option
+		wait    time.Duration
+		calls   int
+		err     error
+		revoked bool
+	}{
+		{
+			name:  "it serve cached token",
+			calls: 1,
+		},
+		{
+			name:  "it re-validate token when max cache age exceeded",
+			opts:  []auth.Option{SetMaxCacheAge(time.Millisecond)},
+			wait:  time.Millisecond * 5,
+			calls: 2,
+		},
+		{
+			name:  "it serve cached token when max cache age not exceeded",
+			opts:  []auth.Option{SetMaxCacheAge(time.Hour)},
+			calls: 1,
+		},
+		{
+			name:    "it return error and purge token when revoked upstream",
+			revoked: true,
+			calls:   1,
+			err:     ErrTokenRevoked,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			fn := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+				calls++
+				return auth.NewDefaultUser("test", "1", nil, nil), nil
+			}
+
+			check := func(ctx context.Context, r *http.Request, token string, info auth.Info) (bool, error) {
+				return tt.revoked, nil
+			}
+
+			cache := store.New(0)
+			opts := append(tt.opts, SetRevocationCheck(check))
+			strategy := New(fn, cache, opts...)
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer token")
+
+			_, err := strategy.Authenticate(r.Context(), r)
+			assert.NoError(t, err)
+
+			time.Sleep(tt.wait)
+
+			_, err = strategy.Authenticate(r.Context(), r)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.calls, calls)
+
+			if tt.revoked {
+				_, ok, _ := cache.Load("token", r)
+				assert.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestCachedTokenStaleWhileRevalidate(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+		err   error
+	)
+
+	fn := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return auth.NewDefaultUser("test", "1", nil, nil), err
+	}
+
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+
+	cache := store.New(0)
+	strategy := New(fn, cache, SetMaxCacheAge(time.Millisecond), SetStaleWhileRevalidate(time.Hour))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer token")
+
+	_, _ = strategy.Authenticate(r.Context(), r)
+	time.Sleep(time.Millisecond * 5)
+
+	// stale result served while re-validated in the background.
+	info, e := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, e)
+	assert.Equal(t, "1", info.ID())
+	assert.Eventually(t, func() bool { return count() == 2 }, time.Second, time.Millisecond)
+
+	// transient background validation failure keep the stale result.
+	mu.Lock()
+	err = fmt.Errorf("upstream unavailable")
+	mu.Unlock()
+
+	time.Sleep(time.Millisecond * 5)
+	_, _ = strategy.Authenticate(r.Context(), r)
+	assert.Eventually(t, func() bool { return count() == 3 }, time.Second, time.Millisecond)
+
+	info, e = strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, e)
+	assert.Equal(t, "1", info.ID())
+	assert.Eventually(t, func() bool { return count() == 4 }, time.Second, time.Millisecond)
+
+	// rejected token drop the cached result.
+	mu.Lock()
+	err = ErrTokenNotFound
+	mu.Unlock()
+
+	time.Sleep(time.Millisecond * 5)
+	_, _ = strategy.Authenticate(r.Context(), r)
+
+	assert.Eventually(t, func() bool {
+		_, ok, _ := cache.Load("token", r)
+		return !ok
+	}, time.Second, time.Millisecond)
+}
+
+func TestCachedTokenClose(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	fn := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			<-release
+		}
+		return auth.NewDefaultUser("test", "1", nil, nil), nil
+	}
+
+	cache := store.NewFIFO(context.Background(), time.Hour)
+	strategy := New(fn, cache, SetMaxCacheAge(time.Millisecond), SetStaleWhileRevalidate(time.Hour))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer token")
+
+	_, _ = strategy.Authenticate(r.Context(), r)
+	time.Sleep(time.Millisecond * 5)
+
+	// trigger a background re-validation blocked until released.
+	_, _ = strategy.Authenticate(r.Context(), r)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, time.Second, time.Millisecond)
+
+	done := make(chan error)
+	go func() { done <- auth.Close(strategy) }()
+
+	select {
+	case <-done:
+		t.Fatal("Close returned before background re-validation finished")
+	case <-time.After(time.Millisecond * 10):
+	}
+
+	close(release)
+	assert.NoError(t, <-done)
+
+	// no background re-validation started once closed.
+	_, err := strategy.Authenticate(r.Context(), r)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond * 5)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCachedTokenCloseCancel(t *testing.T) {
+	var calls int32
+
+	fn := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+		if atomic.AddInt32(&calls, 1) > 1 {
+			// hung upstream.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return auth.NewDefaultUser("test", "1", nil, nil), nil
+	}
+
+	cache := store.New(0)
+	strategy := New(fn, cache, SetMaxCacheAge(time.Millisecond), SetStaleWhileRevalidate(time.Hour))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer token")
+
+	_, _ = strategy.Authenticate(r.Context(), r)
+	time.Sleep(time.Millisecond * 5)
+
+	_, _ = strategy.Authenticate(r.Context(), r)
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, time.Second, time.Millisecond)
+
+	done := make(chan error)
+	go func() { done <- auth.Close(strategy) }()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on hung background re-validation")
+	}
+
+	// canceled re-validation keep the stale result.
+	_, ok, _ := cache.Load("token", r)
+	assert.True(t, ok)
+}
+
+func TestCachedTokenRefreshAhead(t *testing.T) {
+	table := []struct {
+		name     string
+		window   time.Duration
+		expected int32
+	}{
+		{name: "it refresh token accessed within window", window: time.Hour, expected: 2},
+		{name: "it does not refresh token accessed outside window", window: time.Millisecond, expected: 1},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			fn := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+				atomic.AddInt32(&calls, 1)
+				return auth.NewDefaultUser("test", "1", nil, nil), nil
+			}
+
+			cache := store.New(0)
+			strategy := New(fn, cache, SetMaxCacheAge(time.Hour), SetRefreshAhead(tt.window))
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer token")
+
+			_, _ = strategy.Authenticate(r.Context(), r)
+			v, _, _ := cache.Load("token", r)
+			validatedAt := v.(*cacheEntry).ValidatedAt
+
+			// cached result served while refreshed in the background.
+			info, err := strategy.Authenticate(r.Context(), r)
+			assert.NoError(t, err)
+			assert.Equal(t, "1", info.ID())
+
+			assert.Eventually(t, func() bool {
+				return atomic.LoadInt32(&calls) == tt.expected
+			}, time.Second, time.Millisecond)
+
+			if tt.expected > 1 {
+				assert.Eventually(t, func() bool {
+					v, _, _ := cache.Load("token", r)
+					return v.(*cacheEntry).ValidatedAt.After(validatedAt)
+				}, time.Second, time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestCachedTokenSingleflight(t *testing.T) {
+	table := []struct {
+		name     string
+		opts     []auth.Option
+		expected int32
+	}{
+		{
+			name:     "it invoke authenticate function once for concurrent requests",
+			expected: 1,
+		},
+		{
+			name:     "it invoke authenticate function per request when singleflight disabled",
+			opts:     []auth.Option{SetSingleflight(false)},
+			expected: 10,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := int32(0)
+			release := make(chan struct{})
+
+			fn := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return auth.NewDefaultUser("test", "1", nil, nil), nil
+			}
+
+			strategy := New(fn, store.New(0), tt.opts...)
+			wg := sync.WaitGroup{}
+
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					r, _ := http.NewRequest("GET", "/", nil)
+					r.Header.Set("Authorization", "Bearer token")
+					info, err := strategy.Authenticate(r.Context(), r)
+					assert.NoError(t, err)
+					assert.Equal(t, "1", info.ID())
+				}()
+			}
+
+			time.Sleep(time.Millisecond * 50)
+			close(release)
+			wg.Wait()
+
+			assert.Equal(t, tt.expected, atomic.LoadInt32(&calls))
+		})
+	}
+}
+
+func TestCachedTokenPurge(t *testing.T) {
+	cache := store.New(0)
+	strategy := New(NoOpAuthenticate, cache)
+
+	_ = auth.Append(strategy, "token", auth.NewDefaultUser("test", "1", nil, nil), nil)
+	assert.NoError(t, auth.PurgeToken(strategy, "token", nil))
+
+	_, ok, _ := cache.Load("token", nil)
+	assert.False(t, ok)
+}
+
+func TestCachedTokenHashedKeys(t *testing.T) {
+	cache := store.New(0)
+	calls := 0
+	authFunc := func(_ context.Context, _ *http.Request, _ string) (auth.Info, error) {
+		calls++
+		return auth.NewDefaultUser("test", "1", nil, nil), nil
+	}
+	strategy := New(authFunc, cache, SetHashedKeys(true))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer token")
+
+	for i := 0; i < 2; i++ {
+		info, err := strategy.Authenticate(r.Context(), r)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", info.ID())
+	}
+
+	// SHA-256 of "token".
+	key := "3c469e9d6c5875d37a43f353d4f88e61fcf812c66eee3457465a40b0da4153e0"
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, []string{key}, cache.Keys())
+
+	_ = auth.Append(strategy, "appended", auth.NewDefaultUser("test", "2", nil, nil), nil)
+	_, ok, _ := cache.Load("appended", nil)
+	assert.False(t, ok)
+
+	assert.NoError(t, auth.Revoke(strategy, "token", nil))
+	_, ok, _ = cache.Load(key, nil)
+	assert.False(t, ok)
+}
+
+func TestCachedTokenAuthenticateToken(t *testing.T) {
+	var calls int32
+	authFunc := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+		atomic.AddInt32(&calls, 1)
+		if token != "token" || r.Context() != ctx {
+			return nil, ErrTokenNotFound
+		}
+		return auth.NewDefaultUser("test", "1", nil, nil), nil
+	}
+
+	strategy := New(authFunc, store.New(0))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		info, err := auth.AuthenticateToken(ctx, strategy, "token")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", info.ID())
+	}
+
+	_, err := auth.AuthenticateToken(ctx, strategy, "invalid")
+	assert.Equal(t, ErrTokenNotFound, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCachedTokenAuthenticateRequest(t *testing.T) {
+	authFunc := func(ctx context.Context, r *http.Request, token string) (auth.Info, error) {
+		if token != "token" || r.Header.Get("X-Id") != "1" {
+			return nil, ErrTokenNotFound
+		}
+		return auth.NewDefaultUser("test", "1", nil, nil), nil
+	}
+
+	strategy := New(authFunc, store.New(0))
+	req := auth.MetadataRequest(context.Background(), map[string][]string{
+		"authorization": {"Bearer token"},
+		"x-id":          {"1"},
+	}, nil, nil)
+
+	info, err := auth.AuthenticateWith(strategy, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+
+	req = auth.MetadataRequest(context.Background(), map[string][]string{}, nil, nil)
+	_, err = auth.AuthenticateWith(strategy, req)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestCachedTokenManager(t *testing.T) {
+	strategy := New(NoOpAuthenticate, make(mockCache))
+	assert.Implements(t, (*auth.TokenManager)(nil), strategy)
+}
+
 func TestCahcedTokenChallenge(t *testing.T) {
 	strategy := &cachedToken{
 		typ: Bearer,
@@ -123,6 +553,12 @@ func TestCahcedTokenChallenge(t *testing.T) {
 	expected := `Bearer realm="Test Realm", title="Bearer Token Based Authentication Scheme"`
 
 	assert.Equal(t, expected, got)
+
+	SetRealm("Strategy Realm").Apply(strategy)
+	got = strategy.Challenge("")
+	expected = `Bearer realm="Strategy Realm", title="Bearer Token Based Authentication Scheme"`
+
+	assert.Equal(t, expected, got)
 }
 
 func BenchmarkCachedToken(b *testing.B) {
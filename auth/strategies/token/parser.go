@@ -2,7 +2,9 @@ package token
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/shaj13/go-guardian/auth"
 	"github.com/shaj13/go-guardian/internal"
 )
 
@@ -17,22 +19,51 @@ func (fn tokenFn) Token(r *http.Request) (string, error) {
 	return fn(r)
 }
 
+// RequestParser is implemented by parsers extracting token from the transport agnostic auth.Request,
+// e.g header, authorization, and cookie parsers.
+type RequestParser interface {
+	Parser
+	RequestToken(r auth.Request) (string, error)
+}
+
+type requestTokenFn func(r auth.Request) (string, error)
+
+func (fn requestTokenFn) Token(r *http.Request) (string, error) {
+	return fn(auth.NetHTTPRequest(r))
+}
+
+func (fn requestTokenFn) RequestToken(r auth.Request) (string, error) {
+	return fn(r)
+}
+
+// requestToken extract token from the given request using the parser,
+// directly if the parser implements RequestParser, Otherwise, via auth.HTTPRequest.
+func requestToken(p Parser, r auth.Request) (string, error) {
+	if rp, ok := p.(RequestParser); ok {
+		return rp.RequestToken(r)
+	}
+
+	return p.Token(auth.HTTPRequest(r))
+}
+
 // XHeaderParser return a token parser, where token extracted form "X-" header.
 func XHeaderParser(header string) Parser {
-	fn := func(r *http.Request) (string, error) {
-		return internal.ParseHeader(header, r, ErrInvalidToken)
+	// canonicalize once, so header lookups does not allocate per request.
+	header = http.CanonicalHeaderKey(header)
+	fn := func(r auth.Request) (string, error) {
+		return internal.ParseHeaderValue(header, r.Header(), ErrInvalidToken)
 	}
 
-	return tokenFn(fn)
+	return requestTokenFn(fn)
 }
 
 // AuthorizationParser return a token parser, where token extracted form Authorization header.
 func AuthorizationParser(key string) Parser {
-	fn := func(r *http.Request) (string, error) {
-		return internal.ParseAuthorizationHeader(key, r, ErrInvalidToken)
+	fn := func(r auth.Request) (string, error) {
+		return internal.ParseAuthorizationHeaderValue(key, r.Header(), ErrInvalidToken)
 	}
 
-	return tokenFn(fn)
+	return requestTokenFn(fn)
 }
 
 // QueryParser return a token parser, where token extracted form HTTP query string.
@@ -46,9 +77,18 @@ func QueryParser(key string) Parser {
 
 // CookieParser return a token parser, where token extracted form HTTP Cookie.
 func CookieParser(key string) Parser {
-	fn := func(r *http.Request) (string, error) {
-		return internal.ParseCookie(key, r, ErrInvalidToken)
+	fn := func(r auth.Request) (string, error) {
+		cookie, err := r.Cookie(key)
+		if err != nil {
+			return "", err
+		}
+
+		if value := strings.TrimSpace(cookie.Value); len(value) > 0 {
+			return value, nil
+		}
+
+		return "", ErrInvalidToken
 	}
 
-	return tokenFn(fn)
+	return requestTokenFn(fn)
 }
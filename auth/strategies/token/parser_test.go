@@ -1,10 +1,13 @@
 package token
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
 )
 
 func TestParser(t *testing.T) {
@@ -133,3 +136,63 @@ func TestParser(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestParser(t *testing.T) {
+	req := auth.MetadataRequest(context.Background(), map[string][]string{
+		"x-api-token":   {"api-token"},
+		"authorization": {"Bearer token"},
+		"cookie":        {"token=cookie-token; empty= "},
+	}, nil, nil)
+
+	table := []struct {
+		name   string
+		parser Parser
+		err    error
+		token  string
+	}{
+		{
+			name:   "XHeaderParser return token",
+			parser: XHeaderParser("x-api-token"),
+			token:  "api-token",
+		},
+		{
+			name:   "AuthorizationParser return token",
+			parser: AuthorizationParser("Bearer"),
+			token:  "token",
+		},
+		{
+			name:   "AuthorizationParser return error when key mismatch",
+			parser: AuthorizationParser("Basic"),
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "CookieParser return token",
+			parser: CookieParser("token"),
+			token:  "cookie-token",
+		},
+		{
+			name:   "CookieParser return error when cookie empty",
+			parser: CookieParser("empty"),
+			err:    ErrInvalidToken,
+		},
+		{
+			name:   "CookieParser return error when cookie missing",
+			parser: CookieParser("missing"),
+			err:    http.ErrNoCookie,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := requestToken(tt.parser, req)
+			assert.Implements(t, (*RequestParser)(nil), tt.parser)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.token, token)
+		})
+	}
+
+	// query parser does not implement RequestParser, so the request converted.
+	token, err := requestToken(QueryParser("token"), req)
+	assert.Equal(t, ErrInvalidToken, err)
+	assert.Empty(t, token)
+}
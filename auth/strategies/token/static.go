@@ -23,17 +23,29 @@ type Static struct {
 	Tokens map[string]auth.Info
 	Type   Type
 	Parser Parser
+	// Realm used in the challenge when the realm passed at challenge time empty.
+	Realm string
 }
 
 // Authenticate user request against predefined tokens by verifying request token existence in the static Map.
 // Once token found auth.Info returned with a nil error,
 // Otherwise, a nil auth.Info and ErrTokenNotFound returned.
 func (s *Static) Authenticate(ctx context.Context, r *http.Request) (auth.Info, error) {
-	token, err := s.Parser.Token(r)
+	return s.AuthenticateRequest(ctx, auth.NetHTTPRequest(r))
+}
+
+// AuthenticateRequest behaves as Authenticate, but extract the token from the transport agnostic request.
+func (s *Static) AuthenticateRequest(ctx context.Context, r auth.Request) (auth.Info, error) {
+	token, err := requestToken(s.Parser, r)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.AuthenticateToken(ctx, token)
+}
+
+// AuthenticateToken verify the given token existence in the static Map, without an HTTP request.
+func (s *Static) AuthenticateToken(_ context.Context, token string) (auth.Info, error) {
 	s.MU.Lock()
 	defer s.MU.Unlock()
 	info, ok := s.Tokens[token]
@@ -61,9 +73,21 @@ func (s *Static) Revoke(token string, _ *http.Request) error {
 	return nil
 }
 
+// Renew verify token existence in static store, since static tokens never expire.
+func (s *Static) Renew(token string, _ *http.Request) error {
+	s.MU.Lock()
+	defer s.MU.Unlock()
+
+	if _, ok := s.Tokens[token]; !ok {
+		return ErrTokenNotFound
+	}
+
+	return nil
+}
+
 // Challenge returns string indicates the authentication scheme.
 // Typically used to adds a HTTP WWW-Authenticate header.
-func (s *Static) Challenge(realm string) string { return challenge(realm, s.Type) }
+func (s *Static) Challenge(realm string) string { return challenge(realm, s.Realm, s.Type) }
 
 // NewStaticFromFile returns static auth.Strategy, populated from a CSV file.
 // The CSV file must contain records in one of following formats
@@ -81,7 +105,8 @@ func NewStaticFromFile(path string, opts ...auth.Option) (auth.Strategy, error)
 	reader := csv.NewReader(file)
 	reader.FieldsPerRecord = -1
 
-	for {
+	// errors refer to the record number, since records contain tokens.
+	for n := 1; ; n++ {
 		record, err := reader.Read()
 
 		if err == io.EOF {
@@ -94,20 +119,20 @@ func NewStaticFromFile(path string, opts ...auth.Option) (auth.Strategy, error)
 
 		if len(record) < 3 {
 			return nil, fmt.Errorf(
-				"static: record must have at least 3 columns (token, username, id), Record: %v",
-				record,
+				"static: record must have at least 3 columns (token, username, id), Record: %d",
+				n,
 			)
 		}
 
 		if record[0] == "" {
-			return nil, fmt.Errorf("static: a non empty token is required, Record: %v", record)
+			return nil, fmt.Errorf("static: a non empty token is required, Record: %d", n)
 		}
 
 		// if token Contains Bearer remove it
 		record[0] = strings.TrimPrefix(record[0], "Bearer ")
 
 		if _, ok := tokens[record[0]]; ok {
-			return nil, fmt.Errorf("static: token already exists, Record: %v", record)
+			return nil, fmt.Errorf("static: token already exists, Record: %d", n)
 		}
 
 		info := auth.NewUserInfo(record[1], record[2], nil, nil)
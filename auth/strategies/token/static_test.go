@@ -115,6 +115,28 @@ func TestNewStatic(t *testing.T) {
 	}
 }
 
+func TestStaticAuthenticateToken(t *testing.T) {
+	strategy := NewStatic(map[string]auth.Info{
+		"token": auth.NewDefaultUser("test", "1", nil, nil),
+	})
+
+	info, err := auth.AuthenticateToken(context.Background(), strategy, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+
+	_, err = auth.AuthenticateToken(context.Background(), strategy, "unknown")
+	assert.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestStaticRenew(t *testing.T) {
+	strategy := NewStatic(map[string]auth.Info{
+		"token": auth.NewDefaultUser("test", "1", nil, nil),
+	}).(auth.TokenManager)
+
+	assert.NoError(t, strategy.Renew("token", nil))
+	assert.Equal(t, ErrTokenNotFound, strategy.Renew("unknown", nil))
+}
+
 func TestStaticChallenge(t *testing.T) {
 	strategy := &Static{
 		Type: Bearer,
@@ -124,6 +146,12 @@ func TestStaticChallenge(t *testing.T) {
 	expected := `Bearer realm="Test Realm", title="Bearer Token Based Authentication Scheme"`
 
 	assert.Equal(t, expected, got)
+
+	SetRealm("Strategy Realm").Apply(strategy)
+	got = strategy.Challenge("")
+	expected = `Bearer realm="Strategy Realm", title="Bearer Token Based Authentication Scheme"`
+
+	assert.Equal(t, expected, got)
 }
 
 func BenchmarkStaticToken(b *testing.B) {
@@ -146,3 +174,15 @@ func BenchmarkStaticToken(b *testing.B) {
 		}
 	})
 }
+
+func TestStaticAuthenticateRequest(t *testing.T) {
+	info := auth.NewDefaultUser("1", "1", nil, nil)
+	strategy := NewStatic(map[string]auth.Info{"1": info})
+	req := auth.MetadataRequest(context.Background(), map[string][]string{
+		"authorization": {"Bearer 1"},
+	}, nil, nil)
+
+	got, err := auth.AuthenticateWith(strategy, req)
+	assert.NoError(t, err)
+	assert.Equal(t, info, got)
+}
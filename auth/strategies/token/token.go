@@ -3,17 +3,29 @@ package token
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/internal/singleflight"
 )
 
 var (
 	// ErrInvalidToken indicate a hit of an invalid token format.
 	// And it's returned by Token Parser.
-	ErrInvalidToken = errors.New("strategies/token: Invalid token")
+	ErrInvalidToken = gerrors.WithReason(
+		errors.New("strategies/token: Invalid token"),
+		gerrors.ReasonMissingCredentials,
+	)
 	// ErrTokenNotFound is returned by authenticating functions for token strategies,
 	// when token not found in their store.
-	ErrTokenNotFound = errors.New("strategies/token: Token does not exists")
+	ErrTokenNotFound = gerrors.WithReason(
+		errors.New("strategies/token: Token does not exists"),
+		gerrors.ReasonInvalidCredentials,
+	)
+	// ErrTokenRevoked is returned by cached strategy,
+	// when the revocation check reports the cached token revoked upstream.
+	ErrTokenRevoked = gerrors.WithReason(errors.New("strategies/token: Token revoked"), gerrors.ReasonRevoked)
 )
 
 // Type is Authentication token type or scheme. A common type is Bearer.
@@ -51,6 +63,106 @@ func SetParser(p Parser) auth.Option {
 	})
 }
 
-func challenge(realm string, t Type) string {
+// SetMaxCacheAge sets the max duration a cached token validation result trusted,
+// once exceeded the token re-validated using the authenticate function,
+// regardless of the cache entries lifetime, so revoked upstream tokens stop working quickly.
+// SetMaxCacheAge applies only to the cached token strategy.
+func SetMaxCacheAge(d time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedToken); ok {
+			v.maxAge = d
+		}
+	})
+}
+
+// SetStaleWhileRevalidate sets the staleness budget a cached token validation result
+// served after max cache age exceeded, while the token re-validated in the background,
+// to smooth over upstream (e.g introspection, JWKS, TokenReview) latency spikes.
+// Once the budget exceeded the token re-validated synchronously.
+// SetStaleWhileRevalidate has no effect unless max cache age sets, See SetMaxCacheAge.
+// SetStaleWhileRevalidate applies only to the cached token strategy.
+func SetStaleWhileRevalidate(budget time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedToken); ok {
+			v.stale = budget
+		}
+	})
+}
+
+// SetRefreshAhead sets the window before max cache age exceeded,
+// where accessed tokens re-validated in the background while the cached result served,
+// so hot tokens never pay the upstream latency at expiry boundaries.
+// Re-validated tokens stored again, which also renews the cache entries lifetime,
+// thus set the max cache age equal or less than the cache TTL.
+// SetRefreshAhead has no effect unless max cache age sets, See SetMaxCacheAge.
+// SetRefreshAhead applies only to the cached token strategy.
+func SetRefreshAhead(window time.Duration) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedToken); ok {
+			v.ahead = window
+		}
+	})
+}
+
+// SetRevocationCheck sets function to check whether a cached token revoked upstream,
+// revoked tokens purged from the cache and ErrTokenRevoked returned.
+// SetRevocationCheck applies only to the cached token strategy.
+func SetRevocationCheck(fn RevocationCheckFunc) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedToken); ok {
+			v.revoked = fn
+		}
+	})
+}
+
+// SetSingleflight enable or disable duplicate suppression of the authenticate function calls,
+// when enabled, concurrent requests carrying the same uncached token results in one upstream call,
+// and share its result, including errors caused by the first request context cancellation.
+// SetSingleflight applies only to the cached token strategy.
+// Default enabled.
+func SetSingleflight(enabled bool) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedToken); ok {
+			v.flight = nil
+			if enabled {
+				v.flight = new(singleflight.Group)
+			}
+		}
+	})
+}
+
+// SetHashedKeys enable or disable keying cache entries by the SHA-256 of the token,
+// instead of the raw token, so tokens never sit as plaintext keys in shared caches (e.g Redis, Disk),
+// and long tokens (e.g JWT) shrink to a fixed size key.
+// Use the strategy Append function to add tokens, as raw tokens stored directly in the cache never match.
+// SetHashedKeys applies only to the cached token strategy.
+// Default disabled.
+func SetHashedKeys(enabled bool) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		if v, ok := v.(*cachedToken); ok {
+			v.hashKeys = enabled
+		}
+	})
+}
+
+// SetRealm sets the strategy protection space realm,
+// used in the challenge when the realm passed at challenge time empty.
+// Typically used by deployments having multiple realms, so each strategy challenge its own realm.
+func SetRealm(realm string) auth.Option {
+	return auth.OptionFunc(func(v interface{}) {
+		switch v := v.(type) {
+		case *Static:
+			v.Realm = realm
+		case *cachedToken:
+			v.realm = realm
+		}
+	})
+}
+
+func challenge(realm, defaultRealm string, t Type) string {
+	if len(realm) == 0 {
+		realm = defaultRealm
+	}
+
 	return fmt.Sprintf(`%s realm="%s", title="%s Token Based Authentication Scheme"`, t, realm, t)
 }
@@ -0,0 +1,136 @@
+package twofactor
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+// EventKind represents a second factor event kind.
+type EventKind string
+
+const (
+	// EventFailure emitted when the user-supplied an invalid one time password.
+	EventFailure EventKind = "failure"
+	// EventLockout emitted when the one time password verification refused,
+	// because the user locked out, e.g otp.ErrMaxAttempts or otp.VerificationDisabledError.
+	EventLockout EventKind = "lockout"
+	// EventRecoveryCode emitted when the user signed in using a recovery code,
+	// See Strategy.RecoveryCodeUsed.
+	EventRecoveryCode EventKind = "recovery_code"
+)
+
+// Event represents a second factor event,
+// emitted to detect abuse such as OTP brute-force campaigns.
+type Event struct {
+	Kind EventKind
+	// User is the user authenticated by the first factor.
+	User auth.Info
+	// Err is the verification error, if any.
+	Err  error
+	Time time.Time
+}
+
+// EventFunc define function signature invoked by the strategy on each second factor event,
+// Typically used to record audit events and metrics, See Monitor.
+type EventFunc func(ctx context.Context, r *http.Request, e Event)
+
+// AlertFunc define function signature invoked by Monitor when events count reaches a threshold,
+// user is empty when the global threshold reached.
+type AlertFunc func(kind EventKind, user string, count int)
+
+// Monitor counts second factor events per user and globally within a fixed window,
+// and invoke Alert once per window when a count reaches its threshold,
+// so security teams alert on OTP brute-force campaigns, either targeting a user or spread across users.
+//
+//	m := &twofactor.Monitor{Window: time.Hour, UserThreshold: 10, GlobalThreshold: 1000, Alert: alert}
+//	strategy := twofactor.Strategy{Primary: basic, Parser: p, Manager: mng, Events: m.Observe}
+type Monitor struct {
+	// Window define the counting window.
+	// Default 1 hour.
+	Window time.Duration
+	// UserThreshold define the per user events count to alert at, zero disables it.
+	UserThreshold int
+	// GlobalThreshold define the global events count to alert at, zero disables it.
+	GlobalThreshold int
+	// Alert invoked when a threshold reached.
+	Alert AlertFunc
+
+	mu     sync.Mutex
+	start  time.Time
+	global map[EventKind]int
+	users  map[EventKind]map[string]int
+}
+
+// Observe count the given event, and satisfies EventFunc.
+func (m *Monitor) Observe(_ context.Context, _ *http.Request, e Event) {
+	user := ""
+	if e.User != nil {
+		user = e.User.ID()
+	}
+
+	now := e.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	m.mu.Lock()
+	m.roll(now)
+
+	m.global[e.Kind]++
+	global := m.global[e.Kind]
+
+	if m.users[e.Kind] == nil {
+		m.users[e.Kind] = make(map[string]int)
+	}
+
+	m.users[e.Kind][user]++
+	count := m.users[e.Kind][user]
+	m.mu.Unlock()
+
+	if m.Alert == nil {
+		return
+	}
+
+	if m.UserThreshold > 0 && count == m.UserThreshold {
+		m.Alert(e.Kind, user, count)
+	}
+
+	if m.GlobalThreshold > 0 && global == m.GlobalThreshold {
+		m.Alert(e.Kind, "", global)
+	}
+}
+
+// Count return the given kind events count of the given user within the current window,
+// or the global count when user is empty.
+func (m *Monitor) Count(kind EventKind, user string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roll(time.Now())
+
+	if len(user) == 0 {
+		return m.global[kind]
+	}
+
+	return m.users[kind][user]
+}
+
+// roll resets the counts once the window elapsed.
+func (m *Monitor) roll(now time.Time) {
+	window := m.Window
+	if window <= 0 {
+		window = time.Hour
+	}
+
+	if m.global != nil && now.Sub(m.start) < window {
+		return
+	}
+
+	m.start = now
+	m.global = make(map[EventKind]int)
+	m.users = make(map[EventKind]map[string]int)
+}
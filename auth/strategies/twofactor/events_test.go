@@ -0,0 +1,112 @@
+package twofactor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/otp"
+)
+
+func TestStrategyEvents(t *testing.T) {
+	table := []struct {
+		name     string
+		ok       bool
+		err      error
+		expected []EventKind
+	}{
+		{
+			name:     "it emit failure when otp invalid",
+			expected: []EventKind{EventFailure},
+		},
+		{
+			name:     "it emit lockout when user locked out",
+			err:      otp.ErrMaxAttempts,
+			expected: []EventKind{EventLockout},
+		},
+		{
+			name:     "it emit lockout when verification disabled",
+			err:      otp.VerificationDisabledError(time.Minute),
+			expected: []EventKind{EventLockout},
+		},
+		{
+			name: "it does not emit when otp valid",
+			ok:   true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			var kinds []EventKind
+
+			o := &mockOTP{mock.Mock{}}
+			o.On("Verify").Return(tt.ok, tt.err)
+
+			mng := &mockManager{mock.Mock{}}
+			mng.On("Enabled").Return(true)
+			mng.On("Load").Return(o, nil)
+			mng.On("Store").Return(nil)
+
+			s := Strategy{
+				Parser:  XHeaderParser("X-OTP"),
+				Manager: mng,
+				Events: func(_ context.Context, _ *http.Request, e Event) {
+					assert.Equal(t, "1", e.User.ID())
+					kinds = append(kinds, e.Kind)
+				},
+			}
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Set("X-OTP", "123456")
+			_, _ = s.Verify(r.Context(), r, auth.NewUserInfo("alice", "1", nil, nil))
+
+			assert.Equal(t, tt.expected, kinds)
+		})
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	type alert struct {
+		user  string
+		count int
+	}
+
+	var alerts []alert
+
+	m := &Monitor{
+		Window:          time.Minute,
+		UserThreshold:   2,
+		GlobalThreshold: 3,
+		Alert: func(kind EventKind, user string, count int) {
+			assert.Equal(t, EventFailure, kind)
+			alerts = append(alerts, alert{user, count})
+		},
+	}
+
+	alice := auth.NewUserInfo("alice", "1", nil, nil)
+	bob := auth.NewUserInfo("bob", "2", nil, nil)
+	now := time.Now()
+
+	s := Strategy{Events: m.Observe}
+	s.RecoveryCodeUsed(context.Background(), nil, alice)
+	assert.Equal(t, 1, m.Count(EventRecoveryCode, "1"))
+
+	for _, info := range []auth.Info{alice, alice, alice, bob} {
+		m.Observe(context.Background(), nil, Event{Kind: EventFailure, User: info, Time: now})
+	}
+
+	assert.Equal(t, 3, m.Count(EventFailure, "1"))
+	assert.Equal(t, 1, m.Count(EventFailure, "2"))
+	assert.Equal(t, 4, m.Count(EventFailure, ""))
+	assert.Equal(t, []alert{{"1", 2}, {"", 3}}, alerts)
+
+	// window elapsed.
+	m.Observe(context.Background(), nil, Event{Kind: EventFailure, User: bob, Time: now.Add(time.Hour)})
+	assert.Equal(t, 0, m.Count(EventFailure, "1"))
+	assert.Equal(t, 1, m.Count(EventFailure, "2"))
+}
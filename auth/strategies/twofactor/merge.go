@@ -0,0 +1,59 @@
+package twofactor
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+const (
+	// ExtensionAMR represents a key for the authentication methods references in info extensions,
+	// as described in RFC 8176.
+	ExtensionAMR = "amr"
+	// ExtensionAuthTime represents a key for the authentication time in info extensions,
+	// the value is the number of seconds since Unix epoch.
+	ExtensionAuthTime = "auth_time"
+)
+
+// MergeFunc define function signature to merge metadata from both factors into the resulting user info,
+// invoked by the strategy once the primary strategy and one-time password verification succeeds.
+type MergeFunc func(info auth.Info, otp OTP) (auth.Info, error)
+
+// AMRMerge return MergeFunc that adds the authentication methods references
+// and the authentication time to the resulting user info extensions.
+// The primary argument represents the primary strategy method reference (e.g "pwd"),
+// and ignored if user info already carries authentication methods references.
+// The second factor method reference is always "otp".
+//
+// The returned info is a new info object, so the primary strategy cached info does not mutated.
+func AMRMerge(primary string) MergeFunc {
+	return func(info auth.Info, _ OTP) (auth.Info, error) {
+		exts := make(map[string][]string)
+		for k, v := range info.Extensions() {
+			exts[k] = v
+		}
+
+		amr := exts[ExtensionAMR]
+		if len(amr) == 0 && len(primary) > 0 {
+			amr = []string{primary}
+		}
+
+		exts[ExtensionAMR] = appendUnique(amr, "otp")
+		exts[ExtensionAuthTime] = []string{strconv.FormatInt(time.Now().UTC().Unix(), 10)}
+
+		return auth.NewUserInfo(info.UserName(), info.ID(), info.Groups(), exts), nil
+	}
+}
+
+func appendUnique(s []string, v string) []string {
+	for _, e := range s {
+		if e == v {
+			return s
+		}
+	}
+
+	c := make([]string, len(s), len(s)+1)
+	copy(c, s)
+	return append(c, v)
+}
@@ -0,0 +1,77 @@
+package twofactor
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/shaj13/go-guardian/auth"
+)
+
+func TestAMRMerge(t *testing.T) {
+	table := []struct {
+		name     string
+		primary  string
+		exts     map[string][]string
+		expected []string
+	}{
+		{
+			name:     "it adds primary and otp methods",
+			primary:  "pwd",
+			expected: []string{"pwd", "otp"},
+		},
+		{
+			name:     "it keeps existing methods",
+			primary:  "pwd",
+			exts:     map[string][]string{ExtensionAMR: {"mtls"}},
+			expected: []string{"mtls", "otp"},
+		},
+		{
+			name:     "it does not duplicate otp method",
+			exts:     map[string][]string{ExtensionAMR: {"pwd", "otp"}},
+			expected: []string{"pwd", "otp"},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			info := auth.NewDefaultUser("test", "1", []string{"admin"}, tt.exts)
+
+			merged, err := AMRMerge(tt.primary)(info, nil)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, merged.Extensions()[ExtensionAMR])
+			assert.NotEmpty(t, merged.Extensions()[ExtensionAuthTime])
+			assert.Equal(t, info.Groups(), merged.Groups())
+			assert.Equal(t, tt.exts, info.Extensions())
+		})
+	}
+}
+
+func TestStrategyMerge(t *testing.T) {
+	m := &mockStrategy{mock.Mock{}}
+	m.On("Authenticate").Return(nil, nil)
+	otp := &mockOTP{mock.Mock{}}
+	otp.On("Verify").Return(true, nil)
+	mng := &mockManager{mock.Mock{}}
+	mng.On("Enabled").Return(true)
+	mng.On("Load").Return(otp, nil)
+	mng.On("Store").Return(nil)
+
+	s := Strategy{
+		Primary: m,
+		Manager: mng,
+		Parser:  XHeaderParser("X-TEST-OTP"),
+		Merge:   AMRMerge("pwd"),
+	}
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("X-TEST-OTP", "123456")
+
+	info, err := s.Authenticate(r.Context(), r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pwd", "otp"}, info.Extensions()[ExtensionAMR])
+}
@@ -4,12 +4,16 @@ import (
 	"errors"
 	"net/http"
 
+	gerrors "github.com/shaj13/go-guardian/errors"
 	"github.com/shaj13/go-guardian/internal"
 )
 
 // ErrMissingPin is returned by Parser,
 // When one-time password missing or empty in HTTP request.
-var ErrMissingPin = errors.New("strategies/twofactor: One-time password missing or empty")
+var ErrMissingPin = gerrors.WithReason(
+	errors.New("strategies/twofactor: One-time password missing or empty"),
+	gerrors.ReasonMissingCredentials,
+)
 
 // Parser parse and extract one-time password from incoming HTTP request.
 type Parser interface {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
 )
 
 // StrategyKey export identifier for the two factor strategy,
@@ -14,7 +16,10 @@ const StrategyKey = auth.StrategyKey("2FA.Strategy")
 
 // ErrInvalidPin is returned by strategy,
 // When the user-supplied an invalid one time password and verification process failed.
-var ErrInvalidPin = errors.New("strategies/twofactor: Invalid one time password")
+var ErrInvalidPin = gerrors.WithReason(
+	errors.New("strategies/twofactor: Invalid one time password"),
+	gerrors.ReasonInvalidCredentials,
+)
 
 // OTP represents one-time password verification.
 type OTP interface {
@@ -39,6 +44,13 @@ type Strategy struct {
 	Primary auth.Strategy
 	Parser  Parser
 	Manager OTPManager
+	// Merge optionally merge metadata from both factors into the resulting user info.
+	// if nil the primary strategy user info returned as is.
+	// See AMRMerge.
+	Merge MergeFunc
+	// Events optionally invoked on second factor failures and lockouts,
+	// to record audit events and metrics, See Monitor.
+	Events EventFunc
 }
 
 // Authenticate returns user info or error by authenticating request using primary strategy,
@@ -49,6 +61,13 @@ func (s Strategy) Authenticate(ctx context.Context, r *http.Request) (auth.Info,
 		return nil, err
 	}
 
+	return s.Verify(ctx, r, info)
+}
+
+// Verify verifies the one-time password of the user authenticated by another strategy,
+// and implements auth.SecondFactor, so the strategy used without a primary strategy in composition,
+// e.g auth.Compose().Try(basic).RequireSecondFactor(twofactor.Strategy{Parser: p, Manager: m}).
+func (s Strategy) Verify(ctx context.Context, r *http.Request, info auth.Info) (auth.Info, error) {
 	if !s.Manager.Enabled(info) {
 		return info, nil
 	}
@@ -67,12 +86,32 @@ func (s Strategy) Authenticate(ctx context.Context, r *http.Request) (auth.Info,
 
 	ok, err := otp.Verify(pin)
 	if err != nil {
+		if gerrors.ReasonOf(err) == gerrors.ReasonLockedOut {
+			s.emit(ctx, r, EventLockout, info, err)
+		}
 		return nil, err
 	}
 
 	if !ok {
+		s.emit(ctx, r, EventFailure, info, ErrInvalidPin)
 		return nil, ErrInvalidPin
 	}
 
+	if s.Merge != nil {
+		return s.Merge(info, otp)
+	}
+
 	return info, nil
 }
+
+// RecoveryCodeUsed emits EventRecoveryCode of the given user,
+// invoked by the application when the user signed in using a recovery code instead of a one time password.
+func (s Strategy) RecoveryCodeUsed(ctx context.Context, r *http.Request, info auth.Info) {
+	s.emit(ctx, r, EventRecoveryCode, info, nil)
+}
+
+func (s Strategy) emit(ctx context.Context, r *http.Request, kind EventKind, info auth.Info, err error) {
+	if s.Events != nil {
+		s.Events(ctx, r, Event{Kind: kind, User: info, Err: err, Time: time.Now()})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"net/http"
 
 	"github.com/shaj13/go-guardian/auth"
+	gerrors "github.com/shaj13/go-guardian/errors"
 )
 
 // StrategyKey export identifier for the x509 strategy,
@@ -20,7 +21,10 @@ var (
 	// ErrMissingCN is returned by DefaultBuilder when Certificate CommonName missing.
 	ErrMissingCN = errors.New("x509.strategy: Certificate subject CN missing")
 	// ErrInvalidRequest is returned by x509 strategy when a non TLS request received.
-	ErrInvalidRequest = errors.New("x509.strategy: Invalid request, missing TLS parameters")
+	ErrInvalidRequest = gerrors.WithReason(
+		errors.New("x509.strategy: Invalid request, missing TLS parameters"),
+		gerrors.ReasonMissingCredentials,
+	)
 )
 
 // InfoBuilder declare a function signature for building Info from certificate chain.
@@ -29,7 +33,8 @@ type InfoBuilder func(chain [][]*x509.Certificate) (auth.Info, error)
 // Builder define default InfoBuilder by building Info from certificate chain subject.
 // where the subject values mapped  in the following format,
 // CommonName to UserName, SerialNumber to ID, Organization to groups
-// and country, postalCode, streetAddress, locality, province mapped to Extensions.
+// and country, postalCode, streetAddress, locality, province mapped to Extensions,
+// and the certificate NotBefore and NotAfter mapped to the user validity window.
 var Builder = InfoBuilder(func(chain [][]*x509.Certificate) (auth.Info, error) {
 	subject := chain[0][0].Subject
 
@@ -45,12 +50,16 @@ var Builder = InfoBuilder(func(chain [][]*x509.Certificate) (auth.Info, error) {
 		"province":      subject.Province,
 	}
 
-	return auth.NewUserInfo(
+	info := auth.NewUserInfo(
 		subject.CommonName,
 		subject.SerialNumber,
 		subject.Organization,
 		exts,
-	), nil
+	)
+
+	auth.SetUserValidity(info, chain[0][0].NotBefore, chain[0][0].NotAfter)
+
+	return info, nil
 })
 
 type authenticateFunc func() x509.VerifyOptions
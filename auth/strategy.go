@@ -3,7 +3,11 @@ package auth
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+	"github.com/shaj13/go-guardian/store"
 )
 
 // ErrInvalidStrategy is returned by Append/Revoke functions,
@@ -19,6 +23,27 @@ type Strategy interface {
 	Authenticate(ctx context.Context, r *http.Request) (Info, error)
 }
 
+// TokenManager is implemented by strategies that store tokens,
+// and allow application code to pre-provision, revoke, and renew tokens
+// without importing each strategy concrete type.
+type TokenManager interface {
+	// Append new Info to a strategy store.
+	Append(token string, info Info, r *http.Request) error
+	// Revoke delete Info from strategy store.
+	Revoke(token string, r *http.Request) error
+	// Renew re-store the token Info to extend its lifetime in strategy store,
+	// if token does not exist in strategy store an error returned.
+	Renew(token string, r *http.Request) error
+}
+
+// TokenStrategy is implemented by strategies that authenticate a raw token,
+// so message-queue consumers, CLI tools, and gRPC services can validate a credential they already extracted,
+// without an HTTP request.
+type TokenStrategy interface {
+	// AuthenticateToken authenticate the given token and return user information or error.
+	AuthenticateToken(ctx context.Context, token string) (Info, error)
+}
+
 // Option configures Strategy using the functional options paradigm popularized by Rob Pike and Dave Cheney.
 // If you're unfamiliar with this style,
 // see https://commandcenter.blogspot.com/2014/01/self-referential-functions-and-design.html and
@@ -41,34 +66,172 @@ func (fn OptionFunc) Apply(v interface{}) {
 //
 // WARNING: Append function does not guarantee safe concurrency, It's natively depends on strategy store.
 func Append(s Strategy, key string, info Info, r *http.Request) error {
-	u, ok := s.(interface {
-		Append(key string, info Info, r *http.Request) error
-	})
+	for ; s != nil; s = Unwrap(s) {
+		u, ok := s.(interface {
+			Append(key string, info Info, r *http.Request) error
+		})
 
-	if ok {
-		return u.Append(key, info, r)
+		if ok {
+			return u.Append(key, info, r)
+		}
 	}
 
 	return ErrInvalidStrategy
 }
 
+// Preload append the iterator records to the strategy cache and return the number of appended records,
+// the records keys (e.g tokens) and values (user information) appended using Append function.
+// Typically used at startup to repopulate the strategy cache from a persistent store or a snapshot,
+// so the first requests after a deploy don't all pay a cold validation.
+// Preload stops at the first error, and return ErrInvalidStrategy if strategy does not implement Append.
+func Preload(s Strategy, it store.Iterator) (int, error) {
+	n := 0
+
+	for it.Next() {
+		key, v := it.Record()
+
+		info, ok := v.(Info)
+		if !ok {
+			return n, gerrors.NewInvalidType((*Info)(nil), v)
+		}
+
+		if err := Append(s, key, info, nil); err != nil {
+			return n, err
+		}
+
+		n++
+	}
+
+	return n, it.Err()
+}
+
+// AuthenticateToken authenticate the given token using the passed strategy.
+// if passed strategy does not implement AuthenticateToken type ErrInvalidStrategy returned.
+// Decorators wrapping the strategy (e.g RateLimit, CircuitBreaker) applied to the token authentication.
+func AuthenticateToken(ctx context.Context, s Strategy, token string) (Info, error) {
+	for ; s != nil; s = Unwrap(s) {
+		if u, ok := s.(TokenStrategy); ok {
+			return u.AuthenticateToken(ctx, token)
+		}
+	}
+
+	return nil, ErrInvalidStrategy
+}
+
 // Revoke delete Info from strategy store.
 // if passed strategy does not implement Revoke type ErrInvalidStrategy returned,
 // Otherwise, nil.
 //
 // WARNING: Revoke function does not guarantee safe concurrency, It's natively depends on strategy store.
 func Revoke(s Strategy, key string, r *http.Request) error {
-	u, ok := s.(interface {
-		Revoke(key string, r *http.Request) error
-	})
+	for ; s != nil; s = Unwrap(s) {
+		u, ok := s.(interface {
+			Revoke(key string, r *http.Request) error
+		})
+
+		if ok {
+			return u.Revoke(key, r)
+		}
+	}
+
+	return ErrInvalidStrategy
+}
+
+// Renew re-store Info in a strategy store to extend its lifetime.
+// if passed strategy does not implement Renew type ErrInvalidStrategy returned,
+// Otherwise, nil.
+//
+// WARNING: Renew function does not guarantee safe concurrency, It's natively depends on strategy store.
+func Renew(s Strategy, key string, r *http.Request) error {
+	for ; s != nil; s = Unwrap(s) {
+		u, ok := s.(interface {
+			Renew(key string, r *http.Request) error
+		})
+
+		if ok {
+			return u.Renew(key, r)
+		}
+	}
+
+	return ErrInvalidStrategy
+}
 
-	if ok {
-		return u.Revoke(key, r)
+// PurgeToken drop the cached validation result of a token from a strategy cache,
+// so the next request re-validate the token upstream, typically used once
+// the token revoked by its issuer (RFC 7009).
+// if passed strategy does not implement Purge type ErrInvalidStrategy returned,
+// Otherwise, nil.
+func PurgeToken(s Strategy, token string, r *http.Request) error {
+	for ; s != nil; s = Unwrap(s) {
+		u, ok := s.(interface {
+			Purge(token string, r *http.Request) error
+		})
+
+		if ok {
+			return u.Purge(token, r)
+		}
 	}
 
 	return ErrInvalidStrategy
 }
 
+// Close release the background resources owned by the strategy and the strategies it wraps,
+// (e.g cache garbage collectors and background token re-validations),
+// by calling Close on each of them that implements io.Closer.
+// All the closers called even if one fails, and their errors aggregated.
+func Close(s Strategy) error {
+	errs := gerrors.MultiError{}
+
+	for ; s != nil; s = Unwrap(s) {
+		c, ok := s.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Challenge return string indicates the strategy authentication scheme,
+// if passed strategy or the strategy it wraps contains an Challenge method call it.
+// The ok result indicates whether strategy have a challenge.
+func Challenge(s Strategy, realm string) (string, bool) {
+	for ; s != nil; s = Unwrap(s) {
+		u, ok := s.(interface {
+			Challenge(string) string
+		})
+
+		if ok {
+			return u.Challenge(realm), true
+		}
+	}
+
+	return "", false
+}
+
+// Challenges return the challenges of the authenticator registered strategies keyed by strategy key,
+// strategies that does not have a challenge ignored.
+// Typically used to document the supported authentication schemes.
+func Challenges(a Authenticator, realm string) map[StrategyKey]string {
+	m := make(map[StrategyKey]string)
+
+	for k, s := range a.Strategies() {
+		if c, ok := Challenge(s, realm); ok {
+			m[k] = c
+		}
+	}
+
+	return m
+}
+
 // SetWWWAuthenticate adds a HTTP WWW-Authenticate header to the provided ResponseWriter's headers.
 // by consolidating the result of calling Challenge methods on provided strategies.
 // if strategy contains an Challenge method call it.
@@ -81,12 +244,8 @@ func SetWWWAuthenticate(w http.ResponseWriter, realm string, strategies ...Strat
 	}
 
 	for _, s := range strategies {
-		u, ok := s.(interface {
-			Challenge(string) string
-		})
-
-		if ok {
-			str = str + u.Challenge(realm) + ", "
+		if c, ok := Challenge(s, realm); ok {
+			str = str + c + ", "
 		}
 	}
 
@@ -2,11 +2,15 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/store"
 )
 
 func TestAppendRevoke(t *testing.T) {
@@ -35,6 +39,26 @@ func TestAppendRevoke(t *testing.T) {
 			name:        "it call append, when strategy valid",
 			expectedErr: false,
 		},
+		{
+			funcName:    "renew",
+			name:        "it return error when strategy, not of type bearer",
+			expectedErr: true,
+		},
+		{
+			funcName:    "renew",
+			name:        "it call renew, when strategy valid",
+			expectedErr: false,
+		},
+		{
+			funcName:    "purge",
+			name:        "it return error when strategy, does not implement purge",
+			expectedErr: true,
+		},
+		{
+			funcName:    "purge",
+			name:        "it call purge, when strategy valid",
+			expectedErr: false,
+		},
 	}
 
 	for _, tt := range table {
@@ -52,6 +76,10 @@ func TestAppendRevoke(t *testing.T) {
 				err = Append(strategy, "", nil, nil)
 			case "revoke":
 				err = Revoke(strategy, "", nil)
+			case "renew":
+				err = Renew(strategy, "", nil)
+			case "purge":
+				err = PurgeToken(strategy, "", nil)
 			default:
 				t.Errorf("Unsupported function %s", tt.funcName)
 				return
@@ -66,6 +94,47 @@ func TestAppendRevoke(t *testing.T) {
 	}
 }
 
+func TestPreload(t *testing.T) {
+	table := []struct {
+		name        string
+		strategy    Strategy
+		records     map[string]interface{}
+		n           int
+		expectedErr bool
+	}{
+		{
+			name:     "it append all records",
+			strategy: new(mockStrategy),
+			records: map[string]interface{}{
+				"1": NewDefaultUser("1", "1", nil, nil),
+				"2": NewDefaultUser("2", "2", nil, nil),
+			},
+			n: 2,
+		},
+		{
+			name:        "it return error when strategy does not implement append",
+			strategy:    new(mockInvalidStrategy),
+			records:     map[string]interface{}{"1": NewDefaultUser("1", "1", nil, nil)},
+			expectedErr: true,
+		},
+		{
+			name:        "it return error when record value not of type info",
+			strategy:    new(mockStrategy),
+			records:     map[string]interface{}{"1": NewDefaultUser("1", "1", nil, nil), "2": "2"},
+			n:           1,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Preload(tt.strategy, store.MapIterator(tt.records))
+			assert.Equal(t, tt.expectedErr, err != nil)
+			assert.Equal(t, tt.n, n)
+		})
+	}
+}
+
 func TestSetWWWAuthenticate(t *testing.T) {
 	var (
 		basic   = &mockStrategy{challenge: `Basic realm="test"`}
@@ -111,6 +180,62 @@ func TestSetWWWAuthenticate(t *testing.T) {
 	}
 }
 
+func TestClose(t *testing.T) {
+	inner := &mockCloser{}
+	outer := &mockCloser{err: errors.New("close")}
+
+	chain := Decorate(inner, Timeout(time.Second))
+	chain = &mockWrapper{mockCloser: outer, next: chain}
+
+	err := Close(chain)
+	assert.True(t, errors.Is(err, outer.err))
+	assert.True(t, inner.closed)
+	assert.True(t, outer.closed)
+
+	assert.NoError(t, Close(new(mockStrategy)))
+
+	step, fallback := &mockCloser{}, &mockCloser{}
+	assert.NoError(t, Close(Compose().Try(new(mockStrategy), step).Fallback(fallback).MustBuild()))
+	assert.True(t, step.closed)
+	assert.True(t, fallback.closed)
+}
+
+func TestAuthenticatorClose(t *testing.T) {
+	first, second := &mockCloser{err: errors.New("first")}, &mockCloser{}
+
+	a := New()
+	a.EnableStrategy("1", first)
+	a.EnableStrategy("2", second)
+	a.EnableStrategy("3", new(mockStrategy))
+
+	err := a.Close()
+	assert.True(t, errors.Is(err, first.err))
+	assert.True(t, first.closed)
+	assert.True(t, second.closed)
+
+	assert.NoError(t, New().Close())
+}
+
+type mockCloser struct {
+	mockStrategy
+	err    error
+	closed bool
+}
+
+func (m *mockCloser) Close() error {
+	m.closed = true
+	return m.err
+}
+
+type mockWrapper struct {
+	*mockCloser
+	next Strategy
+}
+
+func (m *mockWrapper) Unwrap() Strategy {
+	return m.next
+}
+
 type mockStrategy struct {
 	called    bool
 	challenge string
@@ -129,6 +254,16 @@ func (m *mockStrategy) Revoke(token string, r *http.Request) error {
 	return nil
 }
 
+func (m *mockStrategy) Renew(token string, r *http.Request) error {
+	m.called = true
+	return nil
+}
+
+func (m *mockStrategy) Purge(token string, r *http.Request) error {
+	m.called = true
+	return nil
+}
+
 func (m *mockStrategy) Challenge(string) string {
 	return m.challenge
 }
@@ -138,3 +273,18 @@ type mockInvalidStrategy struct{}
 func (m *mockInvalidStrategy) Authenticate(ctx context.Context, r *http.Request) (Info, error) {
 	return nil, nil
 }
+
+func TestChallenges(t *testing.T) {
+	a := New()
+	a.EnableStrategy("basic", &mockStrategy{challenge: `Basic realm="test"`})
+	a.EnableStrategy("invalid", new(mockInvalidStrategy))
+
+	c, ok := Challenge(a.Strategy("basic"), "test")
+	assert.True(t, ok)
+	assert.Equal(t, `Basic realm="test"`, c)
+
+	_, ok = Challenge(a.Strategy("invalid"), "test")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[StrategyKey]string{"basic": `Basic realm="test"`}, Challenges(a, "test"))
+}
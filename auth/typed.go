@@ -0,0 +1,113 @@
+//go:build go1.18
+// +build go1.18
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+// TypedAuthenticator is similar to Authenticator,
+// but return the user information as the concrete type T,
+// So callers does not need to assert the returned Info type.
+//
+// TypedAuthenticator requires go1.18 or later.
+type TypedAuthenticator[T Info] interface {
+	// Authenticate dispatch the request to the registered authentication strategies,
+	// and return user information as T from the first strategy that successfully authenticates the request.
+	// if returned user information not of type T, an InvalidType error returned.
+	// See Authenticator.Authenticate documentation for more info.
+	Authenticate(r *http.Request) (T, error)
+	// AuthenticateToken dispatch the token to the registered token strategies,
+	// and return user information as T, See Authenticator.AuthenticateToken documentation for more info.
+	AuthenticateToken(ctx context.Context, token string) (T, error)
+	// EnableStrategy register a new strategy to the authenticator.
+	EnableStrategy(key StrategyKey, strategy Strategy)
+	// DisableStrategy unregister a strategy from the authenticator.
+	DisableStrategy(key StrategyKey)
+	// SetStrategyTimeout sets the max duration of a strategy authentication attempt.
+	SetStrategyTimeout(key StrategyKey, d time.Duration)
+	// Strategy return a registered strategy, Otherwise, nil.
+	Strategy(key StrategyKey) Strategy
+	// Strategies return a copy of the registered strategies map.
+	Strategies() map[StrategyKey]Strategy
+	// StrategyKeys return the registered strategies keys sorted in increasing order.
+	StrategyKeys() []StrategyKey
+	// DisabledPaths return a map[string]struct{} represents a paths disabled from authentication.
+	DisabledPaths() map[string]struct{}
+	// Close release the background resources owned by the registered strategies,
+	// See Authenticator.Close documentation for more info.
+	Close() error
+	// Untyped return the underlying Authenticator.
+	Untyped() Authenticator
+}
+
+type typedAuthenticator[T Info] struct {
+	Authenticator
+}
+
+func (t typedAuthenticator[T]) Authenticate(r *http.Request) (T, error) {
+	info, err := t.Authenticator.Authenticate(r)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return As[T](info)
+}
+
+func (t typedAuthenticator[T]) AuthenticateToken(ctx context.Context, token string) (T, error) {
+	info, err := t.Authenticator.AuthenticateToken(ctx, token)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return As[T](info)
+}
+
+func (t typedAuthenticator[T]) Untyped() Authenticator {
+	return t.Authenticator
+}
+
+// NewTyped return new TypedAuthenticator and disables authentication process at a given paths.
+// The returned authenticator not safe for concurrent access.
+func NewTyped[T Info](paths ...string) TypedAuthenticator[T] {
+	return Typed[T](New(paths...))
+}
+
+// Typed wraps the given Authenticator and return TypedAuthenticator,
+// Typically used to keep the existing interface based API while,
+// obtaining the concrete user type from Authenticate.
+func Typed[T Info](a Authenticator) TypedAuthenticator[T] {
+	return typedAuthenticator[T]{a}
+}
+
+// As assert the given user information to the concrete type T,
+// if info is not of type T, an InvalidType error returned.
+func As[T Info](info Info) (T, error) {
+	v, ok := info.(T)
+	if !ok {
+		var zero T
+		return zero, gerrors.NewInvalidType((*T)(nil), info)
+	}
+
+	return v, nil
+}
+
+// UserAs return user information of type T from request context.
+// The ok result indicates whether user information found and it's of type T.
+func UserAs[T Info](r *http.Request) (T, bool) {
+	return UserFromCtxAs[T](r.Context())
+}
+
+// UserFromCtxAs return user information of type T from context.
+// The ok result indicates whether user information found and it's of type T.
+func UserFromCtxAs[T Info](ctx context.Context) (T, bool) {
+	v, ok := UserFromCtx(ctx).(T)
+	return v, ok
+}
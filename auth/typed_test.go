@@ -0,0 +1,106 @@
+//go:build go1.18
+// +build go1.18
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/errors"
+)
+
+type customUser struct {
+	*DefaultUser
+	email string
+}
+
+func TestTypedAuthenticator(t *testing.T) {
+	table := []struct {
+		name        string
+		strategy    Strategy
+		expectedErr bool
+		userID      string
+	}{
+		{
+			name:        "it return error when strategy return error",
+			strategy:    strategy{returnErr: true},
+			expectedErr: true,
+		},
+		{
+			name:        "it return error when info not of type T",
+			strategy:    infoStrategy{info: customUser{DefaultUser: NewDefaultUser("test", "1", nil, nil)}},
+			expectedErr: true,
+		},
+		{
+			name:     "it return info as T",
+			strategy: strategy{id: "1"},
+			userID:   "1",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := NewTyped[*DefaultUser]()
+			authenticator.EnableStrategy("test", tt.strategy)
+			r, _ := http.NewRequest("GET", "/", nil)
+
+			info, err := authenticator.Authenticate(r)
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				assert.Nil(t, info)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.userID, info.id)
+		})
+	}
+}
+
+func TestTypedAuthenticateToken(t *testing.T) {
+	authenticator := NewTyped[*DefaultUser]()
+	authenticator.EnableStrategy("test", tokenStrategy{strategy: strategy{id: "1"}, token: "token"})
+
+	info, err := authenticator.AuthenticateToken(context.Background(), "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.id)
+
+	info, err = authenticator.AuthenticateToken(context.Background(), "invalid")
+	assert.Error(t, err)
+	assert.Nil(t, info)
+}
+
+func TestTypedUntyped(t *testing.T) {
+	a := New()
+	assert.Equal(t, a, Typed[*DefaultUser](a).Untyped())
+}
+
+func TestAs(t *testing.T) {
+	info := customUser{DefaultUser: NewDefaultUser("test", "1", nil, nil), email: "test@example.com"}
+
+	u, err := As[customUser](info)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", u.email)
+
+	_, err = As[*DefaultUser](info)
+	assert.IsType(t, errors.InvalidType{}, err)
+}
+
+func TestUserAs(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+
+	_, ok := UserAs[*DefaultUser](r)
+	assert.False(t, ok)
+
+	info := NewDefaultUser("test", "1", nil, nil)
+	r = RequestWithUser(info, r)
+
+	u, ok := UserAs[*DefaultUser](r)
+	assert.True(t, ok)
+	assert.Equal(t, info, u)
+}
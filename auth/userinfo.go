@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// UserInfo represents the user information written by UserInfoHandler.
+type UserInfo struct {
+	ID         string              `json:"sub,omitempty"`
+	UserName   string              `json:"name,omitempty"`
+	Groups     []string            `json:"groups,omitempty"`
+	Roles      []string            `json:"roles,omitempty"`
+	Extensions map[string][]string `json:"extensions,omitempty"`
+	AuthMethod StrategyKey         `json:"auth_method,omitempty"`
+	Expiry     int64               `json:"exp,omitempty"`
+}
+
+// UserInfo fields names, used to filter UserInfoHandler response.
+const (
+	UserInfoID         = "sub"
+	UserInfoUserName   = "name"
+	UserInfoGroups     = "groups"
+	UserInfoRoles      = "roles"
+	UserInfoExtensions = "extensions"
+	UserInfoAuthMethod = "auth_method"
+	UserInfoExpiry     = "exp"
+)
+
+type userInfoHandler struct {
+	fields     map[string]bool
+	extensions map[string]bool
+}
+
+func (u *userInfoHandler) include(field string) bool {
+	return u.fields == nil || u.fields[field]
+}
+
+func (u *userInfoHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	info := User(r)
+	if info == nil {
+		http.Error(w, StatusMessage(r, http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	ui := UserInfo{}
+
+	if u.include(UserInfoID) {
+		ui.ID = info.ID()
+	}
+
+	if u.include(UserInfoUserName) {
+		ui.UserName = info.UserName()
+	}
+
+	if u.include(UserInfoGroups) {
+		ui.Groups = info.Groups()
+	}
+
+	if u.include(UserInfoRoles) {
+		ui.Roles = UserRoles(info)
+	}
+
+	if u.include(UserInfoExtensions) && len(u.extensions) > 0 {
+		ui.Extensions = make(map[string][]string)
+		for k, v := range info.Extensions() {
+			if u.extensions[k] && !IsInternalExtension(k) {
+				ui.Extensions[k] = v
+			}
+		}
+	}
+
+	if u.include(UserInfoAuthMethod) {
+		ui.AuthMethod, _ = AuthMethod(r)
+	}
+
+	if e, ok := info.(interface{ Expiry() time.Time }); ok && u.include(UserInfoExpiry) && !e.Expiry().IsZero() {
+		ui.Expiry = e.Expiry().Unix()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(ui)
+}
+
+// UserInfoHandler return HTTP handler that writes the authenticated user information found,
+// in the request context as JSON, so clients (e.g SPAs) can query "who am I".
+// The handler must be wrapped with Middleware, Otherwise, it respond 401 Unauthorized.
+// By default all fields written except extensions, which written only when selected,
+// using SetUserInfoExtensions, so sensitive extensions never exposed by accident.
+// Use SetUserInfoFields to filter the written fields.
+func UserInfoHandler(opts ...Option) http.Handler {
+	u := new(userInfoHandler)
+
+	for _, opt := range opts {
+		opt.Apply(u)
+	}
+
+	return u
+}
+
+// SetUserInfoFields sets the fields written by UserInfoHandler, e.g UserInfoID.
+func SetUserInfoFields(fields ...string) Option {
+	return OptionFunc(func(v interface{}) {
+		if u, ok := v.(*userInfoHandler); ok {
+			u.fields = stringSet(fields)
+		}
+	})
+}
+
+// SetUserInfoExtensions sets the extensions keys written by UserInfoHandler,
+// internal extensions never written, See IsInternalExtension.
+func SetUserInfoExtensions(keys ...string) Option {
+	return OptionFunc(func(v interface{}) {
+		if u, ok := v.(*userInfoHandler); ok {
+			u.extensions = stringSet(keys)
+		}
+	})
+}
+
+func stringSet(keys []string) map[string]bool {
+	m := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		m[k] = true
+	}
+	return m
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserInfoHandler(t *testing.T) {
+	table := []struct {
+		name string
+		info Info
+		opts []Option
+		code int
+		body string
+	}{
+		{
+			name: "it respond 401 when user not authenticated",
+			code: http.StatusUnauthorized,
+			body: "Unauthorized\n",
+		},
+		{
+			name: "it write all user info fields except extensions",
+			info: rolesUser("test", "1", []string{"admin"}, map[string][]string{"k": {"v"}, "secret": {"s"}}),
+			code: http.StatusOK,
+			body: `{"sub":"1","name":"test","groups":["admin"],"roles":["editor"],"auth_method":"test"}` + "\n",
+		},
+		{
+			name: "it never write internal extensions",
+			info: NewUserInfo("test", "1", nil, map[string][]string{
+				"k":                            {"v"},
+				"x-go-guardian-basic-password": {"h"},
+			}),
+			opts: []Option{
+				SetUserInfoFields(UserInfoExtensions),
+				SetUserInfoExtensions("k", "x-go-guardian-basic-password"),
+			},
+			code: http.StatusOK,
+			body: `{"extensions":{"k":["v"]}}` + "\n",
+		},
+		{
+			name: "it write filtered fields and extensions",
+			info: NewUserInfo("test", "1", []string{"admin"}, map[string][]string{"k": {"v"}, "secret": {"s"}}),
+			opts: []Option{
+				SetUserInfoFields(UserInfoID, UserInfoExtensions, UserInfoExpiry),
+				SetUserInfoExtensions("k"),
+			},
+			code: http.StatusOK,
+			body: `{"sub":"1","extensions":{"k":["v"]}}` + "\n",
+		},
+		{
+			name: "it write user expiry",
+			info: expiringUser{Info: NewUserInfo("test", "1", nil, nil), exp: time.Unix(10, 0)},
+			opts: []Option{SetUserInfoFields(UserInfoExpiry)},
+			code: http.StatusOK,
+			body: `{"exp":10}` + "\n",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New()
+			a.EnableStrategy("test", infoStrategy{tt.info})
+
+			r, _ := http.NewRequest("GET", "/", nil)
+			w := httptest.NewRecorder()
+
+			h := UserInfoHandler(tt.opts...)
+			if tt.info != nil {
+				h = Middleware(a)(h)
+			}
+
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+}
+
+func rolesUser(name, id string, groups []string, ext map[string][]string) Info {
+	info := NewUserInfo(name, id, groups, ext)
+	SetUserRoles(info, []string{"editor"})
+	return info
+}
+
+type expiringUser struct {
+	Info
+	exp time.Time
+}
+
+func (e expiringUser) Expiry() time.Time { return e.exp }
+
+type infoStrategy struct {
+	info Info
+}
+
+func (s infoStrategy) Authenticate(context.Context, *http.Request) (Info, error) {
+	return s.info, nil
+}
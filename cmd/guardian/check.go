@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func checkCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	basicCreds := fs.String("basic", "", "basic credentials in the form user:password")
+	bearer := fs.String("bearer", "", "bearer token")
+	method := fs.String("method", http.MethodGet, "request method")
+	timeout := fs.Duration("timeout", 10*time.Second, "request timeout")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("guardian: check requires a single url argument")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	r, err := http.NewRequestWithContext(ctx, *method, fs.Arg(0), nil)
+	if err != nil {
+		return err
+	}
+
+	if len(*basicCreds) > 0 {
+		i := strings.IndexByte(*basicCreds, ':')
+		if i < 0 {
+			return fmt.Errorf("guardian: basic credentials must be in the form user:password")
+		}
+		r.SetBasicAuth((*basicCreds)[:i], (*basicCreds)[i+1:])
+	}
+
+	if len(*bearer) > 0 {
+		r.Header.Set("Authorization", "Bearer "+*bearer)
+	}
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	fmt.Fprintln(stdout, "status:", resp.Status)
+
+	for _, c := range resp.Header["Www-Authenticate"] {
+		fmt.Fprintln(stdout, "challenge:", c)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("guardian: Authentication failed with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+)
+
+func hashCmd(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("hash", flag.ContinueOnError)
+	cost := fs.Int("cost", basic.DefaultHashCost, "PBKDF2 iterations count")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 || strings.Contains(fs.Arg(0), ":") {
+		return fmt.Errorf("guardian: hash requires a single user name argument, without colons")
+	}
+
+	password, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	password = strings.TrimRight(password, "\r\n")
+	if len(password) == 0 {
+		return fmt.Errorf("guardian: hash requires a non empty password on stdin")
+	}
+
+	h, err := basic.HashPassword(password, *cost)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "%s:%s\n", fs.Arg(0), h)
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/jwt"
+	ijwt "github.com/shaj13/go-guardian/internal/jwt"
+)
+
+// keeperFlags register the flags shared by the jwt commands,
+// and return function building the secrets keeper and the strategy options from the parsed flags,
+// the keeper nil when no secret provided.
+func keeperFlags(fs *flag.FlagSet) func() (jwt.SecretsKeeper, []auth.Option) {
+	secret := fs.String("secret", "", "HMAC secret")
+	kid := fs.String("kid", "default", "secret key id")
+	alg := fs.String("alg", "HS256", "HMAC algorithm, HS256, HS384 or HS512")
+	iss := fs.String("iss", "", "token issuer")
+	aud := fs.String("aud", "", "comma separated token audience")
+
+	return func() (jwt.SecretsKeeper, []auth.Option) {
+		var keeper jwt.SecretsKeeper
+		if len(*secret) > 0 {
+			keeper = jwt.StaticSecret{
+				ID:        *kid,
+				Secret:    []byte(*secret),
+				Algorithm: *alg,
+			}
+		}
+
+		opts := []auth.Option{jwt.SetIssuer(*iss)}
+		if len(*aud) > 0 {
+			opts = append(opts, jwt.SetAudience(strings.Split(*aud, ",")...))
+		}
+
+		return keeper, opts
+	}
+}
+
+func mintCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("jwt mint", flag.ContinueOnError)
+	keeper := keeperFlags(fs)
+	sub := fs.String("sub", "", "token subject (user id)")
+	name := fs.String("name", "", "token user name")
+	groups := fs.String("groups", "", "comma separated user groups")
+	exp := fs.Duration("exp", time.Hour, "token lifetime")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	s, opts := keeper()
+	if s == nil || len(*sub) == 0 {
+		return fmt.Errorf("guardian: jwt mint requires -secret and -sub")
+	}
+
+	var g []string
+	if len(*groups) > 0 {
+		g = strings.Split(*groups, ",")
+	}
+
+	info := auth.NewUserInfo(*name, *sub, g, nil)
+	tk, err := jwt.IssueAccessToken(info, s, append(opts, jwt.SetExpDuration(*exp))...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, tk)
+
+	return nil
+}
+
+func inspectCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("jwt inspect", flag.ContinueOnError)
+	keeper := keeperFlags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("guardian: jwt inspect requires a single token argument")
+	}
+
+	tk := fs.Arg(0)
+
+	t, err := ijwt.Parse(tk)
+	if err != nil {
+		return err
+	}
+
+	header, _ := json.MarshalIndent(t.Header, "", "  ")
+	payload := new(bytes.Buffer)
+	if err := json.Indent(payload, t.Payload(), "", "  "); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "header: %s\npayload: %s\n", header, payload)
+
+	s, opts := keeper()
+	if s == nil {
+		fmt.Fprintln(stdout, "signature: not verified, no secret provided")
+		return nil
+	}
+
+	info, err := jwt.GetAuthenticateFunc(s, opts...)(context.Background(), nil, tk)
+	if err != nil {
+		return fmt.Errorf("guardian: Invalid token Err: %s", err)
+	}
+
+	fmt.Fprintf(stdout, "signature: valid, user: %s, id: %s\n", info.UserName(), info.ID())
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Command guardian is a CLI for go-guardian operational tasks,
+// it generate OTP secrets, hash passwords for htpasswd files, mint and inspect test JWTs,
+// and exercise a running server authentication endpoint, using the library code paths.
+//
+// Usage:
+//
+//	guardian otp [flags]             generate OTP secret and key URI.
+//	guardian hash [flags] user       hash the password read from stdin as an htpasswd entry.
+//	guardian jwt mint [flags]        mint a signed JWT.
+//	guardian jwt inspect [flags] jwt decode a JWT, and verify it when a secret provided.
+//	guardian check [flags] url       send an authenticated request and report the outcome.
+//
+// Run guardian <command> -h to list the command flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage: guardian <command> [flags]
+
+Commands:
+  otp          generate OTP secret and key URI
+  hash         hash the password read from stdin as an htpasswd entry
+  jwt mint     mint a signed JWT
+  jwt inspect  decode a JWT, and verify it when a secret provided
+  check        send an authenticated request and report the outcome
+`
+
+var errUsage = errors.New("guardian: Invalid usage")
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if err == errUsage {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "otp":
+		return otpCmd(args[1:], stdout)
+	case "hash":
+		return hashCmd(args[1:], stdin, stdout)
+	case "jwt":
+		if len(args) < 2 {
+			return errUsage
+		}
+
+		switch args[1] {
+		case "mint":
+			return mintCmd(args[2:], stdout)
+		case "inspect":
+			return inspectCmd(args[2:], stdout)
+		}
+	case "check":
+		return checkCmd(args[1:], stdout)
+	}
+
+	return errUsage
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/otp"
+)
+
+func TestRunUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"unknown"}, {"jwt"}, {"jwt", "unknown"}} {
+		err := run(args, nil, new(bytes.Buffer))
+		assert.Equal(t, errUsage, err)
+	}
+}
+
+func TestOTP(t *testing.T) {
+	out := new(bytes.Buffer)
+	err := run([]string{"otp", "-issuer", "guardian", "-account", "alice", "-digits", "8"}, nil, out)
+	assert.NoError(t, err)
+
+	uri := strings.TrimPrefix(strings.Split(out.String(), "\n")[2], "uri: ")
+	key, err := otp.NewKeyFromRaw(uri)
+	assert.NoError(t, err)
+	assert.Equal(t, otp.TOTP, key.Type())
+	assert.Equal(t, "guardian", key.Issuer())
+	assert.Equal(t, otp.EightDigits, key.Digits())
+
+	err = run([]string{"otp", "-type", "unknown"}, nil, out)
+	assert.Error(t, err)
+}
+
+func TestHash(t *testing.T) {
+	out := new(bytes.Buffer)
+	err := run([]string{"hash", "-cost", "10", "alice"}, strings.NewReader("secret\n"), out)
+	assert.NoError(t, err)
+
+	fn, err := basic.Htpasswd(out)
+	assert.NoError(t, err)
+
+	info, err := fn(context.Background(), nil, "alice", "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", info.UserName())
+
+	err = run([]string{"hash", "alice"}, strings.NewReader(""), out)
+	assert.Error(t, err)
+}
+
+func TestJWT(t *testing.T) {
+	out := new(bytes.Buffer)
+	args := []string{"jwt", "mint", "-secret", "test", "-sub", "1", "-name", "alice", "-aud", "api"}
+	err := run(args, nil, out)
+	assert.NoError(t, err)
+
+	tk := strings.TrimSpace(out.String())
+
+	out.Reset()
+	err = run([]string{"jwt", "inspect", tk}, nil, out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `"preferred_username": "alice"`)
+	assert.Contains(t, out.String(), "not verified")
+
+	out.Reset()
+	err = run([]string{"jwt", "inspect", "-secret", "test", "-aud", "api", tk}, nil, out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "signature: valid, user: alice, id: 1")
+
+	err = run([]string{"jwt", "inspect", "-secret", "invalid", tk}, nil, out)
+	assert.Error(t, err)
+
+	err = run([]string{"jwt", "mint", "-sub", "1"}, nil, out)
+	assert.Error(t, err)
+}
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); ok && u == "alice" && p == "secret" {
+			return
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	out := new(bytes.Buffer)
+	err := run([]string{"check", "-basic", "alice:secret", srv.URL}, nil, out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "status: 200 OK")
+
+	out.Reset()
+	err = run([]string{"check", srv.URL}, nil, out)
+	assert.Error(t, err)
+	assert.Contains(t, out.String(), `challenge: Basic realm="test"`)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/shaj13/go-guardian/otp"
+)
+
+func otpCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("otp", flag.ContinueOnError)
+	typ := fs.String("type", "totp", "OTP type, totp or hotp")
+	issuer := fs.String("issuer", "", "key issuer")
+	account := fs.String("account", "", "key account name")
+	size := fs.Uint("size", 20, "secret size in bytes")
+	digits := fs.Int("digits", 6, "OTP digits, 6 or 8")
+	algo := fs.String("algo", "SHA1", "HMAC algorithm, SHA1, SHA256 or SHA512")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	t := otp.Type(strings.ToLower(*typ))
+	if t != otp.TOTP && t != otp.HOTP {
+		return fmt.Errorf("guardian: Unsupported OTP type %q", *typ)
+	}
+
+	if *digits != int(otp.SixDigits) && *digits != int(otp.EightDigits) {
+		return fmt.Errorf("guardian: Unsupported OTP digits %d", *digits)
+	}
+
+	secret, err := otp.GenerateSecret(*size)
+	if err != nil {
+		return err
+	}
+
+	label := *account
+	if len(*issuer) > 0 {
+		label = *issuer + ":" + *account
+	}
+
+	key := otp.NewKey(t, label, secret)
+	key.SetDigits(otp.Digits(*digits))
+	key.SetAlgorithm(otp.HashAlgorithm(strings.ToUpper(*algo)))
+
+	if len(*issuer) > 0 {
+		key.SetIssuer(*issuer)
+	}
+
+	if t == otp.HOTP {
+		key.SetCounter(0)
+	}
+
+	// validate the key by generating the first OTP through the library verifier.
+	if _, err := otp.New(key).GenerateOTP(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, "secret:", secret)
+	fmt.Fprintln(stdout, "digits:", strconv.Itoa(*digits))
+	fmt.Fprintln(stdout, "uri:", key.String())
+	fmt.Fprintln(stdout, "# render the uri as a QR code, e.g using: qrencode -t ansiutf8 '<uri>'")
+
+	return nil
+}
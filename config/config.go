@@ -0,0 +1,233 @@
+// Package config builds a fully wired go-guardian Authenticator from a declarative document,
+// so deployments can change the authentication setup (strategies, caches, TTLs) without recompiling.
+//
+// The document is JSON, YAML documents must be converted to JSON before loading.
+// References to environment variables in the form ${VAR} or ${VAR:-default}
+// interpolated before the document parsed, so secrets can be kept out of the document.
+//
+// Example document:
+//
+//	{
+//	  "disabled_paths": ["/healthz"],
+//	  "strategies": [
+//	    {
+//	      "key": "static",
+//	      "type": "token.static",
+//	      "timeout": "2s",
+//	      "params": {"file": "${TOKENS_FILE}", "type": "Bearer"}
+//	    },
+//	    {
+//	      "key": "users",
+//	      "type": "basic",
+//	      "cache": {"type": "lru", "max_entries": 1000, "ttl": "5m", "jitter": "30s"},
+//	      "params": {"realm": "api", "hashed_keys": true}
+//	    }
+//	  ]
+//	}
+//
+// The anonymous strategy intentionally has no built-in factory, since the authenticator does not
+// guarantee the strategies order, so a guest may win before the credentials checked,
+// wrap the built authenticator strategies using anonymous.Fallback or auth.Compose instead.
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+var (
+	// ErrUnknownStrategy is returned by Loader,
+	// when the document refers to a strategy type that has no registered factory.
+	ErrUnknownStrategy = errors.New("config: Unknown strategy type")
+
+	// ErrUnknownCache is returned by Loader,
+	// when the document refers to an unknown cache type.
+	ErrUnknownCache = errors.New("config: Unknown cache type")
+)
+
+// Config represents the declarative configuration of an Authenticator.
+type Config struct {
+	// DisabledPaths represents the paths the authenticator skip, See auth.New.
+	DisabledPaths []string `json:"disabled_paths"`
+	// Strategies represents the authenticator strategies.
+	Strategies []Strategy `json:"strategies"`
+}
+
+// Strategy represents the declarative configuration of a strategy.
+type Strategy struct {
+	// Key represents the strategy key within the authenticator.
+	Key string `json:"key"`
+	// Type represents the strategy factory type, See Loader.Register.
+	Type string `json:"type"`
+	// Timeout optionally bounds the strategy authentication, See auth.Authenticator.SetStrategyTimeout.
+	Timeout Duration `json:"timeout"`
+	// Cache optionally represents the strategy cache.
+	Cache *Cache `json:"cache"`
+	// Params represents the strategy type specific parameters, decoded by its factory.
+	Params json.RawMessage `json:"params"`
+}
+
+// Cache represents the declarative configuration of a strategy cache.
+type Cache struct {
+	// Type represents the cache type, one of lru, fifo, filesystem, or none.
+	Type string `json:"type"`
+	// TTL represents the cache records TTL.
+	TTL Duration `json:"ttl"`
+	// Jitter represents the max random duration added to the records TTL (lru and fifo only).
+	Jitter Duration `json:"jitter"`
+	// MaxEntries represents the max number of cache entries (lru only).
+	MaxEntries int `json:"max_entries"`
+	// Path represents the cache directory (filesystem only).
+	Path string `json:"path"`
+}
+
+// Duration represents a time.Duration encoded as a string (e.g "5m") or a number of nanoseconds.
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case float64:
+		*d = Duration(v)
+	case string:
+		dur, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*d = Duration(dur)
+	default:
+		return fmt.Errorf("config: Invalid duration %s", b)
+	}
+
+	return nil
+}
+
+// Factory define function signature to build a strategy from its parameters and cache,
+// the cache is never nil, store.NoCache passed when the strategy has no cache configured.
+type Factory func(params json.RawMessage, c store.Cache) (auth.Strategy, error)
+
+// Loader builds Authenticator from declarative documents.
+// Loader not safe for concurrent registration, register factories before loading documents.
+type Loader struct {
+	ctx       context.Context
+	factories map[string]Factory
+	lookupEnv func(string) (string, bool)
+}
+
+// NewLoader return new Loader, with the built-in strategy types registered, See Register.
+// The context passed to the caches garbage collector.
+func NewLoader(ctx context.Context) *Loader {
+	l := &Loader{
+		ctx:       ctx,
+		factories: make(map[string]Factory),
+		lookupEnv: os.LookupEnv,
+	}
+
+	l.Register("token.static", staticFactory)
+	l.Register("ldap", ldapFactory)
+
+	return l
+}
+
+// Register registers the factory of the given strategy type, replacing any previous one.
+// Typically used to register strategies that need application code,
+// e.g l.Register("basic", BasicFactory(authFunc)).
+func (l *Loader) Register(typ string, f Factory) {
+	l.factories[typ] = f
+}
+
+// LoadFile builds Authenticator from the document at the given path, See Load.
+func (l *Loader) LoadFile(path string) (auth.Authenticator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return l.Load(f)
+}
+
+// Load builds Authenticator from the document read from r,
+// after interpolating the environment variables references.
+func (l *Loader) Load(r io.Reader) (auth.Authenticator, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := new(Config)
+	if err := json.Unmarshal(interpolate(b, l.lookupEnv), cfg); err != nil {
+		return nil, err
+	}
+
+	return l.Build(cfg)
+}
+
+// Build builds Authenticator from the given configuration.
+func (l *Loader) Build(cfg *Config) (auth.Authenticator, error) {
+	a := auth.New(cfg.DisabledPaths...)
+
+	for _, s := range cfg.Strategies {
+		f, ok := l.factories[s.Type]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q, Strategy: %q", ErrUnknownStrategy, s.Type, s.Key)
+		}
+
+		c, err := l.cache(s.Cache)
+		if err != nil {
+			return nil, fmt.Errorf("%w, Strategy: %q", err, s.Key)
+		}
+
+		strategy, err := f(s.Params, c)
+		if err != nil {
+			return nil, fmt.Errorf("config: %v, Strategy: %q", err, s.Key)
+		}
+
+		key := auth.StrategyKey(s.Key)
+		a.EnableStrategy(key, strategy)
+
+		if s.Timeout > 0 {
+			a.SetStrategyTimeout(key, time.Duration(s.Timeout))
+		}
+	}
+
+	return a, nil
+}
+
+func (l *Loader) cache(c *Cache) (store.Cache, error) {
+	if c == nil {
+		return store.NoCache{}, nil
+	}
+
+	switch c.Type {
+	case "", "none":
+		return store.NoCache{}, nil
+	case "lru":
+		lru := store.New(c.MaxEntries)
+		lru.TTL = time.Duration(c.TTL)
+		lru.Jitter = time.Duration(c.Jitter)
+		return lru, nil
+	case "fifo":
+		fifo := store.NewFIFO(l.ctx, time.Duration(c.TTL))
+		fifo.Jitter = time.Duration(c.Jitter)
+		return fifo, nil
+	case "filesystem":
+		return store.NewFileSystem(l.ctx, time.Duration(c.TTL), c.Path), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownCache, c.Type)
+}
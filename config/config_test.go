@@ -0,0 +1,156 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/store"
+)
+
+const document = `
+{
+  "disabled_paths": ["/healthz"],
+  "strategies": [
+    {
+      "key": "static",
+      "type": "token.static",
+      "timeout": "2s",
+      "params": {"file": "${TOKENS_FILE}"}
+    },
+    {
+      "key": "users",
+      "type": "basic",
+      "cache": {"type": "lru", "max_entries": 10, "ttl": "${CACHE_TTL:-5m}"},
+      "params": {"realm": "api", "hashed_keys": true}
+    }
+  ]
+}`
+
+func basicAuthFunc(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+	if userName == "test" && password == "test" {
+		return auth.NewDefaultUser("test", "10", nil, nil), nil
+	}
+	return nil, auth.ErrNOOP
+}
+
+func TestLoader(t *testing.T) {
+	l := NewLoader(context.Background())
+	l.Register("basic", BasicFactory(basicAuthFunc))
+	l.lookupEnv = func(key string) (string, bool) {
+		if key == "TOKENS_FILE" {
+			return "testdata/tokens.csv", true
+		}
+		return "", false
+	}
+
+	a, err := l.Load(strings.NewReader(document))
+	assert.NoError(t, err)
+	assert.Equal(t, []auth.StrategyKey{"static", "users"}, a.StrategyKeys())
+	assert.Contains(t, a.DisabledPaths(), "healthz")
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Authorization", "Bearer testtoken")
+	info, err := a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", info.ID())
+
+	r, _ = http.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("test", "test")
+	info, err = a.Authenticate(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "10", info.ID())
+}
+
+func TestLoaderErrors(t *testing.T) {
+	table := []struct {
+		name        string
+		document    string
+		expectedErr error
+	}{
+		{
+			name:        "it return error when strategy type unknown",
+			document:    `{"strategies": [{"key": "k", "type": "unknown"}]}`,
+			expectedErr: ErrUnknownStrategy,
+		},
+		{
+			name:        "it return error when strategy type anonymous, since it must be a fallback",
+			document:    `{"strategies": [{"key": "k", "type": "anonymous"}]}`,
+			expectedErr: ErrUnknownStrategy,
+		},
+		{
+			name:        "it return error when cache type unknown",
+			document:    `{"strategies": [{"key": "k", "type": "token.static", "cache": {"type": "unknown"}}]}`,
+			expectedErr: ErrUnknownCache,
+		},
+		{
+			name:     "it return error when factory fails",
+			document: `{"strategies": [{"key": "k", "type": "token.static", "params": {"file": "/notfound"}}]}`,
+		},
+		{
+			name:     "it return error when duration invalid",
+			document: `{"strategies": [{"key": "k", "type": "token.static", "timeout": "1x"}]}`,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLoader(context.Background()).Load(strings.NewReader(tt.document))
+			assert.Error(t, err)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			}
+		})
+	}
+}
+
+func TestLoaderCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := NewLoader(ctx)
+
+	c, err := l.cache(&Cache{Type: "fifo", TTL: Duration(time.Minute), Jitter: Duration(time.Second)})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, c.(*store.FIFO).TTL)
+	assert.Equal(t, time.Second, c.(*store.FIFO).Jitter)
+
+	c, err = l.cache(&Cache{Type: "lru", MaxEntries: 5, TTL: Duration(time.Minute)})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, c.(*store.LRU).MaxEntries)
+
+	c, err = l.cache(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, store.NoCache{}, c)
+}
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{"A": "a", "Q": `"quoted"`}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	table := []struct {
+		in       string
+		expected string
+	}{
+		{in: `"${A}"`, expected: `"a"`},
+		{in: `"${B:-b}"`, expected: `"b"`},
+		{in: `"${B}"`, expected: `""`},
+		{in: `"${Q}"`, expected: `"\"quoted\""`},
+		{in: `"$2a$10$hash"`, expected: `"$2a$10$hash"`},
+		{in: `"${A"`, expected: `"${A"`},
+	}
+
+	for _, tt := range table {
+		got := interpolate([]byte(tt.in), lookup)
+		assert.Equal(t, tt.expected, string(got))
+	}
+}
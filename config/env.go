@@ -0,0 +1,46 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// interpolate replaces references to environment variables in the form ${VAR} or ${VAR:-default},
+// with the variable value, or the default value when the variable unset or empty.
+// Only the braced form interpolated, so values containing a bare $ (e.g password hashes) kept as is.
+// The values JSON escaped, since references typically placed within JSON strings.
+func interpolate(b []byte, lookup func(string) (string, bool)) []byte {
+	out := make([]byte, 0, len(b))
+
+	for {
+		i := bytes.Index(b, []byte("${"))
+		if i < 0 {
+			return append(out, b...)
+		}
+
+		j := bytes.IndexByte(b[i:], '}')
+		if j < 0 {
+			return append(out, b...)
+		}
+
+		out = append(out, b[:i]...)
+		out = append(out, expand(string(b[i+2:i+j]), lookup)...)
+		b = b[i+j+1:]
+	}
+}
+
+func expand(ref string, lookup func(string) (string, bool)) string {
+	name, def := ref, ""
+	if i := strings.Index(ref, ":-"); i >= 0 {
+		name, def = ref[:i], ref[i+2:]
+	}
+
+	v, ok := lookup(name)
+	if !ok || len(v) == 0 {
+		v = def
+	}
+
+	b, _ := json.Marshal(v)
+	return string(b[1 : len(b)-1])
+}
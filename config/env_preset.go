@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/auth/strategies/oidc"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// Environment variables read by FromEnv.
+const (
+	// EnvDisabledPaths comma separated paths the authenticator skip.
+	EnvDisabledPaths = "GUARDIAN_DISABLED_PATHS"
+	// EnvCacheTTL the strategies cache TTL (e.g 5m), default 5m.
+	EnvCacheTTL = "GUARDIAN_CACHE_TTL"
+	// EnvCacheSize the strategies cache max entries, default unlimited.
+	EnvCacheSize = "GUARDIAN_CACHE_SIZE"
+	// EnvJWTIssuer the JWT issuer, enables the JWT strategy along with EnvJWTJWKSURL.
+	EnvJWTIssuer = "GUARDIAN_JWT_ISSUER"
+	// EnvJWTAudience comma separated JWT accepted audiences.
+	EnvJWTAudience = "GUARDIAN_JWT_AUDIENCE"
+	// EnvJWTJWKSURL the issuer JWKS URL.
+	EnvJWTJWKSURL = "GUARDIAN_JWT_JWKS_URL"
+	// EnvBasicHtpasswd the htpasswd file path, enables the basic strategy, See basic.Htpasswd.
+	EnvBasicHtpasswd = "GUARDIAN_BASIC_HTPASSWD"
+	// EnvBasicRealm the basic strategy realm.
+	EnvBasicRealm = "GUARDIAN_BASIC_REALM"
+	// EnvTokensFile the static tokens CSV file path, enables the static token strategy,
+	// See token.NewStaticFromFile.
+	EnvTokensFile = "GUARDIAN_TOKENS_FILE"
+)
+
+// JWTStrategyKey export identifier for the JWT strategy enabled by FromEnv.
+const JWTStrategyKey = auth.StrategyKey("Env.JWT.Strategy")
+
+// FromEnv return Authenticator assembled from the documented environment variables (GUARDIAN_*),
+// for twelve-factor services that bootstrap authentication without code or configuration files.
+// Each strategy enabled only when its environment variables set, and cached by its own LRU cache.
+func FromEnv() (auth.Authenticator, error) {
+	return fromEnv(os.LookupEnv)
+}
+
+func fromEnv(lookup func(string) (string, bool)) (auth.Authenticator, error) {
+	get := func(key string) string {
+		v, _ := lookup(key)
+		return strings.TrimSpace(v)
+	}
+
+	ttl := 5 * time.Minute
+	if v := get(EnvCacheTTL); len(v) > 0 {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: Invalid %s Err: %s", EnvCacheTTL, err)
+		}
+		ttl = d
+	}
+
+	size := 0
+	if v := get(EnvCacheSize); len(v) > 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("config: Invalid %s Err: %s", EnvCacheSize, err)
+		}
+		size = n
+	}
+
+	cache := func() store.Cache {
+		c := store.New(size)
+		c.TTL = ttl
+		return c
+	}
+
+	a := auth.New(split(get(EnvDisabledPaths))...)
+
+	if iss, jwks := get(EnvJWTIssuer), get(EnvJWTJWKSURL); len(iss) > 0 || len(jwks) > 0 {
+		aud := split(get(EnvJWTAudience))
+		if len(iss) == 0 || len(jwks) == 0 || len(aud) == 0 {
+			return nil, fmt.Errorf(
+				"config: %s, %s, and %s must be set together",
+				EnvJWTIssuer, EnvJWTJWKSURL, EnvJWTAudience,
+			)
+		}
+
+		p := &oidc.Provider{Issuer: iss, JWKSURI: jwks}
+		a.EnableStrategy(JWTStrategyKey, oidc.New(p, aud[0], cache(), oidc.SetAudience(aud...)))
+	}
+
+	if path := get(EnvBasicHtpasswd); len(path) > 0 {
+		fn, err := basic.HtpasswdFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := []auth.Option{}
+		if realm := get(EnvBasicRealm); len(realm) > 0 {
+			opts = append(opts, basic.SetRealm(realm))
+		}
+
+		a.EnableStrategy(basic.StrategyKey, basic.New(fn, cache(), opts...))
+	}
+
+	if path := get(EnvTokensFile); len(path) > 0 {
+		s, err := token.NewStaticFromFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		a.EnableStrategy(token.StatitcStrategyKey, s)
+	}
+
+	return a, nil
+}
+
+// split split comma separated values and drop the empty ones.
+func split(v string) []string {
+	values := []string{}
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); len(s) > 0 {
+			values = append(values, s)
+		}
+	}
+	return values
+}
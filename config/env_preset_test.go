@@ -0,0 +1,89 @@
+package config
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+)
+
+func TestFromEnv(t *testing.T) {
+	table := []struct {
+		name        string
+		env         map[string]string
+		keys        []auth.StrategyKey
+		expectedErr bool
+	}{
+		{
+			name: "it enable strategies having their variables set",
+			env: map[string]string{
+				EnvBasicHtpasswd: "testdata/htpasswd",
+				EnvTokensFile:    "testdata/tokens.csv",
+				EnvJWTIssuer:     "https://issuer.example.com",
+				EnvJWTAudience:   "api, web",
+				EnvJWTJWKSURL:    "https://issuer.example.com/jwks",
+				EnvCacheTTL:      "1m",
+				EnvCacheSize:     "100",
+			},
+			keys: []auth.StrategyKey{basic.StrategyKey, JWTStrategyKey, token.StatitcStrategyKey},
+		},
+		{
+			name: "it return empty authenticator when no variables set",
+			env:  map[string]string{},
+			keys: []auth.StrategyKey{},
+		},
+		{
+			name:        "it return error when jwt variables partially set",
+			env:         map[string]string{EnvJWTIssuer: "https://issuer.example.com"},
+			expectedErr: true,
+		},
+		{
+			name:        "it return error when cache ttl invalid",
+			env:         map[string]string{EnvCacheTTL: "1x"},
+			expectedErr: true,
+		},
+		{
+			name:        "it return error when htpasswd not found",
+			env:         map[string]string{EnvBasicHtpasswd: "testdata/notfound"},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := fromEnv(func(key string) (string, bool) {
+				v, ok := tt.env[key]
+				return v, ok
+			})
+
+			assert.Equal(t, tt.expectedErr, err != nil)
+
+			if tt.expectedErr {
+				return
+			}
+
+			assert.ElementsMatch(t, tt.keys, a.StrategyKeys())
+		})
+	}
+}
+
+func TestFromEnvAuthenticate(t *testing.T) {
+	a, err := fromEnv(func(key string) (string, bool) {
+		if key == EnvBasicHtpasswd {
+			return "testdata/htpasswd", true
+		}
+		return "", false
+	})
+	assert.NoError(t, err)
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.SetBasicAuth("test", "test")
+	info, err := a.Authenticate(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test", info.UserName())
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shaj13/go-guardian/auth"
+	"github.com/shaj13/go-guardian/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/auth/strategies/ldap"
+	"github.com/shaj13/go-guardian/auth/strategies/token"
+	"github.com/shaj13/go-guardian/store"
+)
+
+// BasicParams represents the parameters of strategies built by BasicFactory.
+type BasicParams struct {
+	Realm      string `json:"realm"`
+	HashedKeys bool   `json:"hashed_keys"`
+	HashCost   int    `json:"hash_cost"`
+}
+
+// TokenParams represents the parameters of strategies built by TokenFactory.
+type TokenParams struct {
+	Type        string   `json:"type"`
+	Realm       string   `json:"realm"`
+	HashedKeys  bool     `json:"hashed_keys"`
+	MaxCacheAge Duration `json:"max_cache_age"`
+}
+
+// BasicFactory return Factory that builds cached basic strategy, using the given authenticate function.
+// The strategy parameters decoded into BasicParams.
+func BasicFactory(fn basic.AuthenticateFunc) Factory {
+	return func(params json.RawMessage, c store.Cache) (auth.Strategy, error) {
+		p := new(BasicParams)
+		if err := decode(params, p); err != nil {
+			return nil, err
+		}
+
+		opts := []auth.Option{
+			basic.SetHashedKeys(p.HashedKeys),
+		}
+
+		if len(p.Realm) > 0 {
+			opts = append(opts, basic.SetRealm(p.Realm))
+		}
+
+		if p.HashCost > 0 {
+			opts = append(opts, basic.SetHashCost(p.HashCost))
+		}
+
+		return basic.New(fn, c, opts...), nil
+	}
+}
+
+// TokenFactory return Factory that builds cached token strategy, using the given authenticate function.
+// The strategy parameters decoded into TokenParams.
+func TokenFactory(fn token.AuthenticateFunc) Factory {
+	return func(params json.RawMessage, c store.Cache) (auth.Strategy, error) {
+		p := new(TokenParams)
+		if err := decode(params, p); err != nil {
+			return nil, err
+		}
+
+		opts := append(tokenOptions(p.Type, p.Realm),
+			token.SetHashedKeys(p.HashedKeys),
+			token.SetMaxCacheAge(time.Duration(p.MaxCacheAge)),
+		)
+
+		return token.New(fn, c, opts...), nil
+	}
+}
+
+func tokenOptions(typ, realm string) []auth.Option {
+	opts := []auth.Option{}
+
+	if len(typ) > 0 {
+		opts = append(opts, token.SetType(token.Type(typ)), token.SetParser(token.AuthorizationParser(typ)))
+	}
+
+	if len(realm) > 0 {
+		opts = append(opts, token.SetRealm(realm))
+	}
+
+	return opts
+}
+
+// staticFactory builds static token strategy populated from a CSV file, See token.NewStaticFromFile.
+func staticFactory(params json.RawMessage, _ store.Cache) (auth.Strategy, error) {
+	p := struct {
+		File  string `json:"file"`
+		Type  string `json:"type"`
+		Realm string `json:"realm"`
+	}{}
+
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	return token.NewStaticFromFile(p.File, tokenOptions(p.Type, p.Realm)...)
+}
+
+// ldapFactory builds cached ldap strategy, See ldap.NewCached.
+// TLS configuration not supported declaratively, register a custom factory instead.
+func ldapFactory(params json.RawMessage, c store.Cache) (auth.Strategy, error) {
+	p := struct {
+		Host         string   `json:"host"`
+		Port         string   `json:"port"`
+		BindDN       string   `json:"bind_dn"`
+		BindPassword string   `json:"bind_password"`
+		BaseDN       string   `json:"base_dn"`
+		Filter       string   `json:"filter"`
+		Attributes   []string `json:"attributes"`
+	}{}
+
+	if err := decode(params, &p); err != nil {
+		return nil, err
+	}
+
+	cfg := &ldap.Config{
+		Host:         p.Host,
+		Port:         p.Port,
+		BindDN:       p.BindDN,
+		BindPassword: p.BindPassword,
+		BaseDN:       p.BaseDN,
+		Filter:       p.Filter,
+		Attributes:   p.Attributes,
+	}
+
+	return ldap.NewCached(cfg, c), nil
+}
+
+func decode(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	return json.Unmarshal(params, v)
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -80,3 +81,19 @@ func TestError(t *testing.T) {
 		assert.Equal(t, tt.errs.Error(), tt.errStr)
 	}
 }
+
+func TestMultiErrorIsAs(t *testing.T) {
+	target := fmt.Errorf("target error")
+	errs := MultiError{
+		fmt.Errorf("1st error"),
+		fmt.Errorf("wrapped: %w", target),
+		InvalidType{Want: "string", Got: "int"},
+	}
+
+	assert.True(t, errors.Is(errs, target))
+	assert.False(t, errors.Is(errs, fmt.Errorf("target error")))
+
+	var it InvalidType
+	assert.True(t, errors.As(errs, &it))
+	assert.Equal(t, "int", it.Got)
+}
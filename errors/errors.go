@@ -53,6 +53,30 @@ func (errs MultiError) Error() string {
 	return fmt.Sprintf("%v: [%s]", errs[0], str)
 }
 
+// Is reports whether any error in errs matches target,
+// Typically invoked by errors.Is.
+func (errs MultiError) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first error in errs that matches target, and if so, sets target to that error value.
+// Typically invoked by errors.As.
+func (errs MultiError) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // New returns an error that formats as the given text.
 // Each call to New returns a distinct error value even if the text is identical.
 func New(str string) error {
@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"context"
+	"errors"
+)
+
+// Reason represents a machine-readable authentication failure cause,
+// attached to the returned errors, so middleware, metrics, and audit hooks,
+// break the failures down by cause, See ReasonOf.
+type Reason string
+
+const (
+	// ReasonUnknown is the reason of errors that does not carry a reason.
+	ReasonUnknown Reason = "unknown"
+	// ReasonMissingCredentials is the reason of requests that does not carry the credentials.
+	ReasonMissingCredentials Reason = "missing_credentials"
+	// ReasonMalformed is the reason of malformed or tampered credentials.
+	ReasonMalformed Reason = "malformed"
+	// ReasonInvalidCredentials is the reason of wrong or unknown credentials.
+	ReasonInvalidCredentials Reason = "invalid_credentials"
+	// ReasonExpired is the reason of expired credentials or sessions.
+	ReasonExpired Reason = "expired"
+	// ReasonRevoked is the reason of revoked credentials.
+	ReasonRevoked Reason = "revoked"
+	// ReasonLockedOut is the reason of accounts locked out after repeated failures.
+	ReasonLockedOut Reason = "locked_out"
+	// ReasonRateLimited is the reason of requests exceeding the rate limit.
+	ReasonRateLimited Reason = "rate_limited"
+	// ReasonUpstreamUnavailable is the reason of upstream failures, e.g timeouts and open circuits.
+	ReasonUpstreamUnavailable Reason = "upstream_unavailable"
+)
+
+// WithReason return error that formats as err and carries the given failure reason,
+// The returned error unwraps to err, so errors.Is and errors.As keep working.
+// Typically used to define sentinel errors, e.g
+//
+//	var ErrExpired = errors.WithReason(errors.New("Token expired"), errors.ReasonExpired)
+func WithReason(err error, reason Reason) error {
+	return reasonError{err: err, reason: reason}
+}
+
+type reasonError struct {
+	err    error
+	reason Reason
+}
+
+func (r reasonError) Error() string  { return r.err.Error() }
+func (r reasonError) Unwrap() error  { return r.err }
+func (r reasonError) Reason() Reason { return r.reason }
+
+// ReasonOf return the failure reason carried by err or the errors it wraps,
+// errors carry a reason by implementing Reason() Reason, See WithReason.
+// context.DeadlineExceeded reported as ReasonUpstreamUnavailable.
+//
+// The most specific reason of MultiError errors returned,
+// i.e ReasonMissingCredentials returned only if no other reason found,
+// since it typically reported by the strategies the request does not target.
+// Empty reason returned if err is nil, Otherwise, ReasonUnknown if err does not carry a reason.
+func ReasonOf(err error) Reason {
+	if err == nil {
+		return ""
+	}
+
+	var errs MultiError
+	if errors.As(err, &errs) {
+		reason := ReasonUnknown
+		for _, e := range errs {
+			switch r := ReasonOf(e); r {
+			case ReasonUnknown, "":
+			case ReasonMissingCredentials:
+				reason = r
+			default:
+				return r
+			}
+		}
+		return reason
+	}
+
+	var re interface{ Reason() Reason }
+	if errors.As(err, &re) {
+		return re.Reason()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ReasonUpstreamUnavailable
+	}
+
+	return ReasonUnknown
+}
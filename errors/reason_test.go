@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReasonOf(t *testing.T) {
+	missing := WithReason(errors.New("missing"), ReasonMissingCredentials)
+	expired := WithReason(errors.New("expired"), ReasonExpired)
+	unknown := errors.New("unknown")
+
+	table := []struct {
+		name     string
+		err      error
+		expected Reason
+	}{
+		{
+			name:     "it return empty reason when error nil",
+			err:      nil,
+			expected: "",
+		},
+		{
+			name:     "it return unknown when error does not carry reason",
+			err:      unknown,
+			expected: ReasonUnknown,
+		},
+		{
+			name:     "it return error reason",
+			err:      expired,
+			expected: ReasonExpired,
+		},
+		{
+			name:     "it return wrapped error reason",
+			err:      fmt.Errorf("strategy: %w", expired),
+			expected: ReasonExpired,
+		},
+		{
+			name:     "it return upstream unavailable when deadline exceeded",
+			err:      context.DeadlineExceeded,
+			expected: ReasonUpstreamUnavailable,
+		},
+		{
+			name:     "it return most specific reason of multi error",
+			err:      MultiError{unknown, missing, expired, missing},
+			expected: ReasonExpired,
+		},
+		{
+			name:     "it return missing credentials of multi error when no other reason",
+			err:      Redacted(MultiError{unknown, missing}),
+			expected: ReasonMissingCredentials,
+		},
+		{
+			name:     "it return unknown of multi error without reasons",
+			err:      MultiError{unknown},
+			expected: ReasonUnknown,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ReasonOf(tt.err))
+		})
+	}
+}
+
+func TestWithReason(t *testing.T) {
+	base := errors.New("token expired")
+	err := WithReason(base, ReasonExpired)
+
+	assert.Equal(t, "token expired", err.Error())
+	assert.True(t, errors.Is(err, base))
+	// comparable, so it can be used as a sentinel error.
+	assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", err), err))
+}
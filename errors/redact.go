@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"regexp"
+)
+
+// RedactedText replaces secrets in redacted strings.
+const RedactedText = "[REDACTED]"
+
+var redactors = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// RFC 8959 secret-token URIs.
+	{
+		re:   regexp.MustCompile(`(?i)secret-token:[^\s"'<>]+`),
+		repl: RedactedText,
+	},
+	// Authorization header values, keep the scheme and redact the credentials.
+	{
+		re:   regexp.MustCompile(`\b(Bearer|Basic|ApiKey|Negotiate)\s+[A-Za-z0-9\-._~+/=:]+`),
+		repl: "${1} " + RedactedText,
+	},
+	// key value pairs, e.g password=secret, "token": "secret", or otp: 123456,
+	// the key must not be part of a path, e.g strategies/token: message.
+	{
+		re: regexp.MustCompile(
+			`(?i)(^|[\s"'&?{,;(])((?:access_|refresh_|id_|api_|x-api-)?(?:token|key)|password|passwd|secret|` +
+				`client_secret|otp|pin|passcode)(["']?\s*[:=]\s*["']?)[^\s"'&,;\]]+`,
+		),
+		repl: "${1}${2}${3}" + RedactedText,
+	},
+}
+
+// Redact return s after replacing the secrets it may contain with RedactedText,
+// it recognizes Authorization header values (e.g Bearer <token>), RFC 8959 secret-token URIs,
+// and key value pairs of common secret keys (e.g password=<secret>, otp: <code>).
+// Redact is best effort, and strategies must avoid formatting secrets into errors in the first place.
+func Redact(s string) string {
+	for _, r := range redactors {
+		s = r.re.ReplaceAllString(s, r.repl)
+	}
+	return s
+}
+
+// Redacted return error that formats as err message after redacting it, See Redact.
+// The returned error unwraps to err, so errors.Is and errors.As keep working.
+// if err message contains no secrets, err returned as is, to keep its identity.
+func Redacted(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := err.(redacted); ok {
+		return err
+	}
+
+	msg := err.Error()
+	if r := Redact(msg); r != msg {
+		return redacted{err: err, msg: r}
+	}
+
+	return err
+}
+
+type redacted struct {
+	err error
+	msg string
+}
+
+func (r redacted) Error() string { return r.msg }
+
+func (r redacted) Unwrap() error { return r.err }
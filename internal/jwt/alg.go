@@ -0,0 +1,188 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/asn1"
+	"math/big"
+
+	// register hash functions.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+// Algorithms supported by the package.
+const (
+	HS256 = "HS256"
+	HS384 = "HS384"
+	HS512 = "HS512"
+	RS256 = "RS256"
+	RS384 = "RS384"
+	RS512 = "RS512"
+	PS256 = "PS256"
+	PS384 = "PS384"
+	PS512 = "PS512"
+	ES256 = "ES256"
+	ES384 = "ES384"
+	ES512 = "ES512"
+	EdDSA = "EdDSA"
+)
+
+func hashOf(alg string) crypto.Hash {
+	switch alg {
+	case HS256, RS256, PS256, ES256:
+		return crypto.SHA256
+	case HS384, RS384, PS384, ES384:
+		return crypto.SHA384
+	case HS512, RS512, PS512, ES512:
+		return crypto.SHA512
+	}
+	return 0
+}
+
+// family return the algorithm family, i.e HS, RS, PS, ES, or EdDSA,
+// or empty string when the algorithm unsupported.
+func family(alg string) string {
+	switch alg {
+	case HS256, HS384, HS512:
+		return "HS"
+	case RS256, RS384, RS512:
+		return "RS"
+	case PS256, PS384, PS512:
+		return "PS"
+	case ES256, ES384, ES512:
+		return "ES"
+	case EdDSA:
+		return EdDSA
+	}
+	return ""
+}
+
+func digest(h crypto.Hash, msg []byte) []byte {
+	hh := h.New()
+	_, _ = hh.Write(msg)
+	return hh.Sum(nil)
+}
+
+func verify(alg string, key interface{}, msg, sig []byte) error {
+	h := hashOf(alg)
+
+	switch k := key.(type) {
+	case []byte:
+		if family(alg) != "HS" {
+			return ErrUnsupportedAlg
+		}
+		if !hmac.Equal(sig, hmacSum(h, k, msg)) {
+			return ErrInvalidSignature
+		}
+	case *rsa.PublicKey:
+		var err error
+		switch family(alg) {
+		case "RS":
+			err = rsa.VerifyPKCS1v15(k, h, digest(h, msg), sig)
+		case "PS":
+			err = rsa.VerifyPSS(k, h, digest(h, msg), sig, pssOptions(h))
+		default:
+			return ErrUnsupportedAlg
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+	case *ecdsa.PublicKey:
+		if family(alg) != "ES" {
+			return ErrUnsupportedAlg
+		}
+		size := curveSize(k)
+		if len(sig) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest(h, msg), r, s) {
+			return ErrInvalidSignature
+		}
+	case ed25519.PublicKey:
+		if alg != EdDSA {
+			return ErrUnsupportedAlg
+		}
+		if !ed25519.Verify(k, msg, sig) {
+			return ErrInvalidSignature
+		}
+	default:
+		return ErrUnsupportedAlg
+	}
+
+	return nil
+}
+
+func sign(alg string, key interface{}, msg []byte) ([]byte, error) {
+	h := hashOf(alg)
+
+	if k, ok := key.([]byte); ok {
+		if family(alg) != "HS" {
+			return nil, ErrUnsupportedAlg
+		}
+		return hmacSum(h, k, msg), nil
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, ErrUnsupportedAlg
+	}
+
+	switch pub := signer.Public().(type) {
+	case *rsa.PublicKey:
+		switch family(alg) {
+		case "RS":
+			return signer.Sign(rand.Reader, digest(h, msg), h)
+		case "PS":
+			return signer.Sign(rand.Reader, digest(h, msg), pssOptions(h))
+		}
+	case *ecdsa.PublicKey:
+		if family(alg) != "ES" {
+			return nil, ErrUnsupportedAlg
+		}
+
+		der, err := signer.Sign(rand.Reader, digest(h, msg), h)
+		if err != nil {
+			return nil, err
+		}
+
+		var v struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(der, &v); err != nil {
+			return nil, err
+		}
+
+		size := curveSize(pub)
+		sig := make([]byte, 2*size)
+		r, s := v.R.Bytes(), v.S.Bytes()
+		copy(sig[size-len(r):size], r)
+		copy(sig[2*size-len(s):], s)
+		return sig, nil
+	case ed25519.PublicKey:
+		if alg != EdDSA {
+			return nil, ErrUnsupportedAlg
+		}
+		return signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	}
+
+	return nil, ErrUnsupportedAlg
+}
+
+func hmacSum(h crypto.Hash, key, msg []byte) []byte {
+	m := hmac.New(h.New, key)
+	_, _ = m.Write(msg)
+	return m.Sum(nil)
+}
+
+func pssOptions(h crypto.Hash) *rsa.PSSOptions {
+	return &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: h}
+}
+
+func curveSize(k *ecdsa.PublicKey) int {
+	return (k.Curve.Params().BitSize + 7) / 8
+}
@@ -0,0 +1,146 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+var (
+	// ErrExpired is returned by Claims.Validate when the token expired.
+	ErrExpired = gerrors.WithReason(errors.New("jwt: Token expired"), gerrors.ReasonExpired)
+
+	// ErrNotValidYet is returned by Claims.Validate when the token used before its nbf.
+	ErrNotValidYet = errors.New("jwt: Token not valid yet")
+
+	// ErrInvalidIssuer is returned by Claims.Validate when the token iss does not match the expected.
+	ErrInvalidIssuer = errors.New("jwt: Invalid issuer")
+
+	// ErrInvalidAudience is returned by Claims.Validate when the token aud does not contain the expected.
+	ErrInvalidAudience = errors.New("jwt: Invalid audience")
+)
+
+// NumericDate represents JSON numeric date value, as seconds since the epoch.
+type NumericDate int64
+
+// NewNumericDate return NumericDate of the given time.
+func NewNumericDate(t time.Time) *NumericDate {
+	n := NumericDate(t.Unix())
+	return &n
+}
+
+// Time return the NumericDate as time.Time.
+func (n *NumericDate) Time() time.Time {
+	if n == nil {
+		return time.Time{}
+	}
+	return time.Unix(int64(*n), 0)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *NumericDate) UnmarshalJSON(b []byte) error {
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		return errors.New("jwt: Invalid numeric date")
+	}
+	*n = NumericDate(math.Round(f))
+	return nil
+}
+
+// Audience represents JWT aud claim, that can be a string or array of strings.
+type Audience []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+
+	var v []string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return errors.New("jwt: Invalid audience claim")
+	}
+
+	*a = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// Contains reports whether aud contains v.
+func (a Audience) Contains(v string) bool {
+	for _, s := range a {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims represents JWT registered claims.
+type Claims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	Expiry    *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Expected define the expected claims values used by Claims.Validate.
+// Empty fields are not validated.
+type Expected struct {
+	// Issuer is the expected iss claim.
+	Issuer string
+	// Audience token aud must contain at least one of the expected audience.
+	Audience []string
+	// Time used to validate exp and nbf, default time.Now.
+	Time time.Time
+	// Leeway is the allowed clock skew.
+	Leeway time.Duration
+}
+
+// Validate validates claims against the expected values.
+func (c Claims) Validate(e Expected) error {
+	now := e.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if c.Expiry != nil && now.Add(-e.Leeway).After(c.Expiry.Time()) {
+		return ErrExpired
+	}
+
+	if c.NotBefore != nil && now.Add(e.Leeway).Before(c.NotBefore.Time()) {
+		return ErrNotValidYet
+	}
+
+	if e.Issuer != "" && e.Issuer != c.Issuer {
+		return ErrInvalidIssuer
+	}
+
+	if len(e.Audience) == 0 {
+		return nil
+	}
+
+	for _, aud := range e.Audience {
+		if c.Audience.Contains(aud) {
+			return nil
+		}
+	}
+
+	return ErrInvalidAudience
+}
@@ -0,0 +1,241 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound is returned when the key set does not have a key with the token kid.
+var ErrKeyNotFound = errors.New("jwt: Signing key not found")
+
+// JSONWebKey represents a public JSON Web Key.
+type JSONWebKey struct {
+	// Key is the parsed public key,
+	// *rsa.PublicKey, *ecdsa.PublicKey, or ed25519.PublicKey.
+	Key       crypto.PublicKey `json:"-"`
+	KeyType   string           `json:"kty"`
+	KeyID     string           `json:"kid,omitempty"`
+	Use       string           `json:"use,omitempty"`
+	Algorithm string           `json:"alg,omitempty"`
+	N         string           `json:"n,omitempty"`
+	E         string           `json:"e,omitempty"`
+	Curve     string           `json:"crv,omitempty"`
+	X         string           `json:"x,omitempty"`
+	Y         string           `json:"y,omitempty"`
+}
+
+// NewJSONWebKey return JSONWebKey of the given public key.
+func NewJSONWebKey(pub crypto.PublicKey, kid, alg string) (JSONWebKey, error) {
+	jwk := JSONWebKey{Key: pub, KeyID: kid, Algorithm: alg, Use: "sig"}
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encoding.EncodeToString(k.N.Bytes())
+		jwk.E = encoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := curveSize(k)
+		x, y := make([]byte, size), make([]byte, size)
+		xb, yb := k.X.Bytes(), k.Y.Bytes()
+		copy(x[size-len(xb):], xb)
+		copy(y[size-len(yb):], yb)
+		jwk.KeyType = "EC"
+		jwk.Curve = k.Curve.Params().Name
+		jwk.X = encoding.EncodeToString(x)
+		jwk.Y = encoding.EncodeToString(y)
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encoding.EncodeToString(k)
+	default:
+		return jwk, fmt.Errorf("jwt: Unsupported public key type %T", pub)
+	}
+
+	return jwk, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, and parse the public key.
+func (j *JSONWebKey) UnmarshalJSON(b []byte) error {
+	type raw JSONWebKey
+	if err := json.Unmarshal(b, (*raw)(j)); err != nil {
+		return err
+	}
+
+	var err error
+	j.Key, err = j.parse()
+	return err
+}
+
+func (j *JSONWebKey) parse() (crypto.PublicKey, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err1 := encoding.DecodeString(j.N)
+		e, err2 := encoding.DecodeString(j.E)
+		if err1 != nil || err2 != nil || len(e) > 4 {
+			return nil, errors.New("jwt: Invalid RSA JWK")
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("jwt: Unsupported JWK curve %s", j.Curve)
+		}
+		x, err1 := encoding.DecodeString(j.X)
+		y, err2 := encoding.DecodeString(j.Y)
+		if err1 != nil || err2 != nil {
+			return nil, errors.New("jwt: Invalid EC JWK")
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	case "OKP":
+		x, err := encoding.DecodeString(j.X)
+		if err != nil || j.Curve != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwt: Invalid OKP JWK")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+
+	// unknown key types ignored as described in RFC 7517 section 5.
+	return nil, nil
+}
+
+// KeySet represents JSON Web Key Set.
+type KeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Key return the key of the given kid.
+// if kid empty and the set has a single key, the key returned.
+func (s KeySet) Key(kid string) (crypto.PublicKey, error) {
+	if kid == "" && len(s.Keys) == 1 && s.Keys[0].Key != nil {
+		return s.Keys[0].Key, nil
+	}
+
+	for _, k := range s.Keys {
+		if k.KeyID == kid && k.Key != nil {
+			return k.Key, nil
+		}
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// RemoteKeySet fetches and caches a JSON Web Key Set from URL,
+// The set re-fetched when a token signed by unknown kid,
+// at most once per the refresh interval, to support issuer keys rotation.
+type RemoteKeySet struct {
+	URL      string
+	Client   *http.Client
+	Interval time.Duration
+
+	mu      sync.Mutex
+	set     KeySet
+	fetched time.Time
+}
+
+// NewRemoteKeySet return new RemoteKeySet.
+func NewRemoteKeySet(url string, c *http.Client) *RemoteKeySet {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	return &RemoteKeySet{
+		URL:      url,
+		Client:   c,
+		Interval: time.Minute,
+	}
+}
+
+// Key return the key of the given kid.
+func (r *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if k, err := r.set.Key(kid); err == nil {
+		return k, nil
+	}
+
+	if !r.fetched.IsZero() && time.Since(r.fetched) < r.Interval {
+		return nil, ErrKeyNotFound
+	}
+
+	if err := r.fetch(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.set.Key(kid)
+}
+
+func (r *RemoteKeySet) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwt: Failed to fetch key set, got status code %d", resp.StatusCode)
+	}
+
+	set := KeySet{}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("jwt: Failed to decode key set Err: %s", err)
+	}
+
+	r.set = set
+	r.fetched = time.Now()
+
+	return nil
+}
+
+// Verify parse the token and verifies its signature using the key set,
+// then decode the payload into v.
+func (r *RemoteKeySet) Verify(ctx context.Context, token string, v interface{}) (*Token, error) {
+	t, err := Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.Key(ctx, t.Header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.Verify(key); err != nil {
+		return nil, err
+	}
+
+	return t, t.Decode(v)
+}
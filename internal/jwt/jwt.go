@@ -0,0 +1,128 @@
+// Package jwt implements the subset of JWS compact serialization (RFC 7515),
+// JWT (RFC 7519) and JWK (RFC 7517) used by go-guardian strategies,
+// on top of the standard library crypto packages.
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	gerrors "github.com/shaj13/go-guardian/errors"
+)
+
+var (
+	// ErrMalformed is returned by Parse when the token is not a JWS compact serialization.
+	ErrMalformed = gerrors.WithReason(errors.New("jwt: Malformed token"), gerrors.ReasonMalformed)
+
+	// ErrUnsupportedAlg is returned when the token alg not supported or not matching the key.
+	ErrUnsupportedAlg = errors.New("jwt: Unsupported signing algorithm")
+
+	// ErrInvalidSignature is returned when the token signature verification fail.
+	ErrInvalidSignature = gerrors.WithReason(
+		errors.New("jwt: Invalid signature"),
+		gerrors.ReasonInvalidCredentials,
+	)
+)
+
+var encoding = base64.RawURLEncoding
+
+// Header represents JOSE header.
+type Header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid,omitempty"`
+	Type      string `json:"typ,omitempty"`
+}
+
+// Token represents a parsed and not yet verified JWT.
+type Token struct {
+	Header    Header
+	payload   []byte
+	signed    []byte
+	signature []byte
+}
+
+// Parse parse the compact serialized token without verifying its signature.
+func Parse(token string) (*Token, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+
+	h, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	payload, err := encoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	sig, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	t := &Token{
+		payload:   payload,
+		signed:    []byte(parts[0] + "." + parts[1]),
+		signature: sig,
+	}
+
+	if err := json.Unmarshal(h, &t.Header); err != nil {
+		return nil, ErrMalformed
+	}
+
+	return t, nil
+}
+
+// Verify verifies the token signature using the given key,
+// key type must match the token alg, See Sign.
+func (t *Token) Verify(key interface{}) error {
+	return verify(t.Header.Algorithm, key, t.signed, t.signature)
+}
+
+// Decode unmarshal the token payload into v.
+func (t *Token) Decode(v interface{}) error {
+	d := json.NewDecoder(bytes.NewReader(t.payload))
+	d.UseNumber()
+	return d.Decode(v)
+}
+
+// Payload return the raw token payload.
+func (t *Token) Payload() []byte {
+	return t.payload
+}
+
+// Sign serialize claims and sign it using the given alg and key,
+// and return compact serialized token.
+// key can be crypto.Signer for asymmetric algorithms or []byte for HMAC.
+func Sign(alg, kid string, key interface{}, claims interface{}) (string, error) {
+	return SignWithType(alg, kid, "JWT", key, claims)
+}
+
+// SignWithType is similar to Sign, but sets the token typ header to the given type,
+// used for explicit typing (RFC 8725 section-3.11) of tokens that must not be confused with each other.
+func SignWithType(alg, kid, typ string, key interface{}, claims interface{}) (string, error) {
+	h, err := json.Marshal(Header{Algorithm: alg, KeyID: kid, Type: typ})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := encoding.EncodeToString(h) + "." + encoding.EncodeToString(payload)
+
+	sig, err := sign(alg, key, []byte(signed))
+	if err != nil {
+		return "", err
+	}
+
+	return signed + "." + encoding.EncodeToString(sig), nil
+}
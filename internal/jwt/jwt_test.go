@@ -0,0 +1,199 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignVerify(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ec521Key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	secret := []byte("secret")
+
+	table := []struct {
+		alg    string
+		key    interface{}
+		verify interface{}
+		err    error
+	}{
+		{alg: HS256, key: secret, verify: secret},
+		{alg: HS512, key: secret, verify: []byte("invalid"), err: ErrInvalidSignature},
+		{alg: RS256, key: rsaKey, verify: &rsaKey.PublicKey},
+		{alg: PS384, key: rsaKey, verify: &rsaKey.PublicKey},
+		{alg: ES256, key: ecKey, verify: &ecKey.PublicKey},
+		{alg: ES512, key: ec521Key, verify: &ec521Key.PublicKey},
+		{alg: EdDSA, key: edKey, verify: edKey.Public()},
+		{alg: RS256, key: rsaKey, verify: secret, err: ErrUnsupportedAlg},
+		{alg: "none", key: rsaKey, verify: &rsaKey.PublicKey, err: ErrUnsupportedAlg},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.alg, func(t *testing.T) {
+			claims := Claims{Subject: "1", Audience: Audience{"test"}}
+
+			token, err := Sign(tt.alg, "kid", tt.key, claims)
+			if tt.alg == "none" {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			tk, err := Parse(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "kid", tk.Header.KeyID)
+			assert.Equal(t, tt.err, tk.Verify(tt.verify))
+
+			got := Claims{}
+			assert.NoError(t, tk.Decode(&got))
+			assert.Equal(t, claims, got)
+		})
+	}
+}
+
+func TestMalformedAlg(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	secret := []byte("secret")
+
+	for _, alg := range []string{"", "H", "E", "R", "P", "HS", "ES", "none"} {
+		t.Run("alg="+alg, func(t *testing.T) {
+			for _, key := range []interface{}{secret, rsaKey, ecKey} {
+				_, err := sign(alg, key, []byte("msg"))
+				assert.Equal(t, ErrUnsupportedAlg, err)
+			}
+
+			for _, key := range []interface{}{secret, &rsaKey.PublicKey, &ecKey.PublicKey} {
+				assert.Equal(t, ErrUnsupportedAlg, verify(alg, key, []byte("msg"), []byte("sig")))
+			}
+
+			header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"` + alg + `"}`))
+			tk, err := Parse(header + ".e30.c2ln")
+			assert.NoError(t, err)
+			assert.Error(t, tk.Verify(&ecKey.PublicKey))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, token := range []string{"", "a.b", "a.b.c", "e30.e30.!"} {
+		_, err := Parse(token)
+		assert.Equal(t, ErrMalformed, err)
+	}
+}
+
+func TestClaimsValidate(t *testing.T) {
+	now := time.Now()
+
+	table := []struct {
+		name     string
+		claims   Claims
+		expected Expected
+		err      error
+	}{
+		{
+			name:   "it return error when token expired",
+			claims: Claims{Expiry: NewNumericDate(now.Add(-time.Minute))},
+			err:    ErrExpired,
+		},
+		{
+			name:     "it allow leeway for expired token",
+			claims:   Claims{Expiry: NewNumericDate(now.Add(-time.Minute))},
+			expected: Expected{Leeway: time.Hour},
+		},
+		{
+			name:   "it return error when token not valid yet",
+			claims: Claims{NotBefore: NewNumericDate(now.Add(time.Minute))},
+			err:    ErrNotValidYet,
+		},
+		{
+			name:     "it return error when issuer invalid",
+			claims:   Claims{Issuer: "a"},
+			expected: Expected{Issuer: "b"},
+			err:      ErrInvalidIssuer,
+		},
+		{
+			name:     "it return error when audience invalid",
+			claims:   Claims{Audience: Audience{"a"}},
+			expected: Expected{Audience: []string{"b"}},
+			err:      ErrInvalidAudience,
+		},
+		{
+			name:     "it return nil when claims valid",
+			claims:   Claims{Issuer: "a", Audience: Audience{"a", "b"}, Expiry: NewNumericDate(now.Add(time.Minute))},
+			expected: Expected{Issuer: "a", Audience: []string{"c", "b"}},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, tt.claims.Validate(tt.expected))
+		})
+	}
+}
+
+func TestAudience(t *testing.T) {
+	a := Audience{}
+	assert.NoError(t, json.Unmarshal([]byte(`"a"`), &a))
+	assert.Equal(t, Audience{"a"}, a)
+
+	b, _ := json.Marshal(a)
+	assert.Equal(t, `"a"`, string(b))
+
+	assert.NoError(t, json.Unmarshal([]byte(`["a","b"]`), &a))
+	assert.Equal(t, Audience{"a", "b"}, a)
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	set := KeySet{}
+	for kid, pub := range map[string]crypto.PublicKey{
+		"rsa": &rsaKey.PublicKey,
+		"ec":  &ecKey.PublicKey,
+		"ed":  edKey.Public(),
+	} {
+		jwk, err := NewJSONWebKey(pub, kid, "")
+		assert.NoError(t, err)
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	ks := NewRemoteKeySet(srv.URL, srv.Client())
+
+	for kid, key := range map[string]interface{}{"rsa": rsaKey, "ec": ecKey, "ed": edKey} {
+		alg := map[string]string{"rsa": RS256, "ec": ES384, "ed": EdDSA}[kid]
+		token, _ := Sign(alg, kid, key, Claims{Subject: kid})
+
+		got := Claims{}
+		_, err := ks.Verify(context.Background(), token, &got)
+		assert.NoError(t, err)
+		assert.Equal(t, kid, got.Subject)
+	}
+
+	_, err := ks.Key(context.Background(), "unknown")
+	assert.Equal(t, ErrKeyNotFound, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,13 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashKey return the hex encoded SHA-256 digest of the given raw credential,
+// used as a cache key, so secrets never sit as plaintext keys in caches.
+func HashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
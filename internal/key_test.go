@@ -0,0 +1,15 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashKey(t *testing.T) {
+	key := HashKey("token")
+
+	assert.Equal(t, "3c469e9d6c5875d37a43f353d4f88e61fcf812c66eee3457465a40b0da4153e0", key)
+	assert.Equal(t, key, HashKey("token"))
+	assert.NotEqual(t, key, HashKey("token2"))
+}
@@ -0,0 +1,89 @@
+// Package keyring provides an AES-GCM key ring,
+// that seal values using the newest key, and open values sealed by any of the keys,
+// so keys rotated by prepending a new key, and dropping the oldest key once its values expired or re-sealed.
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+)
+
+var (
+	// ErrInvalidKey is returned by New, when a key is not 16, 24, or 32 bytes.
+	ErrInvalidKey = errors.New("keyring: Key must be 16, 24, or 32 bytes")
+
+	// ErrMissingKeys is returned by New, when no keys given.
+	ErrMissingKeys = errors.New("keyring: At least one key required")
+
+	// ErrOpen is returned by Ring Open, when the sealed value malformed, tampered, or sealed by an unknown key.
+	ErrOpen = errors.New("keyring: Message authentication failed")
+)
+
+// Ring seal and open values using AES-GCM.
+type Ring struct {
+	aeads []cipher.AEAD
+}
+
+// New return Ring of the given keys, newest first.
+// Keys must be random 16, 24, or 32 bytes, selecting AES-128, AES-192, or AES-256.
+func New(keys [][]byte) (*Ring, error) {
+	if len(keys) == 0 {
+		return nil, ErrMissingKeys
+	}
+
+	r := new(Ring)
+
+	for _, k := range keys {
+		switch len(k) {
+		case 16, 24, 32:
+		default:
+			return nil, ErrInvalidKey
+		}
+
+		block, err := aes.NewCipher(k)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		r.aeads = append(r.aeads, aead)
+	}
+
+	return r, nil
+}
+
+// Seal encrypt and authenticate the given plaintext and additional data using the newest key,
+// and return the random nonce followed by the ciphertext.
+func (r *Ring) Seal(plaintext, ad []byte) ([]byte, error) {
+	aead := r.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, ad), nil
+}
+
+// Open verify and decrypt the given sealed value using any of the keys.
+func (r *Ring) Open(sealed, ad []byte) ([]byte, error) {
+	for _, aead := range r.aeads {
+		if len(sealed) < aead.NonceSize() {
+			continue
+		}
+
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+
+		if plaintext, err := aead.Open(nil, nonce, ciphertext, ad); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, ErrOpen
+}
@@ -0,0 +1,85 @@
+package keyring
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	_, err := New(nil)
+	assert.Equal(t, ErrMissingKeys, err)
+
+	_, err = New([][]byte{[]byte("short")})
+	assert.Equal(t, ErrInvalidKey, err)
+
+	for _, n := range []int{16, 24, 32} {
+		_, err = New([][]byte{bytes.Repeat([]byte("k"), n)})
+		assert.NoError(t, err)
+	}
+}
+
+func TestRing(t *testing.T) {
+	oldKey, newKey := bytes.Repeat([]byte("o"), 32), bytes.Repeat([]byte("n"), 16)
+
+	old, _ := New([][]byte{oldKey})
+	rotated, _ := New([][]byte{newKey, oldKey})
+	other, _ := New([][]byte{newKey})
+
+	sealed, err := old.Seal([]byte("value"), []byte("ad"))
+	assert.NoError(t, err)
+
+	table := []struct {
+		name   string
+		ring   *Ring
+		sealed []byte
+		ad     string
+		err    error
+	}{
+		{
+			name:   "it open value sealed by the same key",
+			ring:   old,
+			sealed: sealed,
+			ad:     "ad",
+		},
+		{
+			name:   "it open value sealed by an older key",
+			ring:   rotated,
+			sealed: sealed,
+			ad:     "ad",
+		},
+		{
+			name:   "it return error when key unknown",
+			ring:   other,
+			sealed: sealed,
+			ad:     "ad",
+			err:    ErrOpen,
+		},
+		{
+			name:   "it return error when additional data mismatch",
+			ring:   old,
+			sealed: sealed,
+			ad:     "other",
+			err:    ErrOpen,
+		},
+		{
+			name:   "it return error when value truncated",
+			ring:   old,
+			sealed: sealed[:4],
+			ad:     "ad",
+			err:    ErrOpen,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.ring.Open(tt.sealed, []byte(tt.ad))
+			assert.Equal(t, tt.err, err)
+
+			if err == nil {
+				assert.Equal(t, "value", string(v))
+			}
+		})
+	}
+}
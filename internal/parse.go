@@ -12,7 +12,12 @@ import (
 
 //ParseHeader extract specific header value or return provided error.
 func ParseHeader(header string, r *http.Request, err error) (string, error) {
-	value := r.Header.Get(header)
+	return ParseHeaderValue(header, r.Header, err)
+}
+
+// ParseHeaderValue extract header value from the given headers or return provided error.
+func ParseHeaderValue(header string, h http.Header, err error) (string, error) {
+	value := h.Get(header)
 	value = strings.TrimSpace(value)
 
 	if value == "" {
@@ -24,23 +29,31 @@ func ParseHeader(header string, r *http.Request, err error) (string, error) {
 
 //ParseAuthorizationHeader extract Authorization header value or return provided error.
 func ParseAuthorizationHeader(key string, r *http.Request, err error) (string, error) {
-	header := r.Header.Get("Authorization")
+	return ParseAuthorizationHeaderValue(key, r.Header, err)
+}
+
+// ParseAuthorizationHeaderValue extract Authorization header value from the given headers
+// or return provided error.
+// The value sliced from the header using index math to avoid allocations on hot path.
+func ParseAuthorizationHeaderValue(key string, h http.Header, err error) (string, error) {
+	header := h.Get("Authorization")
 	header = strings.TrimSpace(header)
 
-	if header == "" {
+	i := strings.IndexByte(header, ' ')
+	if i < 0 || header[:i] != key {
 		return "", err
 	}
 
-	value := strings.Split(header, " ")
-	if len(value) < 2 || value[0] != key {
-		return "", err
+	value := header[i+1:]
+	if j := strings.IndexByte(value, ' '); j >= 0 {
+		value = value[:j]
 	}
 
-	if len(value[1]) == 0 {
+	if len(value) == 0 {
 		return "", err
 	}
 
-	return value[1], nil
+	return value, nil
 }
 
 //ParseQuery extract key value form HTTP query string  or return provided error.
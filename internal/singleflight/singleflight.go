@@ -0,0 +1,58 @@
+// Package singleflight provides a duplicate function call suppression mechanism,
+// a minimal version of golang.org/x/sync/singleflight.
+package singleflight
+
+import "sync"
+
+type call struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+	dup bool
+}
+
+// Group represents a class of work and forms a namespace,
+// in which units of work can be executed with duplicate suppression.
+// The zero value is ready to use.
+type Group struct {
+	mu sync.Mutex
+	m  map[string]*call
+}
+
+// Do executes and returns the results of the given function,
+// making sure that only one execution is in-flight for a given key at a time.
+// If a duplicate comes in, the duplicate caller waits for the original to complete,
+// and receives the same results. The return value shared reports whether v was given to multiple callers.
+func (g *Group) Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+
+	if c, ok := g.m[key]; ok {
+		c.dup = true
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+
+	c := new(call)
+	c.wg.Add(1)
+	g.m[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.m, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.val, c.err = fn()
+
+	g.mu.Lock()
+	shared = c.dup
+	g.mu.Unlock()
+
+	return c.val, c.err, shared
+}
@@ -0,0 +1,58 @@
+package singleflight
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDo(t *testing.T) {
+	g := new(Group)
+	v, err, shared := g.Do("key", func() (interface{}, error) {
+		return "bar", nil
+	})
+
+	assert.Equal(t, "bar", v)
+	assert.NoError(t, err)
+	assert.False(t, shared)
+
+	want := errors.New("test")
+	_, err, _ = g.Do("key", func() (interface{}, error) {
+		return nil, want
+	})
+
+	assert.Equal(t, want, err)
+}
+
+func TestDoDupSuppress(t *testing.T) {
+	g := new(Group)
+	calls := int32(0)
+	release := make(chan struct{})
+	wg := sync.WaitGroup{}
+
+	fn := func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return "bar", nil
+	}
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, _ := g.Do("key", fn)
+			assert.Equal(t, "bar", v)
+			assert.NoError(t, err)
+		}()
+	}
+
+	time.Sleep(time.Millisecond * 50)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,15 @@
+package otp
+
+// CounterStore load and increment HOTP counters outside the Key,
+// so HOTP verification works correctly across multiple server instances,
+// and survives restarts without losing counters, e.g a store backed by a database or Redis INCR.
+//
+// Implementations identify the key counter, typically by the key label.
+type CounterStore interface {
+	// Get return the current counter of the given key.
+	Get(k *Key) (uint64, error)
+	// Increment atomically increments the counter of the given key, and return the incremented counter.
+	// The key counter (i.e the provisioned initial counter) is the current counter,
+	// when the store has no counter of the key.
+	Increment(k *Key) (uint64, error)
+}
@@ -1,15 +1,17 @@
 package otp
 
 import (
-	"bytes"
 	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha512"
 	"encoding/base32"
 	"encoding/binary"
 	"errors"
+	"hash"
 	"math"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // ErrWeakSecretSize is returned by GenerateSecret,
@@ -20,33 +22,51 @@ var ErrWeakSecretSize = errors.New("Weak secret size, The shared secret MUST be
 // The function compliant with RFC 4226, and implemented as mentioned in section 5.3
 // See https://tools.ietf.org/html/rfc4226#section-5.3
 func GenerateOTP(secret string, counter uint64, algo HashAlgorithm, dig Digits) (string, error) {
-	secret = strings.ToUpper(secret)
-	key, err := base32.StdEncoding.DecodeString(secret)
+	g := generators.Get().(*generator)
+	defer generators.Put(g)
 
-	if err != nil {
-		return "", err
-	}
+	code, err := g.generate(secret, counter, algo, dig)
+	return string(code), err
+}
 
-	interval := make([]byte, 8)
-	binary.BigEndian.PutUint64(interval, counter)
+var generators = sync.Pool{
+	New: func() interface{} { return new(generator) },
+}
 
-	hash := hmac.New(algo.Hasher(), key)
-	_, err = hash.Write(interval)
+// generator generates one time passwords into fixed-size buffers,
+// and reuse the HMAC state across calls having the same secret and algorithm.
+// generator not safe for concurrent usage.
+type generator struct {
+	secret string
+	algo   HashAlgorithm
+	mac    hash.Hash
+	msg    [8]byte
+	sum    [sha512.Size]byte
+	code   [20]byte
+}
 
-	if err != nil {
-		return "", err
+// generate return one time password, the returned slice valid until the next call.
+func (g *generator) generate(secret string, counter uint64, algo HashAlgorithm, dig Digits) ([]byte, error) {
+	if g.mac == nil || g.secret != secret || g.algo != algo {
+		key, err := base32.StdEncoding.DecodeString(strings.ToUpper(secret))
+		if err != nil {
+			return nil, err
+		}
+
+		g.mac = hmac.New(algo.Hasher(), key)
+		g.secret = secret
+		g.algo = algo
 	}
 
-	result := hash.Sum(nil)
+	binary.BigEndian.PutUint64(g.msg[:], counter)
+
+	g.mac.Reset()
+	_, _ = g.mac.Write(g.msg[:])
+	result := g.mac.Sum(g.sum[:0])
 
 	// Truncate logic performs Step 2 and Step 3 in RFC 4226 section 5.3
-	var binCode uint32
 	offset := result[len(result)-1] & 0xf
-	reader := bytes.NewReader(result[offset : offset+4])
-	err = binary.Read(reader, binary.BigEndian, &binCode)
-	if err != nil {
-		return "", err
-	}
+	binCode := binary.BigEndian.Uint32(result[offset : offset+4])
 
 	// 0x7FFFFFFF mask is a number in hexadecimal (2,147,483,647 in decimal)
 	// that represents the maximum positive value for a 32-bit signed binary integer.
@@ -56,7 +76,7 @@ func GenerateOTP(secret string, counter uint64, algo HashAlgorithm, dig Digits)
 	// signed bit removes all ambiguity.
 	code := int(binCode&0x7fffffff) % int(math.Pow10(int(dig)))
 
-	return strconv.Itoa(code), nil
+	return strconv.AppendInt(g.code[:0], int64(code), 10), nil
 }
 
 // GenerateSecret return base32 random generated secret.
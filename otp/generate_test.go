@@ -12,6 +12,32 @@ func TestGenerateOTP(t *testing.T) {
 	assert.Equal(t, otp, "639434")
 }
 
+func TestGenerateOTPVectors(t *testing.T) {
+	// RFC 4226 appendix D test values.
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	g := new(generator)
+
+	for i, want := range expected {
+		otp, err := GenerateOTP(secret, uint64(i), SHA1, SixDigits)
+		assert.NoError(t, err)
+		assert.Equal(t, want, otp)
+
+		// generator reuse its state across secrets and algorithms.
+		_, _ = g.generate("GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA", uint64(i), SHA256, EightDigits)
+		code, err := g.generate(secret, uint64(i), SHA1, SixDigits)
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(code))
+	}
+
+	_, err := g.generate("invalid-secret!", 0, SHA1, SixDigits)
+	assert.Error(t, err)
+}
+
 func TestGenerateSecret(t *testing.T) {
 	// Round #1 it return error when secretsize < 16
 	_, err := GenerateSecret(1)
@@ -22,3 +48,29 @@ func TestGenerateSecret(t *testing.T) {
 	assert.Equal(t, len(str), 32)
 	assert.Nil(t, err)
 }
+
+func BenchmarkGenerateOTP(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = GenerateOTP("GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA", uint64(i), SHA1, SixDigits)
+	}
+}
+
+func BenchmarkVerifierGenerateOTP(b *testing.B) {
+	v := New(NewKey(TOTP, "label", "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA"))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = v.GenerateOTP()
+	}
+}
+
+func BenchmarkVerifierVerify(b *testing.B) {
+	v := New(NewKey(TOTP, "label", "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA"))
+	v.EnableLockout = false
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = v.Verify("000000")
+	}
+}
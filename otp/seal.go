@@ -0,0 +1,130 @@
+package otp
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/shaj13/go-guardian/internal/keyring"
+)
+
+var (
+	// ErrInvalidSealed is returned by Sealer,
+	// when the sealed value malformed, tampered, or sealed by an unknown key.
+	ErrInvalidSealed = errors.New("OTP: Invalid sealed secret")
+
+	// ErrInvalidSealKey is returned by NewSealer, when a key is not 16, 24, or 32 bytes.
+	ErrInvalidSealKey = errors.New("OTP: Seal key must be 16, 24, or 32 bytes")
+
+	// ErrMissingSealKeys is returned by NewSealer, when no keys given.
+	ErrMissingSealKeys = errors.New("OTP: At least one seal key required")
+)
+
+// sealAD is the additional data binding sealed values to their purpose.
+var sealAD = []byte("otp.secret")
+
+// Sealer seal and unseal OTP secrets (seeds) using AES-GCM,
+// so seeds encrypted at rest, before handed to the two factor OTP manager for persistence.
+//
+//	s, err := otp.NewSealerFromEnv("OTP_SEAL_KEYS")
+//	sealed, err := s.SealKey(key) // persist sealed instead of key.String().
+//	key, err = s.UnsealKey(sealed)
+type Sealer struct {
+	ring *keyring.Ring
+}
+
+// NewSealer return Sealer that seal values using the first key,
+// and unseal values sealed by any of the keys,
+// so keys rotated by prepending a new key, and dropping the oldest key once its values re-sealed.
+// Keys must be random 16, 24, or 32 bytes, selecting AES-128, AES-192, or AES-256.
+func NewSealer(keys [][]byte) (*Sealer, error) {
+	if len(keys) == 0 {
+		return nil, ErrMissingSealKeys
+	}
+
+	ring, err := keyring.New(keys)
+	if err == keyring.ErrInvalidKey {
+		return nil, ErrInvalidSealKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sealer{ring: ring}, nil
+}
+
+// NewSealerFromEnv return Sealer of the keys held by the given environment variable,
+// See ParseSealKeys.
+func NewSealerFromEnv(name string) (*Sealer, error) {
+	keys, err := ParseSealKeys(os.Getenv(name))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSealer(keys)
+}
+
+// ParseSealKeys parse comma separated base64 (standard encoding) keys, newest first,
+// as held by configuration, environment variables, or secrets providers.
+func ParseSealKeys(v string) ([][]byte, error) {
+	keys := [][]byte{}
+
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 {
+			continue
+		}
+
+		k, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, ErrInvalidSealKey
+		}
+
+		keys = append(keys, k)
+	}
+
+	return keys, nil
+}
+
+// Seal encrypt and authenticate the given secret,
+// and return it base64 URL encoded without padding.
+func (s *Sealer) Seal(secret string) (string, error) {
+	out, err := s.ring.Seal([]byte(secret), sealAD)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+// Unseal verify and decrypt the given sealed secret.
+func (s *Sealer) Unseal(sealed string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", ErrInvalidSealed
+	}
+
+	plaintext, err := s.ring.Open(b, sealAD)
+	if err != nil {
+		return "", ErrInvalidSealed
+	}
+
+	return string(plaintext), nil
+}
+
+// SealKey seal the given key URI, including its secret and parameters.
+func (s *Sealer) SealKey(k *Key) (string, error) {
+	return s.Seal(k.String())
+}
+
+// UnsealKey unseal a key URI sealed by SealKey.
+func (s *Sealer) UnsealKey(sealed string) (*Key, error) {
+	raw, err := s.Unseal(sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyFromRaw(raw)
+}
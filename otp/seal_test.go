@@ -0,0 +1,74 @@
+package otp
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealer(t *testing.T) {
+	oldKey := bytes.Repeat([]byte("o"), 16)
+	newKey := bytes.Repeat([]byte("n"), 32)
+
+	old, err := NewSealer([][]byte{oldKey})
+	assert.NoError(t, err)
+
+	s, err := NewSealer([][]byte{newKey, oldKey})
+	assert.NoError(t, err)
+
+	key := NewKey(TOTP, "alice", "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA")
+
+	// Round #1 seal and unseal key
+	sealed, err := s.SealKey(key)
+	assert.NoError(t, err)
+	assert.NotContains(t, sealed, key.Secret())
+
+	k, err := s.UnsealKey(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, key.String(), k.String())
+
+	// Round #2 unseal value sealed by rotated key
+	sealed, err = old.Seal("seed")
+	assert.NoError(t, err)
+
+	v, err := s.Unseal(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "seed", v)
+
+	// Round #3 return error when value sealed by unknown key or tampered
+	sealed, _ = s.Seal("seed")
+	_, err = old.Unseal(sealed)
+	assert.Equal(t, ErrInvalidSealed, err)
+
+	_, err = s.Unseal(sealed[:len(sealed)-2] + "AA")
+	assert.Equal(t, ErrInvalidSealed, err)
+
+	_, err = s.Unseal("!")
+	assert.Equal(t, ErrInvalidSealed, err)
+}
+
+func TestNewSealer(t *testing.T) {
+	_, err := NewSealer(nil)
+	assert.Equal(t, ErrMissingSealKeys, err)
+
+	_, err = NewSealer([][]byte{[]byte("short")})
+	assert.Equal(t, ErrInvalidSealKey, err)
+
+	os.Setenv("OTP_SEAL_KEYS_TEST", "bmJiYmJiYmJiYmJiYmJiYg==, b29vb29vb29vb29vb29vbw==")
+	defer os.Unsetenv("OTP_SEAL_KEYS_TEST")
+
+	s, err := NewSealerFromEnv("OTP_SEAL_KEYS_TEST")
+	assert.NoError(t, err)
+
+	// the second key unseal values sealed by the older key.
+	old, _ := NewSealer([][]byte{[]byte("oooooooooooooooo")})
+	sealed, _ := old.Seal("secret")
+	v, err := s.Unseal(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", v)
+
+	_, err = NewSealerFromEnv("OTP_SEAL_KEYS_UNSET")
+	assert.Equal(t, ErrMissingSealKeys, err)
+}
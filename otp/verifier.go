@@ -1,13 +1,26 @@
 package otp
 
 import (
-	"errors"
+	"math"
+	"math/rand"
+	"sync"
 	"time"
+
+	"github.com/shaj13/go-guardian/errors"
 )
 
 // ErrMaxAttempts is returned by Verifier,
 // When the verification failures count equal the max attempts.
-var ErrMaxAttempts = errors.New("OTP: Max attempts reached, Account locked out")
+var ErrMaxAttempts error = maxAttemptsError{}
+
+// maxAttemptsError implements auth.LocalizableError,
+// without importing auth, so its client-facing message can be customized or localized.
+type maxAttemptsError struct{}
+
+func (maxAttemptsError) Error() string              { return "OTP: Max attempts reached, Account locked out" }
+func (maxAttemptsError) MessageKey() string         { return "otp.max_attempts" }
+func (maxAttemptsError) MessageArgs() []interface{} { return nil }
+func (maxAttemptsError) Reason() errors.Reason      { return errors.ReasonLockedOut }
 
 // VerificationDisabledError is returned by Verifier
 // when the password verification process disabled for a period of time.
@@ -18,6 +31,40 @@ func (v VerificationDisabledError) Error() string {
 	return "OTP: Password verification disabled, Try again in " + time.Duration(v).String()
 }
 
+// MessageKey return the error message key, to lookup its client-facing message,
+// in a message catalog (e.g auth.MessageCatalog).
+func (v VerificationDisabledError) MessageKey() string {
+	return "otp.verification_disabled"
+}
+
+// MessageArgs return the error message format arguments, the remaining lockout duration.
+func (v VerificationDisabledError) MessageArgs() []interface{} {
+	return []interface{}{time.Duration(v).Round(time.Second).String()}
+}
+
+// Reason return the error failure reason, errors.ReasonLockedOut.
+func (v VerificationDisabledError) Reason() errors.Reason {
+	return errors.ReasonLockedOut
+}
+
+// RetryAfter return the remaining lockout duration,
+// so the error mapped to 429 Too Many Requests with the Retry-After header, See auth.RetryAfterError.
+func (v VerificationDisabledError) RetryAfter() time.Duration {
+	return time.Duration(v)
+}
+
+// Backoff represents the lockout delay growth shape.
+type Backoff int
+
+const (
+	// LinearBackoff grows the lockout delay as delay * failed Attempts, as described in RFC 4226 section-7.3.
+	LinearBackoff Backoff = iota
+	// ExponentialBackoff grows the lockout delay as delay * 2^(failed Attempts - 1), capped at max delay,
+	// with full jitter, i.e a random delay between 0 and the computed delay,
+	// which is the recommended throttling shape for repeated OTP guessing.
+	ExponentialBackoff
+)
+
 // Verifier represents one-time password verification for both HOTP and TOTP.
 type Verifier struct {
 	// EnableLockout enable or disable lockout mechanism
@@ -29,6 +76,12 @@ type Verifier struct {
 	// LockOutDelay define delay window to disable password verification process default 30
 	// the formula is delay * failed Attempts as described in RFC 4226 section-7.3.
 	LockOutDelay uint
+	// LockOutBackoff define the lockout delay growth shape.
+	// Default LinearBackoff.
+	LockOutBackoff Backoff
+	// LockOutMaxDelay define the max lockout delay in seconds, applies to ExponentialBackoff.
+	// Default 0, means no cap.
+	LockOutMaxDelay uint
 	// MaxAttempts define max attempts of verification failures to lock the account default 3.
 	MaxAttempts uint
 	// RemainingAttempts represents the count of remaining verification attempts.
@@ -43,10 +96,62 @@ type Verifier struct {
 	//
 	// Warning: A larger Skew would expose a larger window for attacks.
 	Skew uint
+	// Drift represents the observed TOTP clock drift in periods, between the user device and the server,
+	// recorded each time a password verified within the skew window and applied to subsequent verifications,
+	// so users with chronically skewed device clocks stop failing intermittently.
+	// Drift tracked only when MaxDrift greater than 0.
+	Drift int64
+	// MaxDrift define the max absolute TOTP clock drift in periods to track and apply.
+	// Default 0, means drift tracking disabled.
+	//
+	// Warning: Drift shifts the verification window,
+	// A larger MaxDrift accepts passwords far from the current time.
+	MaxDrift uint
+	// DelayTime represents time until password verification process re-enabled.
+	DelayTime time.Time
 	// DealyTime represents time until password verification process re-enabled.
+	//
+	// Deprecated: Use DelayTime, DealyTime still honored and kept in sync with DelayTime.
 	DealyTime time.Time
 	// Key represnt Uri Format for OTP.
 	Key *Key
+	// Counters optionally load and increment the HOTP counter,
+	// instead of mutating the in-memory Key counter.
+	// Default nil, means the counter kept in the Key.
+	Counters CounterStore
+
+	mu     sync.Mutex
+	params keyParams
+	gen    generator
+}
+
+// keyParams caches the key parameters parsed from its raw query,
+// so the verification hot path does not parse the key on each call.
+type keyParams struct {
+	ok     bool
+	raw    string
+	typ    Type
+	secret string
+	algo   HashAlgorithm
+	digits Digits
+	period uint64
+}
+
+// keyParams return the verifier key parameters, and re-parse them once the key changed.
+func (v *Verifier) keyParams() *keyParams {
+	p := &v.params
+	if !p.ok || p.raw != v.Key.RawQuery || p.typ != v.Key.Type() {
+		*p = keyParams{
+			ok:     true,
+			raw:    v.Key.RawQuery,
+			typ:    v.Key.Type(),
+			secret: v.Key.Secret(),
+			algo:   v.Key.Algorithm(),
+			digits: v.Key.Digits(),
+			period: v.Key.Period(),
+		}
+	}
+	return p
 }
 
 func (v *Verifier) lockOut() error {
@@ -59,7 +164,12 @@ func (v *Verifier) lockOut() error {
 		return ErrMaxAttempts
 	}
 
-	if remaining := v.DealyTime.UTC().Sub(time.Now().UTC()); remaining > 0 {
+	delay := v.DelayTime
+	if v.DealyTime.After(delay) {
+		delay = v.DealyTime
+	}
+
+	if remaining := delay.UTC().Sub(time.Now().UTC()); remaining > 0 {
 		return VerificationDisabledError(remaining)
 	}
 
@@ -88,49 +198,101 @@ func (v *Verifier) updateLockOut(valid bool) {
 	}
 
 	v.Failures++
-	v.DealyTime = time.Now().UTC().Add(time.Second * time.Duration(v.Failures*v.LockOutDelay))
+	v.DelayTime = time.Now().UTC().Add(v.delay())
+	v.DealyTime = v.DelayTime
 }
 
-func (v *Verifier) interval() uint64 {
-	if v.Key.Type() == HOTP {
+// delay return the lockout delay of the current failures count.
+func (v *Verifier) delay() time.Duration {
+	if v.LockOutBackoff != ExponentialBackoff {
+		return time.Second * time.Duration(v.Failures*v.LockOutDelay)
+	}
+
+	max := time.Duration(math.MaxInt64)
+	if v.LockOutMaxDelay > 0 {
+		max = time.Second * time.Duration(v.LockOutMaxDelay)
+	}
+
+	d := time.Second * time.Duration(v.LockOutDelay)
+	for i := uint(1); i < v.Failures && d < max; i++ {
+		if d > max/2 {
+			d = max
+			continue
+		}
+		d *= 2
+	}
+
+	if d > max {
+		d = max
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(d)) + 1) // nolint:gosec
+}
+
+func (v *Verifier) interval(p *keyParams) (uint64, error) {
+	if p.typ == HOTP {
+		if v.Counters != nil {
+			return v.Counters.Increment(v.Key)
+		}
+
 		counter := v.Key.Counter()
 		counter++
 		v.Key.SetCounter(counter)
-		return counter
+		return counter, nil
+	}
+
+	return uint64(time.Now().UTC().Unix()) / p.period, nil
+}
+
+// Counter return the current HOTP counter, from the Counters store if set, otherwise from the Key.
+func (v *Verifier) Counter() (uint64, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.Counters != nil {
+		return v.Counters.Get(v.Key)
 	}
 
-	return uint64(time.Now().UTC().Unix()) / v.Key.Period()
+	return v.Key.Counter(), nil
 }
 
 // Verify one-time password.
 func (v *Verifier) Verify(otp string) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
 	err := v.lockOut()
 	if err != nil {
 		return false, err
 	}
 
-	generate := func(i uint64) (string, error) {
-		return GenerateOTP(v.Key.Secret(), i, v.Key.Algorithm(), v.Key.Digits())
+	p := v.keyParams()
+	current, err := v.interval(p)
+	if err != nil {
+		return false, err
 	}
 
-	current := v.interval()
-	intervals := []uint64{current}
+	current = v.drifted(p, current)
 
-	for i := 1; i <= int(v.Skew); i++ {
-		intervals = append(intervals, current+uint64(i))
-		if v := int64(current) - int64(i); v > -1 {
-			intervals = append(intervals, uint64(v))
+	for i := uint64(0); i <= uint64(v.Skew); i++ {
+		ok, err := v.match(p, otp, current+i)
+		if ok || err != nil {
+			v.trackDrift(p, ok, int64(i))
+			return ok, err
 		}
-	}
 
-	for _, i := range intervals {
-		code, err := generate(i)
-		if err != nil {
-			return false, err
+		if i == 0 || i > current {
+			continue
 		}
 
-		if code == otp {
-			return true, nil
+		ok, err = v.match(p, otp, current-i)
+		if ok || err != nil {
+			v.trackDrift(p, ok, -int64(i))
+			return ok, err
 		}
 	}
 
@@ -138,10 +300,68 @@ func (v *Verifier) Verify(otp string) (bool, error) {
 	return false, nil
 }
 
+// tracksDrift report whether the TOTP clock drift tracked.
+func (v *Verifier) tracksDrift(p *keyParams) bool {
+	return p.typ == TOTP && v.MaxDrift > 0
+}
+
+// drifted return the given TOTP counter shifted by the bounded recorded drift.
+func (v *Verifier) drifted(p *keyParams, counter uint64) uint64 {
+	if !v.tracksDrift(p) {
+		return counter
+	}
+
+	d := v.boundDrift(v.Drift)
+	if d < 0 && uint64(-d) > counter {
+		return 0
+	}
+
+	return uint64(int64(counter) + d)
+}
+
+// trackDrift record the offset of the matched password from the drifted counter.
+func (v *Verifier) trackDrift(p *keyParams, ok bool, offset int64) {
+	if ok && v.tracksDrift(p) {
+		v.Drift = v.boundDrift(v.boundDrift(v.Drift) + offset)
+	}
+}
+
+func (v *Verifier) boundDrift(d int64) int64 {
+	max := int64(v.MaxDrift)
+	if d > max {
+		return max
+	}
+
+	if d < -max {
+		return -max
+	}
+
+	return d
+}
+
+func (v *Verifier) match(p *keyParams, otp string, counter uint64) (bool, error) {
+	code, err := v.gen.generate(p.secret, counter, p.algo, p.digits)
+	if err != nil {
+		return false, err
+	}
+
+	return string(code) == otp, nil
+}
+
 // GenerateOTP return one time password or an error if occurs
 // The Method is alias for GenerateOTP Function.
 func (v *Verifier) GenerateOTP() (string, error) {
-	return GenerateOTP(v.Key.Secret(), v.interval(), v.Key.Algorithm(), v.Key.Digits())
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	p := v.keyParams()
+	counter, err := v.interval(p)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := v.gen.generate(p.secret, counter, p.algo, p.digits)
+	return string(code), err
 }
 
 // New return's new Verifier, with defaults values.
@@ -1,10 +1,13 @@
 package otp
 
 import (
+	"math"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/errors"
 )
 
 func TestNew(t *testing.T) {
@@ -33,9 +36,34 @@ func TestVerifierLockOut(t *testing.T) {
 	// Round #3 return err when Password verification disabled
 	v.MaxAttempts = 3
 	v.Failures = 1
-	v.DealyTime = time.Now().Add(time.Hour)
+	v.DelayTime = time.Now().Add(time.Hour)
 	err = v.lockOut()
 	assert.Contains(t, err.Error(), "Password verification disabled")
+
+	// Round #4 honor the deprecated DealyTime
+	v.DelayTime = time.Time{}
+	v.DealyTime = time.Now().Add(time.Hour)
+	err = v.lockOut()
+	assert.IsType(t, VerificationDisabledError(0), err)
+}
+
+func TestVerifierErrorsMessage(t *testing.T) {
+	type localizable interface {
+		MessageKey() string
+		MessageArgs() []interface{}
+	}
+
+	m, ok := ErrMaxAttempts.(localizable)
+	assert.True(t, ok)
+	assert.Equal(t, "otp.max_attempts", m.MessageKey())
+	assert.Empty(t, m.MessageArgs())
+
+	d := VerificationDisabledError(time.Minute + time.Millisecond)
+	assert.Equal(t, "otp.verification_disabled", d.MessageKey())
+	assert.Equal(t, []interface{}{"1m0s"}, d.MessageArgs())
+	assert.Equal(t, time.Minute+time.Millisecond, d.RetryAfter())
+	assert.Equal(t, errors.ReasonLockedOut, errors.ReasonOf(d))
+	assert.Equal(t, errors.ReasonLockedOut, errors.ReasonOf(ErrMaxAttempts))
 }
 
 func TestVerifierUpdateLockOut(t *testing.T) {
@@ -55,8 +83,46 @@ func TestVerifierUpdateLockOut(t *testing.T) {
 	// Round #3 increase Failures and set delay time
 	v.updateLockOut(false)
 	assert.Equal(t, v.Failures, uint(1))
-	assert.WithinDuration(t, v.DealyTime, time.Now().UTC(), time.Second*time.Duration(v.Failures*v.LockOutDelay))
+	assert.WithinDuration(t, v.DelayTime, time.Now().UTC(), time.Second*time.Duration(v.Failures*v.LockOutDelay))
+
+}
+
+func TestVerifierExponentialBackoff(t *testing.T) {
+	table := []struct {
+		failures uint
+		maxDelay uint
+		expected time.Duration
+	}{
+		{failures: 1, expected: time.Second * 30},
+		{failures: 2, expected: time.Second * 60},
+		{failures: 5, expected: time.Second * 480},
+		{failures: 5, maxDelay: 100, expected: time.Second * 100},
+		{failures: 200, maxDelay: 3600, expected: time.Hour},
+		{failures: 200, expected: time.Duration(math.MaxInt64)},
+	}
+
+	for _, tt := range table {
+		v := &Verifier{
+			LockOutDelay:    30,
+			LockOutBackoff:  ExponentialBackoff,
+			LockOutMaxDelay: tt.maxDelay,
+			Failures:        tt.failures,
+		}
 
+		delays := make(map[time.Duration]struct{})
+		for i := 0; i < 20; i++ {
+			d := v.delay()
+			assert.True(t, d > 0 && d <= tt.expected, "delay %v not in (0, %v]", d, tt.expected)
+			delays[d] = struct{}{}
+		}
+
+		// full jitter randomize the delay.
+		assert.Greater(t, len(delays), 1)
+	}
+
+	// linear backoff kept by default.
+	v := &Verifier{LockOutDelay: 30, Failures: 3}
+	assert.Equal(t, time.Second*90, v.delay())
 }
 
 func TestVerifierVerify(t *testing.T) {
@@ -83,6 +149,84 @@ func TestVerifierVerify(t *testing.T) {
 	assert.True(t, ok)
 }
 
+type counterStore map[string]uint64
+
+func (c counterStore) Get(k *Key) (uint64, error) {
+	if v, ok := c[k.Label()]; ok {
+		return v, nil
+	}
+	return k.Counter(), nil
+}
+
+func (c counterStore) Increment(k *Key) (uint64, error) {
+	v, _ := c.Get(k)
+	c[k.Label()] = v + 1
+	return v + 1, nil
+}
+
+func TestVerifierCounterStore(t *testing.T) {
+	key := NewKey(HOTP, "label", "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA")
+	counters := counterStore{}
+
+	// Round #1 verify increments the stored counter, not the key counter
+	ver := New(key)
+	ver.Counters = counters
+	ok, err := ver.Verify("345515")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), key.Counter())
+	assert.Equal(t, uint64(1), counters["label"])
+
+	// Round #2 another verifier (e.g instance) continue from the stored counter
+	ver = New(key)
+	ver.Counters = counters
+	ver.Skew = 0
+	ok, err = ver.Verify("345515")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	c, err := ver.Counter()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), c)
+}
+
+func TestVerifierDrift(t *testing.T) {
+	key := NewKey(TOTP, "label", "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA")
+	code := func(offset int64) string {
+		counter := uint64(time.Now().UTC().Unix())/key.Period() + uint64(offset)
+		c, err := GenerateOTP(key.Secret(), counter, key.Algorithm(), key.Digits())
+		assert.NoError(t, err)
+		return c
+	}
+
+	// Round #1 drift not tracked by default
+	ver := New(key)
+	ok, err := ver.Verify(code(1))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), ver.Drift)
+
+	// Round #2 drift recorded within skew window and applied on subsequent verifications
+	ver = New(key)
+	ver.MaxDrift = 2
+	ok, _ = ver.Verify(code(1))
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), ver.Drift)
+
+	ok, _ = ver.Verify(code(2))
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), ver.Drift)
+
+	// Round #3 drift bounded by max drift
+	ok, _ = ver.Verify(code(3))
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), ver.Drift)
+
+	ok, _ = ver.Verify(code(4))
+	assert.False(t, ok)
+	assert.Equal(t, int64(2), ver.Drift)
+}
+
 func TestLockOutE2E(t *testing.T) {
 	// Round #1 check if verification disabled when lockout start from 0
 	v := &Verifier{
@@ -0,0 +1,201 @@
+// Package secrets provides a secrets provider abstraction,
+// used to load JWT signing keys, HMAC secrets, and OTP seeds,
+// from a secrets manager such as Vault,
+// instead of requiring secrets as plain strings in code or configuration.
+package secrets
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/shaj13/go-guardian/otp"
+)
+
+var (
+	// ErrNotFound is returned by provider when the secret does not exist.
+	ErrNotFound = errors.New("secrets: Secret not found")
+
+	// ErrMissingKey is returned when the secret does not have the requested key.
+	ErrMissingKey = errors.New("secrets: Secret missing key")
+
+	// ErrInvalidKey is returned by Signer when the secret value is not a PEM encoded private key.
+	ErrInvalidKey = errors.New("secrets: Invalid PEM private key")
+)
+
+// Secret represents a secret loaded from a provider.
+type Secret struct {
+	// Data is the secret key/value pairs.
+	Data map[string]string
+	// LeaseID is the secret lease id, if the provider issue leases.
+	LeaseID string
+	// LeaseDuration is the secret lease duration,
+	// zero means the secret does not expire.
+	LeaseDuration time.Duration
+	// Renewable reports whether the secret lease can be renewed.
+	Renewable bool
+}
+
+// Provider loads secrets by path.
+type Provider interface {
+	// Secret return the secret of the given path.
+	Secret(ctx context.Context, path string) (*Secret, error)
+}
+
+// Renewer is an optional interface implemented by providers that issue renewable leases.
+type Renewer interface {
+	// Renew extends the given secret lease and return the renewed secret.
+	Renew(ctx context.Context, s *Secret) (*Secret, error)
+}
+
+// Static implements Provider and keeps secrets in memory, keyed by path.
+// Typically used in tests and development.
+type Static map[string]map[string]string
+
+// Secret return the secret of the given path.
+func (s Static) Secret(_ context.Context, path string) (*Secret, error) {
+	data, ok := s[path]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return &Secret{Data: data}, nil
+}
+
+// Value return the value of the given key from the secret of the given path.
+func Value(ctx context.Context, p Provider, path, key string) (string, error) {
+	s, err := p.Secret(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	v, ok := s.Data[key]
+	if !ok {
+		return "", ErrMissingKey
+	}
+
+	return v, nil
+}
+
+// Bytes return the value of the given key from the secret of the given path as bytes,
+// Typically used to load HMAC secrets.
+func Bytes(ctx context.Context, p Provider, path, key string) ([]byte, error) {
+	v, err := Value(ctx, p, path, key)
+	return []byte(v), err
+}
+
+// Signer return the PEM encoded private key (PKCS #8, PKCS #1, or EC),
+// of the given key from the secret of the given path,
+// Typically used to load JWT signing keys.
+func Signer(ctx context.Context, p Provider, path, key string) (crypto.Signer, error) {
+	v, err := Value(ctx, p, path, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePrivateKey([]byte(v))
+}
+
+// ParsePrivateKey parse PEM encoded private key (PKCS #8, PKCS #1, or EC).
+func ParsePrivateKey(b []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if k, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if s, ok := k.(crypto.Signer); ok {
+			return s, nil
+		}
+		return nil, ErrInvalidKey
+	}
+
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+
+	if k, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return k, nil
+	}
+
+	return nil, ErrInvalidKey
+}
+
+// OTPKey return otp key of the given key from the secret of the given path.
+// the secret value can be an otpauth URI, Otherwise, it's used as the key base32 seed.
+func OTPKey(ctx context.Context, p Provider, path, key string, t otp.Type, label string) (*otp.Key, error) {
+	v, err := Value(ctx, p, path, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(v, "otpauth://") {
+		return otp.NewKeyFromRaw(v)
+	}
+
+	return otp.NewKey(t, label, v), nil
+}
+
+// OTPSealer return otp.Sealer of the keys held by the given key from the secret of the given path,
+// as comma separated base64 keys, newest first, See otp.ParseSealKeys.
+func OTPSealer(ctx context.Context, p Provider, path, key string) (*otp.Sealer, error) {
+	v, err := Value(ctx, p, path, key)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := otp.ParseSealKeys(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return otp.NewSealer(keys)
+}
+
+// Watch load the secret of the given path and invoke fn with it,
+// then keep the secret fresh until ctx done,
+// by renewing its lease at two-thirds of the lease duration when the provider implements Renewer,
+// Otherwise, by loading the secret again.
+// Watch returns once the secret does not have a lease, or ctx done.
+// fn invoked with the error when renewal or load fail, and the watch retried after a minute.
+func Watch(ctx context.Context, p Provider, path string, fn func(*Secret, error)) {
+	s, err := p.Secret(ctx, path)
+	fn(s, err)
+
+	for {
+		d := time.Minute
+
+		if err == nil {
+			if s.LeaseDuration <= 0 {
+				return
+			}
+			d = s.LeaseDuration * 2 / 3
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d):
+		}
+
+		var ns *Secret
+
+		if r, ok := p.(Renewer); ok && err == nil && s.Renewable {
+			ns, err = r.Renew(ctx, s)
+		} else {
+			ns, err = p.Secret(ctx, path)
+		}
+
+		if err != nil {
+			fn(nil, err)
+			continue
+		}
+
+		s = ns
+		fn(s, nil)
+	}
+}
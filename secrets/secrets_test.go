@@ -0,0 +1,124 @@
+package secrets
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/otp"
+)
+
+func TestValue(t *testing.T) {
+	p := Static{"jwt": {"hmac": "secret"}}
+
+	v, err := Value(context.Background(), p, "jwt", "hmac")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", v)
+
+	_, err = Value(context.Background(), p, "jwt", "unknown")
+	assert.Equal(t, ErrMissingKey, err)
+
+	_, err = Bytes(context.Background(), p, "unknown", "hmac")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestSigner(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	ec, _ := x509.MarshalECPrivateKey(ecKey)
+	pkcs1 := x509.MarshalPKCS1PrivateKey(rsaKey)
+
+	p := Static{"keys": {
+		"pkcs1":   string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs1})),
+		"pkcs8":   string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+		"ec":      string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ec})),
+		"invalid": "invalid",
+	}}
+
+	for _, key := range []string{"pkcs1", "pkcs8", "ec"} {
+		s, err := Signer(context.Background(), p, "keys", key)
+		assert.NoError(t, err, key)
+		assert.NotNil(t, s, key)
+	}
+
+	_, err := Signer(context.Background(), p, "keys", "invalid")
+	assert.Equal(t, ErrInvalidKey, err)
+}
+
+func TestOTPKey(t *testing.T) {
+	p := Static{"otp": {
+		"seed": "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA",
+		"uri":  "otpauth://totp/label?secret=GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA",
+	}}
+
+	k, err := OTPKey(context.Background(), p, "otp", "seed", otp.TOTP, "label")
+	assert.NoError(t, err)
+	assert.Equal(t, "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA", k.Secret())
+
+	k, err = OTPKey(context.Background(), p, "otp", "uri", otp.HOTP, "")
+	assert.NoError(t, err)
+	assert.Equal(t, otp.TOTP, k.Type())
+	assert.Equal(t, "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA", k.Secret())
+}
+
+func TestOTPSealer(t *testing.T) {
+	p := Static{"otp": {"seal": "MDEyMzQ1Njc4OWFiY2RlZg==", "invalid": "!"}}
+
+	s, err := OTPSealer(context.Background(), p, "otp", "seal")
+	assert.NoError(t, err)
+
+	sealed, err := s.Seal("GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA")
+	assert.NoError(t, err)
+
+	v, err := s.Unseal(sealed)
+	assert.NoError(t, err)
+	assert.Equal(t, "GXNRHI2MFRFWXQGJHWZJFOSYI6E7MEVA", v)
+
+	_, err = OTPSealer(context.Background(), p, "otp", "invalid")
+	assert.Equal(t, otp.ErrInvalidSealKey, err)
+}
+
+func TestWatch(t *testing.T) {
+	p := &leased{}
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	Watch(ctx, p, "db", func(s *Secret, err error) {
+		assert.NoError(t, err)
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+	})
+
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, 2, p.renewed)
+}
+
+func TestWatchStatic(t *testing.T) {
+	calls := 0
+	Watch(context.Background(), Static{"a": {}}, "a", func(*Secret, error) { calls++ })
+	assert.Equal(t, 1, calls)
+}
+
+type leased struct {
+	renewed int
+}
+
+func (l *leased) Secret(context.Context, string) (*Secret, error) {
+	return &Secret{LeaseID: "1", LeaseDuration: time.Millisecond * 30, Renewable: true}, nil
+}
+
+func (l *leased) Renew(_ context.Context, s *Secret) (*Secret, error) {
+	l.renewed++
+	return s, nil
+}
@@ -0,0 +1,150 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Vault implements Provider and Renewer using HashiCorp Vault HTTP API.
+// It reads secrets from any secrets engine (e.g KV v1/v2, database, transit exported keys),
+// KV v2 responses unwrapped, so the secret data is the stored key/value pairs.
+type Vault struct {
+	// Address is the Vault server address e.g https://vault:8200.
+	Address string
+	// Token is the Vault token used to authenticate the requests.
+	Token string
+	// Namespace is the Vault enterprise namespace, optional.
+	Namespace string
+	// Client is the underlying http client.
+	Client *http.Client
+}
+
+// NewVault return new Vault provider.
+func NewVault(addr, token string) *Vault {
+	return &Vault{
+		Address: strings.TrimSuffix(addr, "/"),
+		Token:   token,
+		Client:  http.DefaultClient,
+	}
+}
+
+type vaultResponse struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int64                  `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+	Errors        []string               `json:"errors"`
+}
+
+// Secret return the secret of the given path, e.g secret/data/jwt.
+func (v *Vault) Secret(ctx context.Context, path string) (*Secret, error) {
+	resp, err := v.do(ctx, http.MethodGet, "/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := resp.Data
+
+	// KV v2 nest the secret data within data and add metadata.
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	s := secret(resp)
+	s.Data = make(map[string]string, len(data))
+
+	for k, val := range data {
+		if str, ok := val.(string); ok {
+			s.Data[k] = str
+			continue
+		}
+
+		b, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+
+		s.Data[k] = string(b)
+	}
+
+	return s, nil
+}
+
+// Renew extends the given secret lease using sys/leases/renew endpoint.
+func (v *Vault) Renew(ctx context.Context, s *Secret) (*Secret, error) {
+	body := map[string]interface{}{
+		"lease_id":  s.LeaseID,
+		"increment": int64(s.LeaseDuration / time.Second),
+	}
+
+	resp, err := v.do(ctx, http.MethodPut, "/v1/sys/leases/renew", body)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := secret(resp)
+	ns.Data = s.Data
+
+	return ns, nil
+}
+
+func (v *Vault) do(ctx context.Context, method, path string, body interface{}) (*vaultResponse, error) {
+	buf := new(bytes.Buffer)
+
+	if body != nil {
+		if err := json.NewEncoder(buf).Encode(body); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, v.Address+path, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Vault-Token", v.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	vr := new(vaultResponse)
+	_ = json.NewDecoder(resp.Body).Decode(vr)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf(
+			"secrets: Vault respond with status code %d, Errors: %s",
+			resp.StatusCode,
+			strings.Join(vr.Errors, ", "),
+		)
+	}
+
+	return vr, nil
+}
+
+func secret(resp *vaultResponse) *Secret {
+	return &Secret{
+		LeaseID:       resp.LeaseID,
+		LeaseDuration: time.Duration(resp.LeaseDuration) * time.Second,
+		Renewable:     resp.Renewable,
+	}
+}
@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "ns" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/jwt":
+			_, _ = w.Write([]byte(`{"data":{"data":{"hmac":"secret","n":1},"metadata":{"version":1}}}`))
+		case "/v1/database/creds/app":
+			_, _ = w.Write([]byte(`{"lease_id":"l1","lease_duration":60,"renewable":true,"data":{"password":"p"}}`))
+		case "/v1/sys/leases/renew":
+			body := map[string]interface{}{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "l1", body["lease_id"])
+			_, _ = w.Write([]byte(`{"lease_id":"l1","lease_duration":120,"renewable":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	v := NewVault(srv.URL, "token")
+	v.Namespace = "ns"
+	ctx := context.Background()
+
+	s, err := v.Secret(ctx, "secret/data/jwt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"hmac": "secret", "n": "1"}, s.Data)
+
+	s, err = v.Secret(ctx, "database/creds/app")
+	assert.NoError(t, err)
+	assert.Equal(t, "p", s.Data["password"])
+	assert.Equal(t, time.Minute, s.LeaseDuration)
+	assert.True(t, s.Renewable)
+
+	s, err = v.Renew(ctx, s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, s.LeaseDuration)
+	assert.Equal(t, "p", s.Data["password"])
+
+	_, err = v.Secret(ctx, "unknown")
+	assert.Equal(t, ErrNotFound, err)
+
+	v.Token = "invalid"
+	_, err = v.Secret(ctx, "secret/data/jwt")
+	assert.EqualError(t, err, "secrets: Vault respond with status code 403, Errors: permission denied")
+}
@@ -0,0 +1,62 @@
+package store
+
+import "net/http"
+
+// BatchCache is implemented by caches that load or delete many keys in a single round trip,
+// e.g a Redis cache using MGET and pipelining.
+// Use LoadMulti and DeleteMulti to benefit from it while supporting any Cache.
+type BatchCache interface {
+	Cache
+	// LoadMulti returns the values stored in the cache for the given keys,
+	// keys not present in the cache or expired omitted from the returned map.
+	LoadMulti(keys []string, r *http.Request) (map[string]interface{}, error)
+	// DeleteMulti deletes the values for the given keys.
+	DeleteMulti(keys []string, r *http.Request) error
+}
+
+// LoadMulti returns the values stored in the cache for the given keys,
+// keys not present in the cache or expired omitted from the returned map.
+// if cache implements BatchCache the keys loaded in a single batch,
+// Otherwise, the keys loaded one by one.
+func LoadMulti(c Cache, keys []string, r *http.Request) (map[string]interface{}, error) {
+	if b, ok := c.(BatchCache); ok {
+		return b.LoadMulti(keys, r)
+	}
+
+	values := make(map[string]interface{}, len(keys))
+
+	for _, k := range keys {
+		v, ok, err := c.Load(k, r)
+		if err == ErrCachedExp {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			values[k] = v
+		}
+	}
+
+	return values, nil
+}
+
+// DeleteMulti deletes the values for the given keys,
+// Typically used by revocation sweeps, e.g invalidating all tokens of a user.
+// if cache implements BatchCache the keys deleted in a single batch,
+// Otherwise, the keys deleted one by one.
+func DeleteMulti(c Cache, keys []string, r *http.Request) error {
+	if b, ok := c.(BatchCache); ok {
+		return b.DeleteMulti(keys, r)
+	}
+
+	for _, k := range keys {
+		if err := c.Delete(k, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type batchCache struct {
+	*LRU
+	batches int
+}
+
+func (b *batchCache) LoadMulti(keys []string, r *http.Request) (map[string]interface{}, error) {
+	b.batches++
+	values := make(map[string]interface{})
+	for _, k := range keys {
+		if v, ok, _ := b.Load(k, r); ok {
+			values[k] = v
+		}
+	}
+	return values, nil
+}
+
+func (b *batchCache) DeleteMulti(keys []string, r *http.Request) error {
+	b.batches++
+	for _, k := range keys {
+		_ = b.Delete(k, r)
+	}
+	return nil
+}
+
+func TestLoadDeleteMulti(t *testing.T) {
+	lru := New(0)
+	batch := &batchCache{LRU: New(0)}
+
+	for _, c := range []Cache{lru, batch} {
+		_ = c.Store("1", 1, nil)
+		_ = c.Store("2", 2, nil)
+
+		values, err := LoadMulti(c, []string{"1", "2", "3"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"1": 1, "2": 2}, values)
+
+		assert.NoError(t, DeleteMulti(c, []string{"1", "2"}, nil))
+		assert.Empty(t, c.Keys())
+	}
+
+	assert.Equal(t, 2, batch.batches)
+}
+
+func TestLoadMultiExpired(t *testing.T) {
+	lru := New(0)
+	lru.TTL = time.Nanosecond
+	_ = lru.Store("1", 1, nil)
+	time.Sleep(time.Millisecond)
+
+	values, err := LoadMulti(lru, []string{"1"}, nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
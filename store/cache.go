@@ -4,6 +4,7 @@ package store
 
 import (
 	"errors"
+	"math/rand"
 	"net/http"
 	"time"
 )
@@ -46,3 +47,11 @@ type record struct {
 	Key   string
 	Value interface{}
 }
+
+// jitter return a random duration in [0, max).
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max))) // nolint:gosec
+}
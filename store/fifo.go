@@ -4,7 +4,9 @@ import (
 	"context"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unsafe"
 )
 
 // NewFIFO return a simple FIFO Cache instance safe for concurrent usage,
@@ -14,18 +16,18 @@ import (
 // Otherwise, wait for the next record.
 // When the all expired record collected the garbage collector will be blocked,
 // until new record stored to repeat the process.
-// The context will be Passed to garbage collector
+// The context will be Passed to garbage collector, and Close stops the garbage collector too.
+// Use Manager to collect the expired records of many caches on a single goroutine, See Manager.NewFIFO.
 func NewFIFO(ctx context.Context, ttl time.Duration) *FIFO {
-	queue := &queue{
-		notify: make(chan struct{}, 1),
-		mu:     &sync.Mutex{},
-	}
+	queue := newQueue()
+	ctx, cancel := context.WithCancel(ctx)
 
 	f := &FIFO{
 		queue:   queue,
 		TTL:     ttl,
 		records: make(map[string]*record),
 		MU:      &sync.Mutex{},
+		cancel:  cancel,
 	}
 
 	go gc(ctx, queue, f)
@@ -39,6 +41,12 @@ type FIFO struct {
 	// TTL Must be greater than 0.
 	TTL time.Duration
 
+	// Jitter optionally specifies the max random duration added to each record TTL,
+	// to spread the expiry of records stored at the same moment (e.g mass-issued tokens),
+	// so they don't expire all at once and stampede the upstream validator.
+	// 0 Jitter means no jitter.
+	Jitter time.Duration
+
 	// OnEvicted optionally specifies a callback function to be
 	// executed when an entry is purged from the cache.
 	OnEvicted OnEvicted
@@ -46,6 +54,17 @@ type FIFO struct {
 	MU      *sync.Mutex
 	records map[string]*record
 	queue   *queue
+	manager *Manager
+	cancel  context.CancelFunc
+}
+
+// Close stops the cache garbage collector goroutine, the cache records kept,
+// and expire lazily on Load. Close of a cache created by a Manager is a no-op, See Manager.Stop.
+func (f *FIFO) Close() error {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return nil
 }
 
 // Load returns the value stored in the Cache for a key, or nil if no value is present.
@@ -70,17 +89,23 @@ func (f *FIFO) Load(key string, _ *http.Request) (interface{}, bool, error) {
 
 // Store sets the value for a key.
 func (f *FIFO) Store(key string, value interface{}, _ *http.Request) error {
-	f.MU.Lock()
-	defer f.MU.Unlock()
-
-	exp := time.Now().UTC().Add(f.TTL)
+	exp := time.Now().UTC().Add(f.TTL + jitter(f.Jitter))
 	record := &record{
 		Key:   key,
 		Exp:   exp,
 		Value: value,
 	}
 
+	f.MU.Lock()
 	f.records[key] = record
+	f.MU.Unlock()
+
+	if f.manager != nil {
+		f.manager.schedule(f, record)
+		return nil
+	}
+
+	// push outside the records lock, the queue is lock-free.
 	f.queue.push(record)
 
 	return nil
@@ -135,58 +160,85 @@ func (f *FIFO) Keys() []string {
 
 type node struct {
 	record *record
-	next   *node
+	next   unsafe.Pointer // *node
 }
 
+// queue is a lock-free multi-producer single-consumer queue,
+// based on Dmitry Vyukov intrusive MPSC node-based queue,
+// so concurrent Store calls push records without serializing on a mutex,
+// while the garbage collector goroutine is the only consumer.
 type queue struct {
-	mu     *sync.Mutex
-	head   *node
-	tail   *node
-	notify chan struct{}
+	head    unsafe.Pointer // *node, most recently pushed node.
+	tail    *node          // consumer side, owned by the garbage collector.
+	waiting int32
+	notify  chan struct{}
 }
 
+func newQueue() *queue {
+	stub := new(node)
+	return &queue{
+		head:   unsafe.Pointer(stub),
+		tail:   stub,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// next return the oldest record or nil if the queue empty,
+// next must be called only from the consumer goroutine.
 func (q *queue) next() *record {
-	q.mu.Lock()
-	defer q.mu.Unlock()
-	if q.head != nil {
-		current := q.head
-		q.head = current.next
-		return current.record
+	next := (*node)(atomic.LoadPointer(&q.tail.next))
+	if next == nil {
+		return nil
 	}
-	return nil
+
+	q.tail = next
+	r := next.record
+	next.record = nil
+
+	return r
 }
 
 func (q *queue) push(r *record) {
-	q.mu.Lock()
-	defer q.mu.Unlock()
-	node := &node{
-		record: r,
-		next:   nil,
-	}
-	if q.head == nil {
-		q.head = node
-		q.tail = q.head
+	n := &node{record: r}
+	prev := (*node)(atomic.SwapPointer(&q.head, unsafe.Pointer(n)))
+	atomic.StorePointer(&prev.next, unsafe.Pointer(n))
+
+	// wake up the consumer only when it's waiting, to keep pushes off the channel lock.
+	if atomic.CompareAndSwapInt32(&q.waiting, 1, 0) {
 		select {
 		case q.notify <- struct{}{}:
 		default:
 		}
-		return
 	}
-	q.tail.next = node
-	q.tail = q.tail.next
+}
+
+// wait blocks the consumer until a record pushed, it reports whether ctx done.
+func (q *queue) wait(ctx context.Context) bool {
+	atomic.StoreInt32(&q.waiting, 1)
+
+	// re-check, a record may have been pushed before the waiting flag set.
+	if atomic.LoadPointer(&q.tail.next) != nil {
+		atomic.StoreInt32(&q.waiting, 0)
+		return false
+	}
+
+	select {
+	case <-q.notify:
+		return false
+	case <-ctx.Done():
+		return true
+	}
 }
 
 func gc(ctx context.Context, queue *queue, cache Cache) {
-	for {
+	for ctx.Err() == nil {
 		record := queue.next()
 
 		if record == nil {
-			select {
-			case <-queue.notify:
-				continue
-			case <-ctx.Done():
+			if queue.wait(ctx) {
 				return
 			}
+			continue
 		}
 
 		_, ok, _ := cache.Load(record.Key, nil)
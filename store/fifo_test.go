@@ -15,10 +15,7 @@ import (
 func TestFIFOStore(t *testing.T) {
 	const key = "key"
 
-	queue := &queue{
-		notify: make(chan struct{}, 1),
-		mu:     new(sync.Mutex),
-	}
+	queue := newQueue()
 
 	cache := &FIFO{
 		MU:      new(sync.Mutex),
@@ -177,10 +174,7 @@ func TestFIFOEvict(t *testing.T) {
 }
 
 func TestQueue(t *testing.T) {
-	queue := &queue{
-		notify: make(chan struct{}, 10),
-		mu:     &sync.Mutex{},
-	}
+	queue := newQueue()
 
 	for i := 0; i < 5; i++ {
 		queue.push(
@@ -196,6 +190,74 @@ func TestQueue(t *testing.T) {
 	}
 }
 
+func TestQueueConcurrentPush(t *testing.T) {
+	const producers, n = 8, 100
+	queue := newQueue()
+	wg := sync.WaitGroup{}
+
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				queue.push(&record{Key: "any", Value: p*n + i})
+			}
+		}(p)
+	}
+
+	seen := make(map[int]bool)
+	last := make(map[int]int)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	for len(seen) < producers*n {
+		r := queue.next()
+		if r == nil {
+			if queue.wait(ctx) {
+				t.Fatal("timed out waiting for pushed records")
+			}
+			continue
+		}
+
+		v := r.Value.(int)
+		p := v / n
+		if prev, ok := last[p]; ok {
+			assert.Less(t, prev, v, "records of the same producer must keep push order")
+		}
+		last[p] = v
+		seen[v] = true
+	}
+
+	wg.Wait()
+	assert.Nil(t, queue.next())
+}
+
+func TestFIFOJitter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := NewFIFO(ctx, time.Minute)
+	cache.Jitter = time.Minute
+
+	start := time.Now().UTC()
+	exps := make(map[time.Time]struct{})
+
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("key%d", i)
+		_ = cache.Store(key, "value", nil)
+
+		cache.MU.Lock()
+		exp := cache.records[key].Exp
+		cache.MU.Unlock()
+		exps[exp] = struct{}{}
+
+		assert.False(t, exp.Before(start.Add(cache.TTL)))
+		assert.True(t, exp.Before(time.Now().UTC().Add(cache.TTL+cache.Jitter)))
+	}
+
+	assert.Greater(t, len(exps), 1)
+}
+
 func TestFifoKeys(t *testing.T) {
 	ctx, cacnel := context.WithCancel(context.Background())
 	defer cacnel()
@@ -226,11 +288,46 @@ func TestGC(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestFIFOClose(t *testing.T) {
+	cache := NewFIFO(context.Background(), time.Nanosecond*50)
+	assert.NoError(t, cache.Close())
+
+	cache.Store("1", 1, nil)
+	time.Sleep(time.Millisecond)
+
+	// garbage collector stopped, expired record removed lazily on load.
+	_, ok, err := cache.Load("1", nil)
+	assert.True(t, ok)
+	assert.Equal(t, ErrCachedExp, err)
+
+	_, ok, _ = cache.Load("1", nil)
+	assert.False(t, ok)
+
+	// cache created by a manager.
+	assert.NoError(t, NewManager().NewFIFO(time.Minute).Close())
+}
+
 func BenchmarkFIFIO(b *testing.B) {
 	cache := NewFIFO(context.Background(), time.Minute)
 	benchmarkCache(b, cache)
 }
 
+func BenchmarkFIFOStoreParallel(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := NewFIFO(ctx, time.Minute)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			_ = cache.Store(strconv.Itoa(i&1023), i, nil)
+			i++
+		}
+	})
+}
+
 func benchmarkCache(b *testing.B, cache Cache) {
 	keys := []string{}
 
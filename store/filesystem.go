@@ -27,8 +27,19 @@ type FileSystem struct {
 
 	MU *sync.RWMutex
 
-	path  string
-	queue *queue
+	path    string
+	queue   *queue
+	manager *Manager
+	cancel  context.CancelFunc
+}
+
+// Close stops the cache garbage collector goroutine, the cache records kept,
+// and expire lazily on Load. Close of a cache created by a Manager is a no-op, See Manager.Stop.
+func (f *FileSystem) Close() error {
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return nil
 }
 
 // Load returns the value stored in the Cache for a key, or nil if no value is present.
@@ -83,7 +94,7 @@ func (f *FileSystem) Store(key string, value interface{}, _ *http.Request) error
 
 	if f.TTL > 0 {
 		r.Value = nil
-		f.queue.push(r)
+		f.schedule(r)
 	}
 
 	return ioutil.WriteFile(filename, b, 0600)
@@ -137,6 +148,14 @@ func (f *FileSystem) decode(r *record, data []byte) error {
 	return gob.NewDecoder(b).Decode(r)
 }
 
+func (f *FileSystem) schedule(r *record) {
+	if f.manager != nil {
+		f.manager.schedule(f, r)
+		return
+	}
+	f.queue.push(r)
+}
+
 func (f *FileSystem) encode(r *record) ([]byte, error) {
 	b := new(bytes.Buffer)
 	err := gob.NewEncoder(b).Encode(r)
@@ -150,18 +169,18 @@ func (f *FileSystem) encode(r *record) ([]byte, error) {
 // Otherwise, wait for the next record.
 // When the all expired record collected the garbage collector will be blocked,
 // until new record stored to repeat the process.
-// The context will be Passed to garbage collector
+// The context will be Passed to garbage collector, and Close stops the garbage collector too.
+// Use Manager to collect the expired records of many caches on a single goroutine, See Manager.NewFileSystem.
 func NewFileSystem(ctx context.Context, ttl time.Duration, path string) *FileSystem {
-	queue := &queue{
-		notify: make(chan struct{}, 1),
-		mu:     &sync.Mutex{},
-	}
+	queue := newQueue()
+	ctx, cancel := context.WithCancel(ctx)
 
 	f := &FileSystem{
-		path:  path,
-		MU:    &sync.RWMutex{},
-		queue: queue,
-		TTL:   ttl,
+		path:   path,
+		MU:     &sync.RWMutex{},
+		queue:  queue,
+		TTL:    ttl,
+		cancel: cancel,
 	}
 
 	if ttl > 0 {
@@ -50,13 +50,10 @@ func TestFileSystemStore(t *testing.T) {
 			defer os.RemoveAll(path)
 
 			f := &FileSystem{
-				path: path,
-				MU:   &sync.RWMutex{},
-				queue: &queue{
-					notify: make(chan struct{}, 1),
-					mu:     &sync.Mutex{},
-				},
-				TTL: tt.ttl,
+				path:  path,
+				MU:    &sync.RWMutex{},
+				queue: newQueue(),
+				TTL:   tt.ttl,
 			}
 
 			err := f.Store(tt.key, tt.value, nil)
@@ -22,6 +22,12 @@ type LRU struct {
 	// 0 TTL means no expiry policy specified.
 	TTL time.Duration
 
+	// Jitter optionally specifies the max random duration added to each record TTL,
+	// to spread the expiry of records stored at the same moment (e.g mass-issued tokens),
+	// so they don't expire all at once and stampede the upstream validator.
+	// 0 Jitter means no jitter.
+	Jitter time.Duration
+
 	MU *sync.Mutex
 
 	ll    *list.List
@@ -90,7 +96,7 @@ func (l *LRU) Update(key string, value interface{}, _ *http.Request) error {
 
 func (l *LRU) withTTL(r *record) {
 	if l.TTL > 0 {
-		r.Exp = time.Now().UTC().Add(l.TTL)
+		r.Exp = time.Now().UTC().Add(l.TTL + jitter(l.Jitter))
 	}
 }
 
@@ -132,6 +132,27 @@ func TestTTLLRU(t *testing.T) {
 	assert.Nil(t, v)
 }
 
+func TestLRUJitter(t *testing.T) {
+	cache := New(0)
+	cache.TTL = time.Minute
+	cache.Jitter = time.Minute
+
+	start := time.Now().UTC()
+	exps := make(map[time.Time]struct{})
+
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("key%d", i)
+		_ = cache.Store(key, "value", nil)
+		exp := cache.cache[key].Value.(*record).Exp
+		exps[exp] = struct{}{}
+
+		assert.False(t, exp.Before(start.Add(cache.TTL)))
+		assert.True(t, exp.Before(time.Now().UTC().Add(cache.TTL+cache.Jitter)))
+	}
+
+	assert.Greater(t, len(exps), 1)
+}
+
 func TestLRUEvict(t *testing.T) {
 	evictedKeys := make([]string, 0)
 	onEvictedFun := func(key string, value interface{}) {
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Manager runs the garbage collection of the caches it creates on a single worker goroutine,
+// driven by a single timer heap of records expiry, instead of spawning a goroutine per cache.
+// Typically used when many short-lived caches created (e.g in tests or per-tenant setups),
+// so stopping the manager releases all garbage collection resources at once.
+//
+// Caches keep expiring records lazily on Load while the manager stopped.
+// Manager safe for concurrent usage.
+type Manager struct {
+	mu      sync.Mutex
+	records expiryHeap
+	wake    chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewManager return new Manager, call Start to run its garbage collection worker.
+func NewManager() *Manager {
+	return &Manager{
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// NewFIFO return a FIFO Cache instance safe for concurrent usage,
+// where expired records collected by the manager worker, See NewFIFO.
+func (m *Manager) NewFIFO(ttl time.Duration) *FIFO {
+	return &FIFO{
+		TTL:     ttl,
+		MU:      &sync.Mutex{},
+		records: make(map[string]*record),
+		manager: m,
+	}
+}
+
+// NewFileSystem return FileSystem Cache instance safe for concurrent usage,
+// where expired records collected by the manager worker, See NewFileSystem.
+func (m *Manager) NewFileSystem(ttl time.Duration, path string) *FileSystem {
+	return &FileSystem{
+		path:    path,
+		MU:      &sync.RWMutex{},
+		TTL:     ttl,
+		manager: m,
+	}
+}
+
+// Start run the manager garbage collection worker, if it's not already running.
+func (m *Manager) Start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stop != nil {
+		return
+	}
+
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.run(m.stop, m.done)
+}
+
+// Stop gracefully stop the manager garbage collection worker and wait until it exits.
+// Scheduled records kept, and collected once the manager started again.
+func (m *Manager) Stop() {
+	m.mu.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+}
+
+// Len return the number of records scheduled for garbage collection.
+func (m *Manager) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.records)
+}
+
+func (m *Manager) schedule(c Cache, r *record) {
+	m.mu.Lock()
+	heap.Push(&m.records, expiry{exp: r.Exp, key: r.Key, cache: c})
+	earliest := m.records[0].exp.Equal(r.Exp)
+	m.mu.Unlock()
+
+	// wake up the worker only when the new record expires first.
+	if earliest {
+		select {
+		case m.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// expired pop the expired records and return the duration until the next expiry,
+// or a negative duration when there are no scheduled records.
+func (m *Manager) expired(now time.Time) ([]expiry, time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var records []expiry
+	for len(m.records) > 0 && !m.records[0].exp.After(now) {
+		records = append(records, heap.Pop(&m.records).(expiry))
+	}
+
+	if len(m.records) == 0 {
+		return records, -1
+	}
+
+	return records, m.records[0].exp.Sub(now)
+}
+
+func (m *Manager) run(stop, done chan struct{}) {
+	defer close(done)
+
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		records, d := m.expired(time.Now().UTC())
+
+		// invoke Load to expire the record, the load method used over delete
+		// cause the record may be renewed, and scheduled again.
+		for _, r := range records {
+			_, _, _ = r.cache.Load(r.key, nil)
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+
+		var c <-chan time.Time
+		if d >= 0 {
+			timer.Reset(d)
+			c = timer.C
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-m.wake:
+		case <-c:
+		}
+	}
+}
+
+type expiry struct {
+	exp   time.Time
+	key   string
+	cache Cache
+}
+
+type expiryHeap []expiry
+
+func (h expiryHeap) Len() int            { return len(h) }
+func (h expiryHeap) Less(i, j int) bool  { return h[i].exp.Before(h[j].exp) }
+func (h expiryHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *expiryHeap) Push(x interface{}) { *h = append(*h, x.(expiry)) }
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = expiry{}
+	*h = old[:n-1]
+	return x
+}
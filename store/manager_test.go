@@ -0,0 +1,84 @@
+package store
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerFIFO(t *testing.T) {
+	m := NewManager()
+	m.Start()
+	defer m.Stop()
+
+	evicted := make(chan string, 2)
+	short := m.NewFIFO(time.Millisecond * 10)
+	short.OnEvicted = func(key string, _ interface{}) { evicted <- key }
+	long := m.NewFIFO(time.Hour)
+
+	_ = long.Store("long", 1, nil)
+	_ = short.Store("short", 1, nil)
+
+	select {
+	case key := <-evicted:
+		assert.Equal(t, "short", key)
+	case <-time.After(time.Second):
+		t.Fatal("expected expired record to be collected")
+	}
+
+	_, ok, _ := long.Load("long", nil)
+	assert.True(t, ok)
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestManagerRenewedRecord(t *testing.T) {
+	m := NewManager()
+	m.Start()
+	defer m.Stop()
+
+	cache := m.NewFIFO(time.Millisecond * 20)
+	_ = cache.Store("key", 1, nil)
+
+	cache.TTL = time.Hour
+	_ = cache.Store("key", 2, nil)
+
+	assert.Eventually(t, func() bool { return m.Len() == 1 }, time.Second, time.Millisecond)
+
+	v, ok, err := cache.Load("key", nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+}
+
+func TestManagerStartStop(t *testing.T) {
+	m := NewManager()
+	cache := m.NewFIFO(time.Millisecond)
+
+	goroutines := runtime.NumGoroutine()
+
+	m.Start()
+	m.Start()
+
+	for i := 0; i < 100; i++ {
+		_ = m.NewFIFO(time.Minute).Store(fmt.Sprint(i), i, nil)
+	}
+
+	assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines+1)
+
+	m.Stop()
+	m.Stop()
+
+	// records kept while stopped and collected once started again.
+	_ = cache.Store("key", 1, nil)
+	time.Sleep(time.Millisecond * 5)
+	assert.Equal(t, 101, m.Len())
+
+	m.Start()
+	defer m.Stop()
+
+	assert.Eventually(t, func() bool { return m.Len() == 100 }, time.Second, time.Millisecond)
+	assert.Empty(t, cache.Keys())
+}
@@ -0,0 +1,182 @@
+package store
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/shaj13/go-guardian/errors"
+)
+
+// Sharded is a Cache that stripes keys across independent caches (shards),
+// by the key FNV-1a hash, so concurrent operations on different keys rarely
+// contend on the same lock. Typically used in front of LRU or FIFO under heavy
+// mixed read/write load.
+//
+// Eviction policy and max entries apply per shard, e.g a sharded LRU of 16 shards,
+// each with 1000 max entries holds up to 16000 entries.
+//
+// Sharded implements io.Closer and Snapshotter by forwarding to the shards,
+// thus the shards must implement Snapshotter to snapshot or restore the cache.
+type Sharded struct {
+	shards []Cache
+}
+
+// NewSharded return new Sharded cache of n shards created using the given factory.
+// n rounded up to a power of two.
+func NewSharded(n int, factory func() Cache) *Sharded {
+	if factory == nil {
+		panic("Cache Factory required and can't be nil")
+	}
+
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+
+	s := &Sharded{
+		shards: make([]Cache, size),
+	}
+
+	for i := range s.shards {
+		s.shards[i] = factory()
+	}
+
+	return s
+}
+
+// NewShardedLRU return new Sharded cache of n LRU shards,
+// each shard has the given max entries.
+func NewShardedLRU(n, maxEntries int) *Sharded {
+	return NewSharded(n, func() Cache {
+		return New(maxEntries)
+	})
+}
+
+// Load returns the value stored in the Cache for a key, or nil if no value is present.
+// The ok result indicates whether value was found in the Cache.
+func (s *Sharded) Load(key string, r *http.Request) (interface{}, bool, error) {
+	return s.shard(key).Load(key, r)
+}
+
+// Store sets the value for a key.
+func (s *Sharded) Store(key string, value interface{}, r *http.Request) error {
+	return s.shard(key).Store(key, value, r)
+}
+
+// Delete the value for a key.
+func (s *Sharded) Delete(key string, r *http.Request) error {
+	return s.shard(key).Delete(key, r)
+}
+
+// Keys return cache records keys.
+func (s *Sharded) Keys() []string {
+	keys := make([]string, 0)
+	for _, c := range s.shards {
+		keys = append(keys, c.Keys()...)
+	}
+	return keys
+}
+
+// Shards return the underlying shards.
+func (s *Sharded) Shards() []Cache {
+	return s.shards
+}
+
+// Close closes the shards that own background resources, e.g FIFO garbage collector.
+func (s *Sharded) Close() error {
+	errs := errors.MultiError{}
+
+	for _, c := range s.shards {
+		if cl, ok := c.(io.Closer); ok {
+			if err := cl.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// Snapshot writes the shards live records to w, Typically on shutdown.
+// The snapshot format is the same as the shards snapshot, See Snapshotter.
+func (s *Sharded) Snapshot(w io.Writer) error {
+	records := make([]*record, 0)
+
+	for _, c := range s.shards {
+		sc, ok := c.(Snapshotter)
+		if !ok {
+			return errors.NewInvalidType((*Snapshotter)(nil), c)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := sc.Snapshot(buf); err != nil {
+			return err
+		}
+
+		rs, err := decodeRecords(buf)
+		if err != nil {
+			return err
+		}
+
+		records = append(records, rs...)
+	}
+
+	return encodeRecords(w, records)
+}
+
+// Restore reads records written by Snapshot from r and restores them in their shards,
+// Typically on startup. The records re-sharded, so the number of shards may differ
+// from the snapshotted cache. See Snapshotter.
+func (s *Sharded) Restore(r io.Reader) error {
+	records, err := decodeRecords(r)
+	if err != nil {
+		return err
+	}
+
+	shards := make([][]*record, len(s.shards))
+	for i := range shards {
+		shards[i] = make([]*record, 0)
+	}
+
+	for _, r := range records {
+		i := s.index(r.Key)
+		shards[i] = append(shards[i], r)
+	}
+
+	for i, c := range s.shards {
+		sc, ok := c.(Snapshotter)
+		if !ok {
+			return errors.NewInvalidType((*Snapshotter)(nil), c)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := encodeRecords(buf, shards[i]); err != nil {
+			return err
+		}
+
+		if err := sc.Restore(buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Sharded) shard(key string) Cache {
+	return s.shards[s.index(key)]
+}
+
+func (s *Sharded) index(key string) int {
+	// inline FNV-1a, avoid hash.Hash allocation.
+	h := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= 16777619
+	}
+	return int(h & uint32(len(s.shards)-1))
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharded(t *testing.T) {
+	s := NewShardedLRU(3, 0)
+	assert.Len(t, s.Shards(), 4)
+
+	for i := 0; i < 100; i++ {
+		_ = s.Store(strconv.Itoa(i), i, nil)
+	}
+
+	for i := 0; i < 100; i++ {
+		v, ok, err := s.Load(strconv.Itoa(i), nil)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+
+	assert.Len(t, s.Keys(), 100)
+
+	for _, c := range s.Shards() {
+		assert.NotEmpty(t, c.Keys(), "keys should spread across shards")
+	}
+
+	_ = s.Delete("1", nil)
+	_, ok, _ := s.Load("1", nil)
+	assert.False(t, ok)
+	assert.Len(t, s.Keys(), 99)
+}
+
+func TestShardedFIFO(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewSharded(4, func() Cache {
+		return NewFIFO(ctx, time.Millisecond)
+	})
+
+	_ = s.Store("key", "value", nil)
+	time.Sleep(time.Millisecond * 5)
+
+	_, ok, err := s.Load("key", nil)
+	assert.False(t, ok && err == nil)
+}
+
+func TestShardedSnapshotRestore(t *testing.T) {
+	s := NewShardedLRU(4, 0)
+	for i := 0; i < 100; i++ {
+		_ = s.Store(strconv.Itoa(i), i, nil)
+	}
+
+	buf := new(bytes.Buffer)
+	err := s.Snapshot(buf)
+	assert.NoError(t, err)
+
+	// records re-sharded when restored into a different number of shards.
+	restored := NewShardedLRU(2, 0)
+	err = restored.Restore(buf)
+	assert.NoError(t, err)
+	assert.Len(t, restored.Keys(), 100)
+
+	for i := 0; i < 100; i++ {
+		v, ok, err := restored.Load(strconv.Itoa(i), nil)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+	}
+
+	err = NewSharded(1, func() Cache { return struct{ Cache }{New(0)} }).Snapshot(buf)
+	assert.Error(t, err)
+}
+
+type closerCache struct {
+	Cache
+	closed bool
+}
+
+func (c *closerCache) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestShardedClose(t *testing.T) {
+	s := NewSharded(2, func() Cache {
+		return &closerCache{Cache: New(0)}
+	})
+
+	err := s.Close()
+	assert.NoError(t, err)
+
+	for _, c := range s.Shards() {
+		assert.True(t, c.(*closerCache).closed)
+	}
+}
+
+func benchmarkMixed(b *testing.B, c Cache) {
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = "token-" + strconv.Itoa(i)
+		_ = c.Store(keys[i], i, nil)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i&1023]
+			// 1 write per 8 reads.
+			if i&7 == 0 {
+				_ = c.Store(key, i, nil)
+			} else {
+				_, _, _ = c.Load(key, nil)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkLRUMixed(b *testing.B) {
+	benchmarkMixed(b, New(0))
+}
+
+func BenchmarkShardedLRUMixed(b *testing.B) {
+	benchmarkMixed(b, NewShardedLRU(32, 0))
+}
+
+func BenchmarkFIFOMixed(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	benchmarkMixed(b, NewFIFO(ctx, time.Hour))
+}
+
+func BenchmarkShardedFIFOMixed(b *testing.B) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	benchmarkMixed(b, NewSharded(32, func() Cache { return NewFIFO(ctx, time.Hour) }))
+}
@@ -0,0 +1,150 @@
+package store
+
+import (
+	"container/list"
+	"encoding/gob"
+	"io"
+	"time"
+)
+
+// Snapshotter is implemented by in-memory caches that serialize their live records,
+// so single-node services keep the cached authentication decisions across restarts.
+// Records encoded using encoding/gob, therefore the stored values types must be registered,
+// using gob.Register before snapshot or restore.
+type Snapshotter interface {
+	// Snapshot writes the cache live records to w, expired records omitted.
+	Snapshot(w io.Writer) error
+	// Restore reads records written by Snapshot from r and stores them in the cache,
+	// the records keep their remaining TTL and already expired records dropped.
+	Restore(r io.Reader) error
+}
+
+// Snapshot writes the cache live records to w, Typically on shutdown.
+// Records restored in the same recently used order, See Snapshotter.
+func (l *LRU) Snapshot(w io.Writer) error {
+	l.MU.Lock()
+	records := make([]*record, 0)
+	now := time.Now().UTC()
+
+	if l.ll != nil {
+		// oldest first, so restore pushes the most recently used to the front.
+		for e := l.ll.Back(); e != nil; e = e.Prev() {
+			r := e.Value.(*record)
+			if l.TTL > 0 && now.After(r.Exp) {
+				continue
+			}
+			records = append(records, r)
+		}
+	}
+
+	err := encodeRecords(w, records)
+	l.MU.Unlock()
+
+	return err
+}
+
+// Restore reads records written by Snapshot from r and stores them in the cache,
+// Typically on startup. See Snapshotter.
+func (l *LRU) Restore(r io.Reader) error {
+	records, err := decodeRecords(r)
+	if err != nil {
+		return err
+	}
+
+	l.MU.Lock()
+	defer l.MU.Unlock()
+
+	if l.cache == nil {
+		l.cache = make(map[string]*list.Element)
+		l.ll = list.New()
+	}
+
+	for _, r := range records {
+		if l.TTL > 0 && r.Exp.IsZero() {
+			l.withTTL(r)
+		}
+
+		if ee, ok := l.cache[r.Key]; ok {
+			ee.Value = r
+			l.ll.MoveToFront(ee)
+			continue
+		}
+
+		l.cache[r.Key] = l.ll.PushFront(r)
+
+		if l.MaxEntries != 0 && l.ll.Len() > l.MaxEntries {
+			l.removeOldest()
+		}
+	}
+
+	return nil
+}
+
+// Snapshot writes the cache live records to w, Typically on shutdown.
+// See Snapshotter.
+func (f *FIFO) Snapshot(w io.Writer) error {
+	f.MU.Lock()
+	records := make([]*record, 0, len(f.records))
+	now := time.Now().UTC()
+
+	for _, r := range f.records {
+		if now.After(r.Exp) {
+			continue
+		}
+		records = append(records, r)
+	}
+
+	err := encodeRecords(w, records)
+	f.MU.Unlock()
+
+	return err
+}
+
+// Restore reads records written by Snapshot from r and stores them in the cache,
+// Typically on startup. See Snapshotter.
+func (f *FIFO) Restore(r io.Reader) error {
+	records, err := decodeRecords(r)
+	if err != nil {
+		return err
+	}
+
+	f.MU.Lock()
+	for _, r := range records {
+		f.records[r.Key] = r
+	}
+	f.MU.Unlock()
+
+	for _, r := range records {
+		if f.manager != nil {
+			f.manager.schedule(f, r)
+			continue
+		}
+		f.queue.push(r)
+	}
+
+	return nil
+}
+
+func encodeRecords(w io.Writer, records []*record) error {
+	return gob.NewEncoder(w).Encode(records)
+}
+
+// decodeRecords decode records and drop the expired ones.
+func decodeRecords(r io.Reader) ([]*record, error) {
+	records := []*record{}
+	if err := gob.NewDecoder(r).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	live := records[:0]
+
+	for _, r := range records {
+		if !r.Exp.IsZero() && now.After(r.Exp) {
+			continue
+		}
+		live = append(live, r)
+	}
+
+	return live, nil
+}
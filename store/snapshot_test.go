@@ -0,0 +1,93 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUSnapshotRestore(t *testing.T) {
+	l := New(0)
+	l.TTL = time.Hour
+	_ = l.Store("1", "1", nil)
+	_ = l.Store("2", "2", nil)
+	_ = l.Store("3", "3", nil)
+	_, _, _ = l.Load("1", nil)
+
+	// expired record omitted from the snapshot.
+	l.cache["2"].Value.(*record).Exp = time.Now().UTC().Add(-time.Second)
+
+	buf := new(bytes.Buffer)
+	err := l.Snapshot(buf)
+	assert.NoError(t, err)
+
+	restored := New(1)
+	restored.TTL = time.Hour
+	err = restored.Restore(buf)
+	assert.NoError(t, err)
+
+	// most recently used record kept when restored cache has fewer entries.
+	v, ok, err := restored.Load("1", nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+	assert.Equal(t, 1, restored.Len())
+	assert.Equal(t, l.cache["1"].Value.(*record).Exp.Unix(), restored.cache["1"].Value.(*record).Exp.Unix())
+}
+
+func TestFIFOSnapshotRestore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := NewFIFO(ctx, time.Hour)
+	_ = f.Store("1", "1", nil)
+	_ = f.Store("2", "2", nil)
+
+	f.MU.Lock()
+	f.records["2"].Exp = time.Now().UTC().Add(-time.Second)
+	exp := f.records["1"].Exp
+	f.MU.Unlock()
+
+	buf := new(bytes.Buffer)
+	err := f.Snapshot(buf)
+	assert.NoError(t, err)
+
+	restored := NewFIFO(ctx, time.Minute)
+	err = restored.Restore(buf)
+	assert.NoError(t, err)
+
+	v, ok, err := restored.Load("1", nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+	assert.Equal(t, []string{"1"}, restored.Keys())
+
+	// remaining TTL kept over the restored cache TTL.
+	restored.MU.Lock()
+	assert.True(t, exp.Equal(restored.records["1"].Exp))
+	restored.MU.Unlock()
+}
+
+func TestRestoreDropExpired(t *testing.T) {
+	records := []*record{
+		{Key: "1", Value: "1", Exp: time.Now().UTC().Add(-time.Second)},
+		{Key: "2", Value: "2", Exp: time.Now().UTC().Add(time.Hour)},
+		{Key: "3", Value: "3"},
+	}
+
+	buf := new(bytes.Buffer)
+	_ = encodeRecords(buf, records)
+
+	got, err := decodeRecords(buf)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, "2", got[0].Key)
+	assert.Equal(t, "3", got[1].Key)
+
+	_, err = decodeRecords(strings.NewReader("invalid"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+//go:build go1.18
+// +build go1.18
+
+package store
+
+import (
+	"net/http"
+
+	"github.com/shaj13/go-guardian/errors"
+)
+
+// TypedCache wraps a Cache and stores/loads values of type T,
+// So callers does not need to assert the loaded value type.
+//
+// TypedCache requires go1.18 or later.
+type TypedCache[T any] struct {
+	Cache
+}
+
+// Load returns the value of type T stored in the cache for a key, or zero value if no value is present.
+// The ok result indicates whether value was found in the Cache.
+// If the stored value not of type T, an InvalidType error returned.
+func (t TypedCache[T]) Load(key string, r *http.Request) (T, bool, error) {
+	var zero T
+
+	v, ok, err := t.Cache.Load(key, r)
+	if err != nil || !ok {
+		return zero, ok, err
+	}
+
+	value, valid := v.(T)
+	if !valid {
+		return zero, ok, errors.NewInvalidType((*T)(nil), v)
+	}
+
+	return value, ok, nil
+}
+
+// Store sets the value for a key.
+func (t TypedCache[T]) Store(key string, value T, r *http.Request) error {
+	return t.Cache.Store(key, value, r)
+}
+
+// Typed return TypedCache wraps the given cache.
+func Typed[T any](c Cache) TypedCache[T] {
+	return TypedCache[T]{c}
+}
@@ -0,0 +1,32 @@
+//go:build go1.18
+// +build go1.18
+
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/shaj13/go-guardian/errors"
+)
+
+func TestTypedCache(t *testing.T) {
+	cache := Typed[int](New(2))
+
+	v, ok, err := cache.Load("key", nil)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, v)
+
+	_ = cache.Store("key", 1, nil)
+	v, ok, err = cache.Load("key", nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, v)
+
+	_ = cache.Cache.Store("invalid", "value", nil)
+	_, ok, err = cache.Load("invalid", nil)
+	assert.True(t, ok)
+	assert.IsType(t, errors.InvalidType{}, err)
+}
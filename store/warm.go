@@ -0,0 +1,62 @@
+package store
+
+import "sort"
+
+// Iterator iterates over records used to warm up a cache,
+// e.g rows of a persistent store or a snapshot.
+type Iterator interface {
+	// Next advances the iterator to the next record,
+	// and return false when there are no more records or an error occurs.
+	Next() bool
+	// Record returns the current record key and value.
+	Record() (key string, value interface{})
+	// Err returns the error, if any, that occurred during iteration.
+	Err() error
+}
+
+// Warm stores the iterator records in the cache and return the number of stored records,
+// Typically used at startup to repopulate the cache and avoid a latency spike of cold lookups.
+// Warm stops at the first iteration or store error.
+func Warm(c Cache, it Iterator) (int, error) {
+	n := 0
+
+	for it.Next() {
+		k, v := it.Record()
+		if err := c.Store(k, v, nil); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, it.Err()
+}
+
+// MapIterator return Iterator over the given map records, ordered by key.
+func MapIterator(m map[string]interface{}) Iterator {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return &mapIterator{m: m, keys: keys, i: -1}
+}
+
+type mapIterator struct {
+	m    map[string]interface{}
+	keys []string
+	i    int
+}
+
+func (m *mapIterator) Next() bool {
+	m.i++
+	return m.i < len(m.keys)
+}
+
+func (m *mapIterator) Record() (string, interface{}) {
+	k := m.keys[m.i]
+	return k, m.m[k]
+}
+
+func (m *mapIterator) Err() error { return nil }
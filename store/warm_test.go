@@ -0,0 +1,75 @@
+package store
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type errIterator struct {
+	Iterator
+	err error
+}
+
+func (e errIterator) Err() error { return e.err }
+
+type errCache struct {
+	NoCache
+}
+
+func (errCache) Store(_ string, _ interface{}, _ *http.Request) error {
+	return errors.New("store error")
+}
+
+func TestWarm(t *testing.T) {
+	m := map[string]interface{}{"1": 1, "2": 2, "3": 3}
+
+	table := []struct {
+		name        string
+		cache       Cache
+		it          Iterator
+		n           int
+		expectedErr bool
+	}{
+		{
+			name:  "it store all records",
+			cache: New(0),
+			it:    MapIterator(m),
+			n:     3,
+		},
+		{
+			name:        "it return iteration error",
+			cache:       New(0),
+			it:          errIterator{Iterator: MapIterator(m), err: errors.New("iteration error")},
+			n:           3,
+			expectedErr: true,
+		},
+		{
+			name:        "it return store error",
+			cache:       errCache{},
+			it:          MapIterator(m),
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Warm(tt.cache, tt.it)
+
+			assert.Equal(t, tt.expectedErr, err != nil)
+			assert.Equal(t, tt.n, n)
+
+			if tt.expectedErr {
+				return
+			}
+
+			for k, v := range m {
+				got, ok, _ := tt.cache.Load(k, nil)
+				assert.True(t, ok)
+				assert.Equal(t, v, got)
+			}
+		})
+	}
+}